@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of change recorded by an audit event.
+type AuditAction string
+
+// available audit actions.
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEvent records a change made to a resource of the platform.
+type AuditEvent struct {
+	ID   ID        `json:"id,omitempty"`
+	Time time.Time `json:"time"`
+
+	// ActorID is the user that made the change.
+	ActorID ID `json:"actorID,omitempty"`
+	// AuthorizerKind and AuthorizerID identify the authorization or the
+	// session used to make the change.
+	AuthorizerKind string `json:"authorizerKind,omitempty"`
+	AuthorizerID   ID     `json:"authorizerID,omitempty"`
+
+	Action       AuditAction  `json:"action"`
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+
+	RequestID string `json:"requestID,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+
+	// Before and After are the representations of the resource around the
+	// change, with any credentials removed.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+	// Changes lists the top level fields that differ between Before and After.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// ops for audit log.
+const (
+	OpLogAuditEvent   = "LogAuditEvent"
+	OpFindAuditEvents = "FindAuditEvents"
+)
+
+// AuditLogService records and retrieves audit events.
+type AuditLogService interface {
+	// LogAuditEvent records the event, setting its ID and, if unset, its time.
+	LogAuditEvent(ctx context.Context, e *AuditEvent) error
+
+	// FindAuditEvents returns the events that match the filter, ordered by
+	// time, and the total count of matching events.
+	FindAuditEvents(ctx context.Context, filter AuditEventFilter, opt ...FindOptions) ([]*AuditEvent, int, error)
+}
+
+// AuditEventFilter represents a set of filters that restrict the returned events.
+type AuditEventFilter struct {
+	// Start and Stop bound the time of the events, Stop is exclusive.
+	Start *time.Time
+	Stop  *time.Time
+
+	ResourceType *ResourceType
+	ResourceID   *ID
+	ActorID      *ID
+}
+
+// Matches returns true if the event matches the filter.
+func (f AuditEventFilter) Matches(e *AuditEvent) bool {
+	if f.Start != nil && e.Time.Before(*f.Start) {
+		return false
+	}
+	if f.Stop != nil && !e.Time.Before(*f.Stop) {
+		return false
+	}
+	if f.ResourceType != nil && e.ResourceType != *f.ResourceType {
+		return false
+	}
+	if f.ResourceID != nil && e.ResourceID != *f.ResourceID {
+		return false
+	}
+	if f.ActorID != nil && e.ActorID != *f.ActorID {
+		return false
+	}
+	return true
+}
+
+// DefaultAuditLogFindOptions are the default options for the audit log.
+var DefaultAuditLogFindOptions = FindOptions{
+	Descending: true,
+	Limit:      100,
+}
@@ -0,0 +1,79 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/audit"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/tsdb"
+)
+
+func TestLineProtocol(t *testing.T) {
+	es := []*platform.AuditEvent{
+		{
+			ID:             platform.ID(1),
+			Time:           time.Unix(0, 10),
+			ActorID:        platform.ID(2),
+			AuthorizerKind: "session",
+			Action:         platform.AuditUpdate,
+			ResourceType:   platform.BucketResourceType,
+			ResourceID:     platform.ID(3),
+			Path:           "/api/v2/buckets/0000000000000003",
+			Changes:        []string{"name", "retentionRules"},
+		},
+	}
+
+	b, err := audit.LineProtocol(es)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `audit,action=update,authorizerKind=session,resourceType=bucket actorID="0000000000000002",changes="name,retentionRules",id="0000000000000001",path="/api/v2/buckets/0000000000000003",resourceID="0000000000000003" 10` + "\n"
+	if got := string(b); got != want {
+		t.Errorf("unexpected line protocol:\ngot  %s\nwant %s", got, want)
+	}
+}
+
+func TestBucketWriter(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "_audit", OrganizationID: org.ID}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	pw := &mock.PointsWriter{}
+	w := audit.NewBucketWriter(svc, svc, pw, bucket.ID)
+
+	e := &platform.AuditEvent{Action: platform.AuditDelete, ResourceType: platform.TaskResourceType}
+	if err := w.LogAuditEvent(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(pw.Points); got != 1 {
+		t.Fatalf("expected 1 point, got %d", got)
+	}
+	name := tsdb.EncodeName(org.ID, bucket.ID)
+	if got := string(pw.Points[0].Name()); got != string(name[:]) {
+		t.Errorf("point written to %q, want %q", got, string(name[:]))
+	}
+
+	// failing to write to the bucket does not lose the event.
+	pw.ForceError(errors.New("engine closed"))
+	if err := w.LogAuditEvent(ctx, &platform.AuditEvent{Action: platform.AuditCreate}); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, err := svc.FindAuditEvents(ctx, platform.AuditEventFilter{}); err != nil || n != 2 {
+		t.Fatalf("expected 2 events, got %d: %v", n, err)
+	}
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"go.uber.org/zap"
+)
+
+var _ platform.AuditLogService = (*BucketWriter)(nil)
+
+// BucketWriter is an audit log that also writes every event it records
+// into a bucket, so that the log can be queried along with other data.
+type BucketWriter struct {
+	platform.AuditLogService
+
+	BucketService platform.BucketService
+	PointsWriter  storage.PointsWriter
+	Logger        *zap.Logger
+
+	// BucketID is the bucket the events are written to.
+	BucketID platform.ID
+}
+
+// NewBucketWriter returns an audit log that writes the events of svc to
+// the bucket with the given ID.
+func NewBucketWriter(svc platform.AuditLogService, bucketSvc platform.BucketService, w storage.PointsWriter, bucketID platform.ID) *BucketWriter {
+	return &BucketWriter{
+		AuditLogService: svc,
+		BucketService:   bucketSvc,
+		PointsWriter:    w,
+		Logger:          zap.NewNop(),
+		BucketID:        bucketID,
+	}
+}
+
+// LogAuditEvent records the event then writes it to the bucket. Failing to
+// write the event to the bucket does not fail the recording of the event.
+func (w *BucketWriter) LogAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	if err := w.AuditLogService.LogAuditEvent(ctx, e); err != nil {
+		return err
+	}
+
+	if err := w.write(ctx, e); err != nil {
+		w.Logger.Info("failed to write audit event to bucket",
+			zap.String("bucket_id", w.BucketID.String()),
+			zap.Error(err))
+	}
+	return nil
+}
+
+func (w *BucketWriter) write(ctx context.Context, e *platform.AuditEvent) error {
+	b, err := w.BucketService.FindBucketByID(ctx, w.BucketID)
+	if err != nil {
+		return err
+	}
+
+	ps, err := Points([]*platform.AuditEvent{e})
+	if err != nil {
+		return err
+	}
+
+	exploded, err := tsdb.ExplodePoints(b.OrganizationID, b.ID, ps)
+	if err != nil {
+		return err
+	}
+	return w.PointsWriter.WritePoints(exploded)
+}
@@ -0,0 +1,79 @@
+// Package audit records the changes made to the resources of the platform
+// by wrapping their services, and exports the audit log as points.
+package audit
+
+import (
+	"strings"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+)
+
+// Measurement is the measurement of the points of audit events.
+const Measurement = "audit"
+
+// Points returns the audit events as points of the audit measurement. The
+// kind of change is stored as tags, the details as fields.
+func Points(es []*platform.AuditEvent) ([]models.Point, error) {
+	ps := make([]models.Point, 0, len(es))
+	for _, e := range es {
+		p, err := point(e)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func point(e *platform.AuditEvent) (models.Point, error) {
+	tags := map[string]string{
+		"action":       string(e.Action),
+		"resourceType": string(e.ResourceType),
+	}
+	if e.AuthorizerKind != "" {
+		tags["authorizerKind"] = e.AuthorizerKind
+	}
+
+	fields := models.Fields{
+		"id": e.ID.String(),
+	}
+	if e.ResourceID.Valid() {
+		fields["resourceID"] = e.ResourceID.String()
+	}
+	if e.ActorID.Valid() {
+		fields["actorID"] = e.ActorID.String()
+	}
+	if e.AuthorizerID.Valid() {
+		fields["authorizerID"] = e.AuthorizerID.String()
+	}
+	for k, v := range map[string]string{
+		"requestID": e.RequestID,
+		"method":    e.Method,
+		"path":      e.Path,
+		"changes":   strings.Join(e.Changes, ","),
+		"before":    string(e.Before),
+		"after":     string(e.After),
+	} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+
+	return models.NewPoint(Measurement, models.NewTags(tags), fields, e.Time)
+}
+
+// LineProtocol returns the audit events encoded as line protocol.
+func LineProtocol(es []*platform.AuditEvent) ([]byte, error) {
+	ps, err := Points(es)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	for _, p := range ps {
+		b = p.AppendString(b)
+		b = append(b, '\n')
+	}
+	return b, nil
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"go.uber.org/zap"
+)
+
+// SystemAuthorizerKind is the kind of authorizer of the changes made without
+// one, by the bootstrap config at startup or the background deletions.
+const SystemAuthorizerKind = "system"
+
+// redactedFields are never recorded in the audit log.
+var redactedFields = map[string]bool{
+	"links":         true,
+	"password":      true,
+	"previousToken": true,
+	"token":         true,
+}
+
+// Recorder records the changes made through the services of the audit
+// package to the audit log, whether they are made by the API, the bootstrap
+// config, the import of bundles or the deletion of organizations.
+type Recorder struct {
+	AuditLogService platform.AuditLogService
+	// UserResourceMappingService finds the type of the resources labels are
+	// added to; it is left empty if nil.
+	UserResourceMappingService platform.UserResourceMappingService
+	Logger                     *zap.Logger
+}
+
+// NewRecorder returns a recorder of changes to svc.
+func NewRecorder(svc platform.AuditLogService) *Recorder {
+	return &Recorder{
+		AuditLogService: svc,
+		Logger:          zap.NewNop(),
+	}
+}
+
+// record records a change of a resource given its states before and after the
+// change.
+func (r *Recorder) record(ctx context.Context, action platform.AuditAction, rt platform.ResourceType, id platform.ID, before, after interface{}) {
+	e := &platform.AuditEvent{
+		Action:       action,
+		ResourceType: rt,
+		ResourceID:   id,
+		Before:       snapshot(before),
+		After:        snapshot(after),
+	}
+	if action == platform.AuditUpdate {
+		e.Changes = changes(e.Before, e.After)
+	}
+	r.log(ctx, e)
+}
+
+// log records an event made by the actor and the request of ctx. Failing to
+// record the event does not fail the change, the failure is logged.
+func (r *Recorder) log(ctx context.Context, e *platform.AuditEvent) {
+	e.AuthorizerKind = SystemAuthorizerKind
+	if a, err := platcontext.GetAuthorizer(ctx); err == nil {
+		e.ActorID = a.GetUserID()
+		e.AuthorizerKind = a.Kind()
+		e.AuthorizerID = a.Identifier()
+	}
+	if req, ok := platcontext.GetRequest(ctx); ok {
+		e.RequestID = req.ID
+		e.Method = req.Method
+		e.Path = req.Path
+	}
+
+	if err := r.AuditLogService.LogAuditEvent(ctx, e); err != nil {
+		r.Logger.Info("failed to record audit event",
+			zap.String("request_id", e.RequestID),
+			zap.String("resource_type", string(e.ResourceType)),
+			zap.Stringer("resource_id", e.ResourceID),
+			zap.Error(err))
+	}
+}
+
+// resourceType returns the type of a resource from its mappings to users.
+func (r *Recorder) resourceType(ctx context.Context, id platform.ID) platform.ResourceType {
+	if r.UserResourceMappingService == nil {
+		return ""
+	}
+	ms, _, err := r.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{ResourceID: id})
+	if err != nil || len(ms) == 0 {
+		return ""
+	}
+	return ms[0].ResourceType
+}
+
+// snapshot returns the JSON representation of the state of a resource with
+// its credentials removed, or nil if there is none.
+func snapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return redact(b)
+}
+
+// redact removes the credentials and links from a JSON document.
+func redact(b []byte) json.RawMessage {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	v = redactValue(v)
+
+	octets, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return octets
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if redactedFields[k] {
+				delete(v, k)
+				continue
+			}
+			v[k] = redactValue(fv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
+
+// changes returns the top level fields that differ between two JSON objects.
+func changes(before, after json.RawMessage) []string {
+	var b, a map[string]interface{}
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil
+		}
+	}
+
+	var changes []string
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || !reflect.DeepEqual(bv, v) {
+			changes = append(changes, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			changes = append(changes, k)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,702 @@
+package audit
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+var (
+	_ platform.UserService                = (*UserService)(nil)
+	_ platform.OrganizationService        = (*OrganizationService)(nil)
+	_ platform.BucketService              = (*BucketService)(nil)
+	_ platform.AuthorizationService       = (*AuthorizationService)(nil)
+	_ platform.UserResourceMappingService = (*UserResourceMappingService)(nil)
+	_ platform.GroupService               = (*GroupService)(nil)
+	_ platform.DashboardService           = (*DashboardService)(nil)
+	_ platform.ViewService                = (*ViewService)(nil)
+	_ platform.MacroService               = (*MacroService)(nil)
+	_ platform.TaskService                = (*TaskService)(nil)
+	_ platform.TelegrafConfigStore        = (*TelegrafService)(nil)
+	_ platform.ScraperTargetStoreService  = (*ScraperTargetStoreService)(nil)
+	_ platform.SourceService              = (*SourceService)(nil)
+	_ platform.LabelService               = (*LabelService)(nil)
+	_ platform.SecretService              = (*SecretService)(nil)
+)
+
+// UserService records the changes made to users.
+type UserService struct {
+	platform.UserService
+	Recorder *Recorder
+}
+
+// CreateUser creates a user and records it.
+func (s *UserService) CreateUser(ctx context.Context, u *platform.User) error {
+	if err := s.UserService.CreateUser(ctx, u); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.UserResourceType, u.ID, nil, u)
+	return nil
+}
+
+// UpdateUser updates a user and records the change.
+func (s *UserService) UpdateUser(ctx context.Context, id platform.ID, upd platform.UserUpdate) (*platform.User, error) {
+	before, _ := s.UserService.FindUserByID(ctx, id)
+	u, err := s.UserService.UpdateUser(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.UserResourceType, id, before, u)
+	return u, nil
+}
+
+// DeleteUser deletes a user and records it.
+func (s *UserService) DeleteUser(ctx context.Context, id platform.ID) error {
+	before, _ := s.UserService.FindUserByID(ctx, id)
+	if err := s.UserService.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.UserResourceType, id, before, nil)
+	return nil
+}
+
+// OrganizationService records the changes made to organizations.
+type OrganizationService struct {
+	platform.OrganizationService
+	Recorder *Recorder
+}
+
+// CreateOrganization creates an organization and records it.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, o *platform.Organization) error {
+	if err := s.OrganizationService.CreateOrganization(ctx, o); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.OrgResourceType, o.ID, nil, o)
+	return nil
+}
+
+// UpdateOrganization updates an organization and records the change.
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, id platform.ID, upd platform.OrganizationUpdate) (*platform.Organization, error) {
+	before, _ := s.OrganizationService.FindOrganizationByID(ctx, id)
+	o, err := s.OrganizationService.UpdateOrganization(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.OrgResourceType, id, before, o)
+	return o, nil
+}
+
+// DeleteOrganization deletes an organization and records it.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id platform.ID) error {
+	before, _ := s.OrganizationService.FindOrganizationByID(ctx, id)
+	if err := s.OrganizationService.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.OrgResourceType, id, before, nil)
+	return nil
+}
+
+// BucketService records the changes made to buckets.
+type BucketService struct {
+	platform.BucketService
+	Recorder *Recorder
+}
+
+// CreateBucket creates a bucket and records it.
+func (s *BucketService) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.BucketResourceType, b.ID, nil, b)
+	return nil
+}
+
+// UpdateBucket updates a bucket and records the change.
+func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+	before, _ := s.BucketService.FindBucketByID(ctx, id)
+	b, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.BucketResourceType, id, before, b)
+	return b, nil
+}
+
+// DeleteBucket deletes a bucket and records it.
+func (s *BucketService) DeleteBucket(ctx context.Context, id platform.ID) error {
+	before, _ := s.BucketService.FindBucketByID(ctx, id)
+	if err := s.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.BucketResourceType, id, before, nil)
+	return nil
+}
+
+// AuthorizationService records the changes made to authorizations. Their
+// tokens are never recorded.
+type AuthorizationService struct {
+	platform.AuthorizationService
+	Recorder *Recorder
+}
+
+// CreateAuthorization creates an authorization and records it.
+func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *platform.Authorization) error {
+	if err := s.AuthorizationService.CreateAuthorization(ctx, a); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.TokenResourceType, a.ID, nil, a)
+	return nil
+}
+
+// SetAuthorizationStatus updates the status of an authorization and records
+// the change.
+func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id platform.ID, status platform.Status) error {
+	before, _ := s.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err := s.AuthorizationService.SetAuthorizationStatus(ctx, id, status); err != nil {
+		return err
+	}
+	after, _ := s.AuthorizationService.FindAuthorizationByID(ctx, id)
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.TokenResourceType, id, before, after)
+	return nil
+}
+
+// RotateAuthorization rotates the token of an authorization and records the
+// change.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	before, _ := s.AuthorizationService.FindAuthorizationByID(ctx, id)
+	a, err := s.AuthorizationService.RotateAuthorization(ctx, id, grace)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.TokenResourceType, id, before, a)
+	return a, nil
+}
+
+// DeleteAuthorization deletes an authorization and records it.
+func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platform.ID) error {
+	before, _ := s.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err := s.AuthorizationService.DeleteAuthorization(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.TokenResourceType, id, before, nil)
+	return nil
+}
+
+// UserResourceMappingService records the changes made to the members and
+// owners of resources, as updates of the resources.
+type UserResourceMappingService struct {
+	platform.UserResourceMappingService
+	Recorder *Recorder
+}
+
+// members is the state of the members and owners of a resource.
+type members struct {
+	Members []*platform.UserResourceMapping `json:"members"`
+}
+
+func (s *UserResourceMappingService) members(ctx context.Context, resourceID platform.ID) *members {
+	ms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{ResourceID: resourceID})
+	if err != nil {
+		return nil
+	}
+	return &members{Members: ms}
+}
+
+// CreateUserResourceMapping creates a mapping and records the change of the
+// members of its resource.
+func (s *UserResourceMappingService) CreateUserResourceMapping(ctx context.Context, m *platform.UserResourceMapping) error {
+	before := s.members(ctx, m.ResourceID)
+	if err := s.UserResourceMappingService.CreateUserResourceMapping(ctx, m); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, m.ResourceType, m.ResourceID, before, s.members(ctx, m.ResourceID))
+	return nil
+}
+
+// DeleteUserResourceMapping deletes a mapping and records the change of the
+// members of its resource.
+func (s *UserResourceMappingService) DeleteUserResourceMapping(ctx context.Context, resourceID, userID platform.ID) error {
+	before := s.members(ctx, resourceID)
+	if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, resourceID, userID); err != nil {
+		return err
+	}
+	var rt platform.ResourceType
+	if before != nil && len(before.Members) > 0 {
+		rt = before.Members[0].ResourceType
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, rt, resourceID, before, s.members(ctx, resourceID))
+	return nil
+}
+
+// GroupService records the changes made to groups.
+type GroupService struct {
+	platform.GroupService
+	Recorder *Recorder
+}
+
+// CreateGroup creates a group and records it.
+func (s *GroupService) CreateGroup(ctx context.Context, g *platform.Group) error {
+	if err := s.GroupService.CreateGroup(ctx, g); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.GroupResourceType, g.ID, nil, g)
+	return nil
+}
+
+// UpdateGroup updates a group and records the change.
+func (s *GroupService) UpdateGroup(ctx context.Context, id platform.ID, upd platform.GroupUpdate) (*platform.Group, error) {
+	before, _ := s.GroupService.FindGroupByID(ctx, id)
+	g, err := s.GroupService.UpdateGroup(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.GroupResourceType, id, before, g)
+	return g, nil
+}
+
+// DeleteGroup deletes a group and records it.
+func (s *GroupService) DeleteGroup(ctx context.Context, id platform.ID) error {
+	before, _ := s.GroupService.FindGroupByID(ctx, id)
+	if err := s.GroupService.DeleteGroup(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.GroupResourceType, id, before, nil)
+	return nil
+}
+
+// DashboardService records the changes made to dashboards, including those
+// made to their cells.
+type DashboardService struct {
+	platform.DashboardService
+	Recorder *Recorder
+}
+
+// CreateDashboard creates a dashboard and records it.
+func (s *DashboardService) CreateDashboard(ctx context.Context, d *platform.Dashboard) error {
+	if err := s.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.DashboardResourceType, d.ID, nil, d)
+	return nil
+}
+
+// UpdateDashboard updates a dashboard and records the change.
+func (s *DashboardService) UpdateDashboard(ctx context.Context, id platform.ID, upd platform.DashboardUpdate) (*platform.Dashboard, error) {
+	before, _ := s.DashboardService.FindDashboardByID(ctx, id)
+	d, err := s.DashboardService.UpdateDashboard(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.DashboardResourceType, id, before, d)
+	return d, nil
+}
+
+// updateCells changes the cells of a dashboard with fn and records the change.
+func (s *DashboardService) updateCells(ctx context.Context, id platform.ID, fn func() error) error {
+	before, _ := s.DashboardService.FindDashboardByID(ctx, id)
+	if err := fn(); err != nil {
+		return err
+	}
+	after, _ := s.DashboardService.FindDashboardByID(ctx, id)
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.DashboardResourceType, id, before, after)
+	return nil
+}
+
+// AddDashboardCell adds a cell to a dashboard and records the change.
+func (s *DashboardService) AddDashboardCell(ctx context.Context, id platform.ID, c *platform.Cell, opts platform.AddDashboardCellOptions) error {
+	return s.updateCells(ctx, id, func() error {
+		return s.DashboardService.AddDashboardCell(ctx, id, c, opts)
+	})
+}
+
+// RemoveDashboardCell removes a cell of a dashboard and records the change.
+func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID, cellID platform.ID) error {
+	return s.updateCells(ctx, dashboardID, func() error {
+		return s.DashboardService.RemoveDashboardCell(ctx, dashboardID, cellID)
+	})
+}
+
+// UpdateDashboardCell updates a cell of a dashboard and records the change.
+func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID, cellID platform.ID, upd platform.CellUpdate) (*platform.Cell, error) {
+	var c *platform.Cell
+	err := s.updateCells(ctx, dashboardID, func() error {
+		var err error
+		c, err = s.DashboardService.UpdateDashboardCell(ctx, dashboardID, cellID, upd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReplaceDashboardCells replaces the cells of a dashboard and records the change.
+func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id platform.ID, cs []*platform.Cell) error {
+	return s.updateCells(ctx, id, func() error {
+		return s.DashboardService.ReplaceDashboardCells(ctx, id, cs)
+	})
+}
+
+// DeleteDashboard deletes a dashboard and records it.
+func (s *DashboardService) DeleteDashboard(ctx context.Context, id platform.ID) error {
+	before, _ := s.DashboardService.FindDashboardByID(ctx, id)
+	if err := s.DashboardService.DeleteDashboard(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.DashboardResourceType, id, before, nil)
+	return nil
+}
+
+// ViewService records the changes made to views.
+type ViewService struct {
+	platform.ViewService
+	Recorder *Recorder
+}
+
+// CreateView creates a view and records it.
+func (s *ViewService) CreateView(ctx context.Context, v *platform.View) error {
+	if err := s.ViewService.CreateView(ctx, v); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.ViewResourceType, v.ID, nil, v)
+	return nil
+}
+
+// UpdateView updates a view and records the change.
+func (s *ViewService) UpdateView(ctx context.Context, id platform.ID, upd platform.ViewUpdate) (*platform.View, error) {
+	before, _ := s.ViewService.FindViewByID(ctx, id)
+	v, err := s.ViewService.UpdateView(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.ViewResourceType, id, before, v)
+	return v, nil
+}
+
+// DeleteView deletes a view and records it.
+func (s *ViewService) DeleteView(ctx context.Context, id platform.ID) error {
+	before, _ := s.ViewService.FindViewByID(ctx, id)
+	if err := s.ViewService.DeleteView(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.ViewResourceType, id, before, nil)
+	return nil
+}
+
+// MacroService records the changes made to macros.
+type MacroService struct {
+	platform.MacroService
+	Recorder *Recorder
+}
+
+// CreateMacro creates a macro and records it.
+func (s *MacroService) CreateMacro(ctx context.Context, m *platform.Macro) error {
+	if err := s.MacroService.CreateMacro(ctx, m); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.MacroResourceType, m.ID, nil, m)
+	return nil
+}
+
+// UpdateMacro updates a macro and records the change.
+func (s *MacroService) UpdateMacro(ctx context.Context, id platform.ID, upd *platform.MacroUpdate) (*platform.Macro, error) {
+	before, _ := s.MacroService.FindMacroByID(ctx, id)
+	m, err := s.MacroService.UpdateMacro(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.MacroResourceType, id, before, m)
+	return m, nil
+}
+
+// ReplaceMacro replaces or creates a macro and records the change.
+func (s *MacroService) ReplaceMacro(ctx context.Context, m *platform.Macro) error {
+	before, err := s.MacroService.FindMacroByID(ctx, m.ID)
+	action := platform.AuditUpdate
+	if err != nil {
+		action = platform.AuditCreate
+	}
+	if err := s.MacroService.ReplaceMacro(ctx, m); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, action, platform.MacroResourceType, m.ID, before, m)
+	return nil
+}
+
+// DeleteMacro deletes a macro and records it.
+func (s *MacroService) DeleteMacro(ctx context.Context, id platform.ID) error {
+	before, _ := s.MacroService.FindMacroByID(ctx, id)
+	if err := s.MacroService.DeleteMacro(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.MacroResourceType, id, before, nil)
+	return nil
+}
+
+// TaskService records the changes made to tasks.
+type TaskService struct {
+	platform.TaskService
+	Recorder *Recorder
+}
+
+// CreateTask creates a task and records it.
+func (s *TaskService) CreateTask(ctx context.Context, t *platform.Task) error {
+	if err := s.TaskService.CreateTask(ctx, t); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.TaskResourceType, t.ID, nil, t)
+	return nil
+}
+
+// UpdateTask updates a task and records the change.
+func (s *TaskService) UpdateTask(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+	before, _ := s.TaskService.FindTaskByID(ctx, id)
+	t, err := s.TaskService.UpdateTask(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.TaskResourceType, id, before, t)
+	return t, nil
+}
+
+// DeleteTask deletes a task and records it.
+func (s *TaskService) DeleteTask(ctx context.Context, id platform.ID) error {
+	before, _ := s.TaskService.FindTaskByID(ctx, id)
+	if err := s.TaskService.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.TaskResourceType, id, before, nil)
+	return nil
+}
+
+// TelegrafService records the changes made to telegraf configs. The tokens
+// and passwords of their plugins are never recorded.
+type TelegrafService struct {
+	platform.TelegrafConfigStore
+	Recorder *Recorder
+}
+
+// CreateTelegrafConfig creates a telegraf config and records it.
+func (s *TelegrafService) CreateTelegrafConfig(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID, now time.Time) error {
+	if err := s.TelegrafConfigStore.CreateTelegrafConfig(ctx, tc, userID, now); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.TelegrafResourceType, tc.ID, nil, tc)
+	return nil
+}
+
+// UpdateTelegrafConfig updates a telegraf config and records the change.
+func (s *TelegrafService) UpdateTelegrafConfig(ctx context.Context, id platform.ID, tc *platform.TelegrafConfig, userID platform.ID, now time.Time) (*platform.TelegrafConfig, error) {
+	before, _ := s.TelegrafConfigStore.FindTelegrafConfigByID(ctx, id)
+	updated, err := s.TelegrafConfigStore.UpdateTelegrafConfig(ctx, id, tc, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.TelegrafResourceType, id, before, updated)
+	return updated, nil
+}
+
+// DeleteTelegrafConfig deletes a telegraf config and records it.
+func (s *TelegrafService) DeleteTelegrafConfig(ctx context.Context, id platform.ID) error {
+	before, _ := s.TelegrafConfigStore.FindTelegrafConfigByID(ctx, id)
+	if err := s.TelegrafConfigStore.DeleteTelegrafConfig(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.TelegrafResourceType, id, before, nil)
+	return nil
+}
+
+// ScraperTargetStoreService records the changes made to scraper targets.
+type ScraperTargetStoreService struct {
+	platform.ScraperTargetStoreService
+	Recorder *Recorder
+}
+
+// AddTarget creates a scraper target and records it.
+func (s *ScraperTargetStoreService) AddTarget(ctx context.Context, t *platform.ScraperTarget) error {
+	if err := s.ScraperTargetStoreService.AddTarget(ctx, t); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.ScraperResourceType, t.ID, nil, t)
+	return nil
+}
+
+// UpdateTarget updates a scraper target and records the change.
+func (s *ScraperTargetStoreService) UpdateTarget(ctx context.Context, t *platform.ScraperTarget) (*platform.ScraperTarget, error) {
+	before, _ := s.ScraperTargetStoreService.GetTargetByID(ctx, t.ID)
+	updated, err := s.ScraperTargetStoreService.UpdateTarget(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.ScraperResourceType, t.ID, before, updated)
+	return updated, nil
+}
+
+// RemoveTarget deletes a scraper target and records it.
+func (s *ScraperTargetStoreService) RemoveTarget(ctx context.Context, id platform.ID) error {
+	before, _ := s.ScraperTargetStoreService.GetTargetByID(ctx, id)
+	if err := s.ScraperTargetStoreService.RemoveTarget(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.ScraperResourceType, id, before, nil)
+	return nil
+}
+
+// SourceService records the changes made to sources.
+type SourceService struct {
+	platform.SourceService
+	Recorder *Recorder
+}
+
+// CreateSource creates a source and records it.
+func (s *SourceService) CreateSource(ctx context.Context, src *platform.Source) error {
+	if err := s.SourceService.CreateSource(ctx, src); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditCreate, platform.SourceResourceType, src.ID, nil, src)
+	return nil
+}
+
+// UpdateSource updates a source and records the change.
+func (s *SourceService) UpdateSource(ctx context.Context, id platform.ID, upd platform.SourceUpdate) (*platform.Source, error) {
+	before, _ := s.SourceService.FindSourceByID(ctx, id)
+	src, err := s.SourceService.UpdateSource(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.Recorder.record(ctx, platform.AuditUpdate, platform.SourceResourceType, id, before, src)
+	return src, nil
+}
+
+// DeleteSource deletes a source and records it.
+func (s *SourceService) DeleteSource(ctx context.Context, id platform.ID) error {
+	before, _ := s.SourceService.FindSourceByID(ctx, id)
+	if err := s.SourceService.DeleteSource(ctx, id); err != nil {
+		return err
+	}
+	s.Recorder.record(ctx, platform.AuditDelete, platform.SourceResourceType, id, before, nil)
+	return nil
+}
+
+// LabelService records the changes made to labels, as updates of the
+// resources they label.
+type LabelService struct {
+	platform.LabelService
+	Recorder *Recorder
+}
+
+// labels is the state of the labels of a resource.
+type labels struct {
+	Labels []*platform.Label `json:"labels"`
+}
+
+// updateLabels changes the labels of a resource with fn and records the change.
+func (s *LabelService) updateLabels(ctx context.Context, resourceID platform.ID, fn func() error) error {
+	before, _ := s.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: resourceID})
+	if err := fn(); err != nil {
+		return err
+	}
+	after, _ := s.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: resourceID})
+	rt := s.Recorder.resourceType(ctx, resourceID)
+	s.Recorder.record(ctx, platform.AuditUpdate, rt, resourceID, &labels{Labels: before}, &labels{Labels: after})
+	return nil
+}
+
+// CreateLabel creates a label and records the change of its resource.
+func (s *LabelService) CreateLabel(ctx context.Context, l *platform.Label) error {
+	return s.updateLabels(ctx, l.ResourceID, func() error {
+		return s.LabelService.CreateLabel(ctx, l)
+	})
+}
+
+// UpdateLabel updates a label and records the change of its resource.
+func (s *LabelService) UpdateLabel(ctx context.Context, l *platform.Label, upd platform.LabelUpdate) (*platform.Label, error) {
+	var updated *platform.Label
+	err := s.updateLabels(ctx, l.ResourceID, func() error {
+		var err error
+		updated, err = s.LabelService.UpdateLabel(ctx, l, upd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteLabel deletes a label and records the change of its resource.
+func (s *LabelService) DeleteLabel(ctx context.Context, l platform.Label) error {
+	return s.updateLabels(ctx, l.ResourceID, func() error {
+		return s.LabelService.DeleteLabel(ctx, l)
+	})
+}
+
+// SecretService records the changes made to the secrets of organizations,
+// as updates of the organizations listing the changed keys. The values of
+// the secrets are never recorded.
+type SecretService struct {
+	platform.SecretService
+	Recorder *Recorder
+}
+
+func (s *SecretService) record(ctx context.Context, orgID platform.ID, keys []string) {
+	sort.Strings(keys)
+	s.Recorder.log(ctx, &platform.AuditEvent{
+		Action:       platform.AuditUpdate,
+		ResourceType: platform.OrgResourceType,
+		ResourceID:   orgID,
+		Changes:      keys,
+	})
+}
+
+// PutSecret stores a secret and records the change of its organization.
+func (s *SecretService) PutSecret(ctx context.Context, orgID platform.ID, k, v string) error {
+	if err := s.SecretService.PutSecret(ctx, orgID, k, v); err != nil {
+		return err
+	}
+	s.record(ctx, orgID, []string{k})
+	return nil
+}
+
+// PutSecrets replaces the secrets of an organization and records the change.
+// The removed secrets are listed along with the stored ones.
+func (s *SecretService) PutSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	before, _ := s.SecretService.GetSecretKeys(ctx, orgID)
+	if err := s.SecretService.PutSecrets(ctx, orgID, m); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	for _, k := range before {
+		if _, ok := m[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	s.record(ctx, orgID, keys)
+	return nil
+}
+
+// PatchSecrets stores secrets of an organization and records the change.
+func (s *SecretService) PatchSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	if err := s.SecretService.PatchSecrets(ctx, orgID, m); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	s.record(ctx, orgID, keys)
+	return nil
+}
+
+// DeleteSecret deletes secrets of an organization and records the change.
+func (s *SecretService) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
+	if err := s.SecretService.DeleteSecret(ctx, orgID, ks...); err != nil {
+		return err
+	}
+	s.record(ctx, orgID, append([]string(nil), ks...))
+	return nil
+}
@@ -0,0 +1,120 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/audit"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+)
+
+func TestServices_System(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	r := audit.NewRecorder(svc)
+	orgSvc := &audit.OrganizationService{OrganizationService: svc, Recorder: r}
+	authSvc := &audit.AuthorizationService{AuthorizationService: svc, Recorder: r}
+
+	// the changes made at startup or in the background have no authorizer.
+	org := &platform.Organization{Name: "org"}
+	if err := orgSvc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	a := &platform.Authorization{Token: "secret", Status: platform.Active, UserID: user.ID}
+	if err := authSvc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := orgSvc.DeleteOrganization(ctx, org.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	es, _, err := svc.FindAuditEvents(ctx, platform.AuditEventFilter{}, platform.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(es))
+	}
+	for _, e := range es {
+		if e.AuthorizerKind != audit.SystemAuthorizerKind || e.ActorID.Valid() {
+			t.Errorf("unexpected actor %q %s", e.AuthorizerKind, e.ActorID)
+		}
+	}
+	if e := es[1]; e.ResourceType != platform.TokenResourceType || e.ResourceID != a.ID {
+		t.Errorf("unexpected resource %s %s", e.ResourceType, e.ResourceID)
+	} else if len(e.After) == 0 || bytes.Contains(e.After, []byte("secret")) {
+		t.Errorf("token was not removed from %s", e.After)
+	}
+	if e := es[2]; e.Action != platform.AuditDelete || len(e.Before) == 0 || len(e.After) != 0 {
+		t.Errorf("unexpected deletion %s %s -> %s", e.Action, e.Before, e.After)
+	}
+}
+
+func TestServices_Members(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	r := audit.NewRecorder(svc)
+	r.UserResourceMappingService = svc
+	urmSvc := &audit.UserResourceMappingService{UserResourceMappingService: svc, Recorder: r}
+	labelSvc := &audit.LabelService{LabelService: svc, Recorder: r}
+
+	user := &platform.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "bucket", OrganizationID: platform.ID(1)}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = platcontext.SetAuthorizer(ctx, &platform.Authorization{ID: platform.ID(2), UserID: user.ID})
+	ctx = platcontext.SetRequest(ctx, platcontext.Request{ID: "req", Method: "POST", Path: "/api/v2/buckets/" + bucket.ID.String() + "/owners"})
+	if err := urmSvc.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		ResourceID:   bucket.ID,
+		ResourceType: platform.BucketResourceType,
+		UserID:       user.ID,
+		UserType:     platform.Owner,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := labelSvc.CreateLabel(ctx, &platform.Label{ResourceID: bucket.ID, Name: "l"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := urmSvc.DeleteUserResourceMapping(ctx, bucket.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	es, _, err := svc.FindAuditEvents(ctx, platform.AuditEventFilter{}, platform.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Action       platform.AuditAction
+		ResourceType platform.ResourceType
+		ResourceID   platform.ID
+		Changes      []string
+		ActorID      platform.ID
+		RequestID    string
+	}
+	var got []event
+	for _, e := range es {
+		got = append(got, event{e.Action, e.ResourceType, e.ResourceID, e.Changes, e.ActorID, e.RequestID})
+	}
+	want := []event{
+		{platform.AuditUpdate, platform.BucketResourceType, bucket.ID, []string{"members"}, user.ID, "req"},
+		{platform.AuditUpdate, platform.BucketResourceType, bucket.ID, []string{"labels"}, user.ID, "req"},
+		{platform.AuditUpdate, platform.BucketResourceType, bucket.ID, []string{"members"}, user.ID, "req"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+	}
+}
@@ -2,6 +2,7 @@ package platform
 
 import (
 	"context"
+	"time"
 )
 
 // Authorization is a authorization. 🎉
@@ -13,6 +14,18 @@ type Authorization struct {
 	User        string       `json:"user,omitempty"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions,omitempty"`
+
+	// ExpiresAt is when the authorization stops being active; it never
+	// expires if unset.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// LastUsedAt is the last time the token was used to authenticate.
+	// It is recorded asynchronously and with a coarse resolution.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// PreviousToken is the token replaced by the last rotation. It remains
+	// valid until PreviousTokenExpiresAt so that clients can roll over.
+	PreviousToken          string     `json:"previousToken,omitempty"`
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
 }
 
 // Allowed returns true if the authorization is active and request permission
@@ -30,9 +43,38 @@ func IsActive(a *Authorization) bool {
 	return a.IsActive()
 }
 
-// IsActive returns true if the authorization active.
+// IsActive returns true if the authorization active and unexpired.
 func (a *Authorization) IsActive() bool {
-	return a.Status == Active
+	return a.Status == Active && !a.Expired(time.Now())
+}
+
+// Expired returns true if the authorization has expired at now.
+func (a *Authorization) Expired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// Stale returns true if the authorization has not been used since t.
+func (a *Authorization) Stale(t time.Time) bool {
+	return a.LastUsedAt == nil || a.LastUsedAt.Before(t)
+}
+
+// HasPreviousToken returns true if t is the token replaced by the last
+// rotation and its grace window has not passed at now.
+func (a *Authorization) HasPreviousToken(t string, now time.Time) bool {
+	return t != "" && a.PreviousToken == t &&
+		a.PreviousTokenExpiresAt != nil && now.Before(*a.PreviousTokenExpiresAt)
+}
+
+// Matches returns true if the authorization matches the expiry and usage
+// filters at now.
+func (f AuthorizationFilter) Matches(a *Authorization, now time.Time) bool {
+	if f.Expired && !a.Expired(now) {
+		return false
+	}
+	if f.UnusedSince != nil && !a.Stale(*f.UnusedSince) {
+		return false
+	}
+	return true
 }
 
 // GetUserID returns the user id.
@@ -54,6 +96,7 @@ const (
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpSetAuthorizationStatus   = "SetAuthorizationStatus"
 	OpDeleteAuthorization      = "DeleteAuthorization"
+	OpRotateAuthorization      = "RotateAuthorization"
 )
 
 // AuthorizationService represents a service for managing authorization data.
@@ -77,6 +120,16 @@ type AuthorizationService interface {
 
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
+
+	// RotateAuthorization issues a new token for the authorization, keeping
+	// its ID. The previous token remains valid for the grace period.
+	RotateAuthorization(ctx context.Context, id ID, grace time.Duration) (*Authorization, error)
+}
+
+// AuthorizationUsageService records the use of authorizations.
+type AuthorizationUsageService interface {
+	// SetAuthorizationLastUsed sets when the authorization was last used.
+	SetAuthorizationLastUsed(ctx context.Context, id ID, at time.Time) error
 }
 
 // AuthorizationFilter represents a set of filter that restrict the returned results.
@@ -86,4 +139,10 @@ type AuthorizationFilter struct {
 
 	UserID *ID
 	User   *string
+
+	// Expired only matches the authorizations that have expired.
+	Expired bool
+	// UnusedSince only matches the authorizations that have not been used
+	// since that time.
+	UnusedSince *time.Time
 }
@@ -0,0 +1,159 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
+)
+
+var (
+	auditLogBucket = []byte("auditlogv1")
+)
+
+var _ platform.AuditLogService = (*Client)(nil)
+
+func (c *Client) initializeAuditLog(ctx context.Context, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(auditLogBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// auditEventKey orders the events by time, then by ID.
+func auditEventKey(e *platform.AuditEvent) ([]byte, error) {
+	encodedID, err := e.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 8+len(encodedID))
+	binary.BigEndian.PutUint64(key, uint64(e.Time.UnixNano()))
+	copy(key[8:], encodedID)
+	return key, nil
+}
+
+func auditTimeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+// LogAuditEvent records an audit event.
+func (c *Client) LogAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	op := getOp(platform.OpLogAuditEvent)
+	e.ID = c.IDGenerator.ID()
+	if e.Time.IsZero() {
+		e.Time = c.time()
+	}
+	e.Time = e.Time.UTC()
+
+	key, err := auditEventKey(e)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInternal,
+			Op:   op,
+			Err:  err,
+		}
+	}
+
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(auditLogBucket).Put(key, v)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindAuditEvents returns the audit events that match the filter.
+// The time range of the filter is used to bound the scan of the log.
+func (c *Client) FindAuditEvents(ctx context.Context, filter platform.AuditEventFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+	var opts platform.FindOptions
+	if len(opt) > 0 {
+		opts = opt[0]
+	}
+
+	es := []*platform.AuditEvent{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		var err error
+		es, err = c.findAuditEvents(ctx, tx, filter, opts)
+		return err
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  getOp(platform.OpFindAuditEvents),
+			Err: err,
+		}
+	}
+
+	return es, len(es), nil
+}
+
+func (c *Client) findAuditEvents(ctx context.Context, tx *bolt.Tx, filter platform.AuditEventFilter, opts platform.FindOptions) ([]*platform.AuditEvent, error) {
+	cur := tx.Bucket(auditLogBucket).Cursor()
+
+	var k, v []byte
+	next := cur.Next
+	inRange := func(k []byte) bool {
+		return filter.Stop == nil || bytes.Compare(k, auditTimeKey(*filter.Stop)) < 0
+	}
+	if opts.Descending {
+		next = cur.Prev
+		if filter.Stop != nil {
+			// position on the last event before stop.
+			if k, _ = cur.Seek(auditTimeKey(*filter.Stop)); k == nil {
+				k, v = cur.Last()
+			} else {
+				k, v = cur.Prev()
+			}
+		} else {
+			k, v = cur.Last()
+		}
+		inRange = func(k []byte) bool {
+			return filter.Start == nil || bytes.Compare(k, auditTimeKey(*filter.Start)) >= 0
+		}
+	} else if filter.Start != nil {
+		k, v = cur.Seek(auditTimeKey(*filter.Start))
+	} else {
+		k, v = cur.First()
+	}
+
+	es := []*platform.AuditEvent{}
+	skipped := 0
+	for ; k != nil && inRange(k); k, v = next() {
+		e := &platform.AuditEvent{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return nil, err
+		}
+		if !filter.Matches(e) {
+			continue
+		}
+		if skipped < opts.Offset {
+			skipped++
+			continue
+		}
+		es = append(es, e)
+		if opts.Limit > 0 && len(es) >= opts.Limit {
+			break
+		}
+	}
+
+	return es, nil
+}
@@ -0,0 +1,34 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initAuditLogService(f platformtesting.AuditLogFields, t *testing.T) (platform.AuditLogService, string, func()) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	if f.NowFn != nil {
+		c.WithTime(f.NowFn)
+	}
+	ctx := context.TODO()
+	for _, e := range f.Events {
+		if err := c.LogAuditEvent(ctx, e); err != nil {
+			t.Fatalf("failed to populate audit events: %v", err)
+		}
+	}
+	return c, bolt.OpPrefix, func() {
+		defer closeFn()
+	}
+}
+
+func TestAuditLogService(t *testing.T) {
+	platformtesting.AuditLogService(initAuditLogService, t)
+}
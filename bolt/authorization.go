@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/coreos/bbolt"
 	"github.com/influxdata/platform"
@@ -120,29 +121,40 @@ func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n st
 			Err:  err,
 		}
 	}
-	return c.findAuthorizationByID(ctx, tx, id)
+	auth, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+	// the index still references tokens replaced by a rotation once their
+	// grace window has passed.
+	if auth.Token != n && !auth.HasPreviousToken(n, c.time()) {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+	return auth, nil
 }
 
-func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+func filterAuthorizationsFn(filter platform.AuthorizationFilter, now time.Time) func(a *platform.Authorization) bool {
+	fn := func(a *platform.Authorization) bool { return true }
 	if filter.ID != nil {
-		return func(a *platform.Authorization) bool {
+		fn = func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
 		}
-	}
-
-	if filter.Token != nil {
-		return func(a *platform.Authorization) bool {
+	} else if filter.Token != nil {
+		fn = func(a *platform.Authorization) bool {
 			return a.Token == *filter.Token
 		}
-	}
-
-	if filter.UserID != nil {
-		return func(a *platform.Authorization) bool {
+	} else if filter.UserID != nil {
+		fn = func(a *platform.Authorization) bool {
 			return a.UserID == *filter.UserID
 		}
 	}
 
-	return func(a *platform.Authorization) bool { return true }
+	return func(a *platform.Authorization) bool {
+		return fn(a) && filter.Matches(a, now)
+	}
 }
 
 // FindAuthorizations retrives all authorizations that match an arbitrary authorization filter.
@@ -154,6 +166,9 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 		if err != nil {
 			return nil, 0, err
 		}
+		if !filter.Matches(a, c.time()) {
+			return []*platform.Authorization{}, 0, nil
+		}
 
 		return []*platform.Authorization{a}, 1, nil
 	}
@@ -163,6 +178,9 @@ func (c *Client) FindAuthorizations(ctx context.Context, filter platform.Authori
 		if err != nil {
 			return nil, 0, err
 		}
+		if !filter.Matches(a, c.time()) {
+			return []*platform.Authorization{}, 0, nil
+		}
 
 		return []*platform.Authorization{a}, 1, nil
 	}
@@ -195,7 +213,7 @@ func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform
 	}
 
 	as := []*platform.Authorization{}
-	filterFn := filterAuthorizationsFn(f)
+	filterFn := filterAuthorizationsFn(f, c.time())
 	err := c.forEachAuthorization(ctx, tx, func(a *platform.Authorization) bool {
 		if filterFn(a) {
 			as = append(as, a)
@@ -224,6 +242,14 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 			a.UserID = u.ID
 		}
 
+		if u, err := c.findUserByID(ctx, tx, a.UserID); err == nil && !u.IsActive() {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Msg:  platform.ErrUserInactive,
+				Op:   op,
+			}
+		}
+
 		unique := c.uniqueAuthorizationToken(ctx, tx, a)
 
 		if !unique {
@@ -300,6 +326,14 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 			Err:  err,
 		}
 	}
+	if a.PreviousToken != "" {
+		if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.PreviousToken), encodedID); err != nil {
+			return &platform.Error{
+				Code: platform.EInternal,
+				Err:  err,
+			}
+		}
+	}
 	if err := tx.Bucket(authorizationBucket).Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -370,6 +404,13 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 			Err: err,
 		}
 	}
+	if a.PreviousToken != "" {
+		if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.PreviousToken)); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+	}
 	encodedID, err := id.Encode()
 	if err != nil {
 		return &platform.Error{
@@ -422,3 +463,75 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	}
 	return nil
 }
+
+// RotateAuthorization issues a new token for the authorization. The previous
+// token keeps authenticating until the grace period has passed.
+func (c *Client) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	var a *platform.Authorization
+	err := c.db.Update(func(tx *bolt.Tx) (err error) {
+		a, err = c.rotateAuthorization(ctx, tx, id, grace)
+		return err
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  getOp(platform.OpRotateAuthorization),
+		}
+	}
+	return a, nil
+}
+
+func (c *Client) rotateAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	a, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	token, err := c.TokenGenerator.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := tx.Bucket(authorizationIndex)
+	if a.PreviousToken != "" {
+		if err := idx.Delete(authorizationIndexKey(a.PreviousToken)); err != nil {
+			return nil, err
+		}
+	}
+
+	if grace > 0 {
+		expiresAt := c.time().Add(grace)
+		a.PreviousToken = a.Token
+		a.PreviousTokenExpiresAt = &expiresAt
+	} else {
+		if err := idx.Delete(authorizationIndexKey(a.Token)); err != nil {
+			return nil, err
+		}
+		a.PreviousToken = ""
+		a.PreviousTokenExpiresAt = nil
+	}
+	a.Token = token
+
+	if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+		return nil, pe
+	}
+	return a, nil
+}
+
+// SetAuthorizationLastUsed sets when the authorization was last used.
+func (c *Client) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, at time.Time) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		a, pe := c.findAuthorizationByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		if a.LastUsedAt != nil && !at.After(*a.LastUsedAt) {
+			return nil
+		}
+		a.LastUsedAt = &at
+		if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+			return pe
+		}
+		return nil
+	})
+}
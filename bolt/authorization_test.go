@@ -16,6 +16,9 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	}
 	c.IDGenerator = f.IDGenerator
 	c.TokenGenerator = f.TokenGenerator
+	if f.NowFn != nil {
+		c.WithTime(f.NowFn)
+	}
 	ctx := context.TODO()
 	for _, u := range f.Users {
 		if err := c.PutUser(ctx, u); err != nil {
@@ -1,9 +1,13 @@
 package bolt
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"time"
 
 	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,9 +21,17 @@ func (c *Client) SetPassword(ctx context.Context, name string, password string)
 // HashCost currently using the default cost of bcrypt
 var HashCost = bcrypt.DefaultCost
 
+// passwordHashers returns the hashers of the passwords, bcrypt with HashCost
+// unless others are configured.
+func (c *Client) passwordHashers() platform.PasswordHashers {
+	if len(c.PasswordHashers) > 0 {
+		return c.PasswordHashers
+	}
+	return platform.PasswordHashers{platform.BcryptHasher{Cost: HashCost}}
+}
+
 func (c *Client) setPassword(ctx context.Context, tx *bolt.Tx, name string, password string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), HashCost)
-	if err != nil {
+	if err := c.PasswordPolicy.Validate(password); err != nil {
 		return err
 	}
 
@@ -28,7 +40,21 @@ func (c *Client) setPassword(ctx context.Context, tx *bolt.Tx, name string, pass
 		return pe
 	}
 
-	encodedID, err := u.ID.Encode()
+	if err := c.putPasswordHash(tx, u.ID, password); err != nil {
+		return err
+	}
+
+	// a new password lifts any lockout.
+	return c.putPasswordAttempts(tx, u.ID, &passwordAttempts{})
+}
+
+func (c *Client) putPasswordHash(tx *bolt.Tx, id platform.ID, password string) error {
+	hash, err := c.passwordHashers().Hash(password)
+	if err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
 	if err != nil {
 		return err
 	}
@@ -37,33 +63,134 @@ func (c *Client) setPassword(ctx context.Context, tx *bolt.Tx, name string, pass
 }
 
 // ComparePassword compares a provided password with the stored password hash.
+// The attempt is counted as failed before the hash is compared, so that
+// concurrent guesses can't get past the lockout; it is forgotten if the
+// password matches. The hash is compared outside of any transaction, as it
+// is slow on purpose.
 func (c *Client) ComparePassword(ctx context.Context, name string, password string) error {
-	return c.db.View(func(tx *bolt.Tx) error {
-		return c.comparePassword(ctx, tx, name, password)
+	var (
+		id   platform.ID
+		hash []byte
+	)
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		u, pe := c.findUserByName(ctx, tx, name)
+		if pe != nil {
+			return pe
+		}
+		id = u.ID
+
+		encodedID, err := u.ID.Encode()
+		if err != nil {
+			return err
+		}
+		// the value is only valid during tx.
+		hash = append([]byte(nil), tx.Bucket(userpasswordBucket).Get(encodedID)...)
+		return c.reservePasswordAttempt(tx, id)
 	})
-}
-func (c *Client) comparePassword(ctx context.Context, tx *bolt.Tx, name string, password string) error {
-	u, pe := c.findUserByName(ctx, tx, name)
-	if pe != nil {
-		return pe
+	if err != nil {
+		return err
+	}
+
+	hashers := c.passwordHashers()
+	if err := hashers.Compare(hash, password); err != nil {
+		return err
+	}
+	var rehash []byte
+	if hashers.NeedsRehash(hash) {
+		if rehash, err = hashers.Hash(password); err != nil {
+			return err
+		}
 	}
 
-	encodedID, err := u.ID.Encode()
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return c.resetPasswordAttempts(tx, id, hash, rehash)
+	})
+}
+
+// reservePasswordAttempt counts an attempt to sign in as failed until its
+// password is compared, locking the user out once too many attempts failed
+// or are in progress. It fails if the user is locked out.
+func (c *Client) reservePasswordAttempt(tx *bolt.Tx, id platform.ID) error {
+	attempts, err := c.findPasswordAttempts(tx, id)
 	if err != nil {
 		return err
 	}
+	if c.time().Before(attempts.LockedUntil) {
+		return platform.ErrPasswordLocked
+	}
 
-	hash := tx.Bucket(userpasswordBucket).Get(encodedID)
+	attempts.Failures++
+	if c.PasswordPolicy.Locks(attempts.Failures) {
+		attempts.Failures = 0
+		attempts.LockedUntil = c.time().Add(c.PasswordPolicy.LockoutDuration)
+	}
+	return c.putPasswordAttempts(tx, id, attempts)
+}
 
-	return bcrypt.CompareHashAndPassword(hash, []byte(password))
+// resetPasswordAttempts forgets the failed attempts of a user whose password
+// matched. The hash that was compared is replaced with rehash, unless the
+// password was changed meanwhile.
+func (c *Client) resetPasswordAttempts(tx *bolt.Tx, id platform.ID, hash []byte, rehash []byte) error {
+	if rehash != nil {
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		b := tx.Bucket(userpasswordBucket)
+		if bytes.Equal(b.Get(encodedID), hash) {
+			if err := b.Put(encodedID, rehash); err != nil {
+				return err
+			}
+		}
+	}
+	return c.putPasswordAttempts(tx, id, &passwordAttempts{})
 }
 
 // CompareAndSetPassword replaces the old password with the new password if thee old password is correct.
 func (c *Client) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
-		if err := c.comparePassword(ctx, tx, name, old); err != nil {
-			return err
-		}
-		return c.setPassword(ctx, tx, name, new)
-	})
+	if err := c.ComparePassword(ctx, name, old); err != nil {
+		return err
+	}
+	return c.SetPassword(ctx, name, new)
+}
+
+// passwordAttempts are the failed attempts to sign in of a user.
+type passwordAttempts struct {
+	// Failures is the number of consecutive failed attempts.
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+func (c *Client) findPasswordAttempts(tx *bolt.Tx, id platform.ID) (*passwordAttempts, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &passwordAttempts{}
+	v := tx.Bucket(userpasswordAttemptsBucket).Get(encodedID)
+	if len(v) == 0 {
+		return a, nil
+	}
+	if err := json.Unmarshal(v, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (c *Client) putPasswordAttempts(tx *bolt.Tx, id platform.ID, a *passwordAttempts) error {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return err
+	}
+
+	if a.Failures == 0 && a.LockedUntil.IsZero() {
+		return tx.Bucket(userpasswordAttemptsBucket).Delete(encodedID)
+	}
+
+	v, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(userpasswordAttemptsBucket).Put(encodedID, v)
 }
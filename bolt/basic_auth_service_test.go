@@ -2,7 +2,9 @@ package bolt_test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
 	platformtesting "github.com/influxdata/platform/testing"
@@ -39,3 +41,90 @@ func TestBasicAuth_CompareAndSet(t *testing.T) {
 	t.Parallel()
 	platformtesting.CompareAndSetPassword(initBasicAuthService, t)
 }
+
+func initBasicAuthPolicyService(f platformtesting.BasicAuthFields, t *testing.T) (platform.BasicAuthService, func()) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	if f.NowFn != nil {
+		c.WithTime(f.NowFn)
+	}
+	c.PasswordPolicy = f.PasswordPolicy
+	c.PasswordHashers = f.PasswordHashers
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := c.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users")
+		}
+	}
+	return c, func() {
+		defer closeFn()
+		for _, u := range f.Users {
+			if err := c.DeleteUser(ctx, u.ID); err != nil {
+				t.Logf("failed to remove users: %v", err)
+			}
+		}
+	}
+}
+
+func TestBasicAuth_PasswordPolicy(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordPolicy(initBasicAuthPolicyService, t)
+}
+
+func TestBasicAuth_PasswordLockout(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordLockout(initBasicAuthPolicyService, t)
+}
+
+func TestBasicAuth_PasswordRehash(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordRehash(initBasicAuthPolicyService, t)
+}
+
+func TestBasicAuth_ConcurrentPasswordAttempts(t *testing.T) {
+	t.Parallel()
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	c.PasswordPolicy = platform.PasswordPolicy{MaxFailedAttempts: 3, LockoutDuration: time.Hour}
+
+	ctx := context.Background()
+	if err := c.CreateUser(ctx, &platform.User{Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetPassword(ctx, "user", "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the guesses compared concurrently are counted before their hash is.
+	const guesses = 10
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		compared int
+	)
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.ComparePassword(ctx, "user", "guess"); err != platform.ErrPasswordLocked {
+				mu.Lock()
+				compared++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if compared != 3 {
+		t.Errorf("expected 3 guesses to be compared, got %d", compared)
+	}
+	if err := c.ComparePassword(ctx, "user", "howdydoody"); err != platform.ErrPasswordLocked {
+		t.Errorf("expected the user to be locked out, got %v", err)
+	}
+}
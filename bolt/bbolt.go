@@ -30,6 +30,11 @@ type Client struct {
 	IDGenerator    platform.IDGenerator
 	TokenGenerator platform.TokenGenerator
 	time           func() time.Time
+
+	// PasswordPolicy are the rules of the passwords and of the sign in attempts.
+	PasswordPolicy platform.PasswordPolicy
+	// PasswordHashers hash the passwords, the first one hashes new passwords.
+	PasswordHashers platform.PasswordHashers
 }
 
 // NewClient returns an instance of a Client.
@@ -148,11 +153,21 @@ func (c *Client) initialize(ctx context.Context) error {
 			return err
 		}
 
+		// Always create Groups bucket.
+		if err := c.initializeGroups(ctx, tx); err != nil {
+			return err
+		}
+
 		// Always create labels bucket.
 		if err := c.initializeLabels(ctx, tx); err != nil {
 			return err
 		}
 
+		// Always create audit log bucket.
+		if err := c.initializeAuditLog(ctx, tx); err != nil {
+			return err
+		}
+
 		// Always create Session bucket.
 		if err := c.initializeSessions(ctx, tx); err != nil {
 			return err
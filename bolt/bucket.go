@@ -406,9 +406,12 @@ func (c *Client) putBucket(ctx context.Context, tx *bolt.Tx, b *platform.Bucket)
 		return pe
 	}
 
-	if err := tx.Bucket(bucketIndex).Put(key, encodedID); err != nil {
-		return &platform.Error{
-			Err: err,
+	// deleted buckets aren't indexed by name, freeing it for new buckets.
+	if b.DeletedAt == nil {
+		if err := tx.Bucket(bucketIndex).Put(key, encodedID); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
 		}
 	}
 	if err := tx.Bucket(bucketBucket).Put(encodedID, v); err != nil {
@@ -452,13 +455,19 @@ func (c *Client) forEachBucket(ctx context.Context, tx *bolt.Tx, fn func(*platfo
 	return nil
 }
 
+// uniqueBucketName returns true unless the name of b is indexed for another
+// bucket of its organization.
 func (c *Client) uniqueBucketName(ctx context.Context, tx *bolt.Tx, b *platform.Bucket) bool {
 	key, err := bucketIndexKey(b)
 	if err != nil {
 		return false
 	}
 	v := tx.Bucket(bucketIndex).Get(key)
-	return len(v) == 0
+	if len(v) == 0 {
+		return true
+	}
+	var id platform.ID
+	return id.Decode(v) == nil && id == b.ID
 }
 
 // UpdateBucket updates a bucket according the parameters set on upd.
@@ -482,20 +491,25 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 		return nil, err
 	}
 
-	if upd.RetentionPeriod != nil {
-		b.RetentionPeriod = *upd.RetentionPeriod
-	}
-
-	if upd.Name != nil {
+	if b.DeletedAt == nil && (upd.Name != nil || upd.DeletedAt != nil) {
 		key, err := bucketIndexKey(b)
 		if err != nil {
 			return nil, err
 		}
-		// Buckets are indexed by name and so the bucket index must be pruned when name is modified.
+		// Buckets are indexed by name and so the bucket index must be pruned when name is modified,
+		// or when the bucket is deleted.
 		if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
 			return nil, err
 		}
-		b.Name = *upd.Name
+	}
+	upd.Apply(b)
+
+	// a restored bucket takes its name back, unless another bucket took it meanwhile.
+	if b.DeletedAt == nil && !c.uniqueBucketName(ctx, tx, b) {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Msg:  fmt.Sprintf("bucket with name %s already exists", b.Name),
+		}
 	}
 
 	if err := c.appendBucketEventToLog(ctx, tx, b.ID, bucketUpdatedEvent); err != nil {
@@ -535,9 +549,12 @@ func (c *Client) deleteBucket(ctx context.Context, tx *bolt.Tx, id platform.ID)
 		return pe
 	}
 	// make lowercase deleteBucket with tx
-	if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
-		return &platform.Error{
-			Err: err,
+	// The name of a deleted bucket isn't indexed, it may be another bucket's.
+	if b.DeletedAt == nil {
+		if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
 		}
 	}
 	encodedID, err := id.Encode()
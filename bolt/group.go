@@ -0,0 +1,334 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
+)
+
+var (
+	groupBucket = []byte("groupsv1")
+)
+
+var _ platform.GroupService = (*Client)(nil)
+
+func (c *Client) initializeGroups(ctx context.Context, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists([]byte(groupBucket)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindGroupByID retrieves a group by id.
+func (c *Client) FindGroupByID(ctx context.Context, id platform.ID) (*platform.Group, error) {
+	var g *platform.Group
+	err := c.db.View(func(tx *bolt.Tx) error {
+		group, pe := c.findGroupByID(ctx, tx, id)
+		if pe != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpFindGroupByID),
+				Err: pe,
+			}
+		}
+		g = group
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (c *Client) findGroupByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Group, *platform.Error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v := tx.Bucket(groupBucket).Get(encodedID)
+	if len(v) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "group not found",
+		}
+	}
+
+	var g platform.Group
+	if err := json.Unmarshal(v, &g); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
+	return &g, nil
+}
+
+// FindGroup retrieves the first group matching the filter.
+func (c *Client) FindGroup(ctx context.Context, filter platform.GroupFilter) (*platform.Group, error) {
+	op := getOp(platform.OpFindGroup)
+	if filter.ID != nil {
+		g, err := c.FindGroupByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return g, nil
+	}
+
+	gs, _, err := c.FindGroups(ctx, filter)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if len(gs) == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   op,
+			Msg:  "group not found",
+		}
+	}
+	return gs[0], nil
+}
+
+func filterGroupsFn(filter platform.GroupFilter) func(g *platform.Group) bool {
+	return func(g *platform.Group) bool {
+		return (filter.ID == nil || *filter.ID == g.ID) &&
+			(filter.Name == nil || *filter.Name == g.Name) &&
+			(filter.OrganizationID == nil || *filter.OrganizationID == g.OrganizationID)
+	}
+}
+
+// FindGroups retrieves all groups that match the filter.
+func (c *Client) FindGroups(ctx context.Context, filter platform.GroupFilter, opt ...platform.FindOptions) ([]*platform.Group, int, error) {
+	gs := []*platform.Group{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		var err error
+		gs, err = c.findGroups(ctx, tx, filter)
+		return err
+	})
+
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  getOp(platform.OpFindGroups),
+			Err: err,
+		}
+	}
+
+	return gs, len(gs), nil
+}
+
+func (c *Client) findGroups(ctx context.Context, tx *bolt.Tx, filter platform.GroupFilter) ([]*platform.Group, error) {
+	gs := []*platform.Group{}
+	filterFn := filterGroupsFn(filter)
+	err := forEachGroup(ctx, tx, func(g *platform.Group) bool {
+		if filterFn(g) {
+			gs = append(gs, g)
+		}
+		return true
+	})
+	return gs, err
+}
+
+// CreateGroup creates a group and sets g.ID.
+func (c *Client) CreateGroup(ctx context.Context, g *platform.Group) error {
+	op := getOp(platform.OpCreateGroup)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if g.Name == "" {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Msg:  "group name is required",
+			}
+		}
+		if _, pe := c.findOrganizationByID(ctx, tx, g.OrganizationID); pe != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: pe,
+			}
+		}
+		if pe := c.uniqueGroupName(ctx, tx, g); pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		g.ID = c.IDGenerator.ID()
+		if pe := c.putGroup(ctx, tx, g); pe != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: pe,
+			}
+		}
+		return nil
+	})
+}
+
+// PutGroup will put a group without setting an ID.
+func (c *Client) PutGroup(ctx context.Context, g *platform.Group) error {
+	var err error
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if pe := c.putGroup(ctx, tx, g); pe != nil {
+			err = pe
+		}
+		return err
+	})
+}
+
+func (c *Client) putGroup(ctx context.Context, tx *bolt.Tx, g *platform.Group) *platform.Error {
+	v, err := json.Marshal(g)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	encodedID, err := g.ID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if err := tx.Bucket(groupBucket).Put(encodedID, v); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueGroupName returns an error if another group of the organization has the name of g.
+func (c *Client) uniqueGroupName(ctx context.Context, tx *bolt.Tx, g *platform.Group) *platform.Error {
+	gs, err := c.findGroups(ctx, tx, platform.GroupFilter{
+		Name:           &g.Name,
+		OrganizationID: &g.OrganizationID,
+	})
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	for _, other := range gs {
+		if other.ID != g.ID {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("group with name %s already exists", g.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// forEachGroup will iterate through all groups while fn returns true.
+func forEachGroup(ctx context.Context, tx *bolt.Tx, fn func(*platform.Group) bool) error {
+	cur := tx.Bucket(groupBucket).Cursor()
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		g := &platform.Group{}
+		if err := json.Unmarshal(v, g); err != nil {
+			return err
+		}
+		if !fn(g) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// UpdateGroup updates a group according the parameters set on upd.
+func (c *Client) UpdateGroup(ctx context.Context, id platform.ID, upd platform.GroupUpdate) (*platform.Group, error) {
+	op := getOp(platform.OpUpdateGroup)
+	var g *platform.Group
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		if err := upd.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		group, pe := c.findGroupByID(ctx, tx, id)
+		if pe != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: pe,
+			}
+		}
+
+		if upd.Name != nil {
+			group.Name = *upd.Name
+			if pe := c.uniqueGroupName(ctx, tx, group); pe != nil {
+				pe.Op = op
+				return pe
+			}
+		}
+		if upd.Description != nil {
+			group.Description = *upd.Description
+		}
+
+		if pe := c.putGroup(ctx, tx, group); pe != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: pe,
+			}
+		}
+		g = group
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// DeleteGroup deletes a group, its memberships and the mappings of the group to resources.
+func (c *Client) DeleteGroup(ctx context.Context, id platform.ID) error {
+	op := getOp(platform.OpDeleteGroup)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if _, pe := c.findGroupByID(ctx, tx, id); pe != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: pe,
+			}
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Err:  err,
+			}
+		}
+		if err := tx.Bucket(groupBucket).Delete(encodedID); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		for _, f := range []platform.UserResourceMappingFilter{
+			{ResourceID: id, ResourceType: platform.GroupResourceType},
+			{UserID: id, PrincipalType: platform.GroupPrincipal},
+		} {
+			if err := c.deleteUserResourceMappings(ctx, tx, f); err != nil {
+				return &platform.Error{
+					Op:  op,
+					Err: err,
+				}
+			}
+		}
+		return nil
+	})
+}
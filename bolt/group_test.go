@@ -0,0 +1,38 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform/bolt"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initGroupService(f platformtesting.GroupFields, t *testing.T) (platformtesting.GroupServices, string, func()) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := c.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, g := range f.Groups {
+		if err := c.PutGroup(ctx, g); err != nil {
+			t.Fatalf("failed to populate groups")
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := c.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings")
+		}
+	}
+	return c, bolt.OpPrefix, closeFn
+}
+
+func TestGroupService(t *testing.T) {
+	platformtesting.GroupService(initGroupService, t)
+}
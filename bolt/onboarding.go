@@ -85,6 +85,10 @@ func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		}
 	}
 
+	if err := c.PasswordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
 	u := &platform.User{Name: req.User}
 	if err := c.CreateUser(ctx, u); err != nil {
 		return nil, err
@@ -363,8 +363,8 @@ func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id platfor
 				Err: err,
 			}
 		}
-		o.Name = *upd.Name
 	}
+	upd.Apply(o)
 
 	if err := c.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
 		return nil, &platform.Error{
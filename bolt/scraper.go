@@ -9,15 +9,20 @@ import (
 )
 
 var (
-	scraperBucket = []byte("scraperv2")
+	scraperBucket       = []byte("scraperv2")
+	scraperStatusBucket = []byte("scraperstatusv1")
 )
 
 var _ platform.ScraperTargetStoreService = (*Client)(nil)
+var _ platform.ScraperTargetStatusService = (*Client)(nil)
 
 func (c *Client) initializeScraperTargets(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists([]byte(scraperBucket)); err != nil {
 		return err
 	}
+	if _, err := tx.CreateBucketIfNotExists(scraperStatusBucket); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -73,6 +78,9 @@ func (c *Client) RemoveTarget(ctx context.Context, id platform.ID) error {
 				Err:  err,
 			}
 		}
+		if err := tx.Bucket(scraperStatusBucket).Delete(encID); err != nil {
+			return err
+		}
 		return tx.Bucket(scraperBucket).Delete(encID)
 	})
 	if err != nil {
@@ -175,3 +183,57 @@ func (c *Client) PutTarget(ctx context.Context, target *platform.ScraperTarget)
 		return c.putTarget(ctx, tx, target)
 	})
 }
+
+// GetTargetStatus retrieves the status of the last scrape of a target.
+func (c *Client) GetTargetStatus(ctx context.Context, id platform.ID) (status *platform.ScraperTargetStatus, err error) {
+	err = c.db.View(func(tx *bolt.Tx) error {
+		if _, pe := c.findTargetByID(ctx, tx, id); pe != nil {
+			return pe
+		}
+		encID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		v := tx.Bucket(scraperStatusBucket).Get(encID)
+		if len(v) == 0 {
+			status = &platform.ScraperTargetStatus{
+				Health: platform.ScraperTargetHealthUnknown,
+			}
+			return nil
+		}
+		status = new(platform.ScraperTargetStatus)
+		return json.Unmarshal(v, status)
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  getOp(platform.OpGetTargetStatus),
+			Err: err,
+		}
+	}
+	return status, nil
+}
+
+// PutTargetStatus stores the status of the last scrape of a target.
+func (c *Client) PutTargetStatus(ctx context.Context, id platform.ID, status *platform.ScraperTargetStatus) error {
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		if _, pe := c.findTargetByID(ctx, tx, id); pe != nil {
+			return pe
+		}
+		encID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(scraperStatusBucket).Put(encID, v)
+	})
+	if err != nil {
+		return &platform.Error{
+			Op:  getOp(platform.OpPutTargetStatus),
+			Err: err,
+		}
+	}
+	return nil
+}
@@ -82,6 +82,11 @@ func (c *Client) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string
 	return vs, nil
 }
 
+var errNoSecretKeys = &platform.Error{
+	Code: platform.ENotFound,
+	Msg:  "organization has no secret keys",
+}
+
 func (c *Client) getSecretKeys(ctx context.Context, tx *bolt.Tx, orgID platform.ID) ([]string, error) {
 	cur := tx.Bucket(secretBucket).Cursor()
 	prefix, err := orgID.Encode()
@@ -89,6 +94,9 @@ func (c *Client) getSecretKeys(ctx context.Context, tx *bolt.Tx, orgID platform.
 		return nil, err
 	}
 	k, _ := cur.Seek(prefix)
+	if len(k) == 0 {
+		return nil, errNoSecretKeys
+	}
 
 	id, key, err := decodeSecretKey(k)
 	if err != nil {
@@ -96,7 +104,7 @@ func (c *Client) getSecretKeys(ctx context.Context, tx *bolt.Tx, orgID platform.
 	}
 
 	if id != orgID {
-		return nil, fmt.Errorf("organization has no secret keys")
+		return nil, errNoSecretKeys
 	}
 
 	keys := []string{key}
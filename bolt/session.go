@@ -70,18 +70,14 @@ func (c *Client) findSession(ctx context.Context, tx *bolt.Tx, key string) (*pla
 	}
 
 	// TODO(desa): these values should be cached so it's not so expensive to lookup each time.
-	f := platform.UserResourceMappingFilter{UserID: s.UserID}
-	mappings, err := c.findUserResourceMappings(ctx, tx, f)
+	ps, err := platform.FindUserPermissions(func(f platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, error) {
+		return c.findUserResourceMappings(ctx, tx, f)
+	}, s.UserID)
 	if err != nil {
 		return nil, &platform.Error{
 			Err: err,
 		}
 	}
-
-	ps := make([]platform.Permission, 0, len(mappings))
-	for _, m := range mappings {
-		ps = append(ps, m.ToPermissions()...)
-	}
 	s.Permissions = ps
 	return s, nil
 }
@@ -128,6 +124,43 @@ func (c *Client) ExpireSession(ctx context.Context, key string) error {
 	})
 }
 
+// expireUsersSessions expires all the sessions of a user, or deletes them.
+func (c *Client) expireUsersSessions(ctx context.Context, tx *bolt.Tx, id platform.ID, delete bool) *platform.Error {
+	var ss []*platform.Session
+	cur := tx.Bucket(sessionBucket).Cursor()
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		s := &platform.Session{}
+		if err := json.Unmarshal(v, s); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+		if s.UserID == id {
+			ss = append(ss, s)
+		}
+	}
+
+	now := c.time()
+	for _, s := range ss {
+		if delete {
+			if err := tx.Bucket(sessionBucket).Delete([]byte(s.Key)); err != nil {
+				return &platform.Error{
+					Err: err,
+				}
+			}
+			continue
+		}
+		if s.ExpiresAt.Before(now) {
+			continue
+		}
+		s.ExpiresAt = now
+		if err := c.putSession(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateSession creates a session for a user with the users maximal privileges.
 func (c *Client) CreateSession(ctx context.Context, user string) (*platform.Session, error) {
 	var sess *platform.Session
@@ -154,6 +187,12 @@ func (c *Client) createSession(ctx context.Context, tx *bolt.Tx, user string) (*
 	if pe != nil {
 		return nil, pe
 	}
+	if !u.IsActive() {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  platform.ErrUserInactive,
+		}
+	}
 
 	s := &platform.Session{}
 	s.ID = c.IDGenerator.ID()
@@ -15,6 +15,11 @@ var (
 	userUser           = []byte("usersv1")
 	userIndex          = []byte("userindexv1")
 	userpasswordBucket = []byte("userspasswordv1")
+	// userpasswordAttemptsBucket holds the failed attempts to sign in of users.
+	userpasswordAttemptsBucket = []byte("userspasswordattemptsv1")
+	// userOAuthIndex indexes users by the identity they are linked to at an
+	// external provider.
+	userOAuthIndex = []byte("useroauthindexv1")
 )
 
 var _ platform.UserService = (*Client)(nil)
@@ -28,9 +33,15 @@ func (c *Client) initializeUsers(ctx context.Context, tx *bolt.Tx) error {
 	if _, err := tx.CreateBucketIfNotExists([]byte(userIndex)); err != nil {
 		return err
 	}
+	if _, err := tx.CreateBucketIfNotExists(userOAuthIndex); err != nil {
+		return err
+	}
 	if _, err := tx.CreateBucketIfNotExists([]byte(userpasswordBucket)); err != nil {
 		return err
 	}
+	if _, err := tx.CreateBucketIfNotExists([]byte(userpasswordAttemptsBucket)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -118,8 +129,35 @@ func (c *Client) findUserByName(ctx context.Context, tx *bolt.Tx, n string) (*pl
 	return c.findUserByID(ctx, tx, id)
 }
 
+// findUserByOAuthID returns the user linked to an identity at an external
+// provider.
+func (c *Client) findUserByOAuthID(ctx context.Context, oauthID string) (*platform.User, error) {
+	var u *platform.User
+	err := c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(userOAuthIndex).Get([]byte(oauthID))
+		if v == nil {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  "user not found",
+			}
+		}
+
+		var id platform.ID
+		if err := id.Decode(v); err != nil {
+			return err
+		}
+		usr, pe := c.findUserByID(ctx, tx, id)
+		if pe != nil {
+			return pe
+		}
+		u = usr
+		return nil
+	})
+	return u, err
+}
+
 // FindUser retrives a user using an arbitrary user filter.
-// Filters using ID, or Name should be efficient.
+// Filters using ID, Name or OAuthID should be efficient.
 // Other filters will do a linear scan across users until it finds a match.
 func (c *Client) FindUser(ctx context.Context, filter platform.UserFilter) (*platform.User, error) {
 	var u *platform.User
@@ -147,6 +185,17 @@ func (c *Client) FindUser(ctx context.Context, filter platform.UserFilter) (*pla
 		return u, nil
 	}
 
+	if filter.OAuthID != nil {
+		u, err = c.findUserByOAuthID(ctx, *filter.OAuthID)
+		if err != nil {
+			return nil, &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return u, nil
+	}
+
 	filterFn := filterUsersFn(filter)
 
 	err = c.db.View(func(tx *bolt.Tx) error {
@@ -189,11 +238,17 @@ func filterUsersFn(filter platform.UserFilter) func(u *platform.User) bool {
 		}
 	}
 
+	if filter.OAuthID != nil {
+		return func(u *platform.User) bool {
+			return u.OAuthID == *filter.OAuthID
+		}
+	}
+
 	return func(u *platform.User) bool { return true }
 }
 
 // FindUsers retrives all users that match an arbitrary user filter.
-// Filters using ID, or Name should be efficient.
+// Filters using ID, Name or OAuthID should be efficient.
 // Other filters will do a linear scan across all users searching for a match.
 func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt ...platform.FindOptions) ([]*platform.User, int, error) {
 	op := getOp(platform.OpFindUsers)
@@ -221,6 +276,18 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 		return []*platform.User{u}, 1, nil
 	}
 
+	if filter.OAuthID != nil {
+		u, err := c.findUserByOAuthID(ctx, *filter.OAuthID)
+		if err != nil {
+			return nil, 0, &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+
+		return []*platform.User{u}, 1, nil
+	}
+
 	us := []*platform.User{}
 	filterFn := filterUsersFn(filter)
 	err := c.db.View(func(tx *bolt.Tx) error {
@@ -242,6 +309,10 @@ func (c *Client) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 // CreateUser creates a platform user and sets b.ID.
 func (c *Client) CreateUser(ctx context.Context, u *platform.User) error {
 	err := c.db.Update(func(tx *bolt.Tx) error {
+		if err := platform.ValidateNewUser(u); err != nil {
+			return err
+		}
+
 		unique := c.uniqueUserName(ctx, tx, u)
 
 		if !unique {
@@ -251,6 +322,13 @@ func (c *Client) CreateUser(ctx context.Context, u *platform.User) error {
 			}
 		}
 
+		if u.OAuthID != "" && tx.Bucket(userOAuthIndex).Get([]byte(u.OAuthID)) != nil {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  "identity is already linked to a user",
+			}
+		}
+
 		u.ID = c.IDGenerator.ID()
 
 		if err := c.appendUserEventToLog(ctx, tx, u.ID, userCreatedEvent); err != nil {
@@ -287,6 +365,11 @@ func (c *Client) putUser(ctx context.Context, tx *bolt.Tx, u *platform.User) err
 	if err := tx.Bucket(userIndex).Put(userIndexKey(u.Name), encodedID); err != nil {
 		return err
 	}
+	if u.OAuthID != "" {
+		if err := tx.Bucket(userOAuthIndex).Put([]byte(u.OAuthID), encodedID); err != nil {
+			return err
+		}
+	}
 	return tx.Bucket(userUser).Put(encodedID, v)
 }
 
@@ -334,11 +417,28 @@ func (c *Client) UpdateUser(ctx context.Context, id platform.ID, upd platform.Us
 }
 
 func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, upd platform.UserUpdate) (*platform.User, *platform.Error) {
+	if err := upd.Valid(); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+
 	u, err := c.findUserByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if upd.DefaultOrgID != nil && upd.DefaultOrgID.Valid() {
+		find := func(f platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, error) {
+			return c.findUserResourceMappings(ctx, tx, f)
+		}
+		if err := platform.ValidateDefaultOrg(find, id, *upd.DefaultOrgID); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+	}
+
 	if upd.Name != nil {
 		// Users are indexed by name and so the user index must be pruned
 		// when name is modified.
@@ -347,7 +447,32 @@ func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, up
 				Err: err,
 			}
 		}
-		u.Name = *upd.Name
+	}
+
+	if upd.OAuthID != nil && *upd.OAuthID != u.OAuthID {
+		if *upd.OAuthID != "" && tx.Bucket(userOAuthIndex).Get([]byte(*upd.OAuthID)) != nil {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Msg:  "identity is already linked to a user",
+			}
+		}
+		if err := tx.Bucket(userOAuthIndex).Delete([]byte(u.OAuthID)); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+	}
+
+	deactivated := u.IsActive() && upd.Status != nil && *upd.Status == platform.Inactive
+	upd.Apply(u)
+
+	if deactivated {
+		if err := c.disableUsersAuthorizations(ctx, tx, id); err != nil {
+			return nil, err
+		}
+		if err := c.expireUsersSessions(ctx, tx, id, false); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := c.appendUserEventToLog(ctx, tx, u.ID, userUpdatedEvent); err != nil {
@@ -365,12 +490,33 @@ func (c *Client) updateUser(ctx context.Context, tx *bolt.Tx, id platform.ID, up
 	return u, nil
 }
 
+// disableUsersAuthorizations sets all the authorizations of a user inactive.
+func (c *Client) disableUsersAuthorizations(ctx context.Context, tx *bolt.Tx, id platform.ID) *platform.Error {
+	as, err := c.findAuthorizations(ctx, tx, platform.AuthorizationFilter{
+		UserID: &id,
+	})
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	for _, a := range as {
+		if err := c.updateAuthorization(ctx, tx, a.ID, platform.Inactive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteUser deletes a user and prunes it from the index.
 func (c *Client) DeleteUser(ctx context.Context, id platform.ID) error {
 	err := c.db.Update(func(tx *bolt.Tx) error {
 		if pe := c.deleteUsersAuthorizations(ctx, tx, id); pe != nil {
 			return pe
 		}
+		if pe := c.expireUsersSessions(ctx, tx, id, true); pe != nil {
+			return pe
+		}
 		if pe := c.deleteUser(ctx, tx, id); pe != nil {
 			return pe
 		}
@@ -401,11 +547,25 @@ func (c *Client) deleteUser(ctx context.Context, tx *bolt.Tx, id platform.ID) *p
 			Err: err,
 		}
 	}
+	if u.OAuthID != "" {
+		if err := tx.Bucket(userOAuthIndex).Delete([]byte(u.OAuthID)); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+	}
 	if err := tx.Bucket(userUser).Delete(encodedID); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
+	for _, b := range [][]byte{userpasswordBucket, userpasswordAttemptsBucket} {
+		if err := tx.Bucket(b).Delete(encodedID); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+	}
 	if err := c.deleteUserResourceMappings(ctx, tx, platform.UserResourceMappingFilter{
 		UserID: id,
 	}); err != nil {
@@ -21,12 +21,7 @@ func (c *Client) initializeUserResourceMappings(ctx context.Context, tx *bolt.Tx
 }
 
 func filterMappingsFn(filter platform.UserResourceMappingFilter) func(m *platform.UserResourceMapping) bool {
-	return func(mapping *platform.UserResourceMapping) bool {
-		return (!filter.UserID.Valid() || (filter.UserID == mapping.UserID)) &&
-			(!filter.ResourceID.Valid() || (filter.ResourceID == mapping.ResourceID)) &&
-			(filter.UserType == "" || (filter.UserType == mapping.UserType)) &&
-			(filter.ResourceType == "" || (filter.ResourceType == mapping.ResourceType))
-	}
+	return filter.Matches
 }
 
 // FindUserResourceMappings returns a list of UserResourceMappings that match filter and the total count of matching mappings.
@@ -129,6 +124,8 @@ func (c *Client) createOrgDependentMappings(ctx context.Context, tx *bolt.Tx, m
 			ResourceID:   b.ID,
 			UserType:     m.UserType,
 			UserID:       m.UserID,
+
+			PrincipalType: m.PrincipalType,
 		}
 		if err := c.createUserResourceMapping(ctx, tx, m); err != nil {
 			return err
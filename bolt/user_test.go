@@ -35,3 +35,71 @@ func initUserService(f platformtesting.UserFields, t *testing.T) (platform.UserS
 func TestUserService(t *testing.T) {
 	platformtesting.UserService(initUserService, t)
 }
+
+func initUserLifecycleService(f platformtesting.UserLifecycleFields, t *testing.T) (platformtesting.UserLifecycleService, string, func()) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	c.TokenGenerator = f.TokenGenerator
+
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := c.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users")
+		}
+	}
+	for _, a := range f.Authorizations {
+		if err := c.PutAuthorization(ctx, a); err != nil {
+			t.Fatalf("failed to populate authorizations")
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := c.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings")
+		}
+	}
+	return c, bolt.OpPrefix, closeFn
+}
+
+func TestUserService_Lifecycle(t *testing.T) {
+	platformtesting.UserLifecycle(initUserLifecycleService, t)
+}
+
+func TestUserService_DeactivateExpiresSessions(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	u := &platform.User{Name: "user1"}
+	if err := c.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.CreateSession(ctx, u.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := platform.Inactive
+	if _, err := c.UpdateUser(ctx, u.ID, platform.UserUpdate{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.FindSession(ctx, s.Key); err == nil {
+		t.Errorf("expected the session of the deactivated user to be expired")
+	}
+	if _, err := c.CreateSession(ctx, u.Name); platform.ErrorCode(err) != platform.EForbidden {
+		t.Errorf("expected deactivated user to be forbidden to sign in, got %v", err)
+	}
+
+	if err := c.DeleteUser(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FindSession(ctx, s.Key); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the session of the deleted user to be removed, got %v", err)
+	}
+}
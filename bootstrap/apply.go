@@ -0,0 +1,646 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/task/options"
+)
+
+// Action is what applying a configuration does to a resource.
+type Action string
+
+const (
+	// Create is the action of creating a missing resource.
+	Create Action = "create"
+	// Update is the action of changing a resource to match the configuration.
+	Update Action = "update"
+)
+
+// Change is a change made, or to be made, to a resource.
+type Change struct {
+	Action Action `json:"action"`
+	// Kind is the kind of the resource, e.g. bucket.
+	Kind string `json:"kind"`
+	// Name identifies the resource, e.g. org/bucket for a bucket.
+	Name string `json:"name"`
+	// Detail describes an update.
+	Detail string `json:"detail,omitempty"`
+	// Token is the token of a created authorization.
+	Token string `json:"token,omitempty"`
+}
+
+func (c Change) String() string {
+	sign := "+"
+	if c.Action == Update {
+		sign = "~"
+	}
+	s := fmt.Sprintf("%s %s %s", sign, c.Kind, c.Name)
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// Applier creates and updates the resources of a configuration. Resources
+// that are not in the configuration are left alone, so that a configuration
+// can be applied to an environment which is also changed by its users.
+//
+// The BasicAuthService, TaskService and TelegrafService are optional, and
+// only required by the configurations with passwords, tasks or telegraf
+// configs. The TaskService must not check the authorizer of the context: the
+// configuration is applied at startup without one, and creates tasks in
+// organizations that no one has a permission on yet.
+type Applier struct {
+	UserService                platform.UserService
+	BasicAuthService           platform.BasicAuthService
+	OrganizationService        platform.OrganizationService
+	BucketService              platform.BucketService
+	UserResourceMappingService platform.UserResourceMappingService
+	AuthorizationService       platform.AuthorizationService
+	DashboardService           platform.DashboardService
+	TaskService                platform.TaskService
+	TelegrafService            platform.TelegrafConfigStore
+
+	// Now returns the time of the changes; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Apply changes the resources so that they match the configuration, and
+// returns the changes made. The changes made before an error are returned
+// with it.
+func (a *Applier) Apply(ctx context.Context, c *Config) ([]Change, error) {
+	r := &run{Applier: a, dryRun: false}
+	err := r.apply(ctx, c)
+	return r.changes, err
+}
+
+// Diff returns the changes that applying the configuration would make,
+// without making them.
+func (a *Applier) Diff(ctx context.Context, c *Config) ([]Change, error) {
+	r := &run{Applier: a, dryRun: true}
+	err := r.apply(ctx, c)
+	return r.changes, err
+}
+
+// run is a single application of a configuration. In a dry run, the
+// resources to be created are given invalid IDs, and the resources
+// depending on them are compared to nothing.
+type run struct {
+	*Applier
+	dryRun  bool
+	changes []Change
+
+	users   map[string]*platform.User
+	orgs    map[string]*platform.Organization
+	buckets map[string]*platform.Bucket
+}
+
+func (r *run) record(c Change) {
+	r.changes = append(r.changes, c)
+}
+
+func (r *run) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+func (r *run) apply(ctx context.Context, c *Config) error {
+	if err := r.supports(c); err != nil {
+		return err
+	}
+
+	r.users = map[string]*platform.User{}
+	r.orgs = map[string]*platform.Organization{}
+	r.buckets = map[string]*platform.Bucket{}
+
+	for _, u := range c.Users {
+		if err := r.applyUser(ctx, u); err != nil {
+			return fmt.Errorf("user %q: %v", u.Name, err)
+		}
+	}
+	for _, o := range c.Orgs {
+		if err := r.applyOrg(ctx, o); err != nil {
+			return fmt.Errorf("org %q: %v", o.Name, err)
+		}
+	}
+	for _, d := range c.Dashboards {
+		if err := r.applyDashboard(ctx, d); err != nil {
+			return fmt.Errorf("dashboard %q: %v", d.Name, err)
+		}
+	}
+	for _, t := range c.Telegrafs {
+		if err := r.applyTelegraf(ctx, t); err != nil {
+			return fmt.Errorf("telegraf config %q: %v", t.Name, err)
+		}
+	}
+	for _, t := range c.Tokens {
+		if err := r.applyToken(ctx, t); err != nil {
+			return fmt.Errorf("token %q: %v", t.Description, err)
+		}
+	}
+	return nil
+}
+
+// supports returns an error if the configuration has resources the
+// applier has no service for.
+func (r *run) supports(c *Config) error {
+	for _, u := range c.Users {
+		if u.Password != "" && r.BasicAuthService == nil {
+			return fmt.Errorf("user %q: passwords can't be set by this applier", u.Name)
+		}
+	}
+	for _, o := range c.Orgs {
+		if len(o.Tasks) > 0 && r.TaskService == nil {
+			return fmt.Errorf("org %q: tasks can't be applied by this applier", o.Name)
+		}
+	}
+	if len(c.Telegrafs) > 0 && r.TelegrafService == nil {
+		return fmt.Errorf("telegraf configs can't be applied by this applier")
+	}
+	return nil
+}
+
+func (r *run) applyUser(ctx context.Context, uc UserConfig) error {
+	u, err := r.UserService.FindUser(ctx, platform.UserFilter{Name: &uc.Name})
+	if platform.ErrorCode(err) == platform.ENotFound {
+		u = &platform.User{
+			Name:        uc.Name,
+			Email:       uc.Email,
+			DisplayName: uc.DisplayName,
+		}
+		r.record(Change{Action: Create, Kind: "user", Name: uc.Name})
+		r.users[uc.Name] = u
+		if r.dryRun {
+			return nil
+		}
+		if err := r.UserService.CreateUser(ctx, u); err != nil {
+			return err
+		}
+		if uc.Password != "" {
+			return r.BasicAuthService.SetPassword(ctx, u.Name, uc.Password)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r.users[uc.Name] = u
+
+	var upd platform.UserUpdate
+	var detail []string
+	if u.Email != uc.Email {
+		upd.Email = &uc.Email
+		detail = append(detail, fmt.Sprintf("email %q -> %q", u.Email, uc.Email))
+	}
+	if u.DisplayName != uc.DisplayName {
+		upd.DisplayName = &uc.DisplayName
+		detail = append(detail, fmt.Sprintf("display name %q -> %q", u.DisplayName, uc.DisplayName))
+	}
+	if len(detail) == 0 {
+		return nil
+	}
+	r.record(Change{Action: Update, Kind: "user", Name: uc.Name, Detail: strings.Join(detail, ", ")})
+	if r.dryRun {
+		return nil
+	}
+	u, err = r.UserService.UpdateUser(ctx, u.ID, upd)
+	if err != nil {
+		return err
+	}
+	r.users[uc.Name] = u
+	return nil
+}
+
+// user returns the user named name, from the configuration or the
+// existing users.
+func (r *run) user(ctx context.Context, name string) (*platform.User, error) {
+	if u, ok := r.users[name]; ok {
+		return u, nil
+	}
+	u, err := r.UserService.FindUser(ctx, platform.UserFilter{Name: &name})
+	if err != nil {
+		return nil, fmt.Errorf("user %q: %v", name, err)
+	}
+	r.users[name] = u
+	return u, nil
+}
+
+func (r *run) applyOrg(ctx context.Context, oc OrgConfig) error {
+	o, err := r.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &oc.Name})
+	if platform.ErrorCode(err) == platform.ENotFound {
+		o = &platform.Organization{Name: oc.Name}
+		r.record(Change{Action: Create, Kind: "org", Name: oc.Name})
+		if !r.dryRun {
+			if err := r.OrganizationService.CreateOrganization(ctx, o); err != nil {
+				return err
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+	r.orgs[oc.Name] = o
+
+	for _, name := range oc.Owners {
+		if err := r.applyMapping(ctx, platform.OrgResourceType, o.ID, oc.Name, name, platform.Owner); err != nil {
+			return err
+		}
+	}
+	for _, name := range oc.Members {
+		if err := r.applyMapping(ctx, platform.OrgResourceType, o.ID, oc.Name, name, platform.Member); err != nil {
+			return err
+		}
+	}
+	for _, bc := range oc.Buckets {
+		if err := r.applyBucket(ctx, o, bc); err != nil {
+			return fmt.Errorf("bucket %q: %v", bc.Name, err)
+		}
+	}
+	for _, tc := range oc.Tasks {
+		if err := r.applyTask(ctx, o, tc); err != nil {
+			return fmt.Errorf("task: %v", err)
+		}
+	}
+	return nil
+}
+
+// applyMapping makes the user an owner or member of a resource, replacing
+// its mapping of the other type.
+func (r *run) applyMapping(ctx context.Context, typ platform.ResourceType, id platform.ID, resource, userName string, userType platform.UserType) error {
+	u, err := r.user(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	var existing []*platform.UserResourceMapping
+	if id.Valid() && u.ID.Valid() {
+		existing, _, err = r.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+			ResourceType: typ,
+			ResourceID:   id,
+			UserID:       u.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%s/%s", resource, userName)
+	action := Create
+	for _, m := range existing {
+		if m.UserType == userType {
+			return nil
+		}
+		action = Update
+	}
+	r.record(Change{Action: action, Kind: string(typ) + " " + string(userType), Name: name})
+	if r.dryRun {
+		return nil
+	}
+
+	if len(existing) > 0 {
+		if err := r.UserResourceMappingService.DeleteUserResourceMapping(ctx, id, u.ID); err != nil {
+			return err
+		}
+	}
+	return r.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		ResourceType: typ,
+		ResourceID:   id,
+		UserID:       u.ID,
+		UserType:     userType,
+	})
+}
+
+func (r *run) applyBucket(ctx context.Context, o *platform.Organization, bc BucketConfig) error {
+	name := o.Name + "/" + bc.Name
+	retention := time.Duration(bc.RetentionPeriod)
+
+	var b *platform.Bucket
+	if o.ID.Valid() {
+		var err error
+		b, err = r.BucketService.FindBucket(ctx, platform.BucketFilter{OrganizationID: &o.ID, Name: &bc.Name})
+		if err != nil {
+			if platform.ErrorCode(err) != platform.ENotFound {
+				return err
+			}
+			b = nil
+		}
+	}
+	if b == nil {
+		b = &platform.Bucket{
+			OrganizationID:  o.ID,
+			Name:            bc.Name,
+			RetentionPeriod: retention,
+		}
+		r.record(Change{Action: Create, Kind: "bucket", Name: name})
+		r.buckets[name] = b
+		if r.dryRun {
+			return nil
+		}
+		return r.BucketService.CreateBucket(ctx, b)
+	}
+	r.buckets[name] = b
+
+	if b.RetentionPeriod == retention {
+		return nil
+	}
+	r.record(Change{
+		Action: Update,
+		Kind:   "bucket",
+		Name:   name,
+		Detail: fmt.Sprintf("retention period %v -> %v", b.RetentionPeriod, retention),
+	})
+	if r.dryRun {
+		return nil
+	}
+	b, err := r.BucketService.UpdateBucket(ctx, b.ID, platform.BucketUpdate{RetentionPeriod: &retention})
+	if err != nil {
+		return err
+	}
+	r.buckets[name] = b
+	return nil
+}
+
+func (r *run) applyTask(ctx context.Context, o *platform.Organization, tc TaskConfig) error {
+	opts, err := options.FromScript(tc.Flux)
+	if err != nil {
+		return err
+	}
+	name := o.Name + "/" + opts.Name
+
+	owner, err := r.user(ctx, tc.Owner)
+	if err != nil {
+		return err
+	}
+
+	var existing *platform.Task
+	if o.ID.Valid() {
+		ts, _, err := r.TaskService.FindTasks(ctx, platform.TaskFilter{Organization: &o.ID})
+		if err != nil {
+			return err
+		}
+		for _, t := range ts {
+			if t.Name == opts.Name {
+				existing = t
+				break
+			}
+		}
+	}
+
+	if existing == nil {
+		r.record(Change{Action: Create, Kind: "task", Name: name})
+		if r.dryRun {
+			return nil
+		}
+		return r.TaskService.CreateTask(ctx, &platform.Task{
+			Organization: o.ID,
+			Owner:        *owner,
+			Status:       tc.Status,
+			Flux:         tc.Flux,
+		})
+	}
+
+	var upd platform.TaskUpdate
+	var detail []string
+	if existing.Flux != tc.Flux {
+		upd.Flux = &tc.Flux
+		detail = append(detail, "flux changed")
+	}
+	if tc.Status != "" && existing.Status != tc.Status {
+		upd.Status = &tc.Status
+		detail = append(detail, fmt.Sprintf("status %q -> %q", existing.Status, tc.Status))
+	}
+	if len(detail) == 0 {
+		return nil
+	}
+	r.record(Change{Action: Update, Kind: "task", Name: name, Detail: strings.Join(detail, ", ")})
+	if r.dryRun {
+		return nil
+	}
+	_, err = r.TaskService.UpdateTask(ctx, existing.ID, upd)
+	return err
+}
+
+func (r *run) applyDashboard(ctx context.Context, dc DashboardConfig) error {
+	ds, _, err := r.DashboardService.FindDashboards(ctx, platform.DashboardFilter{}, platform.FindOptions{})
+	if err != nil {
+		return err
+	}
+	var d *platform.Dashboard
+	for _, existing := range ds {
+		if existing.Name == dc.Name {
+			d = existing
+			break
+		}
+	}
+
+	switch {
+	case d == nil:
+		d = &platform.Dashboard{Name: dc.Name, Description: dc.Description}
+		r.record(Change{Action: Create, Kind: "dashboard", Name: dc.Name})
+		if !r.dryRun {
+			if err := r.DashboardService.CreateDashboard(ctx, d); err != nil {
+				return err
+			}
+		}
+	case d.Description != dc.Description:
+		r.record(Change{
+			Action: Update,
+			Kind:   "dashboard",
+			Name:   dc.Name,
+			Detail: fmt.Sprintf("description %q -> %q", d.Description, dc.Description),
+		})
+		if !r.dryRun {
+			if _, err := r.DashboardService.UpdateDashboard(ctx, d.ID, platform.DashboardUpdate{Description: &dc.Description}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range dc.Owners {
+		if err := r.applyMapping(ctx, platform.DashboardResourceType, d.ID, dc.Name, name, platform.Owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *run) applyTelegraf(ctx context.Context, tc TelegrafConfig) error {
+	want := new(platform.TelegrafConfig)
+	if len(tc.Config) > 0 {
+		if err := json.Unmarshal(tc.Config, want); err != nil {
+			return err
+		}
+	}
+	want.Name = tc.Name
+
+	owner, err := r.user(ctx, tc.Owner)
+	if err != nil {
+		return err
+	}
+
+	var existing *platform.TelegrafConfig
+	if owner.ID.Valid() {
+		tcs, _, err := r.TelegrafService.FindTelegrafConfigs(ctx, platform.UserResourceMappingFilter{
+			UserID:       owner.ID,
+			ResourceType: platform.TelegrafResourceType,
+		})
+		if err != nil {
+			return err
+		}
+		for _, t := range tcs {
+			if t.Name == tc.Name {
+				existing = t
+				break
+			}
+		}
+	}
+
+	if existing == nil {
+		r.record(Change{Action: Create, Kind: "telegraf config", Name: tc.Name})
+		if r.dryRun {
+			return nil
+		}
+		return r.TelegrafService.CreateTelegrafConfig(ctx, want, owner.ID, r.now())
+	}
+
+	if reflect.DeepEqual(existing.Agent, want.Agent) && reflect.DeepEqual(existing.Plugins, want.Plugins) {
+		return nil
+	}
+	r.record(Change{Action: Update, Kind: "telegraf config", Name: tc.Name, Detail: "config changed"})
+	if r.dryRun {
+		return nil
+	}
+	want.AuthorizationID = existing.AuthorizationID
+	_, err = r.TelegrafService.UpdateTelegrafConfig(ctx, existing.ID, want, owner.ID, r.now())
+	return err
+}
+
+// applyToken creates the authorization of a token. The permissions of an
+// authorization can't be changed, so an authorization whose permissions
+// differ from the configuration is replaced, with a new token.
+func (r *run) applyToken(ctx context.Context, tc TokenConfig) error {
+	u, err := r.user(ctx, tc.User)
+	if err != nil {
+		return err
+	}
+
+	// the permissions are unknown when they refer to resources to be created.
+	known := u.ID.Valid()
+	ps := make([]platform.Permission, 0, len(tc.Permissions))
+	for _, pc := range tc.Permissions {
+		p, ok, err := r.permission(ctx, pc)
+		if err != nil {
+			return err
+		}
+		known = known && ok
+		ps = append(ps, p)
+	}
+
+	var existing *platform.Authorization
+	if u.ID.Valid() {
+		as, _, err := r.AuthorizationService.FindAuthorizations(ctx, platform.AuthorizationFilter{UserID: &u.ID})
+		if err != nil {
+			return err
+		}
+		for _, a := range as {
+			if a.Description == tc.Description {
+				existing = a
+				break
+			}
+		}
+	}
+
+	name := tc.User + "/" + tc.Description
+	if existing != nil {
+		if known && samePermissions(existing.Permissions, ps) {
+			return nil
+		}
+		r.record(Change{Action: Update, Kind: "token", Name: name, Detail: "permissions changed, the token is replaced"})
+		if r.dryRun {
+			return nil
+		}
+		if err := r.AuthorizationService.DeleteAuthorization(ctx, existing.ID); err != nil {
+			return err
+		}
+	} else if r.dryRun {
+		r.record(Change{Action: Create, Kind: "token", Name: name})
+		return nil
+	}
+
+	a := &platform.Authorization{
+		Description: tc.Description,
+		UserID:      u.ID,
+		Permissions: ps,
+	}
+	if err := r.AuthorizationService.CreateAuthorization(ctx, a); err != nil {
+		return err
+	}
+	if existing == nil {
+		r.record(Change{Action: Create, Kind: "token", Name: name, Token: a.Token})
+	} else {
+		r.changes[len(r.changes)-1].Token = a.Token
+	}
+	return nil
+}
+
+// permission returns the permission of pc, and whether the resources it
+// refers to exist.
+func (r *run) permission(ctx context.Context, pc PermissionConfig) (platform.Permission, bool, error) {
+	p := platform.Permission{Action: pc.Action, Resource: pc.Resource}
+	if pc.Org == "" {
+		return p, true, nil
+	}
+
+	o, ok := r.orgs[pc.Org]
+	if !ok {
+		var err error
+		o, err = r.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &pc.Org})
+		if err != nil {
+			return p, false, fmt.Errorf("org %q: %v", pc.Org, err)
+		}
+		r.orgs[pc.Org] = o
+	}
+	if pc.Bucket == "" {
+		p.Resource = platform.TaskResource(o.ID)
+		return p, o.ID.Valid(), nil
+	}
+
+	name := pc.Org + "/" + pc.Bucket
+	b, ok := r.buckets[name]
+	if !ok {
+		if !o.ID.Valid() {
+			return p, false, fmt.Errorf("bucket %q not found", name)
+		}
+		var err error
+		b, err = r.BucketService.FindBucket(ctx, platform.BucketFilter{OrganizationID: &o.ID, Name: &pc.Bucket})
+		if err != nil {
+			return p, false, fmt.Errorf("bucket %q: %v", name, err)
+		}
+		r.buckets[name] = b
+	}
+	p.Resource = platform.BucketResource(b.ID)
+	return p, b.ID.Valid(), nil
+}
+
+func samePermissions(a, b []platform.Permission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[platform.Permission]bool, len(a))
+	for _, p := range a {
+		set[p] = true
+	}
+	for _, p := range b {
+		if !set[p] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,236 @@
+package bootstrap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	_ "github.com/influxdata/platform/query/builtin"
+	"github.com/influxdata/platform/task/options"
+	itoml "github.com/influxdata/platform/toml"
+)
+
+// newTaskService returns a task service keeping the tasks in memory.
+func newTaskService(ids platform.IDGenerator) *mock.TaskService {
+	var tasks []*platform.Task
+	return &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			var ts []*platform.Task
+			for _, t := range tasks {
+				if filter.Organization == nil || t.Organization == *filter.Organization {
+					ts = append(ts, t)
+				}
+			}
+			return ts, len(ts), nil
+		},
+		CreateTaskFn: func(ctx context.Context, t *platform.Task) error {
+			opts, err := options.FromScript(t.Flux)
+			if err != nil {
+				return err
+			}
+			t.ID = ids.ID()
+			t.Name = opts.Name
+			if t.Status == "" {
+				t.Status = string(platform.Active)
+			}
+			tasks = append(tasks, t)
+			return nil
+		},
+		UpdateTaskFn: func(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+			for _, t := range tasks {
+				if t.ID == id {
+					if upd.Flux != nil {
+						t.Flux = *upd.Flux
+					}
+					if upd.Status != nil {
+						t.Status = *upd.Status
+					}
+					return t, nil
+				}
+			}
+			return nil, &platform.Error{Code: platform.ENotFound}
+		},
+	}
+}
+
+func newApplier() (*bootstrap.Applier, *inmem.Service) {
+	s := inmem.NewService()
+	return &bootstrap.Applier{
+		UserService:                s,
+		BasicAuthService:           s,
+		OrganizationService:        s,
+		BucketService:              s,
+		UserResourceMappingService: s,
+		AuthorizationService:       s,
+		DashboardService:           s,
+		TaskService:                newTaskService(s.IDGenerator),
+		TelegrafService:            s,
+		Now:                        func() time.Time { return time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC) },
+	}, s
+}
+
+func testConfig() *bootstrap.Config {
+	return &bootstrap.Config{
+		Users: []bootstrap.UserConfig{
+			{Name: "admin", Password: "supersecret", Email: "admin@example.com"},
+			{Name: "ops"},
+		},
+		Orgs: []bootstrap.OrgConfig{
+			{
+				Name:    "acme",
+				Owners:  []string{"admin"},
+				Members: []string{"ops"},
+				Buckets: []bootstrap.BucketConfig{
+					{Name: "telegraf", RetentionPeriod: itoml.Duration(72 * time.Hour)},
+				},
+				Tasks: []bootstrap.TaskConfig{
+					{
+						Owner: "admin",
+						Flux: `option task = {name: "downsample", every: 1h}
+from(bucket: "telegraf") |> range(start: -1h)`,
+					},
+				},
+			},
+		},
+		Dashboards: []bootstrap.DashboardConfig{
+			{Name: "hosts", Description: "host metrics", Owners: []string{"ops"}},
+		},
+		Telegrafs: []bootstrap.TelegrafConfig{
+			{
+				Name:   "hosts",
+				Owner:  "ops",
+				Config: []byte(`{"agent": {"collectionInterval": 10000}, "plugins": [{"name": "cpu", "type": "input", "config": {}}]}`),
+			},
+		},
+		Tokens: []bootstrap.TokenConfig{
+			{
+				Description: "telegraf",
+				User:        "ops",
+				Permissions: []bootstrap.PermissionConfig{
+					{Permission: platform.Permission{Action: platform.WriteAction}, Org: "acme", Bucket: "telegraf"},
+				},
+			},
+		},
+	}
+}
+
+func changeList(cs []bootstrap.Change) []string {
+	ss := make([]string, 0, len(cs))
+	for _, c := range cs {
+		ss = append(ss, c.String())
+	}
+	return ss
+}
+
+func TestApplier(t *testing.T) {
+	ctx := context.Background()
+	a, s := newApplier()
+	c := testConfig()
+
+	created := []string{
+		"+ user admin",
+		"+ user ops",
+		"+ org acme",
+		"+ org owner acme/admin",
+		"+ org member acme/ops",
+		"+ bucket acme/telegraf",
+		"+ task acme/downsample",
+		"+ dashboard hosts",
+		"+ dashboard owner hosts/ops",
+		"+ telegraf config hosts",
+		"+ token ops/telegraf",
+	}
+
+	diff, err := a.Diff(ctx, c)
+	if err != nil {
+		t.Fatalf("unexpected error diffing: %v", err)
+	}
+	if d := cmp.Diff(created, changeList(diff)); d != "" {
+		t.Fatalf("unexpected diff -want/+got:\n%s", d)
+	}
+	if _, err := s.FindUser(ctx, platform.UserFilter{Name: &c.Users[0].Name}); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("diff created user admin: %v", err)
+	}
+
+	changes, err := a.Apply(ctx, c)
+	if err != nil {
+		t.Fatalf("unexpected error applying: %v", err)
+	}
+	if d := cmp.Diff(created, changeList(changes)); d != "" {
+		t.Fatalf("unexpected changes -want/+got:\n%s", d)
+	}
+	if token := changes[len(changes)-1].Token; token == "" {
+		t.Fatal("expected the token of the created authorization")
+	}
+	if err := s.ComparePassword(ctx, "admin", "supersecret"); err != nil {
+		t.Fatalf("password of admin was not set: %v", err)
+	}
+
+	changes, err = a.Apply(ctx, c)
+	if err != nil {
+		t.Fatalf("unexpected error reapplying: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("reapplying changed %v", changeList(changes))
+	}
+
+	c.Users[0].Email = "root@example.com"
+	c.Orgs[0].Owners, c.Orgs[0].Members = c.Orgs[0].Members, c.Orgs[0].Owners
+	c.Orgs[0].Buckets[0].RetentionPeriod = itoml.Duration(24 * time.Hour)
+	c.Orgs[0].Tasks[0].Status = string(platform.Inactive)
+	c.Tokens[0].Permissions = append(c.Tokens[0].Permissions, bootstrap.PermissionConfig{
+		Permission: platform.Permission{Action: platform.ReadAction}, Org: "acme", Bucket: "telegraf",
+	})
+	updated := []string{
+		`~ user admin: email "admin@example.com" -> "root@example.com"`,
+		"~ org owner acme/ops",
+		"~ org member acme/admin",
+		"~ bucket acme/telegraf: retention period 72h0m0s -> 24h0m0s",
+		`~ task acme/downsample: status "active" -> "inactive"`,
+		"~ token ops/telegraf: permissions changed, the token is replaced",
+	}
+
+	diff, err = a.Diff(ctx, c)
+	if err != nil {
+		t.Fatalf("unexpected error diffing: %v", err)
+	}
+	if d := cmp.Diff(updated, changeList(diff)); d != "" {
+		t.Fatalf("unexpected diff -want/+got:\n%s", d)
+	}
+
+	changes, err = a.Apply(ctx, c)
+	if err != nil {
+		t.Fatalf("unexpected error applying: %v", err)
+	}
+	if d := cmp.Diff(updated, changeList(changes)); d != "" {
+		t.Fatalf("unexpected changes -want/+got:\n%s", d)
+	}
+
+	ops := c.Users[1].Name
+	as, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{User: &ops})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 1 || len(as[0].Permissions) != 2 {
+		t.Fatalf("expected the token to be replaced, got %+v", as)
+	}
+
+	if changes, err = a.Apply(ctx, c); err != nil || len(changes) != 0 {
+		t.Fatalf("reapplying changed %v, %v", changeList(changes), err)
+	}
+}
+
+func TestApplier_UnknownUser(t *testing.T) {
+	a, _ := newApplier()
+	c := &bootstrap.Config{
+		Orgs: []bootstrap.OrgConfig{{Name: "acme", Owners: []string{"nobody"}}},
+	}
+	if _, err := a.Diff(context.Background(), c); err == nil {
+		t.Fatal("expected an error for an owner that doesn't exist")
+	}
+}
@@ -0,0 +1,206 @@
+// Package bootstrap creates the resources of a platform from a declarative
+// configuration, so that an environment can be set up and kept in shape
+// without going through the onboarding one resource at a time.
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/platform"
+	itoml "github.com/influxdata/platform/toml"
+)
+
+// Config describes the resources of a platform. Resources are identified
+// by their names, and refer to each other by name.
+type Config struct {
+	Users      []UserConfig      `json:"users"`
+	Orgs       []OrgConfig       `json:"orgs"`
+	Dashboards []DashboardConfig `json:"dashboards"`
+	Telegrafs  []TelegrafConfig  `json:"telegrafs"`
+	Tokens     []TokenConfig     `json:"tokens"`
+}
+
+// UserConfig describes a user.
+type UserConfig struct {
+	Name string `json:"name"`
+	// Password is only set when the user is created, so that users can
+	// change it afterwards.
+	Password    string `json:"password"`
+	Email       string `json:"email"`
+	DisplayName string `json:"displayName"`
+}
+
+// OrgConfig describes an organization and the resources it contains.
+type OrgConfig struct {
+	Name string `json:"name"`
+	// Owners and Members are the names of the users of the organization.
+	Owners  []string       `json:"owners"`
+	Members []string       `json:"members"`
+	Buckets []BucketConfig `json:"buckets"`
+	Tasks   []TaskConfig   `json:"tasks"`
+}
+
+// BucketConfig describes a bucket.
+type BucketConfig struct {
+	Name string `json:"name"`
+	// RetentionPeriod is a duration such as "72h"; data is kept forever if unset.
+	RetentionPeriod itoml.Duration `json:"retentionPeriod"`
+}
+
+// TaskConfig describes a task, which is named by the task option of its
+// script.
+type TaskConfig struct {
+	// Owner is the name of the user the task runs as.
+	Owner string `json:"owner"`
+	// Status is either active or inactive; it isn't changed if unset.
+	Status string `json:"status"`
+	Flux   string `json:"flux"`
+}
+
+// DashboardConfig describes a dashboard.
+type DashboardConfig struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Owners      []string `json:"owners"`
+}
+
+// TelegrafConfig describes a telegraf config.
+type TelegrafConfig struct {
+	Name string `json:"name"`
+	// Owner is the name of the user the config belongs to.
+	Owner string `json:"owner"`
+	// Config holds the agent and the plugins of the config, in the format of
+	// the telegrafs API.
+	Config json.RawMessage `json:"config"`
+}
+
+// TokenConfig describes an authorization, which is identified by its
+// description and user.
+type TokenConfig struct {
+	Description string             `json:"description"`
+	User        string             `json:"user"`
+	Permissions []PermissionConfig `json:"permissions"`
+}
+
+// PermissionConfig describes a permission of a token. Either Bucket,
+// naming a bucket of Org, Org alone for the tasks of the organization, or
+// the Resource of the permission are set.
+type PermissionConfig struct {
+	platform.Permission
+	Org    string `json:"org"`
+	Bucket string `json:"bucket"`
+}
+
+// LoadConfig reads the bootstrap configuration at path. Files with a .toml
+// extension are read as TOML, others as YAML or JSON.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(path) == ".toml" {
+		// the TOML is converted to JSON so that the json tags apply.
+		var m map[string]interface{}
+		if err := toml.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("invalid bootstrap config %s: %v", path, err)
+		}
+		if b, err = json.Marshal(m); err != nil {
+			return nil, err
+		}
+	}
+
+	c := new(Config)
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap config %s: %v", path, err)
+	}
+	if err := c.Valid(); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap config %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// Valid returns an error if a resource of the configuration is missing its
+// name or is declared twice.
+func (c *Config) Valid() error {
+	users := map[string]bool{}
+	for _, u := range c.Users {
+		if u.Name == "" {
+			return fmt.Errorf("user name is required")
+		}
+		if users[u.Name] {
+			return fmt.Errorf("user %q is declared twice", u.Name)
+		}
+		users[u.Name] = true
+	}
+
+	orgs := map[string]bool{}
+	for _, o := range c.Orgs {
+		if o.Name == "" {
+			return fmt.Errorf("org name is required")
+		}
+		if orgs[o.Name] {
+			return fmt.Errorf("org %q is declared twice", o.Name)
+		}
+		orgs[o.Name] = true
+
+		buckets := map[string]bool{}
+		for _, b := range o.Buckets {
+			if b.Name == "" {
+				return fmt.Errorf("bucket name is required in org %q", o.Name)
+			}
+			if buckets[b.Name] {
+				return fmt.Errorf("bucket %q is declared twice in org %q", b.Name, o.Name)
+			}
+			buckets[b.Name] = true
+		}
+		for _, t := range o.Tasks {
+			if t.Owner == "" {
+				return fmt.Errorf("task owner is required in org %q", o.Name)
+			}
+			switch t.Status {
+			case "", string(platform.Active), string(platform.Inactive):
+			default:
+				return fmt.Errorf("task status must be %q or %q in org %q", platform.Active, platform.Inactive, o.Name)
+			}
+		}
+	}
+
+	for _, d := range c.Dashboards {
+		if d.Name == "" {
+			return fmt.Errorf("dashboard name is required")
+		}
+	}
+
+	for _, t := range c.Telegrafs {
+		if t.Name == "" || t.Owner == "" {
+			return fmt.Errorf("telegraf config name and owner are required")
+		}
+	}
+
+	for _, t := range c.Tokens {
+		if t.Description == "" || t.User == "" {
+			return fmt.Errorf("token description and user are required")
+		}
+		for _, p := range t.Permissions {
+			switch p.Action {
+			case platform.ReadAction, platform.WriteAction, platform.CreateAction, platform.DeleteAction:
+			default:
+				return fmt.Errorf("invalid action %q in token %q", p.Action, t.Description)
+			}
+			if p.Bucket != "" && p.Org == "" {
+				return fmt.Errorf("bucket %q has no org in token %q", p.Bucket, t.Description)
+			}
+			if p.Org == "" && p.Resource == "" {
+				return fmt.Errorf("permission without resource in token %q", t.Description)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package bootstrap_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
+	itoml "github.com/influxdata/platform/toml"
+)
+
+func TestLoadConfig(t *testing.T) {
+	want := &bootstrap.Config{
+		Users: []bootstrap.UserConfig{{Name: "admin", Password: "supersecret"}},
+		Orgs: []bootstrap.OrgConfig{
+			{
+				Name:    "acme",
+				Owners:  []string{"admin"},
+				Buckets: []bootstrap.BucketConfig{{Name: "telegraf", RetentionPeriod: itoml.Duration(72 * time.Hour)}},
+			},
+		},
+		Tokens: []bootstrap.TokenConfig{
+			{
+				Description: "telegraf",
+				User:        "admin",
+				Permissions: []bootstrap.PermissionConfig{
+					{Permission: platform.Permission{Action: platform.WriteAction}, Org: "acme", Bucket: "telegraf"},
+					{Permission: platform.CreateUserPermission},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		config string
+	}{
+		{
+			name: "yaml",
+			file: "bootstrap.yml",
+			config: `
+users:
+  - name: admin
+    password: supersecret
+orgs:
+  - name: acme
+    owners: [admin]
+    buckets:
+      - name: telegraf
+        retentionPeriod: 72h
+tokens:
+  - description: telegraf
+    user: admin
+    permissions:
+      - {action: write, org: acme, bucket: telegraf}
+      - {action: create, resource: user}
+`,
+		},
+		{
+			name: "json",
+			file: "bootstrap.json",
+			config: `{
+  "users": [{"name": "admin", "password": "supersecret"}],
+  "orgs": [{"name": "acme", "owners": ["admin"], "buckets": [{"name": "telegraf", "retentionPeriod": "72h"}]}],
+  "tokens": [{"description": "telegraf", "user": "admin", "permissions": [
+    {"action": "write", "org": "acme", "bucket": "telegraf"},
+    {"action": "create", "resource": "user"}
+  ]}]
+}`,
+		},
+		{
+			name: "toml",
+			file: "bootstrap.toml",
+			config: `
+[[users]]
+name = "admin"
+password = "supersecret"
+
+[[orgs]]
+name = "acme"
+owners = ["admin"]
+
+  [[orgs.buckets]]
+  name = "telegraf"
+  retentionPeriod = "72h"
+
+[[tokens]]
+description = "telegraf"
+user = "admin"
+
+  [[tokens.permissions]]
+  action = "write"
+  org = "acme"
+  bucket = "telegraf"
+
+  [[tokens.permissions]]
+  action = "create"
+  resource = "user"
+`,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "bootstrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := ioutil.WriteFile(path, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			c, err := bootstrap.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("unexpected error loading config: %v", err)
+			}
+			if diff := cmp.Diff(want, c); diff != "" {
+				t.Errorf("unexpected config -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config bootstrap.Config
+	}{
+		{
+			name:   "user without name",
+			config: bootstrap.Config{Users: []bootstrap.UserConfig{{Password: "supersecret"}}},
+		},
+		{
+			name:   "duplicate org",
+			config: bootstrap.Config{Orgs: []bootstrap.OrgConfig{{Name: "acme"}, {Name: "acme"}}},
+		},
+		{
+			name: "task with invalid status",
+			config: bootstrap.Config{Orgs: []bootstrap.OrgConfig{
+				{Name: "acme", Tasks: []bootstrap.TaskConfig{{Owner: "admin", Status: "paused"}}},
+			}},
+		},
+		{
+			name: "bucket permission without org",
+			config: bootstrap.Config{Tokens: []bootstrap.TokenConfig{
+				{Description: "telegraf", User: "admin", Permissions: []bootstrap.PermissionConfig{
+					{Permission: platform.Permission{Action: platform.WriteAction}, Bucket: "telegraf"},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Valid(); err == nil {
+				t.Error("expected the config to be invalid")
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ type Bucket struct {
 	Name                string        `json:"name"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	// DeletedAt is when the bucket was deleted. Its data is purged once the
+	// grace period of deletions has passed, unless it is restored before.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // ops for buckets error and buckets op logs.
@@ -65,6 +68,28 @@ type BucketService interface {
 type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	// DeletedAt marks the bucket as deleted at that time, or restores it if
+	// zero. Buckets are restored through the BucketLifecycleService, so it
+	// is not part of the API.
+	DeletedAt *time.Time `json:"-"`
+}
+
+// Apply applies the update to the bucket.
+func (u BucketUpdate) Apply(b *Bucket) {
+	if u.Name != nil {
+		b.Name = *u.Name
+	}
+	if u.RetentionPeriod != nil {
+		b.RetentionPeriod = *u.RetentionPeriod
+	}
+	if u.DeletedAt != nil {
+		if u.DeletedAt.IsZero() {
+			b.DeletedAt = nil
+		} else {
+			t := *u.DeletedAt
+			b.DeletedAt = &t
+		}
+	}
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
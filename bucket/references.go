@@ -0,0 +1,178 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
+)
+
+// fluxBucket matches the bucket property of the records of a script, e.g.
+// the bucket of from or to, whose value is name.
+func fluxBucket(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(\bbucket\s*:\s*)"` + regexp.QuoteMeta(name) + `"`)
+}
+
+// orgName returns the name of the organization of a bucket, as telegraf
+// configs and scraper targets refer to buckets by org and bucket names.
+func (s *Service) orgName(ctx context.Context, b *platform.Bucket) (string, error) {
+	if b.Organization != "" || s.OrganizationService == nil {
+		return b.Organization, nil
+	}
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, b.OrganizationID)
+	if err != nil {
+		return "", err
+	}
+	return o.Name, nil
+}
+
+// references returns all the resources pointing at a bucket.
+func (s *Service) references(ctx context.Context, b *platform.Bucket) ([]*platform.BucketReference, error) {
+	refs, err := s.nameReferences(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.DBRPMappingService != nil {
+		ms, _, err := s.DBRPMappingService.FindMany(ctx, platform.DBRPMappingFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			if m.BucketID != b.ID {
+				continue
+			}
+			refs = append(refs, &platform.BucketReference{
+				Kind: platform.DBRPBucketReference,
+				Name: fmt.Sprintf("%s/%s/%s", m.Cluster, m.Database, m.RetentionPolicy),
+			})
+		}
+	}
+	return refs, nil
+}
+
+// nameReferences returns the resources pointing at a bucket by name, which
+// break when it is renamed.
+func (s *Service) nameReferences(ctx context.Context, b *platform.Bucket) ([]*platform.BucketReference, error) {
+	org, err := s.orgName(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*platform.BucketReference
+	if s.TaskService != nil {
+		re := fluxBucket(b.Name)
+		ts, _, err := s.TaskService.FindTasks(ctx, platform.TaskFilter{Organization: &b.OrganizationID})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range ts {
+			if re.MatchString(t.Flux) {
+				refs = append(refs, &platform.BucketReference{
+					Kind: platform.TaskBucketReference,
+					ID:   t.ID,
+					Name: t.Name,
+				})
+			}
+		}
+	}
+
+	if s.TelegrafService != nil {
+		tcs, _, err := s.TelegrafService.FindTelegrafConfigs(ctx, platform.UserResourceMappingFilter{
+			ResourceType: platform.TelegrafResourceType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the configs are listed once per user they are mapped to.
+		seen := map[platform.ID]bool{}
+		for _, tc := range tcs {
+			if !seen[tc.ID] && telegrafOutput(tc, org, b.Name) != nil {
+				seen[tc.ID] = true
+				refs = append(refs, &platform.BucketReference{
+					Kind: platform.TelegrafBucketReference,
+					ID:   tc.ID,
+					Name: tc.Name,
+				})
+			}
+		}
+	}
+
+	if s.ScraperTargetStoreService != nil {
+		targets, err := s.ScraperTargetStoreService.ListTargets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range targets {
+			if t.OrgName == org && t.BucketName == b.Name {
+				refs = append(refs, &platform.BucketReference{
+					Kind: platform.ScraperBucketReference,
+					ID:   t.ID,
+					Name: t.Name,
+				})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// telegrafOutput returns the first influxdb_v2 output of a telegraf config
+// writing to a bucket, if any.
+func telegrafOutput(tc *platform.TelegrafConfig, org, bucket string) *outputs.InfluxDBV2 {
+	for _, p := range tc.Plugins {
+		if out, ok := p.Config.(*outputs.InfluxDBV2); ok && out.Organization == org && out.Bucket == bucket {
+			return out
+		}
+	}
+	return nil
+}
+
+// rewriteReferences points the references to a bucket at its new name.
+func (s *Service) rewriteReferences(ctx context.Context, b *platform.Bucket, name string, refs []*platform.BucketReference) error {
+	org, err := s.orgName(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		switch ref.Kind {
+		case platform.TaskBucketReference:
+			t, err := s.TaskService.FindTaskByID(ctx, ref.ID)
+			if err != nil {
+				return err
+			}
+			flux := fluxBucket(b.Name).ReplaceAllString(t.Flux, `${1}`+fmt.Sprintf("%q", name))
+			if _, err := s.TaskService.UpdateTask(ctx, t.ID, platform.TaskUpdate{Flux: &flux}); err != nil {
+				return err
+			}
+		case platform.TelegrafBucketReference:
+			tc, err := s.TelegrafService.FindTelegrafConfigByID(ctx, ref.ID)
+			if err != nil {
+				return err
+			}
+			for out := telegrafOutput(tc, org, b.Name); out != nil; out = telegrafOutput(tc, org, b.Name) {
+				out.Bucket = name
+			}
+			userID := tc.LastModBy
+			if a, err := platcontext.GetAuthorizer(ctx); err == nil {
+				userID = a.GetUserID()
+			}
+			if _, err := s.TelegrafService.UpdateTelegrafConfig(ctx, tc.ID, tc, userID, s.now()); err != nil {
+				return err
+			}
+		case platform.ScraperBucketReference:
+			t, err := s.ScraperTargetStoreService.GetTargetByID(ctx, ref.ID)
+			if err != nil {
+				return err
+			}
+			t.BucketName = name
+			if _, err := s.ScraperTargetStoreService.UpdateTarget(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+// Package bucket soft deletes buckets, keeping them restorable for a grace
+// period before their data is purged from storage, and guards renaming and
+// deleting buckets that other resources refer to.
+package bucket
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"go.uber.org/zap"
+)
+
+// purgeInterval is how often the buckets past their grace period are looked
+// up by Run.
+const purgeInterval = time.Minute
+
+// Engine deletes the data of buckets from the storage engine.
+type Engine interface {
+	DeleteBucketData(ctx context.Context, orgID, bucketID platform.ID) error
+}
+
+// Service is a platform.BucketService hiding the deleted buckets of the
+// bucket service it wraps until they are purged. Renaming or deleting a
+// bucket checks, or rewrites, the resources referring to it according to
+// the policy set on context.
+type Service struct {
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+
+	// The references from the services left nil are not looked up.
+	TaskService               platform.TaskService
+	TelegrafService           platform.TelegrafConfigStore
+	ScraperTargetStoreService platform.ScraperTargetStoreService
+	DBRPMappingService        platform.DBRPMappingService
+	// Engine deletes the data of the purged buckets; their data is kept if
+	// nil.
+	Engine Engine
+
+	// GracePeriod is how long the deleted buckets can be restored before
+	// being purged; they are purged right away if zero.
+	GracePeriod time.Duration
+
+	Logger *zap.Logger
+	// Now returns the time of deletions; defaults to time.Now.
+	Now func() time.Time
+}
+
+var (
+	_ platform.BucketService          = (*Service)(nil)
+	_ platform.BucketLifecycleService = (*Service)(nil)
+)
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Service) logger() *zap.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return zap.NewNop()
+}
+
+func errBucketNotFound(op string) error {
+	return &platform.Error{
+		Code: platform.ENotFound,
+		Op:   op,
+		Msg:  "bucket not found",
+	}
+}
+
+// FindBucketByID returns a single bucket by ID, unless it is deleted.
+func (s *Service) FindBucketByID(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.DeletedAt != nil {
+		return nil, errBucketNotFound(platform.OpFindBucketByID)
+	}
+	return b, nil
+}
+
+// FindBucket returns the first bucket that matches filter and isn't deleted.
+func (s *Service) FindBucket(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+	bs, _, err := s.BucketService.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bs {
+		if b.DeletedAt == nil {
+			return b, nil
+		}
+	}
+	return nil, errBucketNotFound(platform.OpFindBucket)
+}
+
+// FindBuckets returns the buckets that match filter and aren't deleted, and
+// their count. The deleted buckets are filtered out before the buckets are
+// paginated, so that pages are full.
+func (s *Service) FindBuckets(ctx context.Context, filter platform.BucketFilter, opt ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+	bs, _, err := s.BucketService.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	found := bs[:0]
+	for _, b := range bs {
+		if b.DeletedAt == nil {
+			found = append(found, b)
+		}
+	}
+	n := len(found)
+
+	if len(opt) > 0 {
+		if o := opt[0].Offset; o > 0 {
+			if o > len(found) {
+				o = len(found)
+			}
+			found = found[o:]
+		}
+		if l := opt[0].Limit; l > 0 && len(found) > l {
+			found = found[:l]
+		}
+	}
+	return found, n, nil
+}
+
+// CreateBucket creates a new bucket and sets b.ID with the new identifier.
+func (s *Service) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+	return s.BucketService.CreateBucket(ctx, b)
+}
+
+// UpdateBucket updates a single bucket with changeset. Renaming a bucket
+// fails if it is referenced by name, unless the references are rewritten or
+// ignored.
+func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+	b, err := s.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*platform.BucketReference
+	policy := platcontext.GetBucketReferencePolicy(ctx)
+	if upd.Name != nil && *upd.Name != b.Name && policy != platform.BucketReferencesIgnore {
+		if err := policy.Valid(); err != nil {
+			return nil, err
+		}
+		if refs, err = s.nameReferences(ctx, b); err != nil {
+			return nil, err
+		}
+		if len(refs) > 0 && policy == platform.BucketReferencesCheck {
+			return nil, platform.BucketReferencesError(platform.OpUpdateBucket, refs)
+		}
+	}
+
+	updated, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) > 0 {
+		if err := s.rewriteReferences(ctx, b, updated.Name, refs); err != nil {
+			return nil, &platform.Error{
+				Op:  platform.OpUpdateBucket,
+				Err: err,
+			}
+		}
+		s.logger().Info("Rewrote bucket references", zap.Stringer("bucket_id", id), zap.Int("references", len(refs)))
+	}
+	return updated, nil
+}
+
+// DeleteBucket marks a bucket as deleted, or purges it right away if there
+// is no grace period. It fails if the bucket is referenced, unless the
+// references are ignored.
+func (s *Service) DeleteBucket(ctx context.Context, id platform.ID) error {
+	b, err := s.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	policy := platcontext.GetBucketReferencePolicy(ctx)
+	if err := policy.Valid(); err != nil {
+		return err
+	}
+	if policy != platform.BucketReferencesIgnore {
+		refs, err := s.references(ctx, b)
+		if err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			return platform.BucketReferencesError(platform.OpDeleteBucket, refs)
+		}
+	}
+
+	if s.GracePeriod == 0 {
+		return s.purge(ctx, b)
+	}
+	now := s.now()
+	if _, err := s.BucketService.UpdateBucket(ctx, id, platform.BucketUpdate{DeletedAt: &now}); err != nil {
+		return err
+	}
+	s.logger().Info("Deleted bucket", zap.Stringer("bucket_id", id), zap.Duration("grace_period", s.GracePeriod))
+	return nil
+}
+
+// FindBucketReferences returns the resources pointing at a bucket, deleted
+// or not.
+func (s *Service) FindBucketReferences(ctx context.Context, id platform.ID) ([]*platform.BucketReference, error) {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.references(ctx, b)
+}
+
+// FindDeletedBuckets returns the deleted buckets matching filter that
+// weren't purged yet.
+func (s *Service) FindDeletedBuckets(ctx context.Context, filter platform.BucketFilter) ([]*platform.Bucket, error) {
+	// the names of deleted buckets are free, so the bucket service can't
+	// look them up by name.
+	name := filter.Name
+	filter.Name = nil
+	bs, _, err := s.BucketService.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []*platform.Bucket
+	for _, b := range bs {
+		if b.DeletedAt != nil && (name == nil || b.Name == *name) {
+			deleted = append(deleted, b)
+		}
+	}
+	return deleted, nil
+}
+
+// RestoreBucket restores a deleted bucket that wasn't purged. It fails with
+// a conflict if a bucket of the same name was created since the deletion.
+func (s *Service) RestoreBucket(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.DeletedAt == nil {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Op:   platform.OpRestoreBucket,
+			Msg:  "bucket is not deleted",
+		}
+	}
+
+	var restored time.Time
+	if b, err = s.BucketService.UpdateBucket(ctx, id, platform.BucketUpdate{DeletedAt: &restored}); err != nil {
+		return nil, err
+	}
+	s.logger().Info("Restored bucket", zap.Stringer("bucket_id", id))
+	return b, nil
+}
+
+// purge deletes the data of a bucket before the bucket itself, so that a
+// failed purge is retried.
+func (s *Service) purge(ctx context.Context, b *platform.Bucket) error {
+	if s.Engine != nil {
+		if err := s.Engine.DeleteBucketData(ctx, b.OrganizationID, b.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.BucketService.DeleteBucket(ctx, b.ID); err != nil {
+		return err
+	}
+	s.logger().Info("Purged bucket", zap.Stringer("bucket_id", b.ID))
+	return nil
+}
+
+// PurgeExpired purges the deleted buckets whose grace period has passed.
+// The buckets failing to be purged are logged and retried on the next call.
+func (s *Service) PurgeExpired(ctx context.Context) error {
+	bs, _, err := s.BucketService.FindBuckets(ctx, platform.BucketFilter{})
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, b := range bs {
+		if b.DeletedAt == nil || now.Before(b.DeletedAt.Add(s.GracePeriod)) {
+			continue
+		}
+		if err := s.purge(ctx, b); err != nil {
+			s.logger().Error("Failed to purge bucket", zap.Stringer("bucket_id", b.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Run purges the deleted buckets once their grace period has passed until
+// ctx is done.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.PurgeExpired(ctx); err != nil {
+			s.logger().Error("Failed to find deleted buckets", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package bucket_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/bucket"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
+)
+
+// engine records the buckets whose data is deleted.
+type engine struct {
+	deleted []platform.ID
+}
+
+func (e *engine) DeleteBucketData(ctx context.Context, orgID, bucketID platform.ID) error {
+	e.deleted = append(e.deleted, bucketID)
+	return nil
+}
+
+// newTaskService returns a task service keeping the tasks in memory.
+func newTaskService(tasks ...*platform.Task) *mock.TaskService {
+	byID := map[platform.ID]*platform.Task{}
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	return &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			var ts []*platform.Task
+			for _, t := range byID {
+				if filter.Organization == nil || t.Organization == *filter.Organization {
+					ts = append(ts, t)
+				}
+			}
+			return ts, len(ts), nil
+		},
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+			return byID[id], nil
+		},
+		UpdateTaskFn: func(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+			t := byID[id]
+			if upd.Flux != nil {
+				t.Flux = *upd.Flux
+			}
+			return t, nil
+		},
+	}
+}
+
+type fixture struct {
+	svc    *bucket.Service
+	store  *inmem.Service
+	engine *engine
+	now    time.Time
+
+	org      *platform.Organization
+	bucket   *platform.Bucket
+	task     *platform.Task
+	telegraf *platform.TelegrafConfig
+	target   *platform.ScraperTarget
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx := context.Background()
+	s := inmem.NewService()
+	f := &fixture{
+		store:  s,
+		engine: &engine{},
+		now:    time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+	}
+
+	f.org = &platform.Organization{Name: "acme"}
+	if err := s.CreateOrganization(ctx, f.org); err != nil {
+		t.Fatal(err)
+	}
+	f.bucket = &platform.Bucket{OrganizationID: f.org.ID, Name: "telegraf"}
+	if err := s.CreateBucket(ctx, f.bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	f.task = &platform.Task{
+		ID:           s.IDGenerator.ID(),
+		Organization: f.org.ID,
+		Name:         "downsample",
+		Flux:         `from(bucket: "telegraf") |> range(start: -1h) |> to(bucket: "downsampled")`,
+	}
+	f.telegraf = &platform.TelegrafConfig{
+		Name: "hosts",
+		Plugins: []platform.TelegrafPlugin{
+			{Config: &outputs.InfluxDBV2{URLs: []string{"http://127.0.0.1:9999"}, Organization: "acme", Bucket: "telegraf"}},
+		},
+	}
+	if err := s.CreateTelegrafConfig(ctx, f.telegraf, 1, f.now); err != nil {
+		t.Fatal(err)
+	}
+	f.target = &platform.ScraperTarget{
+		Name:       "node",
+		Type:       platform.PrometheusScraperType,
+		URL:        "http://127.0.0.1:9100/metrics",
+		OrgName:    "acme",
+		BucketName: "telegraf",
+	}
+	if err := s.AddTarget(ctx, f.target); err != nil {
+		t.Fatal(err)
+	}
+
+	f.svc = &bucket.Service{
+		BucketService:             s,
+		OrganizationService:       s,
+		TaskService:               newTaskService(f.task),
+		TelegrafService:           s,
+		ScraperTargetStoreService: s,
+		DBRPMappingService:        s,
+		Engine:                    f.engine,
+		GracePeriod:               24 * time.Hour,
+		Now:                       func() time.Time { return f.now },
+	}
+	return f
+}
+
+func TestService_FindBucketReferences(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	if err := f.store.Create(ctx, &platform.DBRPMapping{
+		Cluster:         "cluster",
+		Database:        "telegraf",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  f.org.ID,
+		BucketID:        f.bucket.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := f.svc.FindBucketReferences(ctx, f.bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[platform.BucketReferenceKind]string{
+		platform.TaskBucketReference:     "downsample",
+		platform.TelegrafBucketReference: "hosts",
+		platform.ScraperBucketReference:  "node",
+		platform.DBRPBucketReference:     "cluster/telegraf/autogen",
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %d references, got %d", len(want), len(refs))
+	}
+	for _, r := range refs {
+		if want[r.Kind] != r.Name {
+			t.Errorf("unexpected reference %+v", r)
+		}
+	}
+}
+
+func TestService_UpdateBucket(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	name := "metrics"
+
+	_, err := f.svc.UpdateBucket(ctx, f.bucket.ID, platform.BucketUpdate{Name: &name})
+	if platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict renaming a referenced bucket, got %v", err)
+	}
+
+	ctx = platcontext.SetBucketReferencePolicy(ctx, platform.BucketReferencesRewrite)
+	b, err := f.svc.UpdateBucket(ctx, f.bucket.ID, platform.BucketUpdate{Name: &name})
+	if err != nil {
+		t.Fatalf("unexpected error renaming the bucket: %v", err)
+	}
+	if b.Name != name {
+		t.Fatalf("expected the bucket to be renamed, got %q", b.Name)
+	}
+
+	if want := `from(bucket: "metrics") |> range(start: -1h) |> to(bucket: "downsampled")`; f.task.Flux != want {
+		t.Errorf("unexpected task script %s", f.task.Flux)
+	}
+	tc, err := f.store.FindTelegrafConfigByID(ctx, f.telegraf.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := tc.Plugins[0].Config.(*outputs.InfluxDBV2); out.Bucket != name {
+		t.Errorf("expected the telegraf output to be rewritten, got %q", out.Bucket)
+	}
+	target, err := f.store.GetTargetByID(ctx, f.target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.BucketName != name {
+		t.Errorf("expected the scraper target to be rewritten, got %q", target.BucketName)
+	}
+}
+
+func TestService_DeleteBucket(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	if err := f.svc.DeleteBucket(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict deleting a referenced bucket, got %v", err)
+	}
+
+	ctx = platcontext.SetBucketReferencePolicy(ctx, platform.BucketReferencesIgnore)
+	if err := f.svc.DeleteBucket(ctx, f.bucket.ID); err != nil {
+		t.Fatalf("unexpected error deleting the bucket: %v", err)
+	}
+	if _, err := f.svc.FindBucketByID(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the deleted bucket to be hidden, got %v", err)
+	}
+	if bs, n, err := f.svc.FindBuckets(ctx, platform.BucketFilter{}); err != nil || n != 0 {
+		t.Fatalf("expected no buckets, got %v %v", bs, err)
+	}
+	deleted, err := f.svc.FindDeletedBuckets(ctx, platform.BucketFilter{OrganizationID: &f.org.ID})
+	if err != nil || len(deleted) != 1 || deleted[0].DeletedAt == nil || !deleted[0].DeletedAt.Equal(f.now) {
+		t.Fatalf("unexpected deleted buckets %v %v", deleted, err)
+	}
+
+	// still in the grace period
+	f.now = f.now.Add(time.Hour)
+	if err := f.svc.PurgeExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.RestoreBucket(ctx, f.bucket.ID); err != nil {
+		t.Fatalf("unexpected error restoring the bucket: %v", err)
+	}
+	if b, err := f.svc.FindBucketByID(ctx, f.bucket.ID); err != nil || b.DeletedAt != nil {
+		t.Fatalf("expected the bucket to be restored: %v %v", b, err)
+	}
+	if _, err := f.svc.RestoreBucket(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring a bucket that is not deleted, got %v", err)
+	}
+
+	// past the grace period
+	if err := f.svc.DeleteBucket(ctx, f.bucket.ID); err != nil {
+		t.Fatal(err)
+	}
+	f.now = f.now.Add(25 * time.Hour)
+	if err := f.svc.PurgeExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.store.FindBucketByID(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the bucket to be purged, got %v", err)
+	}
+	if len(f.engine.deleted) != 1 || f.engine.deleted[0] != f.bucket.ID {
+		t.Fatalf("expected the data of the bucket to be deleted, got %v", f.engine.deleted)
+	}
+}
+
+func TestService_DeleteBucket_NoGracePeriod(t *testing.T) {
+	ctx := platcontext.SetBucketReferencePolicy(context.Background(), platform.BucketReferencesIgnore)
+	f := newFixture(t)
+	f.svc.GracePeriod = 0
+
+	if err := f.svc.DeleteBucket(ctx, f.bucket.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.store.FindBucketByID(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the bucket to be purged right away, got %v", err)
+	}
+	if len(f.engine.deleted) != 1 {
+		t.Fatalf("expected the data of the bucket to be deleted, got %v", f.engine.deleted)
+	}
+}
+
+func newBoltStore(t *testing.T) (*bolt.Client, func()) {
+	f, err := ioutil.TempFile("", "influxdata-platform-bucket-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	c := bolt.NewClient()
+	c.Path = f.Name()
+	if err := c.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return c, func() {
+		c.Close()
+		os.Remove(c.Path)
+	}
+}
+
+func TestService_DeleteBucket_ReuseName(t *testing.T) {
+	type store interface {
+		platform.BucketService
+		platform.OrganizationService
+	}
+	tests := []struct {
+		name  string
+		store func(t *testing.T) (store, func())
+	}{
+		{
+			name:  "inmem",
+			store: func(t *testing.T) (store, func()) { return inmem.NewService(), func() {} },
+		},
+		{
+			name:  "bolt",
+			store: func(t *testing.T) (store, func()) { return newBoltStore(t) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, done := tt.store(t)
+			defer done()
+
+			now := time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+			svc := &bucket.Service{
+				BucketService:       s,
+				OrganizationService: s,
+				GracePeriod:         24 * time.Hour,
+				Now:                 func() time.Time { return now },
+			}
+
+			org := &platform.Organization{Name: "acme"}
+			if err := s.CreateOrganization(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			old := &platform.Bucket{OrganizationID: org.ID, Name: "telegraf"}
+			if err := svc.CreateBucket(ctx, old); err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.DeleteBucket(ctx, old.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			// the name of the deleted bucket is free.
+			b := &platform.Bucket{OrganizationID: org.ID, Name: "telegraf"}
+			if err := svc.CreateBucket(ctx, b); err != nil {
+				t.Fatalf("unexpected error creating a bucket named as a deleted one: %v", err)
+			}
+			name := "telegraf"
+			if found, err := svc.FindBucket(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &name}); err != nil || found.ID != b.ID {
+				t.Fatalf("expected to find the new bucket, got %v %v", found, err)
+			}
+			deleted, err := svc.FindDeletedBuckets(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &name})
+			if err != nil || len(deleted) != 1 || deleted[0].ID != old.ID {
+				t.Fatalf("expected to find the deleted bucket, got %v %v", deleted, err)
+			}
+
+			if _, err := svc.RestoreBucket(ctx, old.ID); platform.ErrorCode(err) != platform.EConflict {
+				t.Fatalf("expected a conflict restoring a bucket whose name was taken, got %v", err)
+			}
+
+			// purging the deleted bucket leaves the name to the new one.
+			now = now.Add(25 * time.Hour)
+			if err := svc.PurgeExpired(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.FindBucketByID(ctx, old.ID); platform.ErrorCode(err) != platform.ENotFound {
+				t.Fatalf("expected the deleted bucket to be purged, got %v", err)
+			}
+			if found, err := svc.FindBucket(ctx, platform.BucketFilter{OrganizationID: &org.ID, Name: &name}); err != nil || found.ID != b.ID {
+				t.Fatalf("expected to find the new bucket, got %v %v", found, err)
+			}
+		})
+	}
+}
+
+func TestService_FindBuckets_Paging(t *testing.T) {
+	ctx := platcontext.SetBucketReferencePolicy(context.Background(), platform.BucketReferencesIgnore)
+	f := newFixture(t)
+	for _, name := range []string{"a", "b", "c"} {
+		if err := f.svc.CreateBucket(ctx, &platform.Bucket{OrganizationID: f.org.ID, Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.svc.DeleteBucket(ctx, f.bucket.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts platform.FindOptions
+		want int
+	}{
+		{opts: platform.FindOptions{Limit: 2}, want: 2},
+		{opts: platform.FindOptions{Limit: 2, Offset: 2}, want: 1},
+		{opts: platform.FindOptions{Offset: 3}, want: 0},
+	}
+	for _, tt := range tests {
+		bs, n, err := f.svc.FindBuckets(ctx, platform.BucketFilter{}, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bs) != tt.want || n != 3 {
+			t.Errorf("unexpected buckets with options %+v: got %d of %d, want %d of 3", tt.opts, len(bs), n, tt.want)
+		}
+		for _, b := range bs {
+			if b.DeletedAt != nil {
+				t.Errorf("unexpected deleted bucket %v", b)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BucketReference is a resource pointing at a bucket. Tasks, telegraf
+// configs and scraper targets refer to buckets by name, DBRP mappings by ID.
+type BucketReference struct {
+	Kind BucketReferenceKind `json:"kind"`
+	// ID is the ID of the resource; DBRP mappings have none.
+	ID   ID     `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// BucketReferenceKind is the kind of resource pointing at a bucket.
+type BucketReferenceKind string
+
+// Kinds of bucket references.
+const (
+	TaskBucketReference     BucketReferenceKind = "task"
+	TelegrafBucketReference BucketReferenceKind = "telegraf"
+	ScraperBucketReference  BucketReferenceKind = "scraper"
+	DBRPBucketReference     BucketReferenceKind = "dbrp"
+)
+
+// BucketReferencePolicy is what renaming or deleting a bucket does about the
+// resources pointing at it.
+type BucketReferencePolicy string
+
+const (
+	// BucketReferencesCheck fails renaming or deleting a bucket with a
+	// conflict listing its references, if any. It is the default policy.
+	BucketReferencesCheck BucketReferencePolicy = "check"
+	// BucketReferencesRewrite points the references at the new name of a
+	// renamed bucket. Deletions can't be rewritten, so they are checked.
+	BucketReferencesRewrite BucketReferencePolicy = "rewrite"
+	// BucketReferencesIgnore leaves the references alone.
+	BucketReferencesIgnore BucketReferencePolicy = "ignore"
+)
+
+// Valid returns an error if the policy is unknown.
+func (p BucketReferencePolicy) Valid() error {
+	switch p {
+	case BucketReferencesCheck, BucketReferencesRewrite, BucketReferencesIgnore:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("unknown bucket reference policy %q", p),
+	}
+}
+
+// BucketReferencesError is the conflict of renaming or deleting a bucket
+// that is referenced by other resources.
+func BucketReferencesError(op string, refs []*BucketReference) error {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		names = append(names, fmt.Sprintf("%s %q", r.Kind, r.Name))
+	}
+	return &Error{
+		Code: EConflict,
+		Op:   op,
+		Msg:  "bucket is referenced by " + strings.Join(names, ", "),
+	}
+}
+
+// ops for bucket lifecycle errors.
+const (
+	OpFindBucketReferences = "FindBucketReferences"
+	OpFindDeletedBuckets   = "FindDeletedBuckets"
+	OpRestoreBucket        = "RestoreBucket"
+)
+
+// BucketLifecycleService lists and restores the deleted buckets in the grace
+// period of their deletion, and the resources referring to buckets.
+type BucketLifecycleService interface {
+	// FindBucketReferences returns the resources pointing at a bucket.
+	FindBucketReferences(ctx context.Context, id ID) ([]*BucketReference, error)
+
+	// FindDeletedBuckets returns the deleted buckets matching filter that
+	// can still be restored.
+	FindDeletedBuckets(ctx context.Context, filter BucketFilter) ([]*Bucket, error)
+
+	// RestoreBucket restores a deleted bucket before its data is purged.
+	RestoreBucket(ctx context.Context, id ID) (*Bucket, error)
+}
@@ -0,0 +1,94 @@
+// Package bundle exports the resources of an organization as a portable
+// bundle, and imports bundles into organizations, so that the setup of a
+// team can be moved between environments.
+package bundle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+// Version is the version of the bundles written by Export. Import reads the
+// bundles of this version and of the previous ones.
+const Version = 1
+
+// Bundle holds the resources of an organization. The IDs of the resources
+// are those of the exporting environment, and are only used to resolve the
+// references between resources, e.g. from the cells of dashboards to views.
+type Bundle struct {
+	Version    int       `json:"version"`
+	Org        string    `json:"org"`
+	ExportedAt time.Time `json:"exportedAt"`
+
+	Buckets    []*platform.Bucket         `json:"buckets"`
+	Dashboards []*platform.Dashboard      `json:"dashboards"`
+	Views      []*platform.View           `json:"views"`
+	Macros     []*platform.Macro          `json:"macros"`
+	Tasks      []*platform.Task           `json:"tasks"`
+	Telegrafs  []*platform.TelegrafConfig `json:"telegrafs"`
+	// Labels are the labels of the other resources of the bundle.
+	Labels []*platform.Label `json:"labels"`
+}
+
+// Valid returns an error if the bundle can't be imported.
+func (b *Bundle) Valid() error {
+	if b.Version < 1 || b.Version > Version {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("unsupported bundle version %d, expected at most %d", b.Version, Version),
+		}
+	}
+	return nil
+}
+
+// ConflictStrategy is what an import does with a resource of the bundle
+// that has the name of an existing resource.
+type ConflictStrategy string
+
+const (
+	// Skip keeps the existing resource, and makes the references of the
+	// bundle to the resource refer to it.
+	Skip ConflictStrategy = "skip"
+	// Overwrite replaces the existing resource with the one of the bundle.
+	Overwrite ConflictStrategy = "overwrite"
+	// Rename imports the resource with a new name.
+	Rename ConflictStrategy = "rename"
+)
+
+// Valid returns an error if the strategy is unknown.
+func (s ConflictStrategy) Valid() error {
+	switch s {
+	case Skip, Overwrite, Rename:
+		return nil
+	}
+	return &platform.Error{
+		Code: platform.EInvalid,
+		Msg:  fmt.Sprintf("conflict strategy must be %q, %q or %q", Skip, Overwrite, Rename),
+	}
+}
+
+// ImportAction is what an import did with a resource of the bundle.
+type ImportAction string
+
+const (
+	// Created is the action of a resource without conflict.
+	Created ImportAction = "created"
+	// Skipped, Overwritten and Renamed are the actions of the conflict strategies.
+	Skipped     ImportAction = "skipped"
+	Overwritten ImportAction = "overwritten"
+	Renamed     ImportAction = "renamed"
+)
+
+// ImportedResource is a resource of the bundle and what was done with it.
+type ImportedResource struct {
+	// Kind is the kind of the resource, e.g. bucket.
+	Kind   string       `json:"kind"`
+	Name   string       `json:"name"`
+	Action ImportAction `json:"action"`
+	// BundleID is the ID of the resource in the bundle, and ID its ID after
+	// the import.
+	BundleID platform.ID `json:"bundleID"`
+	ID       platform.ID `json:"id"`
+}
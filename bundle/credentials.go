@@ -0,0 +1,237 @@
+package bundle
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/telegraf/plugins"
+	"github.com/influxdata/platform/telegraf/plugins/inputs"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
+)
+
+// secretSetting matches the names of the settings of telegraf plugins
+// holding credentials.
+var secretSetting = regexp.MustCompile(`(?i)(password|passwd|token|secret|credential|community|api_?key|access_key)`)
+
+// dsnPassword matches the password of a key/value connection string, exp
+// "host=localhost password=secret".
+var dsnPassword = regexp.MustCompile(`(?i)\bpassword=('[^']*'|\S*)\s*`)
+
+// removeCredentials removes the tokens, passwords and other credentials from
+// the config of a telegraf plugin, including those of the URLs it connects to.
+func removeCredentials(c plugins.Config) {
+	switch c := c.(type) {
+	case *outputs.InfluxDBV2:
+		c.Token = ""
+		removeURLCredentials(c.URLs)
+	case *inputs.Redis:
+		c.Password = ""
+		removeURLCredentials(c.Servers)
+	case *inputs.Postgresql:
+		c.Address = removeDSNCredentials(c.Address)
+		removeSettingsCredentials(c.Settings)
+	case *inputs.HTTP:
+		removeURLCredentials(c.URLs)
+		removeSettingsCredentials(c.Settings)
+	case *inputs.MQTTConsumer:
+		removeURLCredentials(c.Servers)
+		removeSettingsCredentials(c.Settings)
+	case *inputs.KafkaConsumer:
+		removeURLCredentials(c.Brokers)
+		removeSettingsCredentials(c.Settings)
+	case *inputs.SNMP:
+		c.Community = ""
+		removeSettingsCredentials(c.Settings)
+	case *inputs.Exec:
+		removeSettingsCredentials(c.Settings)
+	case *plugins.Generic:
+		removeSettingsCredentials(c.Settings)
+	}
+}
+
+func removeURLCredentials(urls []string) {
+	for i, u := range urls {
+		urls[i] = removeDSNCredentials(u)
+	}
+}
+
+// removeDSNCredentials removes the password of a URL or of a key/value
+// connection string. The user name is kept, so the config can be completed.
+func removeDSNCredentials(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		u.User = url.User(u.User.Username())
+		return u.String()
+	}
+	return strings.TrimSpace(dsnPassword.ReplaceAllString(dsn, ""))
+}
+
+func removeSettingsCredentials(settings map[string]interface{}) {
+	for k, v := range settings {
+		if secretSetting.MatchString(k) {
+			delete(settings, k)
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			settings[k] = removeDSNCredentials(v)
+		case []interface{}:
+			for i, e := range v {
+				switch e := e.(type) {
+				case string:
+					v[i] = removeDSNCredentials(e)
+				case map[string]interface{}:
+					removeSettingsCredentials(e)
+				}
+			}
+		case map[string]interface{}:
+			removeSettingsCredentials(v)
+		}
+	}
+}
+
+// restorePluginsCredentials puts back the credentials of the existing plugins
+// of a telegraf config into the plugins overwriting them, where they were
+// removed on export. A plugin overwrites the existing plugin of the same type
+// and name at the same rank.
+func restorePluginsCredentials(ps, existing []platform.TelegrafPlugin) {
+	ranks := map[string]int{}
+	for _, p := range ps {
+		key := p.Config.Type().Section() + "." + p.Config.PluginName()
+		rank := ranks[key]
+		ranks[key]++
+		for _, e := range existing {
+			if e.Config.Type().Section()+"."+e.Config.PluginName() != key {
+				continue
+			}
+			if rank == 0 {
+				restoreCredentials(p.Config, e.Config)
+				break
+			}
+			rank--
+		}
+	}
+}
+
+// restoreCredentials puts back the credentials of the existing config of a
+// telegraf plugin into c, where c holds what removeCredentials left of them.
+func restoreCredentials(c, existing plugins.Config) {
+	switch c := c.(type) {
+	case *outputs.InfluxDBV2:
+		if e, ok := existing.(*outputs.InfluxDBV2); ok {
+			if c.Token == "" {
+				c.Token = e.Token
+			}
+			restoreURLCredentials(c.URLs, e.URLs)
+		}
+	case *inputs.Redis:
+		if e, ok := existing.(*inputs.Redis); ok {
+			if c.Password == "" {
+				c.Password = e.Password
+			}
+			restoreURLCredentials(c.Servers, e.Servers)
+		}
+	case *inputs.Postgresql:
+		if e, ok := existing.(*inputs.Postgresql); ok {
+			c.Address = restoreDSNCredentials(c.Address, e.Address)
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *inputs.HTTP:
+		if e, ok := existing.(*inputs.HTTP); ok {
+			restoreURLCredentials(c.URLs, e.URLs)
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *inputs.MQTTConsumer:
+		if e, ok := existing.(*inputs.MQTTConsumer); ok {
+			restoreURLCredentials(c.Servers, e.Servers)
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *inputs.KafkaConsumer:
+		if e, ok := existing.(*inputs.KafkaConsumer); ok {
+			restoreURLCredentials(c.Brokers, e.Brokers)
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *inputs.SNMP:
+		if e, ok := existing.(*inputs.SNMP); ok {
+			if c.Community == "" {
+				c.Community = e.Community
+			}
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *inputs.Exec:
+		if e, ok := existing.(*inputs.Exec); ok {
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	case *plugins.Generic:
+		if e, ok := existing.(*plugins.Generic); ok {
+			c.Settings = restoreSettingsCredentials(c.Settings, e.Settings)
+		}
+	}
+}
+
+func restoreURLCredentials(urls, existing []string) {
+	for i, u := range urls {
+		for _, e := range existing {
+			if restored := restoreDSNCredentials(u, e); restored != u {
+				urls[i] = restored
+				break
+			}
+		}
+	}
+}
+
+// restoreDSNCredentials returns the existing URL or connection string if dsn
+// is what is left of it once its password is removed, dsn otherwise.
+func restoreDSNCredentials(dsn, existing string) string {
+	if removeDSNCredentials(existing) == dsn {
+		return existing
+	}
+	return dsn
+}
+
+// restoreSettingsCredentials returns the settings with the secret settings
+// they lack, and the connection strings whose password was removed, taken
+// from the existing settings.
+func restoreSettingsCredentials(settings, existing map[string]interface{}) map[string]interface{} {
+	for k, ev := range existing {
+		v, ok := settings[k]
+		if !ok {
+			if secretSetting.MatchString(k) {
+				if settings == nil {
+					settings = map[string]interface{}{}
+				}
+				settings[k] = ev
+			}
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			if e, ok := ev.(string); ok {
+				settings[k] = restoreDSNCredentials(v, e)
+			}
+		case []interface{}:
+			es, ok := ev.([]interface{})
+			if !ok {
+				continue
+			}
+			for i := 0; i < len(v) && i < len(es); i++ {
+				switch e := v[i].(type) {
+				case string:
+					if existing, ok := es[i].(string); ok {
+						v[i] = restoreDSNCredentials(e, existing)
+					}
+				case map[string]interface{}:
+					if existing, ok := es[i].(map[string]interface{}); ok {
+						restoreSettingsCredentials(e, existing)
+					}
+				}
+			}
+		case map[string]interface{}:
+			if e, ok := ev.(map[string]interface{}); ok {
+				restoreSettingsCredentials(v, e)
+			}
+		}
+	}
+	return settings
+}
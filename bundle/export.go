@@ -0,0 +1,218 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+// Service exports and imports bundles.
+type Service struct {
+	OrganizationService        platform.OrganizationService
+	BucketService              platform.BucketService
+	UserResourceMappingService platform.UserResourceMappingService
+	DashboardService           platform.DashboardService
+	ViewService                platform.ViewService
+	MacroService               platform.MacroService
+	TaskService                platform.TaskService
+	TelegrafService            platform.TelegrafConfigStore
+	LabelService               platform.LabelService
+
+	// Now returns the time of exports and imports; defaults to time.Now.
+	Now func() time.Time
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// Export returns the bundle of the resources of an organization: its
+// buckets, tasks, macros, dashboards, with their views, and telegraf configs,
+// and the labels of all of them. The tokens, passwords and other credentials
+// of the telegraf configs are not exported.
+func (s *Service) Export(ctx context.Context, orgID platform.ID) (*Bundle, error) {
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Version:    Version,
+		Org:        o.Name,
+		ExportedAt: s.now(),
+	}
+
+	buckets, _, err := s.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &o.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		bucket := *bucket
+		bucket.OrganizationID = 0
+		bucket.Organization = ""
+		b.Buckets = append(b.Buckets, &bucket)
+	}
+
+	tasks, _, err := s.TaskService.FindTasks(ctx, platform.TaskFilter{Organization: &o.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		t := *t
+		t.Owner = platform.User{}
+		b.Tasks = append(b.Tasks, &t)
+	}
+
+	dashboards, telegrafs, macros, err := s.orgResources(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := map[platform.ID]bool{}
+	for _, d := range dashboards {
+		d := *d
+		d.OrganizationID = 0
+		b.Dashboards = append(b.Dashboards, &d)
+
+		for _, c := range d.Cells {
+			if !c.ViewID.Valid() || views[c.ViewID] {
+				continue
+			}
+			views[c.ViewID] = true
+			v, err := s.ViewService.FindViewByID(ctx, c.ViewID)
+			if err != nil {
+				return nil, err
+			}
+			b.Views = append(b.Views, v)
+		}
+	}
+
+	for _, tc := range telegrafs {
+		// the config is copied so that removing its credentials doesn't
+		// change the plugins shared with the store.
+		tc, err := copyTelegrafConfig(tc)
+		if err != nil {
+			return nil, err
+		}
+		tc.AuthorizationID = 0
+		tc.OrganizationID = 0
+		for _, p := range tc.Plugins {
+			removeCredentials(p.Config)
+		}
+		b.Telegrafs = append(b.Telegrafs, tc)
+	}
+
+	for _, m := range macros {
+		m := *m
+		m.OrganizationID = 0
+		b.Macros = append(b.Macros, &m)
+	}
+
+	var ids []platform.ID
+	for _, r := range b.Buckets {
+		ids = append(ids, r.ID)
+	}
+	for _, r := range b.Tasks {
+		ids = append(ids, r.ID)
+	}
+	for _, r := range b.Dashboards {
+		ids = append(ids, r.ID)
+	}
+	for _, r := range b.Views {
+		ids = append(ids, r.ID)
+	}
+	for _, r := range b.Telegrafs {
+		ids = append(ids, r.ID)
+	}
+	for _, r := range b.Macros {
+		ids = append(ids, r.ID)
+	}
+	for _, id := range ids {
+		ls, err := s.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: id})
+		if err != nil {
+			return nil, err
+		}
+		b.Labels = append(b.Labels, ls...)
+	}
+
+	return b, nil
+}
+
+// orgResources returns the dashboards and telegraf configs of an
+// organization, which are mapped to its users, and its macros.
+func (s *Service) orgResources(ctx context.Context, orgID platform.ID) (dashboards []*platform.Dashboard, telegrafs []*platform.TelegrafConfig, macros []*platform.Macro, err error) {
+	users, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceType: platform.OrgResourceType,
+		ResourceID:   orgID,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	seen := map[platform.ID]bool{}
+	for _, u := range users {
+		if u.Principal() != platform.UserPrincipal {
+			continue
+		}
+		ms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+			UserID: u.UserID,
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, m := range ms {
+			if seen[m.ResourceID] {
+				continue
+			}
+			switch m.ResourceType {
+			case platform.DashboardResourceType:
+				d, err := s.DashboardService.FindDashboardByID(ctx, m.ResourceID)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				if d.OrganizationID == orgID {
+					dashboards = append(dashboards, d)
+				}
+			case platform.TelegrafResourceType:
+				tc, err := s.TelegrafService.FindTelegrafConfigByID(ctx, m.ResourceID)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				if tc.OrganizationID == orgID {
+					telegrafs = append(telegrafs, tc)
+				}
+			default:
+				continue
+			}
+			seen[m.ResourceID] = true
+		}
+	}
+
+	ms, err := s.MacroService.FindMacros(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, m := range ms {
+		if m.OrganizationID == orgID {
+			macros = append(macros, m)
+		}
+	}
+	return dashboards, telegrafs, macros, nil
+}
+
+func copyTelegrafConfig(tc *platform.TelegrafConfig) (*platform.TelegrafConfig, error) {
+	octets, err := json.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+	c := new(platform.TelegrafConfig)
+	if err := json.Unmarshal(octets, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,448 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
+)
+
+// ImportOptions configure the import of a bundle.
+type ImportOptions struct {
+	// OrgID is the organization the bundle is imported into.
+	OrgID platform.ID
+	// UserID is the user owning the imported dashboards, tasks and telegraf
+	// configs.
+	UserID platform.ID
+	// Conflict is the strategy for the resources with the name of an
+	// existing resource; defaults to Skip.
+	Conflict ConflictStrategy
+}
+
+// Import creates the resources of the bundle in an organization and returns
+// what was done with each of them, except labels. The resources of the
+// bundle get new IDs, and the references between them are updated.
+func (s *Service) Import(ctx context.Context, b *Bundle, opts ImportOptions) ([]ImportedResource, error) {
+	if err := b.Valid(); err != nil {
+		return nil, err
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = Skip
+	}
+	if err := opts.Conflict.Valid(); err != nil {
+		return nil, err
+	}
+
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, opts.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &importer{
+		Service:     s,
+		opts:        opts,
+		org:         o,
+		ids:         map[platform.ID]platform.ID{},
+		skipped:     map[platform.ID]bool{},
+		bucketNames: map[string]string{},
+	}
+	err = i.importBundle(ctx, b)
+	return i.imported, err
+}
+
+// importer is a single import of a bundle.
+type importer struct {
+	*Service
+	opts ImportOptions
+	org  *platform.Organization
+
+	// ids maps the IDs of the bundle to those of the imported resources.
+	ids map[platform.ID]platform.ID
+	// skipped are the IDs of the bundle of the skipped resources.
+	skipped map[platform.ID]bool
+	// bucketNames maps the names of the renamed buckets to their new name.
+	bucketNames map[string]string
+
+	imported []ImportedResource
+}
+
+func (i *importer) importBundle(ctx context.Context, b *Bundle) error {
+	if err := i.importBuckets(ctx, b.Buckets); err != nil {
+		return err
+	}
+	dashboards, telegrafs, macros, err := i.orgResources(ctx, i.org.ID)
+	if err != nil {
+		return err
+	}
+	if err := i.importMacros(ctx, b.Macros, macros); err != nil {
+		return err
+	}
+	if err := i.importDashboards(ctx, b.Dashboards, b.Views, dashboards); err != nil {
+		return err
+	}
+	if err := i.importTasks(ctx, b.Tasks); err != nil {
+		return err
+	}
+	if err := i.importTelegrafs(ctx, b.Telegrafs, telegrafs); err != nil {
+		return err
+	}
+	return i.importLabels(ctx, b.Labels)
+}
+
+// resolve returns what to do with a resource of the bundle named name, and
+// its name after the import. taken are the names of the existing resources
+// of its kind.
+func (i *importer) resolve(name string, taken map[string]bool) (ImportAction, string) {
+	if !taken[name] {
+		taken[name] = true
+		return Created, name
+	}
+	switch i.opts.Conflict {
+	case Overwrite:
+		return Overwritten, name
+	case Rename:
+		for n := 2; ; n++ {
+			renamed := fmt.Sprintf("%s (%d)", name, n)
+			if !taken[renamed] {
+				taken[renamed] = true
+				return Renamed, renamed
+			}
+		}
+	default:
+		return Skipped, name
+	}
+}
+
+func (i *importer) record(kind, name string, action ImportAction, bundleID, id platform.ID) {
+	i.ids[bundleID] = id
+	if action == Skipped {
+		i.skipped[bundleID] = true
+	}
+	i.imported = append(i.imported, ImportedResource{
+		Kind:     kind,
+		Name:     name,
+		Action:   action,
+		BundleID: bundleID,
+		ID:       id,
+	})
+}
+
+func (i *importer) importBuckets(ctx context.Context, bs []*platform.Bucket) error {
+	existing, _, err := i.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &i.org.ID})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Bucket{}
+	taken := map[string]bool{}
+	for _, b := range existing {
+		byName[b.Name] = b
+		taken[b.Name] = true
+	}
+
+	for _, b := range bs {
+		action, name := i.resolve(b.Name, taken)
+		switch action {
+		case Skipped:
+			i.record("bucket", name, action, b.ID, byName[name].ID)
+			continue
+		case Overwritten:
+			retention := b.RetentionPeriod
+			updated, err := i.BucketService.UpdateBucket(ctx, byName[name].ID, platform.BucketUpdate{RetentionPeriod: &retention})
+			if err != nil {
+				return err
+			}
+			i.record("bucket", name, action, b.ID, updated.ID)
+			continue
+		case Renamed:
+			i.bucketNames[b.Name] = name
+		}
+
+		created := &platform.Bucket{
+			OrganizationID:  i.org.ID,
+			Name:            name,
+			RetentionPeriod: b.RetentionPeriod,
+		}
+		if err := i.BucketService.CreateBucket(ctx, created); err != nil {
+			return err
+		}
+		i.record("bucket", name, action, b.ID, created.ID)
+	}
+	return nil
+}
+
+// importMacros imports the macros of the bundle. Only the macros of the
+// organization, existing, can be skipped or overwritten, the macros of other
+// organizations are left alone.
+func (i *importer) importMacros(ctx context.Context, ms []*platform.Macro, existing []*platform.Macro) error {
+	byName := map[string]*platform.Macro{}
+	taken := map[string]bool{}
+	for _, m := range existing {
+		byName[m.Name] = m
+		taken[m.Name] = true
+	}
+
+	for _, m := range ms {
+		action, name := i.resolve(m.Name, taken)
+		imported := &platform.Macro{
+			OrganizationID: i.org.ID,
+			Name:           name,
+			Selected:       m.Selected,
+			Arguments:      m.Arguments,
+		}
+		var err error
+		switch action {
+		case Skipped:
+			i.record("macro", name, action, m.ID, byName[name].ID)
+			continue
+		case Overwritten:
+			imported.ID = byName[name].ID
+			err = i.MacroService.ReplaceMacro(ctx, imported)
+		default:
+			if err = i.MacroService.CreateMacro(ctx, imported); err == nil {
+				err = i.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+					ResourceType: platform.MacroResourceType,
+					ResourceID:   imported.ID,
+					UserID:       i.opts.UserID,
+					UserType:     platform.Owner,
+				})
+			}
+		}
+		if err != nil {
+			return err
+		}
+		i.record("macro", name, action, m.ID, imported.ID)
+	}
+	return nil
+}
+
+func (i *importer) importDashboards(ctx context.Context, ds []*platform.Dashboard, vs []*platform.View, existing []*platform.Dashboard) error {
+	byName := map[string]*platform.Dashboard{}
+	taken := map[string]bool{}
+	for _, d := range existing {
+		byName[d.Name] = d
+		taken[d.Name] = true
+	}
+
+	views := make(map[platform.ID]*platform.View, len(vs))
+	for _, v := range vs {
+		views[v.ID] = v
+	}
+
+	for _, d := range ds {
+		action, name := i.resolve(d.Name, taken)
+		var id platform.ID
+		switch action {
+		case Skipped:
+			i.record("dashboard", name, action, d.ID, byName[name].ID)
+			continue
+		case Overwritten:
+			existing := byName[name]
+			id = existing.ID
+			if _, err := i.DashboardService.UpdateDashboard(ctx, id, platform.DashboardUpdate{Description: &d.Description}); err != nil {
+				return err
+			}
+			for _, c := range existing.Cells {
+				if err := i.DashboardService.RemoveDashboardCell(ctx, id, c.ID); err != nil {
+					return err
+				}
+			}
+		default:
+			created := &platform.Dashboard{OrganizationID: i.org.ID, Name: name, Description: d.Description}
+			if err := i.DashboardService.CreateDashboard(ctx, created); err != nil {
+				return err
+			}
+			id = created.ID
+			if err := i.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+				ResourceType: platform.DashboardResourceType,
+				ResourceID:   id,
+				UserID:       i.opts.UserID,
+				UserType:     platform.Owner,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range d.Cells {
+			cell := &platform.Cell{X: c.X, Y: c.Y, W: c.W, H: c.H}
+			if v, ok := views[c.ViewID]; ok {
+				viewID, err := i.importView(ctx, v)
+				if err != nil {
+					return err
+				}
+				cell.ViewID = viewID
+			}
+			if err := i.DashboardService.AddDashboardCell(ctx, id, cell, platform.AddDashboardCellOptions{}); err != nil {
+				return err
+			}
+		}
+		i.record("dashboard", name, action, d.ID, id)
+	}
+	return nil
+}
+
+// importView creates a view of the bundle, once even if it is shown by
+// several cells.
+func (i *importer) importView(ctx context.Context, v *platform.View) (platform.ID, error) {
+	if id, ok := i.ids[v.ID]; ok {
+		return id, nil
+	}
+	created := &platform.View{
+		ViewContents: platform.ViewContents{Name: v.Name},
+		Properties:   v.Properties,
+	}
+	if err := i.ViewService.CreateView(ctx, created); err != nil {
+		return 0, err
+	}
+	i.record("view", v.Name, Created, v.ID, created.ID)
+	return created.ID, nil
+}
+
+func (i *importer) importTasks(ctx context.Context, ts []*platform.Task) error {
+	existing, _, err := i.TaskService.FindTasks(ctx, platform.TaskFilter{Organization: &i.org.ID})
+	if err != nil {
+		return err
+	}
+	byName := map[string]*platform.Task{}
+	taken := map[string]bool{}
+	for _, t := range existing {
+		byName[t.Name] = t
+		taken[t.Name] = true
+	}
+
+	for _, t := range ts {
+		action, name := i.resolve(t.Name, taken)
+		flux := t.Flux
+		for old, renamed := range i.bucketNames {
+			flux = replaceFluxString(flux, "bucket", old, renamed)
+		}
+
+		switch action {
+		case Skipped:
+			i.record("task", name, action, t.ID, byName[name].ID)
+			continue
+		case Overwritten:
+			status := t.Status
+			updated, err := i.TaskService.UpdateTask(ctx, byName[name].ID, platform.TaskUpdate{Flux: &flux, Status: &status})
+			if err != nil {
+				return err
+			}
+			i.record("task", name, action, t.ID, updated.ID)
+			continue
+		case Renamed:
+			flux = replaceFluxString(flux, "name", t.Name, name)
+		}
+
+		created := &platform.Task{
+			Organization: i.org.ID,
+			Owner:        platform.User{ID: i.opts.UserID},
+			Status:       t.Status,
+			Flux:         flux,
+		}
+		if err := i.TaskService.CreateTask(ctx, created); err != nil {
+			return err
+		}
+		i.record("task", name, action, t.ID, created.ID)
+	}
+	return nil
+}
+
+// replaceFluxString replaces the string value old of the property key of
+// the records of a script, e.g. the bucket of from, with new.
+func replaceFluxString(script, key, old, new string) string {
+	re := regexp.MustCompile(`(\b` + key + `\s*:\s*)"` + regexp.QuoteMeta(old) + `"`)
+	return re.ReplaceAllString(script, `${1}`+fmt.Sprintf("%q", new))
+}
+
+// importTelegrafs imports the telegraf configs of the bundle. The configs
+// overwriting existing ones keep the credentials removed from the bundle.
+func (i *importer) importTelegrafs(ctx context.Context, tcs []*platform.TelegrafConfig, existing []*platform.TelegrafConfig) error {
+	byName := map[string]*platform.TelegrafConfig{}
+	taken := map[string]bool{}
+	for _, tc := range existing {
+		byName[tc.Name] = tc
+		taken[tc.Name] = true
+	}
+
+	for _, tc := range tcs {
+		action, name := i.resolve(tc.Name, taken)
+		if action == Skipped {
+			i.record("telegraf config", name, action, tc.ID, byName[name].ID)
+			continue
+		}
+
+		imported, err := copyTelegrafConfig(tc)
+		if err != nil {
+			return err
+		}
+		imported.ID = 0
+		imported.Name = name
+		imported.AuthorizationID = 0
+		imported.OrganizationID = i.org.ID
+		for _, p := range imported.Plugins {
+			if out, ok := p.Config.(*outputs.InfluxDBV2); ok {
+				out.Organization = i.org.Name
+				if renamed, ok := i.bucketNames[out.Bucket]; ok {
+					out.Bucket = renamed
+				}
+			}
+		}
+
+		if action == Overwritten {
+			existing := byName[name]
+			imported.AuthorizationID = existing.AuthorizationID
+			restorePluginsCredentials(imported.Plugins, existing.Plugins)
+			if _, err := i.TelegrafService.UpdateTelegrafConfig(ctx, existing.ID, imported, i.opts.UserID, i.now()); err != nil {
+				return err
+			}
+			i.record("telegraf config", name, action, tc.ID, existing.ID)
+			continue
+		}
+
+		if err := i.TelegrafService.CreateTelegrafConfig(ctx, imported, i.opts.UserID, i.now()); err != nil {
+			return err
+		}
+		i.record("telegraf config", name, action, tc.ID, imported.ID)
+	}
+	return nil
+}
+
+// importLabels labels the imported resources, replacing the properties of
+// their existing labels of the same name. The labels of the skipped
+// resources are left alone.
+func (i *importer) importLabels(ctx context.Context, ls []*platform.Label) error {
+	for _, l := range ls {
+		id, ok := i.ids[l.ResourceID]
+		if !ok || i.skipped[l.ResourceID] {
+			continue
+		}
+
+		existing, err := i.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: id})
+		if err != nil {
+			return err
+		}
+		var found *platform.Label
+		for _, e := range existing {
+			if e.Name == l.Name {
+				found = e
+				break
+			}
+		}
+
+		if found != nil {
+			if _, err := i.LabelService.UpdateLabel(ctx, found, platform.LabelUpdate{Properties: l.Properties}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := i.LabelService.CreateLabel(ctx, &platform.Label{
+			ResourceID: id,
+			Name:       l.Name,
+			Properties: l.Properties,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Package cascade deletes organizations along with all their resources. The
+// deleted organizations can be restored during a grace period, after which
+// their resources are purged, and their data deleted from storage in the
+// background.
+package cascade
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/platform"
+	"go.uber.org/zap"
+)
+
+// purgeInterval is how often the organizations past their grace period are
+// looked up by Run.
+const purgeInterval = time.Minute
+
+// Engine deletes the data of organizations from the storage engine.
+type Engine interface {
+	DeleteOrganizationData(ctx context.Context, orgID platform.ID, progress func(series uint64)) error
+}
+
+// Service deletes organizations with their resources. The progress of the
+// deletions past their grace period is kept in memory, while the deleted
+// organizations are kept until their data is deleted, so that the deletions
+// interrupted by a restart are resumed by Run.
+type Service struct {
+	OrganizationService        platform.OrganizationService
+	BucketService              platform.BucketService
+	UserService                platform.UserService
+	UserResourceMappingService platform.UserResourceMappingService
+
+	// The resources of the services left nil are not purged.
+	AuthorizationService platform.AuthorizationService
+	DashboardService     platform.DashboardService
+	TaskService          platform.TaskService
+	TelegrafService      platform.TelegrafConfigStore
+	SecretService        platform.SecretService
+	LabelService         platform.LabelService
+	// Engine deletes the data of the purged organizations; their data is
+	// kept if nil.
+	Engine Engine
+
+	// GracePeriod is how long the deleted organizations can be restored
+	// before being purged; they are purged right away if zero.
+	GracePeriod time.Duration
+
+	Logger *zap.Logger
+	// Now returns the time of deletions; defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	deletions map[platform.ID]*deletion
+	wg        sync.WaitGroup
+}
+
+var _ platform.OrganizationDeletionService = (*Service)(nil)
+
+// deletion is the deletion of an organization past its grace period.
+type deletion struct {
+	// series is updated atomically as the data is deleted.
+	series uint64
+
+	platform.OrganizationDeletion
+	// metadataPurged is true once the organization and its resources are
+	// purged, only its data is left to delete.
+	metadataPurged bool
+}
+
+func (d *deletion) snapshot() *platform.OrganizationDeletion {
+	od := d.OrganizationDeletion
+	od.SeriesDeleted = atomic.LoadUint64(&d.series)
+	od.Resources = make(map[string]int, len(d.Resources))
+	for k, n := range d.Resources {
+		od.Resources[k] = n
+	}
+	return &od
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Service) logger() *zap.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return zap.NewNop()
+}
+
+// pending returns the deletion of an organization in its grace period.
+func (s *Service) pending(o *platform.Organization) *platform.OrganizationDeletion {
+	return &platform.OrganizationDeletion{
+		OrgID:     o.ID,
+		OrgName:   o.Name,
+		Status:    platform.OrganizationDeletionPending,
+		DeletedAt: *o.DeletedAt,
+		PurgeAt:   o.DeletedAt.Add(s.GracePeriod),
+	}
+}
+
+// ScheduleOrganizationDeletion marks an organization as deleted, or purges
+// it right away if there is no grace period.
+func (s *Service) ScheduleOrganizationDeletion(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	if s.GracePeriod == 0 {
+		return s.PurgeOrganization(ctx, id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.DeletedAt == nil {
+		now := s.now()
+		if o, err = s.OrganizationService.UpdateOrganization(ctx, id, platform.OrganizationUpdate{DeletedAt: &now}); err != nil {
+			return nil, err
+		}
+		s.logger().Info("Deleted organization", zap.Stringer("org_id", id), zap.Duration("grace_period", s.GracePeriod))
+	}
+	return s.pending(o), nil
+}
+
+// PurgeOrganization purges an organization and its resources, and starts
+// deleting its data in the background. Purging an organization again
+// returns the progress of its deletion, or resumes it if it failed.
+func (s *Service) PurgeOrganization(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deletions[id]
+	if ok && d.Status != platform.OrganizationDeletionFailed {
+		return d.snapshot(), nil
+	}
+
+	if !ok || !d.metadataPurged {
+		o, err := s.OrganizationService.FindOrganizationByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		now := s.now()
+		d = &deletion{
+			OrganizationDeletion: platform.OrganizationDeletion{
+				OrgID:     o.ID,
+				OrgName:   o.Name,
+				DeletedAt: now,
+				PurgeAt:   now,
+				Resources: map[string]int{},
+			},
+		}
+		if o.DeletedAt != nil {
+			d.DeletedAt = *o.DeletedAt
+		} else {
+			// the organization is marked deleted first, so that its data
+			// isn't written meanwhile, and past its grace period, so that
+			// the purge is resumed after a restart.
+			expired := now.Add(-s.GracePeriod)
+			if _, err := s.OrganizationService.UpdateOrganization(ctx, id, platform.OrganizationUpdate{DeletedAt: &expired}); err != nil {
+				return nil, err
+			}
+		}
+		if s.deletions == nil {
+			s.deletions = map[platform.ID]*deletion{}
+		}
+		s.deletions[id] = d
+
+		if err := s.purgeMetadata(ctx, o, d); err != nil {
+			d.Status = platform.OrganizationDeletionFailed
+			d.Error = err.Error()
+			d.CompletedAt = &now
+			s.logger().Error("Failed to purge organization", zap.Stringer("org_id", id), zap.Error(err))
+			return d.snapshot(), err
+		}
+		d.metadataPurged = true
+		s.logger().Info("Purged organization", zap.Stringer("org_id", id), zap.Any("resources", d.Resources))
+	}
+
+	d.Status = platform.OrganizationDeletionPurging
+	d.Error = ""
+	d.CompletedAt = nil
+	if s.Engine == nil {
+		s.complete(d, nil)
+		return d.snapshot(), nil
+	}
+
+	s.wg.Add(1)
+	go s.purgeData(d)
+	return d.snapshot(), nil
+}
+
+// purgeData deletes the data of an organization from storage.
+func (s *Service) purgeData(d *deletion) {
+	defer s.wg.Done()
+
+	err := s.Engine.DeleteOrganizationData(context.Background(), d.OrgID, func(series uint64) {
+		atomic.StoreUint64(&d.series, series)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complete(d, err)
+}
+
+// complete records the end of a deletion, and deletes the organization once
+// its data is deleted; s.mu must be held.
+func (s *Service) complete(d *deletion, err error) {
+	if err == nil {
+		err = s.OrganizationService.DeleteOrganization(context.Background(), d.OrgID)
+	}
+	now := s.now()
+	d.CompletedAt = &now
+	log := s.logger().With(zap.Stringer("org_id", d.OrgID))
+	if err != nil {
+		d.Status = platform.OrganizationDeletionFailed
+		d.Error = err.Error()
+		log.Error("Failed to delete organization data", zap.Error(err))
+		return
+	}
+	d.Status = platform.OrganizationDeletionComplete
+	log.Info("Deleted organization data", zap.Uint64("series", atomic.LoadUint64(&d.series)))
+}
+
+// RestoreOrganization restores a deleted organization that wasn't purged.
+func (s *Service) RestoreOrganization(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.deletions[id]; ok && d.metadataPurged {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Msg:  "organization was already purged",
+		}
+	}
+
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.DeletedAt == nil {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Msg:  "organization is not deleted",
+		}
+	}
+	// the purge of the organization may have been interrupted by a restart.
+	if !s.now().Before(o.DeletedAt.Add(s.GracePeriod)) {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Msg:  "grace period of the organization has passed",
+		}
+	}
+
+	var restored time.Time
+	if o, err = s.OrganizationService.UpdateOrganization(ctx, id, platform.OrganizationUpdate{DeletedAt: &restored}); err != nil {
+		return nil, err
+	}
+	delete(s.deletions, id)
+	s.logger().Info("Restored organization", zap.Stringer("org_id", id))
+	return o, nil
+}
+
+// FindOrganizationDeletion returns the progress of the deletion of an
+// organization. The progress of the purged organizations is lost on
+// restart, those whose data wasn't deleted yet are pending again.
+func (s *Service) FindOrganizationDeletion(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.deletions[id]; ok {
+		return d.snapshot(), nil
+	}
+
+	o, err := s.OrganizationService.FindOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.DeletedAt == nil {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "organization is not deleted",
+		}
+	}
+	return s.pending(o), nil
+}
+
+// PurgeExpired purges the deleted organizations whose grace period has
+// passed. The organizations failing to be purged are logged and retried on
+// the next call.
+func (s *Service) PurgeExpired(ctx context.Context) error {
+	os, _, err := s.OrganizationService.FindOrganizations(ctx, platform.OrganizationFilter{})
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, o := range os {
+		if o.DeletedAt == nil || now.Before(o.DeletedAt.Add(s.GracePeriod)) {
+			continue
+		}
+		// the failure is logged and recorded in the progress of the deletion.
+		s.PurgeOrganization(ctx, o.ID)
+	}
+	return nil
+}
+
+// Run purges the deleted organizations once their grace period has passed
+// until ctx is done, and then waits for the deletions of data in progress.
+func (s *Service) Run(ctx context.Context) error {
+	defer s.wg.Wait()
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.PurgeExpired(ctx); err != nil {
+			s.logger().Error("Failed to find deleted organizations", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package cascade_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/cascade"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+)
+
+// engine is an Engine whose deletions wait for a signal.
+type engine struct {
+	deleted  chan platform.ID
+	proceed  chan error
+	progress uint64
+}
+
+func newEngine() *engine {
+	return &engine{
+		deleted: make(chan platform.ID, 1),
+		proceed: make(chan error),
+	}
+}
+
+func (e *engine) DeleteOrganizationData(ctx context.Context, orgID platform.ID, progress func(series uint64)) error {
+	progress(e.progress)
+	e.deleted <- orgID
+	return <-e.proceed
+}
+
+// newTaskService returns a task service keeping the tasks in memory.
+func newTaskService() *mock.TaskService {
+	tasks := map[platform.ID]*platform.Task{}
+	return &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			var ts []*platform.Task
+			for _, t := range tasks {
+				if filter.Organization == nil || t.Organization == *filter.Organization {
+					ts = append(ts, t)
+				}
+			}
+			return ts, len(ts), nil
+		},
+		CreateTaskFn: func(ctx context.Context, t *platform.Task) error {
+			tasks[t.ID] = t
+			return nil
+		},
+		DeleteTaskFn: func(ctx context.Context, id platform.ID) error {
+			delete(tasks, id)
+			return nil
+		},
+	}
+}
+
+type fixture struct {
+	svc   *cascade.Service
+	store *inmem.Service
+	now   time.Time
+
+	org, other  *platform.Organization
+	user, admin *platform.User
+	bucket      *platform.Bucket
+	dashboard   *platform.Dashboard
+	shared      *platform.Dashboard
+	token       *platform.Authorization
+}
+
+func newFixture(t *testing.T, e cascade.Engine) *fixture {
+	ctx := context.Background()
+	s := inmem.NewService()
+	f := &fixture{
+		store: s,
+		now:   time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+	}
+	f.svc = &cascade.Service{
+		OrganizationService:        s,
+		BucketService:              s,
+		UserService:                s,
+		UserResourceMappingService: s,
+		AuthorizationService:       s,
+		DashboardService:           s,
+		TaskService:                newTaskService(),
+		TelegrafService:            s,
+		LabelService:               s,
+		Engine:                     e,
+		GracePeriod:                24 * time.Hour,
+		Now:                        func() time.Time { return f.now },
+	}
+
+	mapping := func(typ platform.ResourceType, resourceID, userID platform.ID) {
+		if err := s.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+			ResourceType: typ,
+			ResourceID:   resourceID,
+			UserID:       userID,
+			UserType:     platform.Owner,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.org = &platform.Organization{Name: "acme"}
+	f.other = &platform.Organization{Name: "other"}
+	f.user = &platform.User{Name: "user"}
+	f.admin = &platform.User{Name: "admin"}
+	for _, o := range []*platform.Organization{f.org, f.other} {
+		if err := s.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, u := range []*platform.User{f.user, f.admin} {
+		if err := s.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the user is only a member of acme, the admin of both orgs.
+	mapping(platform.OrgResourceType, f.org.ID, f.user.ID)
+	mapping(platform.OrgResourceType, f.org.ID, f.admin.ID)
+	mapping(platform.OrgResourceType, f.other.ID, f.admin.ID)
+	if _, err := s.UpdateUser(ctx, f.user.ID, platform.UserUpdate{DefaultOrgID: &f.org.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.bucket = &platform.Bucket{OrganizationID: f.org.ID, Name: "telegraf"}
+	if err := s.CreateBucket(ctx, f.bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateLabel(ctx, &platform.Label{ResourceID: f.org.ID, Name: "team"}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.dashboard = &platform.Dashboard{Name: "hosts"}
+	f.shared = &platform.Dashboard{Name: "shared"}
+	for _, d := range []*platform.Dashboard{f.dashboard, f.shared} {
+		if err := s.CreateDashboard(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		mapping(platform.DashboardResourceType, d.ID, f.user.ID)
+	}
+	mapping(platform.DashboardResourceType, f.shared.ID, f.admin.ID)
+
+	if err := f.svc.TaskService.CreateTask(ctx, &platform.Task{ID: s.IDGenerator.ID(), Organization: f.org.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.token = &platform.Authorization{
+		UserID:      f.user.ID,
+		Permissions: []platform.Permission{platform.WriteBucketPermission(f.bucket.ID)},
+	}
+	if err := s.CreateAuthorization(ctx, f.token); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestService_ScheduleOrganizationDeletion(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+
+	d, err := f.svc.ScheduleOrganizationDeletion(ctx, f.org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error deleting org: %v", err)
+	}
+	if d.Status != platform.OrganizationDeletionPending || !d.PurgeAt.Equal(f.now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected deletion %+v", d)
+	}
+	if o, err := f.store.FindOrganizationByID(ctx, f.org.ID); err != nil || o.DeletedAt == nil {
+		t.Fatalf("expected the org to be marked as deleted: %v %v", o, err)
+	}
+
+	// still in the grace period
+	f.now = f.now.Add(time.Hour)
+	if err := f.svc.PurgeExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.RestoreOrganization(ctx, f.org.ID); err != nil {
+		t.Fatalf("unexpected error restoring org: %v", err)
+	}
+	if o, err := f.store.FindOrganizationByID(ctx, f.org.ID); err != nil || o.DeletedAt != nil {
+		t.Fatalf("expected the org to be restored: %v %v", o, err)
+	}
+	if _, err := f.svc.FindOrganizationDeletion(ctx, f.org.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected no deletion of a restored org, got %v", err)
+	}
+	if _, err := f.svc.RestoreOrganization(ctx, f.org.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring an org that is not deleted, got %v", err)
+	}
+
+	// past the grace period
+	if _, err := f.svc.ScheduleOrganizationDeletion(ctx, f.org.ID); err != nil {
+		t.Fatal(err)
+	}
+	f.now = f.now.Add(25 * time.Hour)
+	if err := f.svc.PurgeExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.store.FindOrganizationByID(ctx, f.org.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the org to be purged, got %v", err)
+	}
+	if _, err := f.svc.RestoreOrganization(ctx, f.org.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring a purged org, got %v", err)
+	}
+	d, err = f.svc.FindOrganizationDeletion(ctx, f.org.ID)
+	if err != nil || d.Status != platform.OrganizationDeletionComplete {
+		t.Fatalf("expected a complete deletion without engine, got %+v %v", d, err)
+	}
+}
+
+func TestService_PurgeOrganization(t *testing.T) {
+	ctx := context.Background()
+	e := newEngine()
+	e.progress = 42
+	f := newFixture(t, e)
+
+	d, err := f.svc.PurgeOrganization(ctx, f.org.ID)
+	if err != nil {
+		t.Fatalf("unexpected error purging org: %v", err)
+	}
+	if d.Status != platform.OrganizationDeletionPurging {
+		t.Fatalf("expected the data to be deleted in the background, got %+v", d)
+	}
+	want := map[string]int{
+		"authorizations":       1,
+		"buckets":              1,
+		"dashboards":           1,
+		"labels":               1,
+		"tasks":                1,
+		"userResourceMappings": 3,
+	}
+	for k, n := range want {
+		if d.Resources[k] != n {
+			t.Errorf("expected %d %s purged, got %d", n, k, d.Resources[k])
+		}
+	}
+
+	if _, err := f.store.FindBucketByID(ctx, f.bucket.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the bucket to be purged, got %v", err)
+	}
+	if _, err := f.store.FindDashboardByID(ctx, f.dashboard.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the dashboard of the org to be purged, got %v", err)
+	}
+	if _, err := f.store.FindDashboardByID(ctx, f.shared.ID); err != nil {
+		t.Errorf("expected the dashboard shared with a member of another org to be kept, got %v", err)
+	}
+	if _, err := f.store.FindAuthorizationByID(ctx, f.token.ID); err == nil {
+		t.Errorf("expected the authorization on the bucket of the org to be purged")
+	}
+	if u, err := f.store.FindUserByID(ctx, f.user.ID); err != nil || u.DefaultOrgID.Valid() {
+		t.Errorf("expected the default org of the user to be cleared: %v %v", u, err)
+	}
+	if _, err := f.store.FindOrganizationByID(ctx, f.other.ID); err != nil {
+		t.Errorf("expected the other org to be kept, got %v", err)
+	}
+
+	if id := <-e.deleted; id != f.org.ID {
+		t.Fatalf("expected the data of the org to be deleted, got %v", id)
+	}
+	if d, _ := f.svc.FindOrganizationDeletion(ctx, f.org.ID); d.Status != platform.OrganizationDeletionPurging || d.SeriesDeleted != 42 {
+		t.Fatalf("unexpected progress %+v", d)
+	}
+	e.proceed <- &platform.Error{Msg: "engine closed"}
+
+	d = waitDeletion(t, f.svc, f.org.ID)
+	if d.Status != platform.OrganizationDeletionFailed || d.Error == "" {
+		t.Fatalf("expected the deletion to fail, got %+v", d)
+	}
+	// the org is kept, deleted, until its data is deleted.
+	if o, err := f.store.FindOrganizationByID(ctx, f.org.ID); err != nil || o.DeletedAt == nil {
+		t.Fatalf("expected the org to be kept as deleted: %v %v", o, err)
+	}
+
+	// purging again resumes the deletion of the data.
+	if _, err := f.svc.PurgeOrganization(ctx, f.org.ID); err != nil {
+		t.Fatalf("unexpected error resuming the deletion: %v", err)
+	}
+	<-e.deleted
+	e.proceed <- nil
+	if d = waitDeletion(t, f.svc, f.org.ID); d.Status != platform.OrganizationDeletionComplete {
+		t.Fatalf("expected the deletion to complete, got %+v", d)
+	}
+	if _, err := f.store.FindOrganizationByID(ctx, f.org.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the org to be deleted with its data, got %v", err)
+	}
+}
+
+func TestService_PurgeOrganization_Restart(t *testing.T) {
+	ctx := context.Background()
+	e := newEngine()
+	f := newFixture(t, e)
+
+	if _, err := f.svc.PurgeOrganization(ctx, f.org.ID); err != nil {
+		t.Fatalf("unexpected error purging org: %v", err)
+	}
+	<-e.deleted
+	e.proceed <- &platform.Error{Msg: "engine closed"}
+	waitDeletion(t, f.svc, f.org.ID)
+
+	// a new service has lost the progress of the deletion.
+	restarted := &cascade.Service{
+		OrganizationService:        f.store,
+		BucketService:              f.store,
+		UserService:                f.store,
+		UserResourceMappingService: f.store,
+		Engine:                     e,
+		GracePeriod:                f.svc.GracePeriod,
+		Now:                        f.svc.Now,
+	}
+	if _, err := restarted.RestoreOrganization(ctx, f.org.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring an org being purged, got %v", err)
+	}
+	if err := restarted.PurgeExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if id := <-e.deleted; id != f.org.ID {
+		t.Fatalf("expected the deletion of the data of the org to resume, got %v", id)
+	}
+	e.proceed <- nil
+	if d := waitDeletion(t, restarted, f.org.ID); d.Status != platform.OrganizationDeletionComplete {
+		t.Fatalf("expected the deletion to complete, got %+v", d)
+	}
+	if _, err := f.store.FindOrganizationByID(ctx, f.org.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the org to be deleted with its data, got %v", err)
+	}
+}
+
+// waitDeletion waits for the deletion of an org to complete or fail.
+func waitDeletion(t *testing.T, svc *cascade.Service, id platform.ID) *platform.OrganizationDeletion {
+	for i := 0; i < 100; i++ {
+		d, err := svc.FindOrganizationDeletion(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.CompletedAt != nil {
+			return d
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the deletion")
+	return nil
+}
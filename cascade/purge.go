@@ -0,0 +1,264 @@
+package cascade
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+)
+
+// purgeMetadata deletes the resources of an organization, and counts them in
+// d. It is resumed by purging the organization again if it fails midway. The
+// organization itself is deleted once its data is, so that the deletions
+// interrupted by a restart are resumed.
+func (s *Service) purgeMetadata(ctx context.Context, o *platform.Organization, d *deletion) error {
+	buckets, _, err := s.BucketService.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &o.ID})
+	if err != nil {
+		return err
+	}
+
+	// the authorizations are looked up before the buckets are deleted, as
+	// their permissions refer to them.
+	if err := s.purgeAuthorizations(ctx, o.ID, buckets, d); err != nil {
+		return err
+	}
+
+	var purged []platform.ID
+	if s.TaskService != nil {
+		for {
+			ts, _, err := s.TaskService.FindTasks(ctx, platform.TaskFilter{Organization: &o.ID})
+			if err != nil {
+				return err
+			}
+			if len(ts) == 0 {
+				break
+			}
+			for _, t := range ts {
+				if err := s.TaskService.DeleteTask(ctx, t.ID); err != nil {
+					return err
+				}
+				purged = append(purged, t.ID)
+				d.Resources["tasks"]++
+			}
+		}
+	}
+
+	dashboards, telegrafs, err := s.exclusiveResources(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	if s.DashboardService != nil {
+		for _, id := range dashboards {
+			if err := s.DashboardService.DeleteDashboard(ctx, id); err != nil {
+				return err
+			}
+			purged = append(purged, id)
+			d.Resources["dashboards"]++
+		}
+	}
+	if s.TelegrafService != nil {
+		for _, id := range telegrafs {
+			if err := s.TelegrafService.DeleteTelegrafConfig(ctx, id); err != nil {
+				return err
+			}
+			purged = append(purged, id)
+			d.Resources["telegrafs"]++
+		}
+	}
+
+	for _, b := range buckets {
+		if err := s.BucketService.DeleteBucket(ctx, b.ID); err != nil {
+			return err
+		}
+		purged = append(purged, b.ID)
+		d.Resources["buckets"]++
+	}
+
+	if s.SecretService != nil {
+		keys, err := s.SecretService.GetSecretKeys(ctx, o.ID)
+		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := s.SecretService.DeleteSecret(ctx, o.ID, keys...); err != nil {
+				return err
+			}
+			d.Resources["secrets"] += len(keys)
+		}
+	}
+
+	purged = append(purged, o.ID)
+	for _, id := range purged {
+		if err := s.purgeLabels(ctx, id, d); err != nil {
+			return err
+		}
+		if err := s.purgeMappings(ctx, id, d); err != nil {
+			return err
+		}
+	}
+
+	users, _, err := s.UserService.FindUsers(ctx, platform.UserFilter{})
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if u.DefaultOrgID != o.ID {
+			continue
+		}
+		var none platform.ID
+		if _, err := s.UserService.UpdateUser(ctx, u.ID, platform.UserUpdate{DefaultOrgID: &none}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeAuthorizations deletes the authorizations all of whose permissions
+// are on the resources of an organization, as they become useless. The
+// other authorizations are left alone.
+func (s *Service) purgeAuthorizations(ctx context.Context, orgID platform.ID, buckets []*platform.Bucket, d *deletion) error {
+	if s.AuthorizationService == nil {
+		return nil
+	}
+
+	resources := map[string]bool{
+		string(platform.TaskResource(orgID)): true,
+	}
+	for _, b := range buckets {
+		resources[string(platform.BucketResource(b.ID))] = true
+	}
+
+	as, _, err := s.AuthorizationService.FindAuthorizations(ctx, platform.AuthorizationFilter{})
+	if err != nil {
+		return err
+	}
+	for _, a := range as {
+		if len(a.Permissions) == 0 {
+			continue
+		}
+		scoped := true
+		for _, p := range a.Permissions {
+			if !resources[string(p.Resource)] {
+				scoped = false
+				break
+			}
+		}
+		if !scoped {
+			continue
+		}
+		if err := s.AuthorizationService.DeleteAuthorization(ctx, a.ID); err != nil {
+			return err
+		}
+		d.Resources["authorizations"]++
+	}
+	return nil
+}
+
+// exclusiveResources returns the IDs of the dashboards and telegraf configs
+// of an organization. As they are mapped to users rather than to
+// organizations, those are the ones mapped only to users who are members of
+// no other organization.
+func (s *Service) exclusiveResources(ctx context.Context, orgID platform.ID) (dashboards, telegrafs []platform.ID, err error) {
+	members, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceType: platform.OrgResourceType,
+		ResourceID:   orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exclusive := map[platform.ID]bool{}
+	var candidates []*platform.UserResourceMapping
+	for _, m := range members {
+		if m.Principal() != platform.UserPrincipal {
+			continue
+		}
+		ms, err := platform.UserMappings(ctx, s.UserResourceMappingService, m.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		only := true
+		for _, um := range ms {
+			if um.ResourceType == platform.OrgResourceType && um.ResourceID != orgID {
+				only = false
+				break
+			}
+		}
+		if !only {
+			continue
+		}
+		exclusive[m.UserID] = true
+		for _, um := range ms {
+			if um.UserID == m.UserID {
+				candidates = append(candidates, um)
+			}
+		}
+	}
+
+	seen := map[platform.ID]bool{}
+	for _, c := range candidates {
+		if seen[c.ResourceID] {
+			continue
+		}
+		if c.ResourceType != platform.DashboardResourceType && c.ResourceType != platform.TelegrafResourceType {
+			continue
+		}
+		seen[c.ResourceID] = true
+
+		ms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+			ResourceID: c.ResourceID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		shared := false
+		for _, m := range ms {
+			if m.Principal() != platform.UserPrincipal || !exclusive[m.UserID] {
+				shared = true
+				break
+			}
+		}
+		if shared {
+			continue
+		}
+
+		if c.ResourceType == platform.DashboardResourceType {
+			dashboards = append(dashboards, c.ResourceID)
+		} else {
+			telegrafs = append(telegrafs, c.ResourceID)
+		}
+	}
+	return dashboards, telegrafs, nil
+}
+
+func (s *Service) purgeLabels(ctx context.Context, resourceID platform.ID, d *deletion) error {
+	if s.LabelService == nil {
+		return nil
+	}
+	ls, err := s.LabelService.FindLabels(ctx, platform.LabelFilter{ResourceID: resourceID})
+	if err != nil {
+		return err
+	}
+	for _, l := range ls {
+		if err := s.LabelService.DeleteLabel(ctx, *l); err != nil {
+			return err
+		}
+		d.Resources["labels"]++
+	}
+	return nil
+}
+
+func (s *Service) purgeMappings(ctx context.Context, resourceID platform.ID, d *deletion) error {
+	ms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceID: resourceID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, m.ResourceID, m.UserID); err != nil {
+			return err
+		}
+		d.Resources["userResourceMappings"]++
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/influxdata/platform/bootstrap"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/spf13/cobra"
+)
+
+// apply Command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create and update users, orgs, buckets, tasks and tokens from a bootstrap config",
+	Run:   applyF,
+}
+
+var applyFlags struct {
+	file   string
+	dryRun bool
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFlags.file, "file", "f", "", "path to the bootstrap config, in YAML, JSON or TOML")
+	applyCmd.Flags().BoolVarP(&applyFlags.dryRun, "dry-run", "", false, "only show the changes that would be made")
+	applyCmd.MarkFlagRequired("file")
+}
+
+func applyF(cmd *cobra.Command, args []string) {
+	if flags.local {
+		fmt.Println("Local flag not supported for apply command")
+		os.Exit(1)
+	}
+
+	c, err := bootstrap.LoadConfig(applyFlags.file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	s := &http.BootstrapService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	changes, err := s.Apply(context.Background(), c, applyFlags.dryRun)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Action",
+		"Kind",
+		"Name",
+		"Detail",
+		"Token",
+	)
+	for _, change := range changes {
+		w.Write(map[string]interface{}{
+			"Action": string(change.Action),
+			"Kind":   change.Kind,
+			"Name":   change.Name,
+			"Detail": change.Detail,
+			"Token":  change.Token,
+		})
+	}
+	w.Flush()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
@@ -31,6 +32,8 @@ type AuthorizationCreateFlags struct {
 
 	readBucketPermissions  []string
 	writeBucketPermissions []string
+
+	expiresIn time.Duration
 }
 
 var authorizationCreateFlags AuthorizationCreateFlags
@@ -51,6 +54,8 @@ func init() {
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.readBucketPermissions, "read-bucket", "", []string{}, "bucket id")
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.writeBucketPermissions, "write-bucket", "", []string{}, "bucket id")
 
+	authorizationCreateCmd.Flags().DurationVarP(&authorizationCreateFlags.expiresIn, "expires-in", "", 0, "duration after which the token expires, e.g. 720h")
+
 	authorizationCmd.AddCommand(authorizationCreateCmd)
 }
 
@@ -84,6 +89,10 @@ func authorizationCreateF(cmd *cobra.Command, args []string) {
 		User:        authorizationCreateFlags.user,
 		Permissions: permissions,
 	}
+	if authorizationCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authorizationCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
 
 	s, err := newAuthorizationService(flags)
 	if err != nil {
@@ -104,6 +113,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) {
 		"User",
 		"UserID",
 		"Permissions",
+		"ExpiresAt",
 	)
 
 	ps := []string{}
@@ -118,6 +128,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) {
 		"User":        authorization.User,
 		"UserID":      authorization.UserID.String(),
 		"Permissions": ps,
+		"ExpiresAt":   formatAuthorizationTime(authorization.ExpiresAt),
 	})
 	w.Flush()
 }
@@ -127,6 +138,9 @@ type AuthorizationFindFlags struct {
 	user   string
 	userID string
 	id     string
+
+	expired     bool
+	unusedSince time.Duration
 }
 
 var authorizationFindFlags AuthorizationFindFlags
@@ -141,6 +155,8 @@ func init() {
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.user, "user", "u", "", "user")
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.userID, "user-id", "", "", "user ID")
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.id, "id", "i", "", "authorization ID")
+	authorizationFindCmd.Flags().BoolVarP(&authorizationFindFlags.expired, "expired", "", false, "only find expired authorizations")
+	authorizationFindCmd.Flags().DurationVarP(&authorizationFindFlags.unusedSince, "unused-since", "", 0, "only find authorizations unused for this duration, e.g. 720h")
 
 	authorizationCmd.AddCommand(authorizationFindCmd)
 }
@@ -192,6 +208,11 @@ func authorizationFindF(cmd *cobra.Command, args []string) {
 		}
 		filter.UserID = uID
 	}
+	filter.Expired = authorizationFindFlags.expired
+	if authorizationFindFlags.unusedSince > 0 {
+		since := time.Now().Add(-authorizationFindFlags.unusedSince)
+		filter.UnusedSince = &since
+	}
 
 	authorizations, _, err := s.FindAuthorizations(context.Background(), filter)
 	if err != nil {
@@ -207,6 +228,8 @@ func authorizationFindF(cmd *cobra.Command, args []string) {
 		"User",
 		"UserID",
 		"Permissions",
+		"ExpiresAt",
+		"LastUsedAt",
 	)
 
 	for _, a := range authorizations {
@@ -222,6 +245,8 @@ func authorizationFindF(cmd *cobra.Command, args []string) {
 			"User":        a.User,
 			"UserID":      a.UserID.String(),
 			"Permissions": permissions,
+			"ExpiresAt":   formatAuthorizationTime(a.ExpiresAt),
+			"LastUsedAt":  formatAuthorizationTime(a.LastUsedAt),
 		})
 	}
 	w.Flush()
@@ -439,3 +464,72 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) {
 	})
 	w.Flush()
 }
+
+// AuthorizationRotateFlags are command line args used when rotating an authorization
+type AuthorizationRotateFlags struct {
+	id    string
+	grace time.Duration
+}
+
+var authorizationRotateFlags AuthorizationRotateFlags
+
+func init() {
+	authorizationRotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Issue a new token for an authorization",
+		Run:   authorizationRotateF,
+	}
+
+	authorizationRotateCmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "authorization id (required)")
+	authorizationRotateCmd.Flags().DurationVarP(&authorizationRotateFlags.grace, "grace", "", 0, "duration the previous token remains valid, e.g. 1h")
+	authorizationRotateCmd.MarkFlagRequired("id")
+
+	authorizationCmd.AddCommand(authorizationRotateCmd)
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) {
+	s, err := newAuthorizationService(flags)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authorizationRotateFlags.id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, authorizationRotateFlags.grace)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Token",
+		"Status",
+		"User",
+		"UserID",
+		"PreviousTokenExpiresAt",
+	)
+
+	w.Write(map[string]interface{}{
+		"ID":                     a.ID.String(),
+		"Token":                  a.Token,
+		"Status":                 a.Status,
+		"User":                   a.User,
+		"UserID":                 a.UserID.String(),
+		"PreviousTokenExpiresAt": formatAuthorizationTime(a.PreviousTokenExpiresAt),
+	})
+	w.Flush()
+}
+
+func formatAuthorizationTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
 	"github.com/influxdata/platform/cmd/influx/internal"
+	platcontext "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/http"
 	"github.com/influxdata/platform/internal/fs"
 	"github.com/spf13/cobra"
@@ -128,10 +129,11 @@ func bucketCreateF(cmd *cobra.Command, args []string) {
 
 // BucketFindFlags define the Find Command
 type BucketFindFlags struct {
-	name  string
-	id    string
-	org   string
-	orgID string
+	name    string
+	id      string
+	org     string
+	orgID   string
+	deleted bool
 }
 
 var bucketFindFlags BucketFindFlags
@@ -147,6 +149,7 @@ func init() {
 	bucketFindCmd.Flags().StringVarP(&bucketFindFlags.id, "id", "i", "", "bucket ID")
 	bucketFindCmd.Flags().StringVarP(&bucketFindFlags.orgID, "org-id", "", "", "bucket organization ID")
 	bucketFindCmd.Flags().StringVarP(&bucketFindFlags.org, "org", "o", "", "bucket organization name")
+	bucketFindCmd.Flags().BoolVarP(&bucketFindFlags.deleted, "deleted", "", false, "find the deleted buckets that can be restored instead")
 
 	bucketCmd.AddCommand(bucketFindCmd)
 }
@@ -191,37 +194,51 @@ func bucketFindF(cmd *cobra.Command, args []string) {
 		filter.Organization = &bucketFindFlags.org
 	}
 
-	buckets, _, err := s.FindBuckets(context.Background(), filter)
+	var buckets []*platform.Bucket
+	if bucketFindFlags.deleted {
+		buckets, err = newBucketLifecycleService().FindDeletedBuckets(context.Background(), filter)
+	} else {
+		buckets, _, err = s.FindBuckets(context.Background(), filter)
+	}
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
 	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
+	headers := []string{
 		"ID",
 		"Name",
 		"Retention",
 		"Organization",
 		"OrganizationID",
-	)
+	}
+	if bucketFindFlags.deleted {
+		headers = append(headers, "DeletedAt")
+	}
+	w.WriteHeaders(headers...)
 	for _, b := range buckets {
-		w.Write(map[string]interface{}{
+		row := map[string]interface{}{
 			"ID":             b.ID.String(),
 			"Name":           b.Name,
 			"Retention":      b.RetentionPeriod,
 			"Organization":   b.Organization,
 			"OrganizationID": b.OrganizationID.String(),
-		})
+		}
+		if b.DeletedAt != nil {
+			row["DeletedAt"] = b.DeletedAt.Format(time.RFC3339)
+		}
+		w.Write(row)
 	}
 	w.Flush()
 }
 
 // BucketUpdateFlags define the Update Command
 type BucketUpdateFlags struct {
-	id        string
-	name      string
-	retention time.Duration
+	id         string
+	name       string
+	retention  time.Duration
+	references string
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -236,6 +253,7 @@ func init() {
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.id, "id", "i", "", "bucket ID (required)")
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.name, "name", "n", "", "new bucket name")
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "new duration data will live in bucket")
+	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.references, "references", "", "", "what renaming does about the resources referring to the bucket: check, rewrite or ignore (default check)")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -262,7 +280,8 @@ func bucketUpdateF(cmd *cobra.Command, args []string) {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
 
-	b, err := s.UpdateBucket(context.Background(), id, update)
+	ctx := bucketReferencesContext(bucketUpdateFlags.references)
+	b, err := s.UpdateBucket(ctx, id, update)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -288,7 +307,8 @@ func bucketUpdateF(cmd *cobra.Command, args []string) {
 
 // BucketDeleteFlags define the Delete command
 type BucketDeleteFlags struct {
-	id string
+	id         string
+	references string
 }
 
 var bucketDeleteFlags BucketDeleteFlags
@@ -306,7 +326,7 @@ func bucketDeleteF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	ctx := context.TODO()
+	ctx := bucketReferencesContext(bucketDeleteFlags.references)
 	b, err := s.FindBucketByID(ctx, id)
 	if err != nil {
 		fmt.Println(err)
@@ -341,12 +361,128 @@ func bucketDeleteF(cmd *cobra.Command, args []string) {
 func init() {
 	bucketDeleteCmd := &cobra.Command{
 		Use:   "delete",
-		Short: "Delete bucket",
+		Short: "Delete bucket, it can be restored until it is purged with its data",
 		Run:   bucketDeleteF,
 	}
 
 	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.id, "id", "i", "", "bucket id (required)")
+	bucketDeleteCmd.Flags().StringVarP(&bucketDeleteFlags.references, "references", "", "", "what deleting does about the resources referring to the bucket: check or ignore (default check)")
 	bucketDeleteCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketDeleteCmd)
 }
+
+// bucketReferencesContext returns the context renaming or deleting a bucket
+// with a reference policy, if any.
+func bucketReferencesContext(policy string) context.Context {
+	ctx := context.Background()
+	if policy == "" {
+		return ctx
+	}
+	p := platform.BucketReferencePolicy(policy)
+	if err := p.Valid(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return platcontext.SetBucketReferencePolicy(ctx, p)
+}
+
+func newBucketLifecycleService() platform.BucketLifecycleService {
+	if flags.local {
+		fmt.Println("Local flag not supported for bucket deletions")
+		os.Exit(1)
+	}
+	return &http.BucketService{
+		Addr:     flags.host,
+		Token:    flags.token,
+		OpPrefix: bolt.OpPrefix,
+	}
+}
+
+// Restore and references commands
+var bucketLifecycleFlags struct {
+	id string
+}
+
+func bucketLifecycleID() platform.ID {
+	var id platform.ID
+	if err := id.DecodeFromString(bucketLifecycleFlags.id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return id
+}
+
+func bucketRestoreF(cmd *cobra.Command, args []string) {
+	s := newBucketLifecycleService()
+	b, err := s.RestoreBucket(context.Background(), bucketLifecycleID())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Retention",
+		"Organization",
+		"OrganizationID",
+	)
+	w.Write(map[string]interface{}{
+		"ID":             b.ID.String(),
+		"Name":           b.Name,
+		"Retention":      b.RetentionPeriod,
+		"Organization":   b.Organization,
+		"OrganizationID": b.OrganizationID.String(),
+	})
+	w.Flush()
+}
+
+func bucketReferencesF(cmd *cobra.Command, args []string) {
+	s := newBucketLifecycleService()
+	refs, err := s.FindBucketReferences(context.Background(), bucketLifecycleID())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Kind",
+		"ID",
+		"Name",
+	)
+	for _, r := range refs {
+		id := ""
+		if r.ID.Valid() {
+			id = r.ID.String()
+		}
+		w.Write(map[string]interface{}{
+			"Kind": string(r.Kind),
+			"ID":   id,
+			"Name": r.Name,
+		})
+	}
+	w.Flush()
+}
+
+func init() {
+	bucketRestoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a deleted bucket that was not purged yet",
+		Run:   bucketRestoreF,
+	}
+	bucketRestoreCmd.Flags().StringVarP(&bucketLifecycleFlags.id, "id", "i", "", "bucket id (required)")
+	bucketRestoreCmd.MarkFlagRequired("id")
+
+	bucketReferencesCmd := &cobra.Command{
+		Use:   "references",
+		Short: "List the tasks, telegraf configs, scraper targets and DBRP mappings referring to a bucket",
+		Run:   bucketReferencesF,
+	}
+	bucketReferencesCmd.Flags().StringVarP(&bucketLifecycleFlags.id, "id", "i", "", "bucket id (required)")
+	bucketReferencesCmd.MarkFlagRequired("id")
+
+	bucketCmd.AddCommand(bucketRestoreCmd, bucketReferencesCmd)
+}
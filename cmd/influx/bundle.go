@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bundle"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/spf13/cobra"
+)
+
+// export Command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the resources of an organization as a bundle",
+	Run:   exportF,
+}
+
+var exportFlags struct {
+	org   string
+	orgID string
+	file  string
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFlags.org, "org", "o", "", "name of the organization to export")
+	exportCmd.Flags().StringVarP(&exportFlags.orgID, "org-id", "", "", "id of the organization to export")
+	exportCmd.Flags().StringVarP(&exportFlags.file, "file", "f", "", "path to write the bundle to; defaults to stdout")
+}
+
+func exportF(cmd *cobra.Command, args []string) {
+	if flags.local {
+		fmt.Println("Local flag not supported for export command")
+		os.Exit(1)
+	}
+
+	orgID, err := bundleOrgID(exportFlags.org, exportFlags.orgID)
+	if err != nil {
+		fmt.Println(err)
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	s := &http.BundleService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	b, err := s.Export(context.Background(), orgID)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	octets, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	octets = append(octets, '\n')
+
+	if exportFlags.file == "" {
+		os.Stdout.Write(octets)
+		return
+	}
+	if err := ioutil.WriteFile(exportFlags.file, octets, 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// import Command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a bundle into an organization",
+	Run:   importF,
+}
+
+var importFlags struct {
+	org      string
+	orgID    string
+	file     string
+	conflict string
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importFlags.org, "org", "o", "", "name of the organization to import into")
+	importCmd.Flags().StringVarP(&importFlags.orgID, "org-id", "", "", "id of the organization to import into")
+	importCmd.Flags().StringVarP(&importFlags.file, "file", "f", "", "path to the bundle")
+	importCmd.Flags().StringVarP(&importFlags.conflict, "conflict", "", string(bundle.Skip), "what to do with resources named like existing ones: skip, overwrite or rename")
+	importCmd.MarkFlagRequired("file")
+}
+
+func importF(cmd *cobra.Command, args []string) {
+	if flags.local {
+		fmt.Println("Local flag not supported for import command")
+		os.Exit(1)
+	}
+
+	orgID, err := bundleOrgID(importFlags.org, importFlags.orgID)
+	if err != nil {
+		fmt.Println(err)
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	octets, err := ioutil.ReadFile(importFlags.file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	b := new(bundle.Bundle)
+	if err := json.Unmarshal(octets, b); err != nil {
+		fmt.Printf("invalid bundle %s: %v\n", importFlags.file, err)
+		os.Exit(1)
+	}
+
+	s := &http.BundleService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	rs, err := s.Import(context.Background(), b, bundle.ImportOptions{
+		OrgID:    orgID,
+		Conflict: bundle.ConflictStrategy(importFlags.conflict),
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Kind",
+		"Name",
+		"Action",
+		"ID",
+	)
+	for _, r := range rs {
+		w.Write(map[string]interface{}{
+			"Kind":   r.Kind,
+			"Name":   r.Name,
+			"Action": string(r.Action),
+			"ID":     r.ID.String(),
+		})
+	}
+	w.Flush()
+}
+
+// bundleOrgID returns the ID of the organization given by exactly one of
+// its name or ID.
+func bundleOrgID(org, orgID string) (platform.ID, error) {
+	if (org == "") == (orgID == "") {
+		return 0, fmt.Errorf("must specify exactly one of org or org-id")
+	}
+	if org != "" {
+		return findOrgID(context.Background(), org)
+	}
+	id, err := platform.IDFromString(orgID)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing organization id: %v", err)
+	}
+	return *id, nil
+}
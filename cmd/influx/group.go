@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/spf13/cobra"
+)
+
+// Group Command
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Group related commands",
+	Run:   groupF,
+}
+
+func groupF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newGroupService(f Flags) *http.GroupService {
+	return &http.GroupService{
+		Addr:     f.host,
+		Token:    f.token,
+		OpPrefix: bolt.OpPrefix,
+	}
+}
+
+func mustDecodeID(s string) platform.ID {
+	var id platform.ID
+	if err := id.DecodeFromString(s); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return id
+}
+
+func groupRole(owner bool) platform.UserType {
+	if owner {
+		return platform.Owner
+	}
+	return platform.Member
+}
+
+func writeGroups(gs ...*platform.Group) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Description",
+	)
+	for _, g := range gs {
+		w.Write(map[string]interface{}{
+			"ID":             g.ID.String(),
+			"Name":           g.Name,
+			"OrganizationID": g.OrganizationID.String(),
+			"Description":    g.Description,
+		})
+	}
+	w.Flush()
+}
+
+// Create Command
+type GroupCreateFlags struct {
+	name        string
+	orgID       string
+	description string
+}
+
+var groupCreateFlags GroupCreateFlags
+
+func init() {
+	groupCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create group",
+		Run:   groupCreateF,
+	}
+
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.name, "name", "n", "", "name of group that will be created")
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.orgID, "org-id", "o", "", "id of the organization the group belongs to")
+	groupCreateCmd.Flags().StringVarP(&groupCreateFlags.description, "description", "d", "", "description of the group")
+	groupCreateCmd.MarkFlagRequired("name")
+	groupCreateCmd.MarkFlagRequired("org-id")
+
+	groupCmd.AddCommand(groupCreateCmd)
+}
+
+func groupCreateF(cmd *cobra.Command, args []string) {
+	g := &platform.Group{
+		Name:           groupCreateFlags.name,
+		OrganizationID: mustDecodeID(groupCreateFlags.orgID),
+		Description:    groupCreateFlags.description,
+	}
+
+	if err := newGroupService(flags).CreateGroup(context.Background(), g); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	writeGroups(g)
+}
+
+// Find Command
+type GroupFindFlags struct {
+	id    string
+	name  string
+	orgID string
+}
+
+var groupFindFlags GroupFindFlags
+
+func init() {
+	groupFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find groups",
+		Run:   groupFindF,
+	}
+
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.id, "id", "i", "", "group id")
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.name, "name", "n", "", "group name")
+	groupFindCmd.Flags().StringVarP(&groupFindFlags.orgID, "org-id", "o", "", "organization id")
+
+	groupCmd.AddCommand(groupFindCmd)
+}
+
+func groupFindF(cmd *cobra.Command, args []string) {
+	filter := platform.GroupFilter{}
+	if groupFindFlags.id != "" {
+		id := mustDecodeID(groupFindFlags.id)
+		filter.ID = &id
+	}
+	if groupFindFlags.name != "" {
+		filter.Name = &groupFindFlags.name
+	}
+	if groupFindFlags.orgID != "" {
+		orgID := mustDecodeID(groupFindFlags.orgID)
+		filter.OrganizationID = &orgID
+	}
+
+	gs, _, err := newGroupService(flags).FindGroups(context.Background(), filter)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	writeGroups(gs...)
+}
+
+// Update Command
+type GroupUpdateFlags struct {
+	id          string
+	name        string
+	description string
+}
+
+var groupUpdateFlags GroupUpdateFlags
+
+func init() {
+	groupUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update group",
+		Run:   groupUpdateF,
+	}
+
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.id, "id", "i", "", "group id (required)")
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.name, "name", "n", "", "group name")
+	groupUpdateCmd.Flags().StringVarP(&groupUpdateFlags.description, "description", "d", "", "group description")
+	groupUpdateCmd.MarkFlagRequired("id")
+
+	groupCmd.AddCommand(groupUpdateCmd)
+}
+
+func groupUpdateF(cmd *cobra.Command, args []string) {
+	update := platform.GroupUpdate{}
+	if groupUpdateFlags.name != "" {
+		update.Name = &groupUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		update.Description = &groupUpdateFlags.description
+	}
+
+	g, err := newGroupService(flags).UpdateGroup(context.Background(), mustDecodeID(groupUpdateFlags.id), update)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	writeGroups(g)
+}
+
+// Delete Command
+type GroupDeleteFlags struct {
+	id string
+}
+
+var groupDeleteFlags GroupDeleteFlags
+
+func init() {
+	groupDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete group along with its memberships and resource mappings",
+		Run:   groupDeleteF,
+	}
+
+	groupDeleteCmd.Flags().StringVarP(&groupDeleteFlags.id, "id", "i", "", "group id (required)")
+	groupDeleteCmd.MarkFlagRequired("id")
+
+	groupCmd.AddCommand(groupDeleteCmd)
+}
+
+func groupDeleteF(cmd *cobra.Command, args []string) {
+	svc := newGroupService(flags)
+	ctx := context.Background()
+	id := mustDecodeID(groupDeleteFlags.id)
+
+	g, err := svc.FindGroupByID(ctx, id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := svc.DeleteGroup(ctx, id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Deleted",
+	)
+	w.Write(map[string]interface{}{
+		"ID":      g.ID.String(),
+		"Name":    g.Name,
+		"Deleted": true,
+	})
+	w.Flush()
+}
+
+// Member management
+var groupMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "group membership commands",
+	Run:   groupF,
+}
+
+type GroupMembersFlags struct {
+	id       string
+	memberID string
+	owner    bool
+}
+
+var groupMembersFlags GroupMembersFlags
+
+func init() {
+	groupMembersListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List group members",
+		Run:   groupMembersListF,
+	}
+	groupMembersAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add group member",
+		Run:   groupMembersAddF,
+	}
+	groupMembersRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove group member",
+		Run:   groupMembersRemoveF,
+	}
+
+	for _, c := range []*cobra.Command{groupMembersListCmd, groupMembersAddCmd, groupMembersRemoveCmd} {
+		c.Flags().StringVarP(&groupMembersFlags.id, "id", "i", "", "group id (required)")
+		c.Flags().BoolVar(&groupMembersFlags.owner, "owner", false, "operate on the owners of the group rather than its members")
+		c.MarkFlagRequired("id")
+		groupMembersCmd.AddCommand(c)
+	}
+	for _, c := range []*cobra.Command{groupMembersAddCmd, groupMembersRemoveCmd} {
+		c.Flags().StringVarP(&groupMembersFlags.memberID, "member", "m", "", "member id (required)")
+		c.MarkFlagRequired("member")
+	}
+
+	groupCmd.AddCommand(groupMembersCmd)
+}
+
+func groupMembersListF(cmd *cobra.Command, args []string) {
+	users, err := newGroupService(flags).FindMembers(context.Background(), mustDecodeID(groupMembersFlags.id), groupRole(groupMembersFlags.owner))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+	)
+	for _, u := range users {
+		w.Write(map[string]interface{}{
+			"ID":   u.ID.String(),
+			"Name": u.Name,
+		})
+	}
+	w.Flush()
+}
+
+func groupMembersAddF(cmd *cobra.Command, args []string) {
+	err := newGroupService(flags).AddMember(context.Background(),
+		mustDecodeID(groupMembersFlags.id),
+		mustDecodeID(groupMembersFlags.memberID),
+		groupRole(groupMembersFlags.owner),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Member added")
+}
+
+func groupMembersRemoveF(cmd *cobra.Command, args []string) {
+	err := newGroupService(flags).RemoveMember(context.Background(),
+		mustDecodeID(groupMembersFlags.id),
+		mustDecodeID(groupMembersFlags.memberID),
+		groupRole(groupMembersFlags.owner),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Member removed")
+}
+
+// Resource management
+var groupResourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "commands for the resources a group is mapped to",
+	Run:   groupF,
+}
+
+type GroupResourcesFlags struct {
+	id           string
+	resourceID   string
+	resourceType string
+	owner        bool
+}
+
+var groupResourcesFlags GroupResourcesFlags
+
+func init() {
+	groupResourcesListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the resources of a group",
+		Run:   groupResourcesListF,
+	}
+	groupResourcesAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Map a group to a resource",
+		Run:   groupResourcesAddF,
+	}
+	groupResourcesRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove the mapping of a group to a resource",
+		Run:   groupResourcesRemoveF,
+	}
+
+	for _, c := range []*cobra.Command{groupResourcesListCmd, groupResourcesAddCmd, groupResourcesRemoveCmd} {
+		c.Flags().StringVarP(&groupResourcesFlags.id, "id", "i", "", "group id (required)")
+		c.MarkFlagRequired("id")
+		groupResourcesCmd.AddCommand(c)
+	}
+	for _, c := range []*cobra.Command{groupResourcesAddCmd, groupResourcesRemoveCmd} {
+		c.Flags().StringVarP(&groupResourcesFlags.resourceID, "resource", "r", "", "resource id (required)")
+		c.MarkFlagRequired("resource")
+	}
+	groupResourcesAddCmd.Flags().StringVarP(&groupResourcesFlags.resourceType, "type", "t", "", "resource type, e.g. bucket, dashboard or org (required)")
+	groupResourcesAddCmd.Flags().BoolVar(&groupResourcesFlags.owner, "owner", false, "grant the members of the group ownership of the resource")
+	groupResourcesAddCmd.MarkFlagRequired("type")
+
+	groupCmd.AddCommand(groupResourcesCmd)
+}
+
+func groupResourcesListF(cmd *cobra.Command, args []string) {
+	ms, err := newGroupService(flags).FindResources(context.Background(), mustDecodeID(groupResourcesFlags.id))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ResourceID",
+		"ResourceType",
+		"Role",
+	)
+	for _, m := range ms {
+		w.Write(map[string]interface{}{
+			"ResourceID":   m.ResourceID.String(),
+			"ResourceType": m.ResourceType,
+			"Role":         m.UserType,
+		})
+	}
+	w.Flush()
+}
+
+func groupResourcesAddF(cmd *cobra.Command, args []string) {
+	err := newGroupService(flags).AddResource(context.Background(),
+		mustDecodeID(groupResourcesFlags.id),
+		platform.ResourceType(groupResourcesFlags.resourceType),
+		mustDecodeID(groupResourcesFlags.resourceID),
+		groupRole(groupResourcesFlags.owner),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Resource added")
+}
+
+func groupResourcesRemoveF(cmd *cobra.Command, args []string) {
+	err := newGroupService(flags).RemoveResource(context.Background(),
+		mustDecodeID(groupResourcesFlags.id),
+		mustDecodeID(groupResourcesFlags.resourceID),
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Resource removed")
+}
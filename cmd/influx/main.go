@@ -22,11 +22,16 @@ var influxCmd = &cobra.Command{
 }
 
 func init() {
+	influxCmd.AddCommand(applyCmd)
 	influxCmd.AddCommand(authorizationCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(exportCmd)
+	influxCmd.AddCommand(groupCmd)
+	influxCmd.AddCommand(importCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
+	influxCmd.AddCommand(scraperCmd)
 	influxCmd.AddCommand(setupCmd)
 	influxCmd.AddCommand(taskCmd)
 	influxCmd.AddCommand(userCmd)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
@@ -216,25 +217,50 @@ func organizationUpdateF(cmd *cobra.Command, args []string) {
 
 // Delete command
 type OrganizationDeleteFlags struct {
-	id string
+	id    string
+	purge bool
 }
 
 var organizationDeleteFlags OrganizationDeleteFlags
 
 func organizationDeleteF(cmd *cobra.Command, args []string) {
-	orgSvc, err := newOrganizationService(flags)
+	var id platform.ID
+	if err := id.DecodeFromString(organizationDeleteFlags.id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.TODO()
+	if flags.local {
+		organizationDeleteLocal(ctx, id)
+		return
+	}
+
+	s := &http.OrganizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	deleteOrg := s.ScheduleOrganizationDeletion
+	if organizationDeleteFlags.purge {
+		deleteOrg = s.PurgeOrganization
+	}
+	d, err := deleteOrg(ctx, id)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	writeOrganizationDeletion(d)
+}
 
-	var id platform.ID
-	if err := id.DecodeFromString(organizationDeleteFlags.id); err != nil {
+// organizationDeleteLocal deletes the organization from the bolt file,
+// without its resources.
+func organizationDeleteLocal(ctx context.Context, id platform.ID) {
+	orgSvc, err := newOrganizationService(flags)
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	ctx := context.TODO()
 	o, err := orgSvc.FindOrganizationByID(ctx, id)
 	if err != nil {
 		fmt.Println(err)
@@ -260,19 +286,119 @@ func organizationDeleteF(cmd *cobra.Command, args []string) {
 	w.Flush()
 }
 
+func writeOrganizationDeletion(d *platform.OrganizationDeletion) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Status",
+		"PurgeAt",
+		"SeriesDeleted",
+		"Error",
+	)
+	w.Write(map[string]interface{}{
+		"ID":            d.OrgID.String(),
+		"Name":          d.OrgName,
+		"Status":        string(d.Status),
+		"PurgeAt":       d.PurgeAt.Format(time.RFC3339),
+		"SeriesDeleted": d.SeriesDeleted,
+		"Error":         d.Error,
+	})
+	w.Flush()
+}
+
 func init() {
 	organizationDeleteCmd := &cobra.Command{
 		Use:   "delete",
-		Short: "Delete organization",
+		Short: "Delete organization, it can be restored until it is purged with all its resources",
 		Run:   organizationDeleteF,
 	}
 
 	organizationDeleteCmd.Flags().StringVarP(&organizationDeleteFlags.id, "id", "i", "", "organization id (required)")
+	organizationDeleteCmd.Flags().BoolVarP(&organizationDeleteFlags.purge, "purge", "", false, "purge the organization with all its resources right away")
 	organizationDeleteCmd.MarkFlagRequired("id")
 
 	organizationCmd.AddCommand(organizationDeleteCmd)
 }
 
+// Restore and deletion commands
+var organizationDeletionFlags struct {
+	id string
+}
+
+func organizationDeletionID() platform.ID {
+	if flags.local {
+		fmt.Println("Local flag not supported for organization deletions")
+		os.Exit(1)
+	}
+	var id platform.ID
+	if err := id.DecodeFromString(organizationDeletionFlags.id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return id
+}
+
+func organizationRestoreF(cmd *cobra.Command, args []string) {
+	id := organizationDeletionID()
+	s := &http.OrganizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	o, err := s.RestoreOrganization(context.Background(), id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+	)
+	w.Write(map[string]interface{}{
+		"ID":   o.ID.String(),
+		"Name": o.Name,
+	})
+	w.Flush()
+}
+
+func organizationDeletionF(cmd *cobra.Command, args []string) {
+	id := organizationDeletionID()
+	s := &http.OrganizationService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	d, err := s.FindOrganizationDeletion(context.Background(), id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	writeOrganizationDeletion(d)
+}
+
+func init() {
+	organizationRestoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a deleted organization that was not purged yet",
+		Run:   organizationRestoreF,
+	}
+	organizationRestoreCmd.Flags().StringVarP(&organizationDeletionFlags.id, "id", "i", "", "organization id (required)")
+	organizationRestoreCmd.MarkFlagRequired("id")
+
+	organizationDeletionCmd := &cobra.Command{
+		Use:   "deletion",
+		Short: "Show the progress of the deletion of an organization",
+		Run:   organizationDeletionF,
+	}
+	organizationDeletionCmd.Flags().StringVarP(&organizationDeletionFlags.id, "id", "i", "", "organization id (required)")
+	organizationDeletionCmd.MarkFlagRequired("id")
+
+	organizationCmd.AddCommand(organizationRestoreCmd, organizationDeletionCmd)
+}
+
 // Member management
 var organizationMembersCmd = &cobra.Command{
 	Use:   "members",
@@ -1,36 +1,74 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/query"
 	_ "github.com/influxdata/platform/query/builtin"
+	"github.com/influxdata/platform/query/export"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// Output formats of the query command.
+const (
+	tableFormat        = "table"
+	csvFormat          = "csv"
+	rawCSVFormat       = "raw-csv"
+	jsonLinesFormat    = "jsonl"
+	lineProtocolFormat = "lp"
+)
+
 var queryCmd = &cobra.Command{
 	Use:   "query [query literal or @/path/to/query.flux]",
 	Short: "Execute an Flux query",
 	Long: `Execute a literal Flux query provided as a string,
-		or execute a literal Flux query contained in a file by specifying the file prefixed with an @ sign.`,
+		or execute a literal Flux query contained in a file by specifying the file prefixed with an @ sign.
+
+		The results are printed as tables by default; --format exports them instead as
+		annotated CSV (csv), CSV without annotations (raw-csv), one JSON object per row (jsonl)
+		or line protocol (lp), to standard output or to the file given by --out.
+
+		Parameters given by --param key=value are available to the query as params.key.
+		Numbers, durations, date times and booleans keep their type, any other value is a string.
+
+		The list and kill commands show and cancel the queries in flight.`,
 	Args: cobra.ExactArgs(1),
 	Run:  fluxQueryF,
 }
 
 var queryFlags struct {
-	OrgID string
+	OrgID  string
+	Format string
+	Out    string
+	Params []string
 }
 
 func init() {
-	queryCmd.PersistentFlags().StringVar(&queryFlags.OrgID, "org-id", "", "Organization ID")
+	// the flags aren't persistent, so that the list and kill commands don't
+	// require them.
+	queryCmd.Flags().StringVar(&queryFlags.OrgID, "org-id", "", "Organization ID")
 	viper.BindEnv("ORG_ID")
 	if h := viper.GetString("ORG_ID"); h != "" {
 		queryFlags.OrgID = h
 	}
-	queryCmd.MarkPersistentFlagRequired("org-id")
+	queryCmd.MarkFlagRequired("org-id")
+
+	queryCmd.Flags().StringVar(&queryFlags.Format, "format", tableFormat, "Output format: table, csv, raw-csv, jsonl or lp")
+	queryCmd.Flags().StringVar(&queryFlags.Out, "out", "", "File to write the results to instead of standard output")
+	queryCmd.Flags().StringArrayVar(&queryFlags.Params, "param", []string{}, "Query parameter as key=value, available to the query as params.key")
 }
 
 func fluxQueryF(cmd *cobra.Command, args []string) {
@@ -39,12 +77,34 @@ func fluxQueryF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	switch queryFlags.Format {
+	case tableFormat:
+		if queryFlags.Out != "" {
+			fmt.Fprintln(os.Stderr, "--out requires a format other than table")
+			os.Exit(1)
+		}
+	case csvFormat, rawCSVFormat, jsonLinesFormat, lineProtocolFormat:
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", queryFlags.Format)
+		os.Exit(1)
+	}
+
 	q, err := repl.LoadQuery(args[0])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
+	params, err := queryParams(queryFlags.Params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if q, err = query.WithParams(q, params); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	var orgID platform.ID
 	err = orgID.DecodeFromString(queryFlags.OrgID)
 	if err != nil {
@@ -52,6 +112,14 @@ func fluxQueryF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if queryFlags.Format != tableFormat {
+		if err := exportQuery(context.Background(), orgID, q); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	r, err := getFluxREPL(flags.host, flags.token, orgID)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -63,3 +131,164 @@ func fluxQueryF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+// queryParams parses the key=value query parameters.
+func queryParams(kvs []string) (map[string]string, error) {
+	params := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("query parameter %q must be key=value", kv)
+		}
+		params[kv[:i]] = kv[i+1:]
+	}
+	return params, nil
+}
+
+// exportQuery writes the results of q in the format of the flags to the out
+// file, or to standard output.
+func exportQuery(ctx context.Context, orgID platform.ID, q string) error {
+	var w io.Writer = os.Stdout
+	if queryFlags.Out != "" {
+		f, err := os.Create(queryFlags.Out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	req := query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: q},
+	}
+
+	switch queryFlags.Format {
+	case csvFormat, rawCSVFormat:
+		dialect := csv.DefaultDialect()
+		if queryFlags.Format == rawCSVFormat {
+			dialect.ResultEncoderConfig.Annotations = nil
+		}
+		s := &http.FluxService{
+			Addr:  flags.host,
+			Token: flags.token,
+		}
+		_, err := s.Query(ctx, w, &query.ProxyRequest{
+			Request: req,
+			Dialect: dialect,
+		})
+		return err
+	default:
+		var enc flux.MultiResultEncoder = export.NewJSONLinesEncoder()
+		if queryFlags.Format == lineProtocolFormat {
+			enc = export.NewLineProtocolEncoder()
+		}
+		s := &http.FluxQueryService{
+			Addr:  flags.host,
+			Token: flags.token,
+		}
+		results, err := s.Query(ctx, &req)
+		if err != nil {
+			return err
+		}
+		_, err = enc.Encode(w, results)
+		return err
+	}
+}
+
+var queryListFlags struct {
+	orgID string
+}
+
+func init() {
+	queryListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the queries queued or executing",
+		Args:  cobra.NoArgs,
+		Run:   queryListF,
+	}
+	queryListCmd.Flags().StringVar(&queryListFlags.orgID, "org-id", "", "Only list the queries of this organization")
+	queryCmd.AddCommand(queryListCmd)
+
+	queryKillCmd := &cobra.Command{
+		Use:   "kill [query ID]",
+		Short: "Cancel a query queued or executing",
+		Args:  cobra.ExactArgs(1),
+		Run:   queryKillF,
+	}
+	queryCmd.AddCommand(queryKillCmd)
+}
+
+func queryListF(cmd *cobra.Command, args []string) {
+	s := &http.ActiveQueryService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	filter := query.ActiveQueryFilter{}
+	if queryListFlags.orgID != "" {
+		id, err := platform.IDFromString(queryListFlags.orgID)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		filter.OrganizationID = id
+	}
+
+	qs, err := s.FindActiveQueries(context.Background(), filter)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Organization",
+		"User",
+		"Token",
+		"State",
+		"Started",
+		"Memory",
+		"Query",
+	)
+	for _, q := range qs {
+		w.Write(map[string]interface{}{
+			"ID":           q.ID.String(),
+			"Organization": q.OrganizationID.String(),
+			"User":         optionalID(q.UserID),
+			"Token":        optionalID(q.AuthorizationID),
+			"State":        q.State,
+			"Started":      q.StartedAt.Format(time.RFC3339),
+			"Memory":       formatBytes(q.MemoryBytes),
+			"Query":        strings.Join(strings.Fields(q.Query), " "),
+		})
+	}
+	w.Flush()
+}
+
+// optionalID formats an ID which may be unset.
+func optionalID(id platform.ID) string {
+	if !id.Valid() {
+		return ""
+	}
+	return id.String()
+}
+
+func queryKillF(cmd *cobra.Command, args []string) {
+	s := &http.ActiveQueryService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	id, err := platform.IDFromString(args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := s.CancelActiveQuery(context.Background(), *id); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Query %s canceled\n", id)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+// Scraper Command
+var scraperCmd = &cobra.Command{
+	Use:   "scraper",
+	Short: "Scraper target related commands",
+	Run:   scraperF,
+}
+
+func scraperF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+// scraperService is the set of scraper operations used by the CLI.
+type scraperService interface {
+	platform.ScraperTargetStoreService
+	GetTargetStatus(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error)
+}
+
+func newScraperService(f Flags) (scraperService, error) {
+	if flags.local {
+		boltFile, err := fs.BoltFile()
+		if err != nil {
+			return nil, err
+		}
+		c := bolt.NewClient()
+		c.Path = boltFile
+		if err := c.Open(context.Background()); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+	return &http.ScraperService{
+		Addr:     flags.host,
+		Token:    flags.token,
+		OpPrefix: bolt.OpPrefix,
+	}, nil
+}
+
+// Find Command
+type ScraperFindFlags struct {
+	id string
+}
+
+var scraperFindFlags ScraperFindFlags
+
+func init() {
+	scraperFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find scraper targets and the status of their last scrape",
+		Run:   scraperFindF,
+	}
+
+	scraperFindCmd.Flags().StringVarP(&scraperFindFlags.id, "id", "i", "", "scraper target ID")
+
+	scraperCmd.AddCommand(scraperFindCmd)
+}
+
+func scraperFindF(cmd *cobra.Command, args []string) {
+	s, err := newScraperService(flags)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	var targets []platform.ScraperTarget
+	if scraperFindFlags.id != "" {
+		id, err := platform.IDFromString(scraperFindFlags.id)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		target, err := s.GetTargetByID(ctx, *id)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		targets = append(targets, *target)
+	} else {
+		targets, err = s.ListTargets(ctx)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Type",
+		"URL",
+		"Organization",
+		"Bucket",
+		"Health",
+		"LastScrape",
+		"Duration",
+		"Samples",
+		"LastError",
+	)
+	for _, t := range targets {
+		status, err := s.GetTargetStatus(ctx, t.ID)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		lastScrape := ""
+		if !status.LastScrape.IsZero() {
+			lastScrape = status.LastScrape.String()
+		}
+		w.Write(map[string]interface{}{
+			"ID":           t.ID.String(),
+			"Name":         t.Name,
+			"Type":         string(t.Type),
+			"URL":          t.URL,
+			"Organization": t.OrgName,
+			"Bucket":       t.BucketName,
+			"Health":       string(status.Health),
+			"LastScrape":   lastScrape,
+			"Duration":     status.LastScrapeDuration,
+			"Samples":      status.SampleCount,
+			"LastError":    status.LastError,
+		})
+	}
+	w.Flush()
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/influxdata/flux/repl"
 	"github.com/influxdata/platform"
@@ -409,157 +412,94 @@ func taskDeleteF(cmd *cobra.Command, args []string) {
 	w.Flush()
 }
 
-// taskLogFindFlags define the Delete command
-type TaskLogFindFlags struct {
-	taskID string
-	runID  string
-	orgID  string
+// taskRunFlags are the flags shared by the run and log commands.
+type taskRunFlags struct {
+	taskID     string
+	runID      string
+	orgID      string
+	afterTime  string
+	beforeTime string
+	json       bool
 }
 
-var taskLogFindFlags TaskLogFindFlags
-
-func init() {
-	taskLogFindCmd := &cobra.Command{
-		Use:   "find",
-		Short: "find logs for task",
-		Run:   taskLogFindF,
+// register adds the flags to cmd, along with the time range ones if
+// withRange is set.
+func (f *taskRunFlags) register(cmd *cobra.Command, withRange bool) {
+	cmd.Flags().StringVarP(&f.taskID, "task-id", "", "", "task id (required)")
+	cmd.Flags().StringVarP(&f.orgID, "org-id", "", "", "organization id")
+	if withRange {
+		cmd.Flags().StringVarP(&f.afterTime, "after", "", "", "only after this RFC3339 time")
+		cmd.Flags().StringVarP(&f.beforeTime, "before", "", "", "only before this RFC3339 time")
 	}
-
-	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.taskID, "task-id", "", "", "task id (required)")
-	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.runID, "run-id", "", "", "run id")
-	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.orgID, "org-id", "", "", "organization id")
-	taskLogFindCmd.MarkFlagRequired("task-id")
-
-	logCmd.AddCommand(taskLogFindCmd)
+	cmd.Flags().BoolVarP(&f.json, "json", "", false, "output as JSON")
+	cmd.MarkFlagRequired("task-id")
 }
 
-func taskLogFindF(cmd *cobra.Command, args []string) {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
-
-	var filter platform.LogFilter
-	id, err := platform.IDFromString(taskLogFindFlags.taskID)
-	if err != nil {
+func (f *taskRunFlags) ids() (taskID platform.ID, runID, orgID *platform.ID) {
+	if err := taskID.DecodeFromString(f.taskID); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	filter.Task = id
-
-	if taskLogFindFlags.runID != "" {
-		id, err := platform.IDFromString(taskLogFindFlags.runID)
+	if f.runID != "" {
+		id, err := platform.IDFromString(f.runID)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		filter.Run = id
+		runID = id
 	}
-
-	if taskLogFindFlags.orgID != "" {
-		id, err := platform.IDFromString(taskLogFindFlags.orgID)
+	if f.orgID != "" {
+		id, err := platform.IDFromString(f.orgID)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		filter.Org = id
-	}
-
-	ctx := context.TODO()
-	logs, _, err := s.FindLogs(ctx, filter)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		orgID = id
 	}
-
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"Log",
-	)
-	for _, log := range logs {
-		w.Write(map[string]interface{}{
-			"Log": *log,
-		})
-	}
-	w.Flush()
-}
-
-// taskLogFindFlags define the Delete command
-type TaskRunFindFlags struct {
-	runID      string
-	taskID     string
-	orgID      string
-	afterTime  string
-	beforeTime string
-	limit      int
+	return taskID, runID, orgID
 }
 
-var taskRunFindFlags TaskRunFindFlags
-
-func init() {
-	taskRunFindCmd := &cobra.Command{
-		Use:   "find",
-		Short: "find runs for a task",
-		Run:   taskRunFindF,
+// timeRange parses the after and before times, zero if not set.
+func (f *taskRunFlags) timeRange() (after, before time.Time) {
+	var err error
+	if f.afterTime != "" {
+		if after, err = time.Parse(time.RFC3339, f.afterTime); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	}
-
-	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.taskID, "task-id", "", "", "task id (required)")
-	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.runID, "run-id", "", "", "run id")
-	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.orgID, "org-id", "", "", "organization id")
-	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.afterTime, "after", "", "", "after time for filtering")
-	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.beforeTime, "before", "", "", "before time for filtering")
-	taskRunFindCmd.Flags().IntVarP(&taskRunFindFlags.limit, "limit", "", 0, "limit the results")
-
-	taskRunFindCmd.MarkFlagRequired("task-id")
-	taskRunFindCmd.MarkFlagRequired("org-id")
-
-	runCmd.AddCommand(taskRunFindCmd)
+	if f.beforeTime != "" {
+		if before, err = time.Parse(time.RFC3339, f.beforeTime); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	return after, before
 }
 
-func taskRunFindF(cmd *cobra.Command, args []string) {
-	s := &http.TaskService{
+func newTaskService() *http.TaskService {
+	return &http.TaskService{
 		Addr:  flags.host,
 		Token: flags.token,
 	}
+}
 
-	filter := platform.RunFilter{
-		Limit:      taskRunFindFlags.limit,
-		AfterTime:  taskRunFindFlags.afterTime,
-		BeforeTime: taskRunFindFlags.beforeTime,
-	}
-	taskID, err := platform.IDFromString(taskRunFindFlags.taskID)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-	filter.Task = taskID
-
-	orgID, err := platform.IDFromString(taskRunFindFlags.orgID)
-	if err != nil {
+func writeJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	filter.Org = orgID
+}
 
-	var runs []*platform.Run
-	if taskRunFindFlags.runID != "" {
-		id, err := platform.IDFromString(taskRunFindFlags.runID)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		run, err := s.FindRunByID(context.Background(), *filter.Org, *id)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		runs = append(runs, run)
-	} else {
-		runs, _, err = s.FindRuns(context.Background(), filter)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+func writeRuns(runs []*platform.Run, asJSON bool) {
+	if asJSON {
+		if runs == nil {
+			runs = []*platform.Run{}
 		}
+		writeJSON(runs)
+		return
 	}
 
 	w := internal.NewTabWriter(os.Stdout)
@@ -586,49 +526,319 @@ func taskRunFindF(cmd *cobra.Command, args []string) {
 	w.Flush()
 }
 
+// Run list command
+var taskRunListFlags struct {
+	taskRunFlags
+	limit int
+}
+
+func init() {
+	taskRunListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the runs of a task",
+		Run:   taskRunListF,
+	}
+	taskRunListFlags.register(taskRunListCmd, true)
+	taskRunListCmd.Flags().IntVarP(&taskRunListFlags.limit, "limit", "", 0, "limit the results")
+
+	runCmd.AddCommand(taskRunListCmd)
+}
+
+func taskRunListF(cmd *cobra.Command, args []string) {
+	taskID, _, orgID := taskRunListFlags.ids()
+	// the times are validated here, as the server reports them as invalid data.
+	taskRunListFlags.timeRange()
+
+	runs, _, err := newTaskService().FindRuns(context.Background(), platform.RunFilter{
+		Task:       &taskID,
+		Org:        orgID,
+		Limit:      taskRunListFlags.limit,
+		AfterTime:  taskRunListFlags.afterTime,
+		BeforeTime: taskRunListFlags.beforeTime,
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	writeRuns(runs, taskRunListFlags.json)
+}
+
+// Run find, retry and cancel commands
+var taskRunFindFlags taskRunFlags
+
+func init() {
+	taskRunFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find a run of a task",
+		Run:   taskRunFindF,
+	}
+	taskRunFindFlags.register(taskRunFindCmd, false)
+	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.runID, "run-id", "", "", "run id (required)")
+	taskRunFindCmd.MarkFlagRequired("run-id")
+
+	runCmd.AddCommand(taskRunFindCmd)
+}
+
+func taskRunFindF(cmd *cobra.Command, args []string) {
+	taskID, runID, _ := taskRunFindFlags.ids()
+
+	run, err := newTaskService().FindRunByID(context.Background(), taskID, *runID)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	writeRuns([]*platform.Run{run}, taskRunFindFlags.json)
+}
+
 type RunRetryFlags struct {
 	taskID, runID string
 }
 
 var runRetryFlags RunRetryFlags
 
+func init() {
+	newRetryCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:   "retry",
+			Short: "retry a run",
+			Run:   runRetryF,
+		}
+
+		cmd.Flags().StringVarP(&runRetryFlags.taskID, "task-id", "i", "", "task id (required)")
+		cmd.Flags().StringVarP(&runRetryFlags.runID, "run-id", "r", "", "run id (required)")
+		cmd.MarkFlagRequired("task-id")
+		cmd.MarkFlagRequired("run-id")
+		return cmd
+	}
+
+	runCmd.AddCommand(newRetryCmd())
+
+	// task retry predates the run commands.
+	deprecated := newRetryCmd()
+	deprecated.Deprecated = "use task run retry instead"
+	taskCmd.AddCommand(deprecated)
+}
+
+func runRetryF(cmd *cobra.Command, args []string) {
+	taskID, runID := runIDs(runRetryFlags.taskID, runRetryFlags.runID)
+
+	ctx := context.TODO()
+	newRun, err := newTaskService().RetryRun(ctx, taskID, runID)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Retry for task %s's run %s queued as run %s.\n", taskID, runID, newRun.ID)
+}
+
+var runCancelFlags struct {
+	taskID, runID string
+}
+
 func init() {
 	cmd := &cobra.Command{
-		Use:   "retry",
-		Short: "retry a run",
-		Run:   runRetryF,
+		Use:   "cancel",
+		Short: "Cancel a run in progress",
+		Run:   runCancelF,
 	}
 
-	cmd.Flags().StringVarP(&runRetryFlags.taskID, "task-id", "i", "", "task id (required)")
-	cmd.Flags().StringVarP(&runRetryFlags.runID, "run-id", "r", "", "run id (required)")
+	cmd.Flags().StringVarP(&runCancelFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&runCancelFlags.runID, "run-id", "r", "", "run id (required)")
 	cmd.MarkFlagRequired("task-id")
 	cmd.MarkFlagRequired("run-id")
 
-	taskCmd.AddCommand(cmd)
+	runCmd.AddCommand(cmd)
 }
 
-func runRetryF(cmd *cobra.Command, args []string) {
-	s := &http.TaskService{
-		Addr:  flags.host,
-		Token: flags.token,
+func runCancelF(cmd *cobra.Command, args []string) {
+	taskID, runID := runIDs(runCancelFlags.taskID, runCancelFlags.runID)
+
+	if err := newTaskService().CancelRun(context.Background(), taskID, runID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	var taskID, runID platform.ID
-	if err := taskID.DecodeFromString(runRetryFlags.taskID); err != nil {
+	fmt.Printf("Run %s of task %s canceled.\n", runID, taskID)
+}
+
+func runIDs(task, run string) (taskID, runID platform.ID) {
+	if err := taskID.DecodeFromString(task); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	if err := runID.DecodeFromString(runRetryFlags.runID); err != nil {
+	if err := runID.DecodeFromString(run); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	return taskID, runID
+}
 
-	ctx := context.TODO()
-	newRun, err := s.RetryRun(ctx, taskID, runID)
+// Log list command
+var taskLogListFlags struct {
+	taskRunFlags
+	follow bool
+}
+
+// followInterval is how often the logs are polled by log list --follow.
+const followInterval = time.Second
+
+func init() {
+	taskLogListCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"find"},
+		Short:   "List the logs of a task, or of one of its runs",
+		Run:     taskLogListF,
+	}
+	taskLogListFlags.register(taskLogListCmd, true)
+	taskLogListCmd.Flags().StringVarP(&taskLogListFlags.runID, "run-id", "", "", "run id")
+	taskLogListCmd.Flags().BoolVarP(&taskLogListFlags.follow, "follow", "f", false, "keep printing the logs of the runs in progress as they are written")
+
+	logCmd.AddCommand(taskLogListCmd)
+}
+
+// logLine is a line of the log of a run.
+type logLine struct {
+	RunID   platform.ID `json:"runID,omitempty"`
+	Time    string      `json:"time,omitempty"`
+	Message string      `json:"message"`
+}
+
+// logLines splits a log into its lines, keeping the ones in the time range
+// of the flags. The lines are prefixed with the time they were logged at.
+func logLines(runID platform.ID, l platform.Log, after, before time.Time) []logLine {
+	var lines []logLine
+	for _, s := range strings.Split(string(l), "\n") {
+		if s == "" {
+			continue
+		}
+		line := logLine{RunID: runID, Message: s}
+		if i := strings.Index(s, ": "); i > 0 {
+			if t, err := time.Parse(time.RFC3339Nano, s[:i]); err == nil {
+				if (!after.IsZero() && t.Before(after)) || (!before.IsZero() && t.After(before)) {
+					continue
+				}
+				line.Time, line.Message = s[:i], s[i+2:]
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func taskLogListF(cmd *cobra.Command, args []string) {
+	taskID, runID, orgID := taskLogListFlags.ids()
+	after, before := taskLogListFlags.timeRange()
+	s := newTaskService()
+
+	if taskLogListFlags.follow {
+		followLogs(s, taskID, runID, orgID, after, before)
+		return
+	}
+
+	logs, _, err := s.FindLogs(context.Background(), platform.LogFilter{
+		Task: &taskID,
+		Run:  runID,
+		Org:  orgID,
+	})
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Retry for task %s's run %s queued as run %s.\n", taskID, runID, newRun.ID)
+	var id platform.ID
+	if runID != nil {
+		id = *runID
+	}
+	lines := []logLine{}
+	for _, l := range logs {
+		lines = append(lines, logLines(id, *l, after, before)...)
+	}
+
+	if taskLogListFlags.json {
+		writeJSON(lines)
+		return
+	}
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Time",
+		"Message",
+	)
+	for _, l := range lines {
+		w.Write(map[string]interface{}{
+			"Time":    l.Time,
+			"Message": l.Message,
+		})
+	}
+	w.Flush()
+}
+
+// runInProgress returns true if a run may still write logs.
+func runInProgress(r *platform.Run) bool {
+	return r.Status == "scheduled" || r.Status == "started"
+}
+
+// followLogs prints the logs of a run, or of the runs of a task in
+// progress, as they are written. Following a run stops once it is done,
+// following a task never does.
+func followLogs(s *http.TaskService, taskID platform.ID, runID, orgID *platform.ID, after, before time.Time) {
+	ctx := context.Background()
+	printed := map[platform.ID]int{}
+	enc := json.NewEncoder(os.Stdout)
+
+	for {
+		var runs []*platform.Run
+		if runID != nil {
+			r, err := s.FindRunByID(ctx, taskID, *runID)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			runs = append(runs, r)
+		} else {
+			rs, _, err := s.FindRuns(ctx, platform.RunFilter{Task: &taskID, Org: orgID})
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			for _, r := range rs {
+				// the runs that were followed are polled once more after
+				// they are done, for their last lines.
+				if _, ok := printed[r.ID]; ok || runInProgress(r) {
+					runs = append(runs, r)
+				}
+			}
+		}
+
+		for _, r := range runs {
+			logs, _, err := s.FindLogs(ctx, platform.LogFilter{Task: &taskID, Run: &r.ID, Org: orgID})
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			var lines []logLine
+			for _, l := range logs {
+				lines = append(lines, logLines(r.ID, *l, after, before)...)
+			}
+			n := printed[r.ID]
+			if n > len(lines) {
+				n = len(lines)
+			}
+			for _, l := range lines[n:] {
+				if taskLogListFlags.json {
+					enc.Encode(l)
+				} else {
+					fmt.Printf("%s\t%s\t%s\n", l.RunID, l.Time, l.Message)
+				}
+			}
+			printed[r.ID] = len(lines)
+			if !runInProgress(r) {
+				if runID != nil {
+					return
+				}
+				delete(printed, r.ID)
+			}
+		}
+		time.Sleep(followInterval)
+	}
 }
@@ -23,8 +23,12 @@ var userCmd = &cobra.Command{
 
 // UserUpdateFlags are command line args used when updating a user
 type UserUpdateFlags struct {
-	id   string
-	name string
+	id           string
+	name         string
+	email        string
+	displayName  string
+	status       string
+	defaultOrgID string
 }
 
 var userUpdateFlags UserUpdateFlags
@@ -38,6 +42,10 @@ func init() {
 
 	userUpdateCmd.Flags().StringVarP(&userUpdateFlags.id, "id", "i", "", "user id (required)")
 	userUpdateCmd.Flags().StringVarP(&userUpdateFlags.name, "name", "n", "", "user name")
+	userUpdateCmd.Flags().StringVar(&userUpdateFlags.email, "email", "", "user email")
+	userUpdateCmd.Flags().StringVar(&userUpdateFlags.displayName, "display-name", "", "user display name")
+	userUpdateCmd.Flags().StringVar(&userUpdateFlags.status, "status", "", "user status, deactivating a user (inactive) disables its tokens and sessions")
+	userUpdateCmd.Flags().StringVar(&userUpdateFlags.defaultOrgID, "default-org-id", "", "ID of the default organization of the user")
 	userUpdateCmd.MarkFlagRequired("id")
 
 	userCmd.AddCommand(userUpdateCmd)
@@ -100,6 +108,24 @@ func userUpdateF(cmd *cobra.Command, args []string) {
 	if userUpdateFlags.name != "" {
 		update.Name = &userUpdateFlags.name
 	}
+	if cmd.Flags().Changed("email") {
+		update.Email = &userUpdateFlags.email
+	}
+	if cmd.Flags().Changed("display-name") {
+		update.DisplayName = &userUpdateFlags.displayName
+	}
+	if userUpdateFlags.status != "" {
+		status := platform.Status(userUpdateFlags.status)
+		update.Status = &status
+	}
+	if userUpdateFlags.defaultOrgID != "" {
+		orgID, err := platform.IDFromString(userUpdateFlags.defaultOrgID)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		update.DefaultOrgID = orgID
+	}
 
 	user, err := s.UpdateUser(context.Background(), id, update)
 	if err != nil {
@@ -107,21 +133,42 @@ func userUpdateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	writeUsers(user)
+}
+
+// writeUsers writes the users as a table to stdout.
+func writeUsers(users ...*platform.User) {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Name",
+		"Email",
+		"DisplayName",
+		"Status",
+		"DefaultOrgID",
 	)
-	w.Write(map[string]interface{}{
-		"ID":   user.ID.String(),
-		"Name": user.Name,
-	})
+	for _, u := range users {
+		defaultOrgID := ""
+		if u.DefaultOrgID.Valid() {
+			defaultOrgID = u.DefaultOrgID.String()
+		}
+		w.Write(map[string]interface{}{
+			"ID":           u.ID.String(),
+			"Name":         u.Name,
+			"Email":        u.Email,
+			"DisplayName":  u.DisplayName,
+			"Status":       u.Status,
+			"DefaultOrgID": defaultOrgID,
+		})
+	}
 	w.Flush()
 }
 
 // UserCreateFlags are command line args used when creating a user
 type UserCreateFlags struct {
-	name string
+	name        string
+	email       string
+	displayName string
 }
 
 var userCreateFlags UserCreateFlags
@@ -134,6 +181,8 @@ func init() {
 	}
 
 	userCreateCmd.Flags().StringVarP(&userCreateFlags.name, "name", "n", "", "user name (required)")
+	userCreateCmd.Flags().StringVar(&userCreateFlags.email, "email", "", "user email")
+	userCreateCmd.Flags().StringVar(&userCreateFlags.displayName, "display-name", "", "user display name")
 	userCreateCmd.MarkFlagRequired("name")
 
 	userCmd.AddCommand(userCreateCmd)
@@ -147,7 +196,9 @@ func userCreateF(cmd *cobra.Command, args []string) {
 	}
 
 	user := &platform.User{
-		Name: userCreateFlags.name,
+		Name:        userCreateFlags.name,
+		Email:       userCreateFlags.email,
+		DisplayName: userCreateFlags.displayName,
 	}
 
 	if err := s.CreateUser(context.Background(), user); err != nil {
@@ -155,16 +206,7 @@ func userCreateF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-	)
-	w.Write(map[string]interface{}{
-		"ID":   user.ID.String(),
-		"Name": user.Name,
-	})
-	w.Flush()
+	writeUsers(user)
 }
 
 // UserFindFlags are command line args used when finding a user
@@ -214,18 +256,7 @@ func userFindF(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	w := internal.NewTabWriter(os.Stdout)
-	w.WriteHeaders(
-		"ID",
-		"Name",
-	)
-	for _, u := range users {
-		w.Write(map[string]interface{}{
-			"ID":   u.ID.String(),
-			"Name": u.Name,
-		})
-	}
-	w.Flush()
+	writeUsers(users...)
 }
 
 // UserDeleteFlags are command line args used when deleting a user
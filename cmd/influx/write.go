@@ -1,16 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/csv"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/http"
 	"github.com/influxdata/platform/kit/signals"
 	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/pkg/limiter"
+	"github.com/influxdata/platform/toml"
 	"github.com/influxdata/platform/write"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,8 +29,26 @@ var writeCmd = &cobra.Command{
 	Use:   "write line protocol or @/path/to/points.txt",
 	Short: "Write points to influxdb",
 	Long: `Write a single line of line protocol to influx db,
-		or add an entire file specified with an @ prefix`,
-	Args: cobra.ExactArgs(1),
+		or add an entire file specified with an @ prefix,
+		or read from standard input when given - or nothing.
+		Gzipped input is decompressed.
+
+		With --format csv, or for files ending with .csv or .csv.gz, the input is CSV
+		with one point per row. An optional "#datatype" row ahead of the header gives
+		the data types of the columns: measurement, tag, double, long, unsignedLong,
+		boolean, string, ignored, dateTime:RFC3339 or dateTime:number, e.g.
+
+			#datatype measurement,tag,double,dateTime:RFC3339
+			m,host,usage,time
+			cpu,a,0.5,2018-10-02T00:00:00Z
+
+		Without it, _measurement and _time are the measurement and time of the points,
+		and the other columns are fields.
+
+		Writes failing because the server is unavailable or overloaded are retried.
+		With --skip-errors, the lines the server rejects are written with their error
+		to --errors-file instead of failing the write.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: fluxWriteF,
 }
 
@@ -31,6 +58,13 @@ var writeFlags struct {
 	BucketID  string
 	Bucket    string
 	Precision string
+
+	Format     string
+	MaxRetries int
+	RateLimit  string
+	SkipErrors bool
+	ErrorsFile string
+	Progress   bool
 }
 
 func init() {
@@ -63,6 +97,13 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Format, "format", "", "format of the input: lp or csv; defaults to csv for .csv files and lp otherwise")
+	writeCmd.PersistentFlags().IntVar(&writeFlags.MaxRetries, "max-retries", write.DefaultMaxRetries, "number of retries of the writes failing because the server is unavailable or overloaded")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.RateLimit, "rate-limit", "", "maximum bytes of input written per second, e.g. 512k or 1m")
+	writeCmd.PersistentFlags().BoolVar(&writeFlags.SkipErrors, "skip-errors", false, "skip the lines rejected by the server instead of failing")
+	writeCmd.PersistentFlags().StringVar(&writeFlags.ErrorsFile, "errors-file", "", "file to write the rejected lines to with --skip-errors; defaults to standard error")
+	writeCmd.PersistentFlags().BoolVar(&writeFlags.Progress, "progress", false, "print the progress of the write to standard error")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -122,31 +163,195 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 
 	bucketID, orgID := buckets[0].ID, buckets[0].OrganizationID
 
-	var r io.Reader
-	if args[0] == "-" {
-		r = os.Stdin
-	} else if len(args[0]) > 0 && args[0][0] == '@' {
-		f, err := os.Open(args[0][1:])
+	ctx = signals.WithStandardSignals(ctx)
+
+	in, size, err := writeInput(args)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	progress := &progressReader{r: in}
+	var r io.Reader = progress
+	if writeFlags.RateLimit != "" {
+		var limit toml.Size
+		if err := limit.UnmarshalText([]byte(writeFlags.RateLimit)); err != nil {
+			return fmt.Errorf("invalid rate limit: %v", err)
+		}
+		if limit == 0 {
+			return fmt.Errorf("invalid rate limit: must be positive")
+		}
+		r = limiter.NewReader(ctx, r, int(limit), int(limit))
+	}
+
+	// the input is gzipped if it starts with the gzip magic number.
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-		r = f
+		defer gr.Close()
+		r = gr
 	} else {
-		r = strings.NewReader(args[0])
+		r = br
 	}
 
-	s := write.Batcher{
+	var svc platform.WriteService = &write.RetryService{
 		Service: &http.WriteService{
 			Addr:      flags.host,
 			Token:     flags.token,
 			Precision: writeFlags.Precision,
 		},
+		MaxRetries: writeFlags.MaxRetries,
+		OnRetry: func(err error, retry int, wait time.Duration) {
+			fmt.Fprintf(os.Stderr, "write failed, retrying in %s (%d/%d): %v\n", wait, retry, writeFlags.MaxRetries, err)
+		},
 	}
 
-	ctx = signals.WithStandardSignals(ctx)
+	var rejects *write.RejectService
+	if writeFlags.SkipErrors {
+		var w io.Writer = os.Stderr
+		if writeFlags.ErrorsFile != "" {
+			f, err := os.Create(writeFlags.ErrorsFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		rejects = &write.RejectService{
+			Service: svc,
+			Rejects: w,
+		}
+		svc = rejects
+	}
+
+	format := writeFlags.Format
+	if format == "" {
+		format = "lp"
+		if name := strings.TrimSuffix(args0(args), ".gz"); strings.HasPrefix(name, "@") && strings.HasSuffix(name, ".csv") {
+			format = "csv"
+		}
+	}
+	switch format {
+	case "lp":
+	case "csv":
+		cr := write.NewCSVReader(r, writeFlags.Precision)
+		if rejects != nil {
+			cr.OnError = func(line int, row []string, err error) error {
+				var buf bytes.Buffer
+				w := csv.NewWriter(&buf)
+				if err := w.Write(row); err != nil {
+					return err
+				}
+				w.Flush()
+				return rejects.Reject(buf.Bytes(), err)
+			}
+		}
+		r = cr
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if writeFlags.Progress {
+		done := make(chan struct{})
+		defer close(done)
+		go progress.print(done, size, rejects)
+	}
+
+	s := write.Batcher{
+		Service: svc,
+	}
 	if err := s.Write(ctx, orgID, bucketID, r); err != context.Canceled {
 		return err
 	}
 	return nil
 }
+
+func args0(args []string) string {
+	if len(args) == 0 {
+		return "-"
+	}
+	return args[0]
+}
+
+// writeInput opens the input of the write, and returns its size when known.
+func writeInput(args []string) (io.ReadCloser, int64, error) {
+	arg := args0(args)
+	switch {
+	case arg == "-":
+		return ioutil.NopCloser(os.Stdin), -1, nil
+	case strings.HasPrefix(arg, "@"):
+		f, err := os.Open(arg[1:])
+		if err != nil {
+			return nil, 0, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, fi.Size(), nil
+	default:
+		return ioutil.NopCloser(strings.NewReader(arg)), int64(len(arg)), nil
+	}
+}
+
+// progressReader counts the bytes read from the input of a write.
+type progressReader struct {
+	r io.Reader
+	n int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	atomic.AddInt64(&p.n, int64(n))
+	return n, err
+}
+
+// print prints the progress of the write every second until done is
+// closed.
+func (p *progressReader) print(done <-chan struct{}, size int64, rejects *write.RejectService) {
+	start := time.Now()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			p.printLine(start, size, rejects)
+			fmt.Fprintln(os.Stderr)
+			return
+		case <-ticker.C:
+			p.printLine(start, size, rejects)
+		}
+	}
+}
+
+func (p *progressReader) printLine(start time.Time, size int64, rejects *write.RejectService) {
+	n := atomic.LoadInt64(&p.n)
+	line := "read " + formatBytes(n)
+	if size > 0 {
+		line += fmt.Sprintf(" of %s (%d%%)", formatBytes(size), n*100/size)
+	}
+	if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+		line += fmt.Sprintf(", %s/s", formatBytes(int64(float64(n)/elapsed)))
+	}
+	if rejects != nil {
+		line += fmt.Sprintf(", %d rejected", rejects.Rejected())
+	}
+	fmt.Fprintf(os.Stderr, "\r%-60s", line)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,363 @@
+// Package dumptsi dumps the TSI index files and log files of a storage engine.
+package dumptsi
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsi1"
+)
+
+// Command represents the program execution for "influx_inspect dumptsi".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	showSeries         bool
+	showMeasurements   bool
+	showTagKeys        bool
+	showTagValues      bool
+	showTagValueSeries bool
+
+	measurementFilter *regexp.Regexp
+	tagKeyFilter      *regexp.Regexp
+	tagValueFilter    *regexp.Regexp
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("dumptsi", flag.ExitOnError)
+	enginePath := fs.String("engine-path", "", "path of the storage engine")
+	fs.BoolVar(&cmd.showSeries, "series", false, "dump the series")
+	fs.BoolVar(&cmd.showMeasurements, "measurements", false, "dump the measurements, which are the buckets")
+	fs.BoolVar(&cmd.showTagKeys, "tag-keys", false, "dump the tag keys")
+	fs.BoolVar(&cmd.showTagValues, "tag-values", false, "dump the tag values")
+	fs.BoolVar(&cmd.showTagValueSeries, "tag-value-series", false, "dump the series of the tag values")
+	measurementFilter := fs.String("measurement-filter", "", "only dump the measurements, formatted as ORG/BUCKET, matching this regular expression")
+	tagKeyFilter := fs.String("tag-key-filter", "", "only dump the tag keys matching this regular expression")
+	tagValueFilter := fs.String("tag-value-filter", "", "only dump the tag values matching this regular expression")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Dumps the TSI index of a storage engine. Without any dump flag, summarizes the files of the index.\nPATHs restrict the dump to these .tsi and .tsl files.\n\nUsage: influx_inspect dumptsi [flags] [PATH...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if *enginePath == "" {
+		fs.Usage()
+		return nil
+	}
+
+	var err error
+	if cmd.measurementFilter, err = compile(*measurementFilter); err != nil {
+		return fmt.Errorf("invalid measurement filter: %v", err)
+	}
+	if cmd.tagKeyFilter, err = compile(*tagKeyFilter); err != nil {
+		return fmt.Errorf("invalid tag key filter: %v", err)
+	}
+	if cmd.tagValueFilter, err = compile(*tagValueFilter); err != nil {
+		return fmt.Errorf("invalid tag value filter: %v", err)
+	}
+	return cmd.run(*enginePath, fs.Args())
+}
+
+func compile(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
+
+func (cmd *Command) run(enginePath string, paths []string) error {
+	c := storage.NewConfig()
+
+	// Opening the series file creates it if it is missing.
+	sfilePath := c.GetSeriesFilePath(enginePath)
+	if _, err := os.Stat(sfilePath); err != nil {
+		return err
+	}
+	sfile := tsdb.NewSeriesFile(sfilePath)
+	sfile.DisableMetrics()
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	fs, closeFn, err := cmd.openFileSet(sfile, c.GetIndexPath(enginePath), paths)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	if !cmd.showSeries && !cmd.showMeasurements && !cmd.showTagKeys && !cmd.showTagValues && !cmd.showTagValueSeries {
+		return cmd.printFileSummaries(fs)
+	}
+	if cmd.showSeries {
+		if err := cmd.printSeries(sfile, fs); err != nil {
+			return err
+		}
+	}
+	if cmd.showMeasurements || cmd.showTagKeys || cmd.showTagValues || cmd.showTagValueSeries {
+		return cmd.printMeasurements(sfile, fs)
+	}
+	return nil
+}
+
+// openFileSet opens the given index and log files, or all the files of the
+// index if there are none. The returned function closes them.
+func (cmd *Command) openFileSet(sfile *tsdb.SeriesFile, indexPath string, paths []string) (*tsi1.FileSet, func(), error) {
+	if len(paths) == 0 {
+		if _, err := os.Stat(indexPath); err != nil {
+			return nil, nil, err
+		}
+		idx := tsi1.NewIndex(sfile, tsi1.NewConfig(), tsi1.WithPath(indexPath), tsi1.DisableCompactions(), tsi1.DisableMetrics())
+		if err := idx.Open(); err != nil {
+			return nil, nil, err
+		}
+		fs, err := idx.RetainFileSet()
+		if err != nil {
+			idx.Close()
+			return nil, nil, err
+		}
+		return fs, func() { fs.Release(); idx.Close() }, nil
+	}
+
+	var files []tsi1.File
+	closeFiles := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	for _, path := range paths {
+		var f tsi1.File
+		switch filepath.Ext(path) {
+		case tsi1.LogFileExt:
+			lf := tsi1.NewLogFile(sfile, path)
+			if err := lf.Open(); err != nil {
+				closeFiles()
+				return nil, nil, err
+			}
+			f = lf
+		case tsi1.IndexFileExt:
+			idxf := tsi1.NewIndexFile(sfile)
+			idxf.SetPath(path)
+			if err := idxf.Open(); err != nil {
+				closeFiles()
+				return nil, nil, err
+			}
+			f = idxf
+		default:
+			closeFiles()
+			return nil, nil, fmt.Errorf("%s is neither an index file nor a log file", path)
+		}
+		files = append(files, f)
+	}
+	fs, err := tsi1.NewFileSet(nil, sfile, files)
+	if err != nil {
+		closeFiles()
+		return nil, nil, err
+	}
+	return fs, closeFiles, nil
+}
+
+func (cmd *Command) printFileSummaries(fs *tsi1.FileSet) error {
+	for _, f := range fs.Files() {
+		var kind string
+		var measurements uint64
+		switch f := f.(type) {
+		case *tsi1.LogFile:
+			kind, measurements = "LOG FILE", f.MeasurementN()
+		case *tsi1.IndexFile:
+			kind, measurements = "INDEX FILE", f.MeasurementN()
+		default:
+			return errors.New("unexpected file type")
+		}
+		series, err := f.SeriesIDSet()
+		if err != nil {
+			return err
+		}
+		tombstones, err := f.TombstoneSeriesIDSet()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.Stdout, "[%s] %s\n", kind, f.Path())
+		tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+		fmt.Fprintf(tw, "Level:\t%d\n", f.Level())
+		fmt.Fprintf(tw, "Size:\t%d\n", f.Size())
+		fmt.Fprintf(tw, "Measurements:\t%d\n", measurements)
+		fmt.Fprintf(tw, "Series:\t%d\n", series.Cardinality())
+		fmt.Fprintf(tw, "Series Tombstones:\t%d\n", tombstones.Cardinality())
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.Stdout)
+	}
+	return nil
+}
+
+func (cmd *Command) printSeries(sfile *tsdb.SeriesFile, fs *tsi1.FileSet) error {
+	fmt.Fprintln(cmd.Stdout, "Series:")
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	mitr := fs.MeasurementIterator()
+	if mitr == nil {
+		return nil
+	}
+	for e := mitr.Next(); e != nil; e = mitr.Next() {
+		if !cmd.matchesMeasurement(e.Name()) {
+			continue
+		}
+		if err := cmd.printSeriesIDs(tw, sfile, fs.MeasurementSeriesIDIterator(e.Name()), "  "); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(tw)
+	return tw.Flush()
+}
+
+// printSeriesIDs prints the key and the ID of the series of itr matching the
+// tag filters.
+func (cmd *Command) printSeriesIDs(w io.Writer, sfile *tsdb.SeriesFile, itr tsdb.SeriesIDIterator, indent string) error {
+	if itr == nil {
+		return nil
+	}
+	defer itr.Close()
+	for {
+		e, err := itr.Next()
+		if err != nil {
+			return err
+		} else if e.SeriesID.IsZero() {
+			return nil
+		}
+
+		name, tags := sfile.Series(e.SeriesID)
+		if name == nil || !cmd.matchesTags(tags) {
+			continue
+		}
+		deleted := ""
+		if sfile.IsDeleted(e.SeriesID) {
+			deleted = "deleted"
+		}
+		fmt.Fprintf(w, "%s%s\t%d\t%s\n", indent, formatSeries(name, tags), e.SeriesID.RawID(), deleted)
+	}
+}
+
+func (cmd *Command) printMeasurements(sfile *tsdb.SeriesFile, fs *tsi1.FileSet) error {
+	fmt.Fprintln(cmd.Stdout, "Measurements:")
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	mitr := fs.MeasurementIterator()
+	if mitr == nil {
+		return nil
+	}
+	for e := mitr.Next(); e != nil; e = mitr.Next() {
+		if !cmd.matchesMeasurement(e.Name()) {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", formatName(e.Name()), deletedString(e.Deleted()))
+		if cmd.showTagKeys || cmd.showTagValues || cmd.showTagValueSeries {
+			if err := cmd.printTagKeys(tw, sfile, fs, e.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Fprintln(tw)
+	return tw.Flush()
+}
+
+func (cmd *Command) printTagKeys(w io.Writer, sfile *tsdb.SeriesFile, fs *tsi1.FileSet, name []byte) error {
+	kitr := fs.TagKeyIterator(name)
+	if kitr == nil {
+		return nil
+	}
+	for e := kitr.Next(); e != nil; e = kitr.Next() {
+		if cmd.tagKeyFilter != nil && !cmd.tagKeyFilter.Match(e.Key()) {
+			continue
+		}
+		fmt.Fprintf(w, "    %s\t%s\n", e.Key(), deletedString(e.Deleted()))
+		if cmd.showTagValues || cmd.showTagValueSeries {
+			if err := cmd.printTagValues(w, sfile, fs, name, e.Key()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cmd *Command) printTagValues(w io.Writer, sfile *tsdb.SeriesFile, fs *tsi1.FileSet, name, key []byte) error {
+	vitr := fs.TagValueIterator(name, key)
+	if vitr == nil {
+		return nil
+	}
+	for e := vitr.Next(); e != nil; e = vitr.Next() {
+		if cmd.tagValueFilter != nil && !cmd.tagValueFilter.Match(e.Value()) {
+			continue
+		}
+		fmt.Fprintf(w, "      %s\t%s\n", e.Value(), deletedString(e.Deleted()))
+		if cmd.showTagValueSeries {
+			itr, err := fs.TagValueSeriesIDIterator(name, key, e.Value())
+			if err != nil {
+				return err
+			}
+			if err := cmd.printSeriesIDs(w, sfile, itr, "        "); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (cmd *Command) matchesMeasurement(name []byte) bool {
+	return cmd.measurementFilter == nil || cmd.measurementFilter.MatchString(formatName(name))
+}
+
+func (cmd *Command) matchesTags(tags models.Tags) bool {
+	if cmd.tagKeyFilter == nil && cmd.tagValueFilter == nil {
+		return true
+	}
+	for _, t := range tags {
+		if (cmd.tagKeyFilter == nil || cmd.tagKeyFilter.Match(t.Key)) && (cmd.tagValueFilter == nil || cmd.tagValueFilter.Match(t.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func deletedString(deleted bool) string {
+	if deleted {
+		return "deleted"
+	}
+	return ""
+}
+
+// formatName formats the name of a measurement, which is the organization and
+// the bucket of its series, as "org/bucket".
+func formatName(name []byte) string {
+	if len(name) != 16 {
+		return string(name)
+	}
+	var encoded [16]byte
+	copy(encoded[:], name)
+	org, bucket := tsdb.DecodeName(encoded)
+	return org.String() + "/" + bucket.String()
+}
+
+func formatSeries(name []byte, tags models.Tags) string {
+	return formatName(name) + string(tags.HashKey())
+}
@@ -0,0 +1,73 @@
+package dumptsi_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/dumptsi"
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+)
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, testutil.Spec, "0000000000000002", "0000000000000003")
+	defer cleanup()
+
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "summary",
+			want: []string{"[LOG FILE] ", "Level:\t0\n", "Series Tombstones:\t0\n"},
+		},
+		{
+			name: "series",
+			args: []string{"-series"},
+			want: []string{
+				"Series:\n",
+				"  0000000000000001/0000000000000002,_f=usage,_m=cpu,host=host-0\t",
+				"  0000000000000001/0000000000000002,_f=usage,_m=cpu,host=host-1\t",
+				"  0000000000000001/0000000000000002,_f=used,_m=mem\t",
+				"  0000000000000001/0000000000000003,_f=used,_m=mem\t",
+			},
+			notWant: []string{"Measurements:\n", "[LOG FILE]"},
+		},
+		{
+			name: "filters",
+			args: []string{"-series", "-tag-values", "-measurement-filter", "0000000000000003$", "-tag-key-filter", "host"},
+			want: []string{
+				"  0000000000000001/0000000000000003,_f=usage,_m=cpu,host=host-0\t",
+				"Measurements:\n  0000000000000001/0000000000000003\t\n    host\t\n      host-0\t\n      host-1\t\n",
+			},
+			notWant: []string{"0000000000000001/0000000000000002", "_m=mem", "    _f\t"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := dumptsi.NewCommand()
+			cmd.Stdout = &stdout
+			if err := cmd.Run(append([]string{"-engine-path", enginePath}, tc.args...)...); err != nil {
+				t.Fatal(err)
+			}
+
+			// the tables are aligned with tabs.
+			out := stdout.String()
+			for strings.Contains(out, "\t\t") {
+				out = strings.Replace(out, "\t\t", "\t", -1)
+			}
+			for _, s := range tc.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("expected %q in output\n%s", s, out)
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("unexpected %q in output\n%s", s, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Package dumptsm dumps the index and the blocks of a TSM file.
+package dumptsm
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect dumptsm".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	dumpIndex  bool
+	dumpBlocks bool
+	filterKey  string
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("dumptsm", flag.ExitOnError)
+	fs.BoolVar(&cmd.dumpIndex, "index", false, "dump the index of the file")
+	fs.BoolVar(&cmd.dumpBlocks, "blocks", false, "dump the blocks of the file")
+	all := fs.Bool("all", false, "dump the index and the blocks of the file")
+	fs.StringVar(&cmd.filterKey, "filter-key", "", "only dump the index entries and blocks of the keys containing this string")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Dumps the index and the blocks of a TSM file.\n\nUsage: influx_inspect dumptsm [flags] PATH")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() != 1 {
+		fs.Usage()
+		return nil
+	}
+	if *all {
+		cmd.dumpIndex, cmd.dumpBlocks = true, true
+	}
+	return cmd.dump(fs.Arg(0))
+}
+
+// The names of the block types and of the encodings of their values, the
+// timestamps being first.
+var (
+	blockTypes = []string{"float64", "int64", "bool", "string", "uint64"}
+	encodings  = [][]string{
+		{"none", "s8b", "rle"}, // timestamps
+		{"none", "gor"},        // float64
+		{"none", "s8b", "rle"}, // int64
+		{"none", "bp"},         // bool
+		{"none", "snpy"},       // string
+		{"none", "s8b", "rle"}, // uint64
+	}
+)
+
+func encoding(typ int, enc byte) string {
+	if int(enc) < len(encodings[typ]) {
+		return encodings[typ][enc]
+	}
+	return "?"
+}
+
+func (cmd *Command) dump(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %v", path, err)
+	}
+	defer r.Close()
+
+	minTime, maxTime := r.TimeRange()
+	keyCount := r.KeyCount()
+
+	fmt.Fprintln(cmd.Stdout, "Summary:")
+	fmt.Fprintf(cmd.Stdout, "  File: %s\n", path)
+	fmt.Fprintf(cmd.Stdout, "  Time Range: %s - %s\n", formatTime(minTime), formatTime(maxTime))
+	fmt.Fprintf(cmd.Stdout, "  Duration: %s ", time.Duration(maxTime-minTime))
+	fmt.Fprintf(cmd.Stdout, "  Series: %d ", keyCount)
+	fmt.Fprintf(cmd.Stdout, "  File Size: %d\n\n", r.Size())
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	if cmd.dumpIndex {
+		fmt.Fprintln(cmd.Stdout, "Index:")
+		fmt.Fprintln(tw, strings.Join([]string{"Pos", "Min Time", "Max Time", "Ofs", "Size", "Key", "Field"}, "\t"))
+		var (
+			pos     int
+			entries []tsm1.IndexEntry
+		)
+		for i := 0; i < keyCount; i++ {
+			var key []byte
+			key, _, entries = r.Key(i, &entries)
+			if !cmd.matches(key) {
+				pos += len(entries)
+				continue
+			}
+			series, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+			for _, e := range entries {
+				pos++
+				fmt.Fprintln(tw, strings.Join([]string{
+					strconv.Itoa(pos),
+					formatTime(e.MinTime),
+					formatTime(e.MaxTime),
+					strconv.FormatInt(e.Offset, 10),
+					strconv.FormatUint(uint64(e.Size), 10),
+					formatSeriesKey(series),
+					string(field),
+				}, "\t"))
+			}
+		}
+		tw.Flush()
+		fmt.Fprintln(cmd.Stdout)
+	}
+
+	if cmd.dumpBlocks {
+		fmt.Fprintln(cmd.Stdout, "Blocks:")
+		fmt.Fprintln(tw, strings.Join([]string{"Blk", "Chk", "Ofs", "Len", "Type", "Min Time", "Points", "Enc [T/V]", "Len [T/V]"}, "\t"))
+	}
+
+	var (
+		blockCount, pointCount, blockSize int64
+		minBlockSize, maxBlockSize        int
+		encodingCounts                    [6][3]int64
+		entries                           []tsm1.IndexEntry
+		values                            []tsm1.Value
+	)
+	for i := 0; i < keyCount; i++ {
+		var key []byte
+		key, _, entries = r.Key(i, &entries)
+		if !cmd.matches(key) {
+			continue
+		}
+		for j := range entries {
+			e := &entries[j]
+			checksum, block, err := r.ReadBytes(e, nil)
+			if err != nil {
+				return err
+			}
+			blockCount++
+			blockSize += int64(len(block))
+			if minBlockSize == 0 || len(block) < minBlockSize {
+				minBlockSize = len(block)
+			}
+			if len(block) > maxBlockSize {
+				maxBlockSize = len(block)
+			}
+
+			typ, err := tsm1.BlockType(block)
+			if err != nil {
+				return fmt.Errorf("block %d of %q: %v", blockCount, key, err)
+			}
+			values, err = tsm1.DecodeBlock(block, values[:0])
+			if err != nil {
+				return fmt.Errorf("block %d of %q: %v", blockCount, key, err)
+			}
+			pointCount += int64(len(values))
+
+			// A block is its type, the length of its timestamps, its
+			// timestamps and its values, each starting with their encoding.
+			tsLen, n := binary.Uvarint(block[1:])
+			ts := block[1+n : 1+n+int(tsLen)]
+			vs := block[1+n+int(tsLen):]
+			tsEnc, vEnc := ts[0]>>4, vs[0]>>4
+			if int(tsEnc) < 3 {
+				encodingCounts[0][tsEnc]++
+			}
+			if int(vEnc) < 3 {
+				encodingCounts[typ+1][vEnc]++
+			}
+
+			if cmd.dumpBlocks {
+				fmt.Fprintln(tw, strings.Join([]string{
+					strconv.FormatInt(blockCount, 10),
+					strconv.FormatUint(uint64(checksum), 10),
+					strconv.FormatInt(e.Offset, 10),
+					strconv.Itoa(len(block)),
+					blockTypes[typ],
+					formatTime(e.MinTime),
+					strconv.Itoa(len(values)),
+					encoding(0, tsEnc) + "/" + encoding(int(typ)+1, vEnc),
+					fmt.Sprintf("%d/%d", len(ts), len(vs)),
+				}, "\t"))
+			}
+		}
+	}
+	if cmd.dumpBlocks {
+		tw.Flush()
+		fmt.Fprintln(cmd.Stdout)
+	}
+
+	fmt.Fprintln(cmd.Stdout, "Statistics")
+	fmt.Fprintln(cmd.Stdout, "  Blocks:")
+	fmt.Fprintf(cmd.Stdout, "    Total: %d Size: %d Min: %d Max: %d Avg: %d\n", blockCount, blockSize, minBlockSize, maxBlockSize, avg(blockSize, blockCount))
+	fmt.Fprintln(cmd.Stdout, "  Index:")
+	fmt.Fprintf(cmd.Stdout, "    Total: %d Size: %d\n", blockCount, r.IndexSize())
+	fmt.Fprintln(cmd.Stdout, "  Points:")
+	fmt.Fprintf(cmd.Stdout, "    Total: %d\n", pointCount)
+
+	fmt.Fprintln(cmd.Stdout, "  Encoding:")
+	for typ, counts := range encodingCounts {
+		name := "Timestamp"
+		if typ > 0 {
+			name = blockTypes[typ-1]
+		}
+		var parts []string
+		for enc, count := range counts {
+			if count > 0 {
+				parts = append(parts, fmt.Sprintf("%s: %d (%d%%)", encoding(typ, byte(enc)), count, count*100/blockCount))
+			}
+		}
+		if len(parts) > 0 {
+			fmt.Fprintf(cmd.Stdout, "    %s:\t%s\n", name, strings.Join(parts, " "))
+		}
+	}
+	fmt.Fprintln(cmd.Stdout, "  Compression:")
+	fmt.Fprintf(cmd.Stdout, "    Per block: %d bytes/point\n", avg(blockSize, pointCount))
+	fmt.Fprintf(cmd.Stdout, "    Total: %d bytes/point\n", avg(int64(r.Size()), pointCount))
+	return nil
+}
+
+// matches returns true if the key, formatted as the index is dumped,
+// contains the filter.
+func (cmd *Command) matches(key []byte) bool {
+	if cmd.filterKey == "" {
+		return true
+	}
+	series, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	return strings.Contains(formatSeriesKey(series)+" "+string(field), cmd.filterKey)
+}
+
+func avg(total, n int64) int64 {
+	if n == 0 {
+		return 0
+	}
+	return total / n
+}
+
+func formatTime(t int64) string {
+	return time.Unix(0, t).UTC().Format(time.RFC3339Nano)
+}
+
+// formatSeriesKey formats the key of a series in a TSM file, whose name is
+// the organization and the bucket of the series, as "org/bucket,tags".
+func formatSeriesKey(key []byte) string {
+	name, tags := models.ParseKeyBytes(key)
+	if len(name) != 16 {
+		return string(key)
+	}
+	var encoded [16]byte
+	copy(encoded[:], name)
+	org, bucket := tsdb.DecodeName(encoded)
+	return org.String() + "/" + bucket.String() + string(tags.HashKey())
+}
@@ -0,0 +1,78 @@
+package dumptsm_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/dumptsm"
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+	"github.com/influxdata/platform/storage"
+)
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, testutil.Spec, "0000000000000002")
+	defer cleanup()
+	files, err := filepath.Glob(filepath.Join(storage.NewConfig().GetEnginePath(enginePath), "*.tsm"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("unexpected files %v: %v", files, err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "summary",
+			want: []string{
+				"Time Range: 2018-10-01T00:00:00Z - 2018-10-01T00:00:50Z",
+				"Series: 3",
+				"Blocks:\n    Total: 3 ",
+				"Points:\n    Total: 18\n",
+				"float64:\tgor: 2 (66%)",
+				"int64:\t",
+			},
+			notWant: []string{"Index:\nPos", "Blocks:\nBlk"},
+		},
+		{
+			name: "filter key",
+			args: []string{"-all", "-filter-key", "host-1"},
+			want: []string{
+				"Index:\nPos",
+				"0000000000000001/0000000000000002,_f=usage,_m=cpu,host=host-1\tusage\n",
+				"Blocks:\nBlk",
+				"\tfloat64\t2018-10-01T00:00:00Z\t6\t",
+				"Points:\n    Total: 6\n",
+			},
+			notWant: []string{"host=host-0", "int64:\t"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := dumptsm.NewCommand()
+			cmd.Stdout = &stdout
+			if err := cmd.Run(append(tc.args, files[0])...); err != nil {
+				t.Fatal(err)
+			}
+
+			// the tables are aligned with tabs.
+			out := stdout.String()
+			for strings.Contains(out, "\t\t") {
+				out = strings.Replace(out, "\t\t", "\t", -1)
+			}
+			for _, s := range tc.want {
+				if !strings.Contains(out, s) {
+					t.Errorf("expected %q in output\n%s", s, out)
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("unexpected %q in output\n%s", s, out)
+				}
+			}
+		})
+	}
+}
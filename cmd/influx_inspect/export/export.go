@@ -0,0 +1,217 @@
+// Package export exports the data of TSM files as line protocol.
+package export
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/pkg/escape"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect export".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	start    int64
+	end      int64
+	orgID    platform.ID
+	bucketID platform.ID
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	enginePath := fs.String("engine-path", "", "path of the storage engine")
+	out := fs.String("out", "", "file to export to, standard output if empty")
+	start := fs.String("start", "", "only export the values at or after this time, in RFC3339 format")
+	end := fs.String("end", "", "only export the values at or before this time, in RFC3339 format")
+	orgID := fs.String("org-id", "", "only export the buckets of this organization")
+	bucketID := fs.String("bucket-id", "", "only export this bucket")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Exports the data of the TSM files as line protocol.\n\nUsage: influx_inspect export [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 || *enginePath == "" {
+		fs.Usage()
+		return nil
+	}
+
+	cmd.start, cmd.end = math.MinInt64, math.MaxInt64
+	if *start != "" {
+		t, err := time.Parse(time.RFC3339, *start)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+		cmd.start = t.UnixNano()
+	}
+	if *end != "" {
+		t, err := time.Parse(time.RFC3339, *end)
+		if err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+		cmd.end = t.UnixNano()
+	}
+	if cmd.start > cmd.end {
+		return fmt.Errorf("start time is after end time")
+	}
+	if *orgID != "" {
+		if err := cmd.orgID.DecodeFromString(*orgID); err != nil {
+			return fmt.Errorf("invalid org ID: %v", err)
+		}
+	}
+	if *bucketID != "" {
+		if err := cmd.bucketID.DecodeFromString(*bucketID); err != nil {
+			return fmt.Errorf("invalid bucket ID: %v", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(storage.NewConfig().GetEnginePath(*enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+
+	w := cmd.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if err := cmd.export(bw, files); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// export writes the values of the files as line protocol, preceded by a
+// comment naming the organization and the bucket whenever they change. The
+// values of a series written to several files are exported from each file,
+// and deleted values are not exported.
+func (cmd *Command) export(w io.Writer, files []string) error {
+	var (
+		current [16]byte
+		buf     []byte
+		tags    models.Tags
+	)
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %v", path, err)
+		}
+
+		if min, max := r.TimeRange(); max < cmd.start || min > cmd.end {
+			r.Close()
+			continue
+		}
+
+		for i := 0; i < r.KeyCount(); i++ {
+			key, _ := r.KeyAt(i)
+			seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+			var name []byte
+			name, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+			if len(name) != 16 {
+				continue
+			}
+			var encoded [16]byte
+			copy(encoded[:], name)
+			org, bucket := tsdb.DecodeName(encoded)
+			if cmd.orgID.Valid() && org != cmd.orgID || cmd.bucketID.Valid() && bucket != cmd.bucketID {
+				continue
+			}
+
+			values, err := r.ReadAll(key)
+			if err != nil {
+				r.Close()
+				return fmt.Errorf("unable to read %q of %s: %v", key, path, err)
+			}
+
+			// The measurement and the field are tags of the series in the
+			// storage, but not in line protocol.
+			measurement := tags.Get(tsdb.MeasurementTagKeyBytes)
+			lineTags := make(models.Tags, 0, len(tags))
+			for _, t := range tags {
+				if string(t.Key) != tsdb.MeasurementTagKey && string(t.Key) != tsdb.FieldKeyTagKey {
+					lineTags = append(lineTags, t)
+				}
+			}
+			prefix := models.MakeKey(measurement, lineTags)
+			prefix = append(prefix, ' ')
+			prefix = append(prefix, escape.String(string(field))...)
+			prefix = append(prefix, '=')
+
+			for _, v := range values {
+				if ts := v.UnixNano(); ts < cmd.start || ts > cmd.end {
+					continue
+				}
+				if encoded != current {
+					current = encoded
+					if _, err := fmt.Fprintf(w, "# org_id=%s bucket_id=%s\n", org, bucket); err != nil {
+						r.Close()
+						return err
+					}
+				}
+				buf = append(buf[:0], prefix...)
+				buf = appendValue(buf, v.Value())
+				buf = append(buf, ' ')
+				buf = strconv.AppendInt(buf, v.UnixNano(), 10)
+				buf = append(buf, '\n')
+				if _, err := w.Write(buf); err != nil {
+					r.Close()
+					return err
+				}
+			}
+		}
+		r.Close()
+	}
+	return nil
+}
+
+// appendValue appends v to buf, in line protocol.
+func appendValue(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case float64:
+		return strconv.AppendFloat(buf, v, 'g', -1, 64)
+	case int64:
+		return append(strconv.AppendInt(buf, v, 10), 'i')
+	case uint64:
+		return append(strconv.AppendUint(buf, v, 10), 'u')
+	case string:
+		buf = append(buf, '"')
+		buf = append(buf, models.EscapeStringField(v)...)
+		return append(buf, '"')
+	case bool:
+		return strconv.AppendBool(buf, v)
+	}
+	panic(fmt.Sprintf("unsupported value %T", v))
+}
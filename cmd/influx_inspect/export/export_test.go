@@ -0,0 +1,85 @@
+package export_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/export"
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+)
+
+const spec = `
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:00:30Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 2
+  format      = "host-%s"
+
+  [[measurements.fields]]
+  name  = "state"
+  type  = "string"
+  value = "a \"b\""
+
+[[measurements]]
+name = "mem"
+
+  [[measurements.fields]]
+  name         = "used"
+  type         = "integer"
+  distribution = "counter"
+`
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, spec, "0000000000000002", "0000000000000003")
+	defer cleanup()
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "time range",
+			args: []string{"-bucket-id", "0000000000000002", "-start", "2018-10-01T00:00:10Z", "-end", "2018-10-01T00:00:15Z"},
+			want: []string{
+				`# org_id=0000000000000001 bucket_id=0000000000000002`,
+				`cpu,host=host-0 state="a \"b\"" 1538352010000000000`,
+				`cpu,host=host-1 state="a \"b\"" 1538352010000000000`,
+				`mem used=1i 1538352010000000000`,
+			},
+		},
+		{
+			name: "buckets",
+			args: []string{"-org-id", "0000000000000001", "-start", "2018-10-01T00:00:20Z"},
+			want: []string{
+				`# org_id=0000000000000001 bucket_id=0000000000000002`,
+				`cpu,host=host-0 state="a \"b\"" 1538352020000000000`,
+				`cpu,host=host-1 state="a \"b\"" 1538352020000000000`,
+				`mem used=2i 1538352020000000000`,
+				`# org_id=0000000000000001 bucket_id=0000000000000003`,
+				`cpu,host=host-0 state="a \"b\"" 1538352020000000000`,
+				`cpu,host=host-1 state="a \"b\"" 1538352020000000000`,
+				`mem used=2i 1538352020000000000`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := export.NewCommand()
+			cmd.Stdout = &stdout
+			if err := cmd.Run(append([]string{"-engine-path", enginePath}, tc.args...)...); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(stdout.String()); got != strings.Join(tc.want, "\n") {
+				t.Errorf("unexpected lines\n%s", got)
+			}
+		})
+	}
+}
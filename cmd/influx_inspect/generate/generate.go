@@ -0,0 +1,467 @@
+// Package generate writes the synthetic data described by a spec, either as
+// TSM files in the storage of a bucket, or as line protocol streamed to a
+// server.
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/kit/signals"
+	"github.com/influxdata/platform/logger"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/pkg/data/gen"
+	"github.com/influxdata/platform/pkg/escape"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsi1"
+	"github.com/influxdata/platform/tsdb/tsm1"
+	"github.com/influxdata/platform/write"
+	"go.uber.org/zap"
+)
+
+const (
+	// seriesBatchSize is the number of series created in the index at once.
+	seriesBatchSize = 10000
+
+	// maxTSMFileSize is the size above which a new TSM file is started.
+	maxTSMFileSize = 2048 * 1024 * 1024
+)
+
+// Command represents the program execution for "influx_inspect generate".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+	Logger *zap.Logger
+
+	enginePath string
+	host       string
+	token      string
+	print      bool
+	orgID      platform.ID
+	bucketID   platform.ID
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+		Logger: zap.NewNop(),
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	fs.StringVar(&cmd.enginePath, "engine-path", "", "write TSM files to the storage engine at this path; influxd must not be running")
+	fs.StringVar(&cmd.host, "host", "", "stream line protocol to the server at this URL")
+	fs.StringVar(&cmd.token, "token", "", "token to write to the server with")
+	fs.BoolVar(&cmd.print, "print", false, "print line protocol to standard output")
+	orgID := fs.String("org-id", "", "ID of the organization that owns the bucket")
+	bucketID := fs.String("bucket-id", "", "ID of the bucket to write to")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		io.WriteString(cmd.Stdout, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() != 1 {
+		fs.Usage()
+		return nil
+	}
+
+	var modes int
+	for _, set := range []bool{cmd.enginePath != "", cmd.host != "", cmd.print} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return fmt.Errorf("exactly one of -engine-path, -host or -print is required")
+	}
+	if !cmd.print {
+		if err := cmd.orgID.DecodeFromString(*orgID); err != nil {
+			return fmt.Errorf("invalid org ID: %v", err)
+		}
+		if err := cmd.bucketID.DecodeFromString(*bucketID); err != nil {
+			return fmt.Errorf("invalid bucket ID: %v", err)
+		}
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	spec, err := gen.ParseSpec(f)
+	if err != nil {
+		return fmt.Errorf("invalid spec: %v", err)
+	}
+
+	switch {
+	case cmd.print:
+		w := bufio.NewWriter(cmd.Stdout)
+		if err := writeLines(w, spec); err != nil {
+			return err
+		}
+		return w.Flush()
+	case cmd.host != "":
+		return cmd.stream(spec)
+	default:
+		cmd.Logger = logger.New(cmd.Stderr)
+		return cmd.writeTSM(spec)
+	}
+}
+
+const usage = `Generates the data described by a spec.
+
+Usage: influx_inspect generate [flags] SPEC
+
+The spec is a TOML file describing the time range and interval of the
+values, and the measurements with the cardinalities of their tags and the
+types and distributions of their fields:
+
+    start    = 2018-10-01T00:00:00Z
+    end      = 2018-10-02T00:00:00Z
+    interval = "10s"
+
+    [[measurements]]
+    name = "cpu"
+
+      [[measurements.tags]]
+      key         = "host"
+      cardinality = 100
+      format      = "host-%s"
+
+      [[measurements.fields]]
+      name         = "usage"
+      type         = "float"     # float, integer, unsigned, string or boolean
+      distribution = "random"    # constant, random or counter
+      scale        = 100.0       # upper bound of random values
+      # value      = 1           # constant value or first value of a counter
+      # step       = 1           # step of a counter
+
+With -engine-path, TSM files are written directly to the storage engine and
+the series are added to its index; influxd must not be running. With -host,
+line protocol is written to a running server.
+`
+
+// stream writes the data as line protocol to the server.
+func (cmd *Command) stream(spec *gen.Spec) error {
+	ctx := signals.WithStandardSignals(context.Background())
+
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		err := writeLines(w, spec)
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	defer pr.Close()
+
+	b := write.Batcher{
+		Service: &write.RetryService{
+			Service: &http.WriteService{
+				Addr:  cmd.host,
+				Token: cmd.token,
+			},
+			OnRetry: func(err error, retry int, wait time.Duration) {
+				fmt.Fprintf(cmd.Stderr, "write failed, retrying in %s (%d/%d): %v\n", wait, retry, write.DefaultMaxRetries, err)
+			},
+		},
+	}
+	if err := b.Write(ctx, cmd.orgID, cmd.bucketID, pr); err != context.Canceled {
+		return err
+	}
+	return nil
+}
+
+// writeLines writes the data as line protocol, a line for every tag set and
+// time with the values of all the fields of the measurement.
+func writeLines(w io.Writer, spec *gen.Spec) error {
+	var buf []byte
+	for i := range spec.Measurements {
+		m := &spec.Measurements[i]
+		keys := make([][]byte, len(m.Fields))
+		seqs := make([]gen.ValuesSequence, len(m.Fields))
+		for j := range m.Fields {
+			keys[j] = []byte(escape.String(m.Fields[j].Name))
+			seqs[j] = m.Fields[j].NewValuesSequence(spec)
+		}
+
+		tags := m.NewTagsSequence()
+		for tags.Next() {
+			prefix := models.MakeKey([]byte(m.Name), tags.Value())
+			for _, s := range seqs {
+				s.Reset()
+			}
+
+			// The sequences of the fields have the same timestamps, so they
+			// are read in lockstep.
+			for seqs[0].Next() {
+				for _, s := range seqs[1:] {
+					s.Next()
+				}
+				ts := timestamps(seqs[0].Values())
+				for k := range ts {
+					buf = append(buf[:0], prefix...)
+					for j, s := range seqs {
+						if j == 0 {
+							buf = append(buf, ' ')
+						} else {
+							buf = append(buf, ',')
+						}
+						buf = append(buf, keys[j]...)
+						buf = append(buf, '=')
+						buf = appendValue(buf, s.Values(), k)
+					}
+					buf = append(buf, ' ')
+					buf = strconv.AppendInt(buf, ts[k], 10)
+					buf = append(buf, '\n')
+					if _, err := w.Write(buf); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func timestamps(v gen.Values) []int64 {
+	switch a := v.(type) {
+	case *gen.FloatArray:
+		return a.Timestamps
+	case *gen.IntegerArray:
+		return a.Timestamps
+	case *gen.UnsignedArray:
+		return a.Timestamps
+	case *gen.StringArray:
+		return a.Timestamps
+	case *gen.BooleanArray:
+		return a.Timestamps
+	}
+	panic(fmt.Sprintf("unsupported values %T", v))
+}
+
+// appendValue appends the i-th value of v to buf, in line protocol.
+func appendValue(buf []byte, v gen.Values, i int) []byte {
+	switch a := v.(type) {
+	case *gen.FloatArray:
+		return strconv.AppendFloat(buf, a.Values[i], 'g', -1, 64)
+	case *gen.IntegerArray:
+		return append(strconv.AppendInt(buf, a.Values[i], 10), 'i')
+	case *gen.UnsignedArray:
+		return append(strconv.AppendUint(buf, a.Values[i], 10), 'u')
+	case *gen.StringArray:
+		buf = append(buf, '"')
+		buf = append(buf, models.EscapeStringField(a.Values[i])...)
+		return append(buf, '"')
+	case *gen.BooleanArray:
+		return strconv.AppendBool(buf, a.Values[i])
+	}
+	panic(fmt.Sprintf("unsupported values %T", v))
+}
+
+// series is a series of the spec, identified by its key in TSM files.
+type series struct {
+	key   []byte
+	field *gen.FieldSpec
+}
+
+// writeTSM creates the series of the spec in the index of the storage engine,
+// then writes their values to new TSM files.
+func (cmd *Command) writeTSM(spec *gen.Spec) error {
+	c := storage.NewConfig()
+	log := cmd.Logger
+
+	sfile := tsdb.NewSeriesFile(c.GetSeriesFilePath(cmd.enginePath))
+	sfile.WithLogger(log)
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, c.Index, tsi1.WithPath(c.GetIndexPath(cmd.enginePath)))
+	index.WithLogger(log)
+	if err := index.Open(); err != nil {
+		return err
+	}
+	defer index.Close()
+
+	log.Info("Creating series", zap.Int("series", spec.SeriesN()))
+	all, err := createSeries(index, spec, cmd.orgID, cmd.bucketID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(all, func(i, j int) bool { return bytes.Compare(all[i].key, all[j].key) < 0 })
+
+	dir := c.GetEnginePath(cmd.enginePath)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	generation, err := nextGeneration(dir)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Writing TSM files", zap.String("path", dir), zap.Int("points_per_series", spec.PointsPerSeries()))
+	for len(all) > 0 {
+		path := filepath.Join(dir, tsm1.DefaultFormatFileName(generation, 1)+"."+tsm1.TSMFileExtension)
+		n, err := writeTSMFile(path, all, spec)
+		if err != nil {
+			return err
+		}
+		log.Info("Wrote TSM file", zap.String("path", path))
+		all = all[n:]
+		generation++
+	}
+	return nil
+}
+
+// createSeries adds the series of the spec to the index of the bucket, and
+// returns them.
+func createSeries(index *tsi1.Index, spec *gen.Spec, orgID, bucketID platform.ID) ([]series, error) {
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := encoded[:]
+
+	all := make([]series, 0, spec.SeriesN())
+	var collection tsdb.SeriesCollection
+	flush := func() error {
+		if err := index.CreateSeriesListIfNotExists(&collection); err != nil {
+			if _, ok := err.(tsdb.PartialWriteError); !ok {
+				return err
+			}
+		}
+		// A series dropped has been written before with another type.
+		if err := collection.PartialWriteError(); err != nil {
+			return err
+		}
+		collection = tsdb.SeriesCollection{}
+		return nil
+	}
+
+	for i := range spec.Measurements {
+		m := &spec.Measurements[i]
+		seq := m.NewTagsSequence()
+		for seq.Next() {
+			for j := range m.Fields {
+				f := &m.Fields[j]
+				tags := make(models.Tags, 0, len(seq.Value())+2)
+				tags = append(tags, models.NewTag(tsdb.FieldKeyTagKeyBytes, []byte(f.Name)))
+				tags = append(tags, models.NewTag(tsdb.MeasurementTagKeyBytes, []byte(m.Name)))
+				tags = append(tags, seq.Value()...)
+				sort.Sort(tags)
+
+				key := models.MakeKey(name, tags)
+				collection.Keys = append(collection.Keys, key)
+				collection.Names = append(collection.Names, name)
+				collection.Tags = append(collection.Tags, tags)
+				collection.Types = append(collection.Types, f.FieldType())
+				all = append(all, series{key: tsm1.SeriesFieldKeyBytes(string(key), f.Name), field: f})
+
+				if len(collection.Keys) == seriesBatchSize {
+					if err := flush(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	if len(collection.Keys) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return all, nil
+}
+
+// writeTSMFile writes the values of the series, sorted by key, to a new TSM
+// file at path until it is full, and returns the number of series written.
+func writeTSMFile(path string, all []series, spec *gen.Spec) (n int, err error) {
+	tmp := path + "." + tsm1.TmpTSMFileExtension
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return 0, err
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			w.Close()
+			w.Remove()
+			os.Remove(tmp)
+			os.Remove(tsm1.StatsFilename(tmp))
+		}
+	}()
+
+	var buf []byte
+	for ; n < len(all) && w.Size() < maxTSMFileSize; n++ {
+		s := all[n]
+		seq := s.field.NewValuesSequence(spec)
+		for seq.Next() {
+			// The timestamps are encoded in place, so the time range is read
+			// first.
+			v := seq.Values()
+			min, max := v.MinTime(), v.MaxTime()
+			if buf, err = v.Encode(buf[:0]); err != nil {
+				return 0, err
+			}
+			if err := w.WriteBlock(s.key, min, max, buf); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return n, os.Rename(tmp, path)
+}
+
+// nextGeneration returns the generation following the ones of the TSM files
+// in dir.
+func nextGeneration(dir string) (int, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var max int
+	for _, fi := range fis {
+		if !strings.HasSuffix(fi.Name(), "."+tsm1.TSMFileExtension) {
+			continue
+		}
+		generation, _, err := tsm1.DefaultParseFileName(fi.Name())
+		if err != nil {
+			return 0, err
+		}
+		if generation > max {
+			max = generation
+		}
+	}
+	return max + 1, nil
+}
@@ -0,0 +1,189 @@
+package generate_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/generate"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+const spec = `
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:00:30Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 2
+  format      = "host-%s"
+
+  [[measurements.fields]]
+  name  = "usage"
+  type  = "float"
+  value = 0.5
+
+  [[measurements.fields]]
+  name  = "state"
+  type  = "string"
+  value = "a \"b\""
+
+[[measurements]]
+name = "mem"
+
+  [[measurements.fields]]
+  name         = "used"
+  type         = "integer"
+  distribution = "counter"
+`
+
+func writeSpec(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "spec.toml")
+	if err := ioutil.WriteFile(path, []byte(spec), 0666); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCommand_Print(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var stdout bytes.Buffer
+	cmd := generate.NewCommand()
+	cmd.Stdout = &stdout
+	if err := cmd.Run("-print", writeSpec(t, dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	want := []string{
+		`cpu,host=host-0 usage=0.5,state="a \"b\"" 1538352000000000000`,
+		`cpu,host=host-0 usage=0.5,state="a \"b\"" 1538352010000000000`,
+		`cpu,host=host-0 usage=0.5,state="a \"b\"" 1538352020000000000`,
+		`cpu,host=host-1 usage=0.5,state="a \"b\"" 1538352000000000000`,
+		`cpu,host=host-1 usage=0.5,state="a \"b\"" 1538352010000000000`,
+		`cpu,host=host-1 usage=0.5,state="a \"b\"" 1538352020000000000`,
+		`mem used=0i 1538352000000000000`,
+		`mem used=1i 1538352010000000000`,
+		`mem used=2i 1538352020000000000`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected lines\n%s", stdout.String())
+	}
+}
+
+func TestCommand_Host(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var body bytes.Buffer
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/write" || r.URL.Query().Get("bucket") != "0000000000000002" || r.Header.Get("Authorization") != "Token tok" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body.ReadFrom(gr)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cmd := generate.NewCommand()
+	if err := cmd.Run("-host", srv.URL, "-token", "tok", "-org-id", "0000000000000001", "-bucket-id", "0000000000000002", writeSpec(t, dir)); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(body.String(), "\n"); n != 9 {
+		t.Errorf("expected 9 lines to be written, got %d:\n%s", n, body.String())
+	}
+}
+
+func TestCommand_EnginePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	enginePath := filepath.Join(dir, "engine")
+
+	cmd := generate.NewCommand()
+	cmd.Stderr = ioutil.Discard
+	for i := 0; i < 2; i++ {
+		if err := cmd.Run("-engine-path", enginePath, "-org-id", "0000000000000001", "-bucket-id", "0000000000000002", writeSpec(t, dir)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the files of a second run follow the ones of the first.
+	files, err := filepath.Glob(filepath.Join(enginePath, storage.DefaultEngineDirectoryName, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 2 || filepath.Base(files[1]) != tsm1.DefaultFormatFileName(2, 1)+".tsm" {
+		t.Fatalf("unexpected files %v", files)
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if n := r.KeyCount(); n != 5 {
+		t.Errorf("expected 5 series, got %d", n)
+	}
+	if min, max := r.TimeRange(); min != 1538352000000000000 || max != 1538352020000000000 {
+		t.Errorf("unexpected time range %d-%d", min, max)
+	}
+	key, _, _ := r.Key(0, nil)
+	if values, err := r.ReadAll(key); err != nil {
+		t.Fatal(err)
+	} else if len(values) != 3 || values[2].UnixNano() != 1538352020000000000 {
+		t.Errorf("unexpected values %v of %q", values, key)
+	}
+
+	e := storage.NewEngine(enginePath, storage.NewConfig())
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if n := e.SeriesCardinality(); n != 5 {
+		t.Errorf("expected 5 series in the index, got %d", n)
+	}
+}
+
+func TestCommand_Conflict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := generate.NewCommand()
+	if err := cmd.Run("-print", "-host", "http://localhost:9999", writeSpec(t, dir)); err == nil {
+		t.Error("expected an error with several outputs")
+	}
+}
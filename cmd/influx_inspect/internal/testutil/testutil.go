@@ -0,0 +1,72 @@
+// Package testutil generates the engines the influx_inspect commands are
+// tested against.
+package testutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/generate"
+)
+
+// orgID is the organization the data of the generated engines belongs to.
+const orgID = "0000000000000001"
+
+// Spec is a spec of a minute of cpu and mem data, with two cpu series.
+const Spec = `
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:01:00Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 2
+  format      = "host-%s"
+
+  [[measurements.fields]]
+  name  = "usage"
+  type  = "float"
+  value = 0.5
+
+[[measurements]]
+name = "mem"
+
+  [[measurements.fields]]
+  name         = "used"
+  type         = "integer"
+  distribution = "counter"
+`
+
+// GenerateEngine generates the data of spec into each bucket of an engine in
+// a temporary directory. It returns the path of the engine and a function
+// removing it.
+func GenerateEngine(t testing.TB, spec string, bucketIDs ...string) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "influx_inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	specPath := filepath.Join(dir, "spec.toml")
+	if err := ioutil.WriteFile(specPath, []byte(spec), 0666); err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	enginePath := filepath.Join(dir, "engine")
+	gen := generate.NewCommand()
+	gen.Stderr = ioutil.Discard
+	for _, bucketID := range bucketIDs {
+		if err := gen.Run("-engine-path", enginePath, "-org-id", orgID, "-bucket-id", bucketID, specPath); err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+	}
+	return enginePath, cleanup
+}
@@ -0,0 +1,121 @@
+// The influx_inspect command inspects and generates the data files of the
+// storage engine, offline.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/platform/cmd/influx_inspect/dumptsi"
+	"github.com/influxdata/platform/cmd/influx_inspect/dumptsm"
+	"github.com/influxdata/platform/cmd/influx_inspect/export"
+	"github.com/influxdata/platform/cmd/influx_inspect/generate"
+	"github.com/influxdata/platform/cmd/influx_inspect/report"
+	"github.com/influxdata/platform/cmd/influx_inspect/verify/seriesfile"
+	"github.com/influxdata/platform/cmd/influx_inspect/verify/tsm"
+)
+
+func main() {
+	m := NewMain()
+	if err := m.Run(os.Args[1:]...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Main represents the program execution.
+type Main struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewMain returns a new instance of Main.
+func NewMain() *Main {
+	return &Main{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run determines and runs the command specified by the CLI args.
+func (m *Main) Run(args ...string) error {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	switch name {
+	case "", "help":
+		fmt.Fprintln(m.Stdout, usage)
+	case "buildtsi":
+		cmd := buildtsi.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("buildtsi: %s", err)
+		}
+	case "dumptsi":
+		cmd := dumptsi.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("dumptsi: %s", err)
+		}
+	case "dumptsm":
+		cmd := dumptsm.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("dumptsm: %s", err)
+		}
+	case "export":
+		cmd := export.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("export: %s", err)
+		}
+	case "generate":
+		cmd := generate.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("generate: %s", err)
+		}
+	case "report":
+		cmd := report.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("report: %s", err)
+		}
+	case "verify":
+		cmd := tsm.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("verify: %s", err)
+		}
+	case "verify-seriesfile":
+		cmd := seriesfile.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("verify-seriesfile: %s", err)
+		}
+	default:
+		return fmt.Errorf(`unknown command "%s"`+"\n"+`Run 'influx_inspect help' for usage`+"\n\n", name)
+	}
+	return nil
+}
+
+const usage = `Usage: influx_inspect command [arguments]
+
+The commands are:
+
+    buildtsi             builds a TSI index from the data files
+    dumptsi              dumps low-level details about the TSI index
+    dumptsm              dumps low-level details about a TSM file
+    export               exports the data of the TSM files as line protocol
+    generate             generates synthetic data from a spec
+    help                 displays this help
+    report               reports the series and field counts of the buckets
+    verify               verifies the integrity of the TSM files
+    verify-seriesfile    verifies the integrity of the series file
+
+Use "influx_inspect command -help" for more information about a command.`
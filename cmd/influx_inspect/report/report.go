@@ -0,0 +1,227 @@
+// Package report reports the number of series and fields of the buckets
+// stored in TSM files.
+package report
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/pkg/estimator/hll"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect report".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	detailed bool
+	exact    bool
+	orgID    platform.ID
+	bucketID platform.ID
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	enginePath := fs.String("engine-path", "", "path of the storage engine")
+	orgID := fs.String("org-id", "", "only report the buckets of this organization")
+	bucketID := fs.String("bucket-id", "", "only report this bucket")
+	fs.BoolVar(&cmd.detailed, "detailed", false, "report the counts of every measurement")
+	fs.BoolVar(&cmd.exact, "exact", false, "report exact counts instead of estimates, which needs memory for every series")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Reports the number of series and fields of the buckets in the TSM files.\n\nUsage: influx_inspect report [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 || *enginePath == "" {
+		fs.Usage()
+		return nil
+	}
+	if *orgID != "" {
+		if err := cmd.orgID.DecodeFromString(*orgID); err != nil {
+			return fmt.Errorf("invalid org ID: %v", err)
+		}
+	}
+	if *bucketID != "" {
+		if err := cmd.bucketID.DecodeFromString(*bucketID); err != nil {
+			return fmt.Errorf("invalid bucket ID: %v", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(storage.NewConfig().GetEnginePath(*enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	return cmd.report(files)
+}
+
+// counter counts distinct values.
+type counter interface {
+	Add(key []byte)
+	Count() uint64
+}
+
+// exactCounter counts distinct values exactly, keeping them all.
+type exactCounter map[string]struct{}
+
+func (c exactCounter) Add(key []byte) { c[string(key)] = struct{}{} }
+func (c exactCounter) Count() uint64  { return uint64(len(c)) }
+
+func (cmd *Command) newCounter() counter {
+	if cmd.exact {
+		return exactCounter{}
+	}
+	return hll.NewDefaultPlus()
+}
+
+// stats are the counts of a bucket or of a measurement.
+type stats struct {
+	series counter
+	fields counter
+}
+
+func (cmd *Command) newStats() *stats {
+	return &stats{series: cmd.newCounter(), fields: cmd.newCounter()}
+}
+
+type bucket struct {
+	org, bucket  platform.ID
+	stats        *stats
+	measurements map[string]*stats
+}
+
+func (cmd *Command) report(files []string) error {
+	start := time.Now()
+	buckets := make(map[[16]byte]*bucket)
+	minTime, maxTime := int64(math.MaxInt64), int64(math.MinInt64)
+
+	var tags models.Tags
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %v", path, err)
+		}
+
+		if min, max := r.TimeRange(); r.KeyCount() > 0 {
+			if min < minTime {
+				minTime = min
+			}
+			if max > maxTime {
+				maxTime = max
+			}
+		}
+
+		for i := 0; i < r.KeyCount(); i++ {
+			key, _ := r.KeyAt(i)
+			seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+			var name []byte
+			name, tags = models.ParseKeyBytesWithTags(seriesKey, tags)
+			if len(name) != 16 {
+				continue
+			}
+
+			var encoded [16]byte
+			copy(encoded[:], name)
+			b := buckets[encoded]
+			if b == nil {
+				org, id := tsdb.DecodeName(encoded)
+				if cmd.orgID.Valid() && org != cmd.orgID || cmd.bucketID.Valid() && id != cmd.bucketID {
+					continue
+				}
+				b = &bucket{org: org, bucket: id, stats: cmd.newStats(), measurements: make(map[string]*stats)}
+				buckets[encoded] = b
+			}
+
+			measurement := tags.Get(tsdb.MeasurementTagKeyBytes)
+			fieldKey := append(append(append([]byte(nil), measurement...), ','), field...)
+			b.stats.series.Add(seriesKey)
+			b.stats.fields.Add(fieldKey)
+			if cmd.detailed {
+				m := b.measurements[string(measurement)]
+				if m == nil {
+					m = cmd.newStats()
+					b.measurements[string(measurement)] = m
+				}
+				m.series.Add(seriesKey)
+				m.fields.Add(field)
+			}
+		}
+		r.Close()
+	}
+
+	all := make([]*bucket, 0, len(buckets))
+	for _, b := range buckets {
+		all = append(all, b)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].org != all[j].org {
+			return all[i].org < all[j].org
+		}
+		return all[i].bucket < all[j].bucket
+	})
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 2, 1, ' ', 0)
+	if cmd.detailed {
+		fmt.Fprintln(tw, "Org\tBucket\tMeasurement\tSeries\tFields\t")
+	} else {
+		fmt.Fprintln(tw, "Org\tBucket\tSeries\tFields\t")
+	}
+	for _, b := range all {
+		if !cmd.detailed {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t\n", b.org, b.bucket, b.stats.series.Count(), b.stats.fields.Count())
+			continue
+		}
+		names := make([]string, 0, len(b.measurements))
+		for name := range b.measurements {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m := b.measurements[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t\n", b.org, b.bucket, strconv.Quote(name), m.series.Count(), m.fields.Count())
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.Stdout)
+	fmt.Fprintln(cmd.Stdout, "Summary:")
+	fmt.Fprintf(cmd.Stdout, "  Files: %d\n", len(files))
+	if len(buckets) > 0 {
+		fmt.Fprintf(cmd.Stdout, "  Time Range: %s - %s\n", time.Unix(0, minTime).UTC().Format(time.RFC3339Nano), time.Unix(0, maxTime).UTC().Format(time.RFC3339Nano))
+		fmt.Fprintf(cmd.Stdout, "  Duration: %s\n", time.Duration(maxTime-minTime))
+	}
+	if !cmd.exact {
+		fmt.Fprintln(cmd.Stdout, "  The counts are estimates, use -exact for exact counts.")
+	}
+	fmt.Fprintf(cmd.Stdout, "Completed in %s\n", time.Since(start))
+	return nil
+}
@@ -0,0 +1,79 @@
+package report_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+	"github.com/influxdata/platform/cmd/influx_inspect/report"
+)
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, testutil.Spec, "0000000000000002", "0000000000000003")
+	defer cleanup()
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want []string
+		// estimated is true if the counts are reported as estimates.
+		estimated bool
+	}{
+		{
+			name: "detailed",
+			args: []string{"-exact", "-detailed"},
+			want: []string{
+				`Org Bucket Measurement Series Fields`,
+				`0000000000000001 0000000000000002 "cpu" 2 1`,
+				`0000000000000001 0000000000000002 "mem" 1 1`,
+				`0000000000000001 0000000000000003 "cpu" 2 1`,
+				`0000000000000001 0000000000000003 "mem" 1 1`,
+			},
+		},
+		{
+			name: "bucket",
+			args: []string{"-bucket-id", "0000000000000003"},
+			want: []string{
+				`Org Bucket Series Fields`,
+				`0000000000000001 0000000000000003 3 2`,
+			},
+			estimated: true,
+		},
+		{
+			name: "other org",
+			args: []string{"-exact", "-org-id", "0000000000000002"},
+			want: []string{
+				`Org Bucket Series Fields`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := report.NewCommand()
+			cmd.Stdout = &stdout
+			if err := cmd.Run(append([]string{"-engine-path", enginePath}, tc.args...)...); err != nil {
+				t.Fatal(err)
+			}
+
+			// the rows are aligned with spaces and followed by the summary.
+			out := stdout.String()
+			var rows []string
+			for _, line := range strings.Split(out, "\n") {
+				if line == "" {
+					break
+				}
+				rows = append(rows, strings.Join(strings.Fields(line), " "))
+			}
+			if got := strings.Join(rows, "\n"); got != strings.Join(tc.want, "\n") {
+				t.Errorf("unexpected rows\n%s", got)
+			}
+			if !strings.Contains(out, "Files: 2\n") {
+				t.Errorf("expected the files to be counted\n%s", out)
+			}
+			if got := strings.Contains(out, "The counts are estimates"); got != tc.estimated {
+				t.Errorf("unexpected estimate notice\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Package seriesfile verifies the segments and the indexes of the partitions
+// of a series file.
+package seriesfile
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// Command represents the program execution for "influx_inspect verify-seriesfile".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	verbose bool
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("verify-seriesfile", flag.ExitOnError)
+	enginePath := fs.String("engine-path", "", "path of the storage engine")
+	seriesFilePath := fs.String("series-file", "", "path of the series file, instead of the one of the storage engine")
+	fs.BoolVar(&cmd.verbose, "v", false, "print the result of every partition")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Verifies the segments and the indexes of the partitions of a series file.\n\nUsage: influx_inspect verify-seriesfile [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 || (*enginePath == "") == (*seriesFilePath == "") {
+		fs.Usage()
+		return nil
+	}
+
+	path := *seriesFilePath
+	if path == "" {
+		path = storage.NewConfig().GetSeriesFilePath(*enginePath)
+	}
+	return cmd.verify(path)
+}
+
+func (cmd *Command) verify(path string) error {
+	start := time.Now()
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	var brokenPartitions, partitions int
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		partitions++
+		partitionPath := filepath.Join(path, fi.Name())
+		if err := verifyPartition(partitionPath); err != nil {
+			brokenPartitions++
+			fmt.Fprintf(cmd.Stdout, "%s: %v\n", partitionPath, err)
+		} else if cmd.verbose {
+			fmt.Fprintf(cmd.Stdout, "%s: healthy\n", partitionPath)
+		}
+	}
+
+	fmt.Fprintf(cmd.Stdout, "Broken Partitions: %d / %d, in %vs\n", brokenPartitions, partitions, time.Since(start).Seconds())
+
+	if brokenPartitions > 0 {
+		return fmt.Errorf("%d of %d partitions are broken", brokenPartitions, partitions)
+	}
+	return nil
+}
+
+// entry is the last entry of a series ID in the segments of a partition.
+type entry struct {
+	key     []byte
+	offset  int64
+	deleted bool
+}
+
+// verifyPartition verifies the segments of a partition, then that its index
+// maps the IDs of the series to their offsets, and their keys to their IDs.
+func verifyPartition(path string) error {
+	partitionID, err := parsePartitionID(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	var segmentIDs []uint16
+	for _, fi := range fis {
+		if !tsdb.IsValidSeriesSegmentFilename(fi.Name()) {
+			continue
+		}
+		id, err := tsdb.ParseSeriesSegmentFilename(fi.Name())
+		if err != nil {
+			return err
+		}
+		segmentIDs = append(segmentIDs, id)
+	}
+	sort.Slice(segmentIDs, func(i, j int) bool { return segmentIDs[i] < segmentIDs[j] })
+
+	entries := make(map[tsdb.SeriesID]*entry)
+	for _, id := range segmentIDs {
+		if err := verifySegment(filepath.Join(path, fmt.Sprintf("%04x", id)), id, partitionID, entries); err != nil {
+			return err
+		}
+	}
+
+	segments := make([]*tsdb.SeriesSegment, 0, len(segmentIDs))
+	defer func() {
+		for _, s := range segments {
+			s.Close()
+		}
+	}()
+	for _, id := range segmentIDs {
+		s := tsdb.NewSeriesSegment(id, filepath.Join(path, fmt.Sprintf("%04x", id)))
+		if err := s.Open(); err != nil {
+			return err
+		}
+		segments = append(segments, s)
+	}
+	return verifyIndex(filepath.Join(path, "index"), segments, entries)
+}
+
+func parsePartitionID(name string) (int, error) {
+	var id int
+	if _, err := fmt.Sscanf(name, "%02x", &id); err != nil || fmt.Sprintf("%02x", id) != name {
+		return 0, fmt.Errorf("invalid partition directory name %q", name)
+	}
+	return id, nil
+}
+
+// verifySegment verifies the header and the entries of a segment, and records
+// the last entry of every series ID.
+func verifySegment(path string, segmentID uint16, partitionID int, entries map[tsdb.SeriesID]*entry) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if hdr, err := tsdb.ReadSeriesSegmentHeader(data); err != nil {
+		return fmt.Errorf("segment %04x: %v", segmentID, err)
+	} else if hdr.Version != tsdb.SeriesSegmentVersion {
+		return fmt.Errorf("segment %04x: %v", segmentID, tsdb.ErrInvalidSeriesSegmentVersion)
+	}
+
+	// Segments are preallocated, a zero flag ends their entries.
+	for pos := tsdb.SeriesSegmentHeaderSize; pos < len(data) && data[pos] != 0; {
+		flag := data[pos]
+		if !tsdb.IsValidSeriesEntryFlag(flag) {
+			return fmt.Errorf("segment %04x: invalid flag %d of entry at %d", segmentID, flag, pos)
+		} else if len(data)-pos < tsdb.SeriesEntryHeaderSize {
+			return fmt.Errorf("segment %04x: entry at %d is truncated", segmentID, pos)
+		}
+
+		id := tsdb.NewSeriesIDTyped(binary.BigEndian.Uint64(data[pos+1:])).SeriesID()
+		if id.IsZero() {
+			return fmt.Errorf("segment %04x: entry at %d has no series ID", segmentID, pos)
+		} else if int((id.RawID()-1)%tsdb.SeriesFilePartitionN) != partitionID {
+			return fmt.Errorf("segment %04x: series ID %d of entry at %d does not belong to the partition", segmentID, id.RawID(), pos)
+		}
+
+		offset := tsdb.JoinSeriesOffset(segmentID, uint32(pos))
+		sz := tsdb.SeriesEntryHeaderSize
+		switch flag {
+		case tsdb.SeriesEntryInsertFlag:
+			key, err := readSeriesKey(data[pos+tsdb.SeriesEntryHeaderSize:])
+			if err != nil {
+				return fmt.Errorf("segment %04x: key of series ID %d at %d: %v", segmentID, id.RawID(), pos, err)
+			}
+			if e := entries[id]; e != nil && !e.deleted {
+				return fmt.Errorf("segment %04x: series ID %d at %d is already used", segmentID, id.RawID(), pos)
+			}
+			entries[id] = &entry{key: key, offset: offset}
+			sz += len(key)
+
+		case tsdb.SeriesEntryTombstoneFlag:
+			e := entries[id]
+			if e == nil {
+				return fmt.Errorf("segment %04x: deleted series ID %d at %d does not exist", segmentID, id.RawID(), pos)
+			}
+			e.deleted = true
+		}
+		pos += sz
+	}
+	return nil
+}
+
+// readSeriesKey reads a series key from the beginning of data, returning an
+// error rather than panicking if it is invalid.
+func readSeriesKey(data []byte) (key []byte, err error) {
+	sz, n := binary.Uvarint(data)
+	if n <= 0 || sz == 0 || uint64(len(data)-n) < sz {
+		return nil, fmt.Errorf("invalid length")
+	}
+	key = data[:n+int(sz)]
+
+	buf := key[n:]
+	if len(buf) < 2 {
+		return nil, fmt.Errorf("truncated name")
+	}
+	nameLen := int(binary.BigEndian.Uint16(buf))
+	if nameLen == 0 || len(buf) < 2+nameLen {
+		return nil, fmt.Errorf("invalid name length")
+	}
+	buf = buf[2+nameLen:]
+
+	tagN, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of tags")
+	}
+	buf = buf[n:]
+	for i := uint64(0); i < tagN; i++ {
+		for j := 0; j < 2; j++ {
+			if len(buf) < 2 {
+				return nil, fmt.Errorf("truncated tag %d", i)
+			}
+			l := int(binary.BigEndian.Uint16(buf))
+			if len(buf) < 2+l {
+				return nil, fmt.Errorf("truncated tag %d", i)
+			}
+			buf = buf[2+l:]
+		}
+	}
+	if len(buf) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes", len(buf))
+	}
+	return key, nil
+}
+
+// verifyIndex verifies that the index, once recovered from the segments,
+// agrees with their entries.
+func verifyIndex(path string, segments []*tsdb.SeriesSegment, entries map[tsdb.SeriesID]*entry) (err error) {
+	// The index is read without bounds checks, a corrupt one panics.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("index: %v", r)
+		}
+	}()
+
+	idx := tsdb.NewSeriesIndex(path)
+	if err := idx.Open(); err != nil {
+		return fmt.Errorf("index: %v", err)
+	}
+	defer idx.Close()
+	if err := idx.Recover(segments); err != nil {
+		return fmt.Errorf("index: %v", err)
+	}
+
+	ids := make([]tsdb.SeriesID, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+
+	for _, id := range ids {
+		e := entries[id]
+		if e.deleted {
+			if !idx.IsDeleted(id) {
+				return fmt.Errorf("index: series ID %d is not deleted", id.RawID())
+			}
+			continue
+		}
+		if offset := idx.FindOffsetByID(id); offset != e.offset {
+			return fmt.Errorf("index: offset of series ID %d is %d, expected %d", id.RawID(), offset, e.offset)
+		}
+		if got := idx.FindIDBySeriesKey(segments, e.key).SeriesID(); got != id {
+			return fmt.Errorf("index: ID of series key of ID %d is %d", id.RawID(), got.RawID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package seriesfile_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+	"github.com/influxdata/platform/cmd/influx_inspect/verify/seriesfile"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+)
+
+const spec = `
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:01:00Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 20
+
+  [[measurements.fields]]
+  name  = "usage"
+  type  = "float"
+  value = 0.5
+`
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, spec, "0000000000000002")
+	defer cleanup()
+
+	var stdout bytes.Buffer
+	cmd := seriesfile.NewCommand()
+	cmd.Stdout = &stdout
+	if err := cmd.Run("-engine-path", enginePath); err != nil {
+		t.Fatalf("unexpected error %v:\n%s", err, stdout.String())
+	} else if !strings.Contains(stdout.String(), "Broken Partitions: 0 / 8,") {
+		t.Fatalf("unexpected output\n%s", stdout.String())
+	}
+
+	// Overwrite the flag of the first entry of the first segment of a
+	// partition.
+	segment := filepath.Join(storage.NewConfig().GetSeriesFilePath(enginePath), "03", "0000")
+	f, err := os.OpenFile(segment, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte{0x07}, tsdb.SeriesSegmentHeaderSize); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	stdout.Reset()
+	if err := cmd.Run("-engine-path", enginePath); err == nil || err.Error() != "1 of 8 partitions are broken" {
+		t.Fatalf("unexpected error %v", err)
+	} else if !strings.Contains(stdout.String(), "invalid flag 7 of entry at 5") {
+		t.Fatalf("unexpected output\n%s", stdout.String())
+	}
+}
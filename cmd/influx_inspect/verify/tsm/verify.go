@@ -0,0 +1,141 @@
+// Package tsm verifies the checksums of the blocks of TSM files and that
+// their tombstones can be read.
+package tsm
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect verify".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	verbose bool
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	enginePath := fs.String("engine-path", "", "path of the storage engine")
+	fs.BoolVar(&cmd.verbose, "v", false, "print the result of every file")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintln(cmd.Stdout, "Verifies the checksums of the blocks of the TSM files and their tombstones.\n\nUsage: influx_inspect verify [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 || *enginePath == "" {
+		fs.Usage()
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(storage.NewConfig().GetEnginePath(*enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	return cmd.verify(files)
+}
+
+func (cmd *Command) verify(files []string) error {
+	start := time.Now()
+	w := cmd.Stdout
+
+	var brokenFiles, brokenBlocks, totalBlocks, tombstones int
+	for _, path := range files {
+		n, err := verifyTombstones(path)
+		tombstones += n
+		if err != nil {
+			brokenFiles++
+			fmt.Fprintf(w, "%s: could not read tombstones: %v\n", path, err)
+			continue
+		}
+
+		broken, total, err := cmd.verifyBlocks(w, path)
+		brokenBlocks += broken
+		totalBlocks += total
+		if err != nil {
+			brokenFiles++
+			fmt.Fprintf(w, "%s: could not read file: %v\n", path, err)
+			continue
+		}
+		if broken > 0 {
+			brokenFiles++
+		} else if cmd.verbose {
+			fmt.Fprintf(w, "%s: healthy\n", path)
+		}
+	}
+
+	fmt.Fprintf(w, "Broken Blocks: %d / %d, Tombstones: %d, in %vs\n", brokenBlocks, totalBlocks, tombstones, time.Since(start).Seconds())
+
+	if brokenFiles > 0 {
+		return fmt.Errorf("%d of %d files are broken", brokenFiles, len(files))
+	}
+	return nil
+}
+
+// verifyBlocks returns the number of blocks of the file whose checksum
+// doesn't match, and the number of blocks.
+func (cmd *Command) verifyBlocks(w io.Writer, path string) (broken, total int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer r.Close()
+
+	itr := r.BlockIterator()
+	for itr.Next() {
+		total++
+		key, minTime, maxTime, _, checksum, buf, err := itr.Read()
+		if err != nil {
+			return broken, total, err
+		}
+		if crc32.ChecksumIEEE(buf) != checksum {
+			broken++
+			fmt.Fprintf(w, "%s: checksum of block %d of key %q does not match\n", path, total, key)
+		} else if minTime > maxTime {
+			broken++
+			fmt.Fprintf(w, "%s: time range of block %d of key %q is invalid\n", path, total, key)
+		}
+	}
+	return broken, total, itr.Err()
+}
+
+// verifyTombstones reads the tombstones of a TSM file, if any, and returns
+// their number.
+func verifyTombstones(path string) (n int, err error) {
+	t := tsm1.NewTombstoner(path, nil)
+	if !t.HasTombstones() {
+		return 0, nil
+	}
+	err = t.Walk(func(ts tsm1.Tombstone) error {
+		n++
+		if ts.Min > ts.Max {
+			return fmt.Errorf("time range of tombstone %d of key %q is invalid", n, ts.Key)
+		}
+		return nil
+	})
+	return n, err
+}
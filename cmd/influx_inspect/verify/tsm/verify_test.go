@@ -0,0 +1,67 @@
+package tsm_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/internal/testutil"
+	"github.com/influxdata/platform/cmd/influx_inspect/verify/tsm"
+	"github.com/influxdata/platform/storage"
+)
+
+const spec = `
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:01:00Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 2
+
+  [[measurements.fields]]
+  name  = "usage"
+  type  = "float"
+  value = 0.5
+`
+
+func TestCommand_Run(t *testing.T) {
+	enginePath, cleanup := testutil.GenerateEngine(t, spec, "0000000000000002")
+	defer cleanup()
+
+	var stdout bytes.Buffer
+	cmd := tsm.NewCommand()
+	cmd.Stdout = &stdout
+	if err := cmd.Run("-engine-path", enginePath); err != nil {
+		t.Fatalf("unexpected error %v:\n%s", err, stdout.String())
+	} else if !strings.Contains(stdout.String(), "Broken Blocks: 0 / 2,") {
+		t.Fatalf("unexpected output\n%s", stdout.String())
+	}
+
+	// Corrupt the values of the first block, after the file header and the
+	// checksum of the block.
+	files, err := filepath.Glob(filepath.Join(storage.NewConfig().GetEnginePath(enginePath), "*.tsm"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("unexpected files %v: %v", files, err)
+	}
+	f, err := os.OpenFile(files[0], os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte{0xff}, 20); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	stdout.Reset()
+	if err := cmd.Run("-engine-path", enginePath); err == nil || err.Error() != "1 of 1 files are broken" {
+		t.Fatalf("unexpected error %v", err)
+	} else if !strings.Contains(stdout.String(), "checksum of block 1") || !strings.Contains(stdout.String(), "Broken Blocks: 1 / 2,") {
+		t.Fatalf("unexpected output\n%s", stdout.String())
+	}
+}
@@ -15,19 +15,27 @@ import (
 	"github.com/influxdata/flux/control"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/audit"
 	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/bootstrap"
+	"github.com/influxdata/platform/bucket"
+	"github.com/influxdata/platform/cascade"
 	"github.com/influxdata/platform/chronograf/server"
 	"github.com/influxdata/platform/gather"
+	"github.com/influxdata/platform/gather/discovery"
 	"github.com/influxdata/platform/http"
 	"github.com/influxdata/platform/internal/fs"
 	"github.com/influxdata/platform/kit/cli"
 	"github.com/influxdata/platform/kit/prom"
 	"github.com/influxdata/platform/kit/signals"
+	"github.com/influxdata/platform/kit/tracing"
 	influxlogger "github.com/influxdata/platform/logger"
 	"github.com/influxdata/platform/nats"
 	"github.com/influxdata/platform/query"
 	_ "github.com/influxdata/platform/query/builtin"
+	querycache "github.com/influxdata/platform/query/cache"
 	pcontrol "github.com/influxdata/platform/query/control"
+	"github.com/influxdata/platform/query/monitor"
 	"github.com/influxdata/platform/snowflake"
 	"github.com/influxdata/platform/source"
 	"github.com/influxdata/platform/storage"
@@ -44,6 +52,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/time/rate"
 )
 
 func main() {
@@ -80,10 +90,42 @@ type Main struct {
 	developerMode   bool
 	enginePath      string
 
+	scraperDiscoveryConfig string
+	oauthConfig            string
+	bootstrapConfig        string
+	auditBucketID          string
+
+	passwordMinLength           int
+	passwordMinCharacterClasses int
+	passwordMaxFailedAttempts   int
+	passwordLockoutDuration     time.Duration
+	passwordHashCost            int
+	signinRateLimit             int
+
+	orgDeletionGracePeriod    time.Duration
+	bucketDeletionGracePeriod time.Duration
+
+	queryOrgConcurrency int
+	queryOrgQueueSize   int
+	queryOrgMemoryBytes int
+
+	queryMonitoringBucket string
+	querySlowDuration     time.Duration
+	querySlowMemoryBytes  int
+
+	queryCacheMaxBytes int
+	queryCacheTTL      time.Duration
+	queryCacheMaxTTL   time.Duration
+
+	tracingExporter    string
+	tracingEndpoint    string
+	tracingSampleRatio float64
+
 	boltClient *bolt.Client
 	engine     *storage.Engine
 
 	queryController *pcontrol.Controller
+	queryCache      *querycache.Cache
 
 	httpPort   int
 	httpServer *nethttp.Server
@@ -93,6 +135,7 @@ type Main struct {
 	scheduler *taskbackend.TickScheduler
 
 	logger *zap.Logger
+	tracer *tracing.Tracer
 
 	Stdin  io.Reader
 	Stdout io.Writer
@@ -140,6 +183,13 @@ func (m *Main) Shutdown(ctx context.Context) {
 
 	m.wg.Wait()
 
+	if m.tracer != nil {
+		m.logger.Info("Stopping", zap.String("service", "tracing"))
+		if err := m.tracer.Close(); err != nil {
+			m.logger.Info("Failed to export the last spans", zap.Error(err))
+		}
+	}
+
 	m.logger.Sync()
 }
 
@@ -193,6 +243,149 @@ func (m *Main) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "engine"),
 				Desc:    "path to persistent engine files",
 			},
+			{
+				DestP:   &m.scraperDiscoveryConfig,
+				Flag:    "scraper-discovery-config",
+				Default: "",
+				Desc:    "path to the service discovery config of scraper targets",
+			},
+			{
+				DestP:   &m.oauthConfig,
+				Flag:    "oauth-config",
+				Default: "",
+				Desc:    "path to the config of the external identity providers users can sign in with",
+			},
+			{
+				DestP:   &m.bootstrapConfig,
+				Flag:    "bootstrap-config",
+				Default: "",
+				Desc:    "path to the config of the users, orgs, buckets and other resources to create at startup",
+			},
+			{
+				DestP:   &m.auditBucketID,
+				Flag:    "audit-bucket-id",
+				Default: "",
+				Desc:    "ID of the bucket the audit log is also written to",
+			},
+			{
+				DestP:   &m.passwordMinLength,
+				Flag:    "password-min-length",
+				Default: 8,
+				Desc:    "minimum number of characters of user passwords",
+			},
+			{
+				DestP:   &m.passwordMinCharacterClasses,
+				Flag:    "password-min-character-classes",
+				Default: 0,
+				Desc:    "minimum number of lowercase, uppercase, digit and symbol character classes user passwords must mix",
+			},
+			{
+				DestP:   &m.passwordMaxFailedAttempts,
+				Flag:    "password-max-failed-attempts",
+				Default: 0,
+				Desc:    "consecutive failed signin attempts after which a user is locked out, 0 disables the lockout, which anyone knowing the user name can trigger",
+			},
+			{
+				DestP:   &m.passwordLockoutDuration,
+				Flag:    "password-lockout-duration",
+				Default: 15 * time.Minute,
+				Desc:    "how long a user is locked out for after too many failed signin attempts",
+			},
+			{
+				DestP:   &m.passwordHashCost,
+				Flag:    "password-hash-cost",
+				Default: bcrypt.DefaultCost,
+				Desc:    "bcrypt cost of the password hashes, existing hashes are upgraded when users sign in",
+			},
+			{
+				DestP:   &m.signinRateLimit,
+				Flag:    "signin-rate-limit",
+				Default: 10,
+				Desc:    "signin attempts allowed per minute from each client, 0 disables the limit",
+			},
+			{
+				DestP:   &m.orgDeletionGracePeriod,
+				Flag:    "org-deletion-grace-period",
+				Default: 7 * 24 * time.Hour,
+				Desc:    "how long deleted organizations can be restored before they are purged with all their resources and data",
+			},
+			{
+				DestP:   &m.bucketDeletionGracePeriod,
+				Flag:    "bucket-deletion-grace-period",
+				Default: 7 * 24 * time.Hour,
+				Desc:    "how long deleted buckets can be restored before they are purged with their data",
+			},
+			{
+				DestP:   &m.queryOrgConcurrency,
+				Flag:    "query-org-concurrency",
+				Default: 0,
+				Desc:    "number of queries of an organization executing at once; unlimited if 0",
+			},
+			{
+				DestP:   &m.queryOrgQueueSize,
+				Flag:    "query-org-queue-size",
+				Default: 0,
+				Desc:    "number of queries of an organization waiting to execute before its queries are rejected; unlimited if 0",
+			},
+			{
+				DestP:   &m.queryOrgMemoryBytes,
+				Flag:    "query-org-memory-bytes",
+				Default: 0,
+				Desc:    "memory allocated by the executing queries of an organization above which its next queries wait; unlimited if 0",
+			},
+			{
+				DestP:   &m.queryMonitoringBucket,
+				Flag:    "query-monitoring-bucket",
+				Default: monitor.DefaultBucketName,
+				Desc:    "bucket of every organization the statistics of its queries are written to, created if missing; disabled if empty",
+			},
+			{
+				DestP:   &m.querySlowDuration,
+				Flag:    "query-slow-duration",
+				Default: time.Duration(0),
+				Desc:    "duration above which queries are logged as slow; disabled if 0",
+			},
+			{
+				DestP:   &m.querySlowMemoryBytes,
+				Flag:    "query-slow-memory-bytes",
+				Default: 0,
+				Desc:    "memory allocated above which queries are logged as slow; disabled if 0",
+			},
+			{
+				DestP:   &m.queryCacheMaxBytes,
+				Flag:    "query-cache-max-bytes",
+				Default: 0,
+				Desc:    "size of the results of Flux queries cached until data is written where they read; disabled if 0",
+			},
+			{
+				DestP:   &m.queryCacheTTL,
+				Flag:    "query-cache-ttl",
+				Default: time.Duration(0),
+				Desc:    "how long the results of Flux queries are cached, unless the requests set a max-age; only the results of such requests are cached if 0",
+			},
+			{
+				DestP:   &m.queryCacheMaxTTL,
+				Flag:    "query-cache-max-ttl",
+				Default: querycache.DefaultMaxTTL,
+				Desc:    "longest max-age that requests can cache the results of Flux queries for",
+			},
+			{
+				DestP:   &m.tracingExporter,
+				Flag:    "tracing-exporter",
+				Default: "log",
+				Desc:    "where the spans of traces are exported; supported exporters are log, jaeger, otlp, file and none",
+			},
+			{
+				DestP: &m.tracingEndpoint,
+				Flag:  "tracing-endpoint",
+				Desc:  fmt.Sprintf("address of the Jaeger agent (default %s), URL of the OTLP collector (default %s) or path of the file spans are exported to", tracing.DefaultJaegerAgentAddr, tracing.DefaultOTLPURL),
+			},
+			{
+				DestP:   &m.tracingSampleRatio,
+				Flag:    "tracing-sample-ratio",
+				Default: 1.0,
+				Desc:    "ratio of the traces started that are exported, the traces of requests keeping the decision of their caller; the log exporter logs every trace",
+			},
 		},
 	}
 
@@ -201,6 +394,57 @@ func (m *Main) Run(ctx context.Context, args ...string) error {
 	return cmd.Execute()
 }
 
+// newTracer returns the tracer exporting spans with the exporter configured.
+func (m *Main) newTracer() (opentracing.Tracer, error) {
+	const service = "influxd"
+
+	var e tracing.Exporter
+	switch m.tracingExporter {
+	case "log":
+		tracer := new(pzap.Tracer)
+		tracer.Logger = m.logger
+		tracer.IDGenerator = snowflake.NewIDGenerator()
+		return tracer, nil
+	case "none":
+		return opentracing.NoopTracer{}, nil
+	case "jaeger":
+		addr := m.tracingEndpoint
+		if addr == "" {
+			addr = tracing.DefaultJaegerAgentAddr
+		}
+		je, err := tracing.NewJaegerExporter(addr, service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the Jaeger agent: %v", err)
+		}
+		e = je
+	case "otlp":
+		url := m.tracingEndpoint
+		if url == "" {
+			url = tracing.DefaultOTLPURL
+		}
+		e = tracing.NewOTLPExporter(url, service)
+	case "file":
+		if m.tracingEndpoint == "" {
+			return nil, fmt.Errorf("the file exporter requires the path of a file as tracing endpoint")
+		}
+		fe, err := tracing.OpenFileExporter(m.tracingEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		e = fe
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q; supported exporters are log, jaeger, otlp, file and none", m.tracingExporter)
+	}
+
+	m.tracer = tracing.NewTracer(e)
+	m.tracer.SampleRatio = m.tracingSampleRatio
+	m.tracer.Logger = m.logger.With(zap.String("service", "tracing"))
+	if err := m.tracer.Open(); err != nil {
+		return nil, err
+	}
+	return m.tracer, nil
+}
+
 func (m *Main) run(ctx context.Context) (err error) {
 	m.running = true
 	ctx, m.cancel = context.WithCancel(ctx)
@@ -221,9 +465,10 @@ func (m *Main) run(ctx context.Context) (err error) {
 	}
 
 	// set tracing
-	tracer := new(pzap.Tracer)
-	tracer.Logger = m.logger
-	tracer.IDGenerator = snowflake.NewIDGenerator()
+	tracer, err := m.newTracer()
+	if err != nil {
+		return err
+	}
 	opentracing.SetGlobalTracer(tracer)
 
 	reg := prom.NewRegistry()
@@ -233,6 +478,15 @@ func (m *Main) run(ctx context.Context) (err error) {
 	m.boltClient = bolt.NewClient()
 	m.boltClient.Path = m.boltPath
 	m.boltClient.WithLogger(m.logger.With(zap.String("service", "bolt")))
+	m.boltClient.PasswordPolicy = platform.PasswordPolicy{
+		MinLength:           m.passwordMinLength,
+		MinCharacterClasses: m.passwordMinCharacterClasses,
+		MaxFailedAttempts:   m.passwordMaxFailedAttempts,
+		LockoutDuration:     m.passwordLockoutDuration,
+	}
+	m.boltClient.PasswordHashers = platform.PasswordHashers{
+		platform.BcryptHasher{Cost: m.passwordHashCost},
+	}
 
 	if err := m.boltClient.Open(ctx); err != nil {
 		m.logger.Error("failed opening bolt", zap.Error(err))
@@ -241,6 +495,7 @@ func (m *Main) run(ctx context.Context) (err error) {
 
 	var (
 		orgSvc           platform.OrganizationService             = m.boltClient
+		groupSvc         platform.GroupService                    = m.boltClient
 		authSvc          platform.AuthorizationService            = m.boltClient
 		userSvc          platform.UserService                     = m.boltClient
 		viewSvc          platform.ViewService                     = m.boltClient
@@ -256,11 +511,48 @@ func (m *Main) run(ctx context.Context) (err error) {
 		orgLogSvc        platform.OrganizationOperationLogService = m.boltClient
 		onboardingSvc    platform.OnboardingService               = m.boltClient
 		scraperTargetSvc platform.ScraperTargetStoreService       = m.boltClient
+		scraperStatusSvc platform.ScraperTargetStatusService      = m.boltClient
 		telegrafSvc      platform.TelegrafConfigStore             = m.boltClient
 		userResourceSvc  platform.UserResourceMappingService      = m.boltClient
 		labelSvc         platform.LabelService                    = m.boltClient
+		secretSvc        platform.SecretService                   = m.boltClient
 	)
 
+	// The changes made through the services are recorded to the audit log,
+	// whether they are made by the API, the bootstrap config, the import of
+	// bundles or the deletion of organizations.
+	auditRecorder := audit.NewRecorder(m.boltClient)
+	auditRecorder.UserResourceMappingService = m.boltClient
+	auditRecorder.Logger = m.logger.With(zap.String("service", "audit"))
+	orgSvc = &audit.OrganizationService{OrganizationService: orgSvc, Recorder: auditRecorder}
+	groupSvc = &audit.GroupService{GroupService: groupSvc, Recorder: auditRecorder}
+	authSvc = &audit.AuthorizationService{AuthorizationService: authSvc, Recorder: auditRecorder}
+	userSvc = &audit.UserService{UserService: userSvc, Recorder: auditRecorder}
+	viewSvc = &audit.ViewService{ViewService: viewSvc, Recorder: auditRecorder}
+	macroSvc = &audit.MacroService{MacroService: macroSvc, Recorder: auditRecorder}
+	bucketSvc = &audit.BucketService{BucketService: bucketSvc, Recorder: auditRecorder}
+	sourceSvc = &audit.SourceService{SourceService: sourceSvc, Recorder: auditRecorder}
+	dashboardSvc = &audit.DashboardService{DashboardService: dashboardSvc, Recorder: auditRecorder}
+	scraperTargetSvc = &audit.ScraperTargetStoreService{ScraperTargetStoreService: scraperTargetSvc, Recorder: auditRecorder}
+	telegrafSvc = &audit.TelegrafService{TelegrafConfigStore: telegrafSvc, Recorder: auditRecorder}
+	userResourceSvc = &audit.UserResourceMappingService{UserResourceMappingService: userResourceSvc, Recorder: auditRecorder}
+	labelSvc = &audit.LabelService{LabelService: labelSvc, Recorder: auditRecorder}
+	secretSvc = &audit.SecretService{SecretService: secretSvc, Recorder: auditRecorder}
+	// bucketStoreSvc sees the deleted buckets.
+	bucketStoreSvc := bucketSvc
+
+	// The deleted buckets are hidden from the other services until purged,
+	// except for the retention enforcer and the deletion of organizations.
+	bucketLifecycleSvc := &bucket.Service{
+		BucketService:             bucketStoreSvc,
+		OrganizationService:       orgSvc,
+		TelegrafService:           telegrafSvc,
+		ScraperTargetStoreService: scraperTargetSvc,
+		GracePeriod:               m.bucketDeletionGracePeriod,
+		Logger:                    m.logger.With(zap.String("service", "bucket-deletion")),
+	}
+	bucketSvc = bucketLifecycleSvc
+
 	chronografSvc, err := server.NewServiceV2(ctx, m.boltClient.DB())
 	if err != nil {
 		m.logger.Error("failed creating chronograf service", zap.Error(err))
@@ -269,8 +561,9 @@ func (m *Main) run(ctx context.Context) (err error) {
 
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(m.boltClient))
 		m.engine.WithLogger(m.logger)
+		bucketLifecycleSvc.Engine = m.engine
 
 		if err := m.engine.Open(); err != nil {
 			m.logger.Error("failed to open engine", zap.Error(err))
@@ -280,6 +573,16 @@ func (m *Main) run(ctx context.Context) (err error) {
 		reg.MustRegister(m.engine.PrometheusCollectors()...)
 
 		pointsWriter = m.engine
+		if m.queryCacheMaxBytes > 0 {
+			// The results of the queries reading where points are written
+			// are dropped from the cache.
+			m.queryCache = querycache.New(int64(m.queryCacheMaxBytes))
+			reg.MustRegister(m.queryCache.PrometheusCollectors()...)
+			pointsWriter = &querycache.PointsWriter{
+				PointsWriter: m.engine,
+				Cache:        m.queryCache,
+			}
+		}
 
 		const (
 			concurrencyQuota = 10
@@ -294,18 +597,49 @@ func (m *Main) run(ctx context.Context) (err error) {
 		}
 
 		if err := readservice.AddControllerConfigDependencies(
-			&cc, m.engine, bucketSvc, orgSvc,
+			&cc, m.engine, pointsWriter, bucketSvc, orgSvc,
 		); err != nil {
 			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
 			return err
 		}
 
-		m.queryController = pcontrol.New(cc)
+		var queryLogger query.Logger
+		if m.queryMonitoringBucket != "" || m.querySlowDuration > 0 || m.querySlowMemoryBytes > 0 {
+			ql := monitor.NewQueryLogger(bucketSvc, pointsWriter)
+			ql.Logger = m.logger.With(zap.String("service", "query-monitor"))
+			ql.BucketName = m.queryMonitoringBucket
+			ql.SlowQueryDuration = m.querySlowDuration
+			ql.SlowQueryMemoryBytes = int64(m.querySlowMemoryBytes)
+			queryLogger = ql
+		}
+
+		m.queryController = pcontrol.New(pcontrol.Config{
+			Config: cc,
+			OrgLimits: pcontrol.OrgLimits{
+				ConcurrencyQuota: m.queryOrgConcurrency,
+				QueueSize:        m.queryOrgQueueSize,
+				MemoryBytesQuota: int64(m.queryOrgMemoryBytes),
+			},
+			QueryLogger:         queryLogger,
+			OrganizationService: orgSvc,
+		})
 		reg.MustRegister(m.queryController.PrometheusCollectors()...)
 	}
 
 	var storageQueryService query.ProxyQueryService = readservice.NewProxyQueryService(m.queryController)
+	if m.queryCache != nil {
+		storageQueryService = &querycache.ProxyQueryService{
+			ProxyQueryService: storageQueryService,
+			Cache:             m.queryCache,
+			BucketService:     bucketSvc,
+			TTL:               m.queryCacheTTL,
+			MaxTTL:            m.queryCacheMaxTTL,
+		}
+	}
 	var taskSvc platform.TaskService
+	// bootstrapTaskSvc doesn't check the permissions of the context, which
+	// the startup has none of; bootstrap configs are only applied by operators.
+	var bootstrapTaskSvc platform.TaskService
 	{
 		boltStore, err := taskbolt.New(m.boltClient.DB(), "tasks")
 		if err != nil {
@@ -322,8 +656,10 @@ func (m *Main) run(ctx context.Context) (err error) {
 
 		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
 		lr := taskbackend.NewQueryLogReader(queryService)
-		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
-		taskSvc = task.NewValidator(taskSvc, bucketSvc)
+		bootstrapTaskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
+		bootstrapTaskSvc = &audit.TaskService{TaskService: bootstrapTaskSvc, Recorder: auditRecorder}
+		taskSvc = task.NewValidator(bootstrapTaskSvc, bucketSvc)
+		bucketLifecycleSvc.TaskService = taskSvc
 	}
 
 	// NATS streaming server
@@ -346,11 +682,44 @@ func (m *Main) run(ctx context.Context) (err error) {
 		return err
 	}
 
-	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, publisher, subscriber, 0, 0)
+	reg.MustRegister(subscriber.PrometheusCollectors()...)
+
+	// Writes are buffered in the queue while the engine is under write pressure.
+	bufferedWriter := storage.NewBufferedPointsWriter(pointsWriter, publisher, m.engine.UnderWritePressure)
+	bufferedWriter.Logger = m.logger.With(zap.String("service", "write-buffer"))
+	if err := subscriber.Subscribe(storage.WriteBufferSubject, "storage", bufferedWriter); err != nil {
+		m.logger.Error("failed to subscribe to write buffer", zap.Error(err))
+		return err
+	}
+
+	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, scraperStatusSvc, publisher, subscriber, 0, 0)
 	if err != nil {
 		m.logger.Error("failed to create scraper subscriber", zap.Error(err))
 		return err
 	}
+	if err := subscriber.Subscribe(gather.MetricsSubject, "", &gather.StorageHandler{
+		Logger: m.logger.With(zap.String("service", "scraper-storage")),
+		Storage: &gather.PointWriter{
+			OrganizationService: orgSvc,
+			BucketService:       bucketSvc,
+			Writer:              bufferedWriter,
+		},
+	}); err != nil {
+		m.logger.Error("failed to subscribe to scraper metrics", zap.Error(err))
+		return err
+	}
+
+	if m.scraperDiscoveryConfig != "" {
+		c, err := discovery.LoadConfig(m.scraperDiscoveryConfig)
+		if err != nil {
+			m.logger.Error("failed to load scraper discovery config", zap.Error(err))
+			return err
+		}
+		if scraperScheduler.Discoverers, err = c.Discoverers(); err != nil {
+			m.logger.Error("failed to create scraper discoverers", zap.Error(err))
+			return err
+		}
+	}
 
 	m.wg.Add(1)
 	go func(logger *zap.Logger) {
@@ -362,20 +731,115 @@ func (m *Main) run(ctx context.Context) (err error) {
 		logger.Info("Stopping")
 	}(m.logger)
 
+	var oauthConfig *http.OAuthConfig
+	if m.oauthConfig != "" {
+		if oauthConfig, err = http.LoadOAuthConfig(m.oauthConfig); err != nil {
+			m.logger.Error("failed to load oauth config", zap.Error(err))
+			return err
+		}
+		if _, err := oauthConfig.OAuthProviders(); err != nil {
+			m.logger.Error("failed to create oauth providers", zap.Error(err))
+			return err
+		}
+	}
+
+	var auditSvc platform.AuditLogService = m.boltClient
+	if m.auditBucketID != "" {
+		bucketID, err := platform.IDFromString(m.auditBucketID)
+		if err != nil {
+			m.logger.Error("invalid audit bucket id", zap.Error(err))
+			return err
+		}
+		w := audit.NewBucketWriter(auditSvc, bucketSvc, bufferedWriter, *bucketID)
+		w.Logger = m.logger.With(zap.String("service", "audit"))
+		auditSvc = w
+		// the changes are also written to the bucket from now on.
+		auditRecorder.AuditLogService = w
+	}
+
+	if m.bootstrapConfig != "" {
+		c, err := bootstrap.LoadConfig(m.bootstrapConfig)
+		if err != nil {
+			m.logger.Error("failed to load bootstrap config", zap.Error(err))
+			return err
+		}
+		a := &bootstrap.Applier{
+			UserService:                userSvc,
+			BasicAuthService:           basicAuthSvc,
+			OrganizationService:        orgSvc,
+			BucketService:              bucketSvc,
+			UserResourceMappingService: userResourceSvc,
+			AuthorizationService:       authSvc,
+			DashboardService:           dashboardSvc,
+			TaskService:                bootstrapTaskSvc,
+			TelegrafService:            telegrafSvc,
+		}
+		changes, err := a.Apply(ctx, c)
+		for _, change := range changes {
+			m.logger.Info("Applied bootstrap config", zap.Stringer("change", change))
+		}
+		if err != nil {
+			m.logger.Error("failed to apply bootstrap config", zap.Error(err))
+			return err
+		}
+	}
+
+	orgDeletionSvc := &cascade.Service{
+		OrganizationService:        orgSvc,
+		BucketService:              bucketStoreSvc,
+		UserService:                userSvc,
+		UserResourceMappingService: userResourceSvc,
+		AuthorizationService:       authSvc,
+		DashboardService:           dashboardSvc,
+		TaskService:                taskSvc,
+		TelegrafService:            telegrafSvc,
+		SecretService:              secretSvc,
+		LabelService:               labelSvc,
+		Engine:                     m.engine,
+		GracePeriod:                m.orgDeletionGracePeriod,
+		Logger:                     m.logger.With(zap.String("service", "org-deletion")),
+	}
+
+	m.wg.Add(1)
+	go func(logger *zap.Logger) {
+		defer m.wg.Done()
+		if err := orgDeletionSvc.Run(ctx); err != nil {
+			logger.Error("failed org deletion service", zap.Error(err))
+		}
+		logger.Info("Stopping")
+	}(orgDeletionSvc.Logger)
+
+	m.wg.Add(1)
+	go func(logger *zap.Logger) {
+		defer m.wg.Done()
+		if err := bucketLifecycleSvc.Run(ctx); err != nil {
+			logger.Error("failed bucket deletion service", zap.Error(err))
+		}
+		logger.Info("Stopping")
+	}(bucketLifecycleSvc.Logger)
+
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
 	}
 
+	var signinLimiter *http.RateLimiter
+	if m.signinRateLimit > 0 {
+		signinLimiter = http.NewRateLimiter(rate.Every(time.Minute/time.Duration(m.signinRateLimit)), m.signinRateLimit)
+	}
+
 	handlerConfig := &http.APIBackend{
 		Logger:                          m.logger,
 		NewBucketService:                source.NewBucketService,
 		NewQueryService:                 source.NewQueryService,
-		PointsWriter:                    pointsWriter,
+		PointsWriter:                    bufferedWriter,
 		AuthorizationService:            authSvc,
+		AuthorizationUsageService:       m.boltClient,
 		BucketService:                   bucketSvc,
+		BucketLifecycleService:          bucketLifecycleSvc,
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
+		GroupService:                    groupSvc,
 		UserResourceMappingService:      userResourceSvc,
 		LabelService:                    labelSvc,
 		DashboardService:                dashboardSvc,
@@ -383,16 +847,23 @@ func (m *Main) run(ctx context.Context) (err error) {
 		BucketOperationLogService:       bucketLogSvc,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
+		OrganizationDeletionService:     orgDeletionSvc,
+		AuditLogService:                 auditSvc,
 		ViewService:                     viewSvc,
 		SourceService:                   sourceSvc,
 		MacroService:                    macroSvc,
 		BasicAuthService:                basicAuthSvc,
 		OnboardingService:               onboardingSvc,
 		ProxyQueryService:               storageQueryService,
+		ActiveQueryService:              m.queryController,
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
+		ScraperTargetStatusService:      scraperStatusSvc,
 		ChronografService:               chronografSvc,
+		OAuthConfig:                     oauthConfig,
+		SigninLimiter:                   signinLimiter,
+		BootstrapTaskService:            bootstrapTaskSvc,
 	}
 
 	// HTTP server
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
 	"github.com/influxdata/platform/cmd/influxd"
 	"github.com/influxdata/platform/http"
 )
@@ -77,6 +78,61 @@ func TestMain_WriteAndQuery(t *testing.T) {
 	}
 }
 
+const bootstrapConfig = `
+users:
+- name: admin
+orgs:
+- name: acme
+  owners: [admin]
+  buckets:
+  - name: telegraf
+  tasks:
+  - owner: admin
+    flux: |
+      option task = {name: "downsample", every: 1h}
+      from(bucket: "telegraf") |> range(start: -1h)
+`
+
+func TestMain_Bootstrap(t *testing.T) {
+	m := NewMain()
+	path := filepath.Join(m.Path, "bootstrap.yml")
+	if err := ioutil.WriteFile(path, []byte(bootstrapConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the tasks of the config are created at startup, without an authorizer.
+	if err := m.Run(ctx, "--bootstrap-config", path); err != nil {
+		t.Fatal(err)
+	}
+	m.SetupOrFail(t)
+	defer m.ShutdownOrFail(t, ctx)
+
+	c, err := bootstrap.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := &http.BootstrapService{Addr: m.URL(), Token: m.Auth.Token}
+	if changes, err := svc.Apply(ctx, c, true); err != nil {
+		t.Fatal(err)
+	} else if len(changes) != 0 {
+		t.Fatalf("expected the config to be applied at startup, got changes %v", changes)
+	}
+
+	// over HTTP, the operator creates a task in an org created by the same
+	// config, which it has no permission on.
+	c.Orgs[0].Name = "beta"
+	changes, err := svc.Apply(ctx, c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	if diff := cmp.Diff(got, []string{"+ org beta", "+ org owner beta/admin", "+ bucket beta/telegraf", "+ task beta/downsample"}); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 // Main is a test wrapper for main.Main.
 type Main struct {
 	*main.Main
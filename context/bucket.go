@@ -0,0 +1,25 @@
+package context
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+)
+
+const bucketReferencePolicyCtxKey = contextKey("influx/bucket-reference-policy/v1")
+
+// SetBucketReferencePolicy sets on context what renaming or deleting a
+// bucket does about the resources referring to it.
+func SetBucketReferencePolicy(ctx context.Context, p platform.BucketReferencePolicy) context.Context {
+	return context.WithValue(ctx, bucketReferencePolicyCtxKey, p)
+}
+
+// GetBucketReferencePolicy retrieves the bucket reference policy from
+// context, defaulting to platform.BucketReferencesCheck.
+func GetBucketReferencePolicy(ctx context.Context) platform.BucketReferencePolicy {
+	p, ok := ctx.Value(bucketReferencePolicyCtxKey).(platform.BucketReferencePolicy)
+	if !ok || p == "" {
+		return platform.BucketReferencesCheck
+	}
+	return p
+}
@@ -0,0 +1,25 @@
+package context
+
+import (
+	"context"
+)
+
+const requestCtxKey = contextKey("influx/request/v1")
+
+// Request identifies the API request a change is made by.
+type Request struct {
+	ID     string
+	Method string
+	Path   string
+}
+
+// SetRequest sets on context the API request being served.
+func SetRequest(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestCtxKey, r)
+}
+
+// GetRequest retrieves the API request being served from context, if any.
+func GetRequest(ctx context.Context) (Request, bool) {
+	r, ok := ctx.Value(requestCtxKey).(Request)
+	return r, ok
+}
@@ -0,0 +1,104 @@
+// Package discovery finds scraper targets from external sources, so that a
+// dynamic fleet doesn't have to be defined target by target.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/platform"
+)
+
+// Discoverer provides scraper targets discovered from an external source.
+type Discoverer interface {
+	// Targets returns the targets currently known to the source.
+	Targets(ctx context.Context) ([]platform.ScraperTarget, error)
+}
+
+// TargetConfig describes how discovered addresses become scraper targets.
+type TargetConfig struct {
+	// Org and Bucket are where the metrics of discovered targets are written.
+	Org    string `json:"org"`
+	Bucket string `json:"bucket"`
+	// Scheme is the scheme used to scrape targets; defaults to http.
+	Scheme string `json:"scheme"`
+	// MetricsPath is the path of the metrics endpoint; defaults to /metrics.
+	MetricsPath string `json:"metricsPath"`
+}
+
+// target returns the scraper target for an address, e.g. "host:9100".
+func (c TargetConfig) target(addr string, labels map[string]string) platform.ScraperTarget {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := c.MetricsPath
+	if path == "" {
+		path = "/metrics"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   addr,
+		Path:   path,
+	}
+	return platform.ScraperTarget{
+		Name:       addr,
+		Type:       platform.PrometheusScraperType,
+		URL:        u.String(),
+		OrgName:    c.Org,
+		BucketName: c.Bucket,
+		Labels:     labels,
+	}
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// sanitizeLabelName replaces the characters that aren't valid in a
+// prometheus label name with underscores.
+func sanitizeLabelName(name string) string {
+	return invalidLabelChars.ReplaceAllString(name, "_")
+}
+
+// Config is the service discovery configuration of the scraper. It is
+// usually read from a YAML or JSON file.
+type Config struct {
+	Files      []FileConfig       `json:"files"`
+	DNS        []DNSConfig        `json:"dns"`
+	Kubernetes []KubernetesConfig `json:"kubernetes"`
+}
+
+// LoadConfig reads the discovery configuration at path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := new(Config)
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("invalid discovery config %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// Discoverers returns a Discoverer for every provider in the configuration.
+func (c *Config) Discoverers() ([]Discoverer, error) {
+	ds := make([]Discoverer, 0, len(c.Files)+len(c.DNS)+len(c.Kubernetes))
+	for _, fc := range c.Files {
+		ds = append(ds, NewFileDiscoverer(fc))
+	}
+	for _, dc := range c.DNS {
+		ds = append(ds, NewDNSDiscoverer(dc))
+	}
+	for _, kc := range c.Kubernetes {
+		d, err := NewKubernetesDiscoverer(kc)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
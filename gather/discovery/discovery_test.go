@@ -0,0 +1,55 @@
+package discovery_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/influxdata/platform/gather/discovery"
+)
+
+func TestLoadConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "discovery-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`
+files:
+  - org: org
+    bucket: bucket
+    files: ["/etc/influxdb/targets/*.json"]
+dns:
+  - org: org
+    bucket: bucket
+    names: ["_metrics._tcp.example.com"]
+kubernetes:
+  - org: org
+    bucket: bucket
+    apiServer: https://kubernetes.default.svc
+    namespaces: ["monitoring"]
+`)
+	f.Close()
+
+	c, err := discovery.LoadConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Files) != 1 || c.Files[0].Org != "org" || c.Files[0].Files[0] != "/etc/influxdb/targets/*.json" {
+		t.Errorf("unexpected file config %+v", c.Files)
+	}
+	if len(c.DNS) != 1 || c.DNS[0].Names[0] != "_metrics._tcp.example.com" {
+		t.Errorf("unexpected dns config %+v", c.DNS)
+	}
+	if len(c.Kubernetes) != 1 || c.Kubernetes[0].APIServer != "https://kubernetes.default.svc" {
+		t.Errorf("unexpected kubernetes config %+v", c.Kubernetes)
+	}
+
+	ds, err := c.Discoverers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 3 {
+		t.Fatalf("expected 3 discoverers, got %d", len(ds))
+	}
+}
@@ -0,0 +1,55 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/platform"
+)
+
+// DNSConfig configures the discovery of targets from DNS SRV records.
+type DNSConfig struct {
+	TargetConfig
+	// Names are the SRV record names to look up, e.g. _metrics._tcp.example.com.
+	Names []string `json:"names"`
+}
+
+// Resolver looks up DNS SRV records. It is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// DNSDiscoverer discovers a target for every record of a set of DNS SRV
+// names. The record name is added to the targets as the dns_name label.
+type DNSDiscoverer struct {
+	Config   DNSConfig
+	Resolver Resolver
+}
+
+// NewDNSDiscoverer returns a DNSDiscoverer using the default resolver.
+func NewDNSDiscoverer(c DNSConfig) *DNSDiscoverer {
+	return &DNSDiscoverer{
+		Config:   c,
+		Resolver: net.DefaultResolver,
+	}
+}
+
+// Targets looks up the SRV records and returns their targets.
+func (d *DNSDiscoverer) Targets(ctx context.Context) ([]platform.ScraperTarget, error) {
+	var targets []platform.ScraperTarget
+	for _, name := range d.Config.Names {
+		_, srvs, err := d.Resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			addr := net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port)))
+			targets = append(targets, d.Config.target(addr, map[string]string{
+				"dns_name": name,
+			}))
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,71 @@
+package discovery_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/gather/discovery"
+)
+
+type fakeResolver map[string][]*net.SRV
+
+func (r fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	srvs, ok := r[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name}
+	}
+	return name, srvs, nil
+}
+
+func TestDNSDiscoverer(t *testing.T) {
+	d := discovery.NewDNSDiscoverer(discovery.DNSConfig{
+		TargetConfig: discovery.TargetConfig{
+			Org:         "org",
+			Bucket:      "bucket",
+			Scheme:      "https",
+			MetricsPath: "/debug/metrics",
+		},
+		Names: []string{"_metrics._tcp.example.com"},
+	})
+	d.Resolver = fakeResolver{
+		"_metrics._tcp.example.com": {
+			{Target: "node1.example.com.", Port: 9100},
+			{Target: "node2.example.com.", Port: 9200},
+		},
+	}
+
+	got, err := d.Targets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := map[string]string{"dns_name": "_metrics._tcp.example.com"}
+	want := []platform.ScraperTarget{
+		{
+			Name:       "node1.example.com:9100",
+			Type:       platform.PrometheusScraperType,
+			URL:        "https://node1.example.com:9100/debug/metrics",
+			OrgName:    "org",
+			BucketName: "bucket",
+			Labels:     labels,
+		},
+		{
+			Name:       "node2.example.com:9200",
+			Type:       platform.PrometheusScraperType,
+			URL:        "https://node2.example.com:9200/debug/metrics",
+			OrgName:    "org",
+			BucketName: "bucket",
+			Labels:     labels,
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected targets -got/+want\ndiff %s", diff)
+	}
+
+	d.Config.Names = append(d.Config.Names, "_missing._tcp.example.com")
+	if _, err := d.Targets(context.Background()); err == nil {
+		t.Fatal("expected an error for a failed lookup")
+	}
+}
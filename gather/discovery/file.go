@@ -0,0 +1,143 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/platform"
+)
+
+// FileConfig configures the discovery of targets from target files.
+type FileConfig struct {
+	TargetConfig
+	// Files are the paths, or glob patterns, of the target files.
+	Files []string `json:"files"`
+}
+
+// targetGroup is the prometheus file based service discovery format,
+// a list of which is the content of a target file.
+type targetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+type targetFile struct {
+	modTime time.Time
+	size    int64
+	targets []platform.ScraperTarget
+}
+
+// FileDiscoverer discovers targets from JSON or YAML target files in the
+// prometheus file based service discovery format:
+//
+//	[{"targets": ["host1:9100", "host2:9100"], "labels": {"env": "prod"}}]
+//
+// Files are only read again when they change.
+type FileDiscoverer struct {
+	Config FileConfig
+
+	mu    sync.Mutex
+	files map[string]*targetFile
+}
+
+// NewFileDiscoverer returns a FileDiscoverer for the configuration.
+func NewFileDiscoverer(c FileConfig) *FileDiscoverer {
+	return &FileDiscoverer{
+		Config: c,
+		files:  make(map[string]*targetFile),
+	}
+}
+
+// Targets returns the targets listed in the target files.
+func (d *FileDiscoverer) Targets(ctx context.Context) ([]platform.ScraperTarget, error) {
+	paths, err := d.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seen := make(map[string]bool, len(paths))
+	var targets []platform.ScraperTarget
+	for _, path := range paths {
+		f, err := d.load(path)
+		if err != nil {
+			return nil, err
+		}
+		seen[path] = true
+		targets = append(targets, f.targets...)
+	}
+
+	// forget files that were removed.
+	for path := range d.files {
+		if !seen[path] {
+			delete(d.files, path)
+		}
+	}
+	return targets, nil
+}
+
+// paths expands the configured patterns into a sorted list of files.
+func (d *FileDiscoverer) paths() ([]string, error) {
+	var paths []string
+	for _, pattern := range d.Config.Files {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// load returns the targets of the file at path, reading it only if it
+// changed since it was last read.
+func (d *FileDiscoverer) load(path string) (*targetFile, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := d.files[path]; ok && f.modTime.Equal(fi.ModTime()) && f.size == fi.Size() {
+		return f, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var groups []targetGroup
+	switch ext := filepath.Ext(path); ext {
+	case ".json", ".yml", ".yaml":
+		// YAML is a superset of JSON.
+		if err := yaml.Unmarshal(b, &groups); err != nil {
+			return nil, fmt.Errorf("invalid target file %s: %v", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported target file extension %q", ext)
+	}
+
+	f := &targetFile{
+		modTime: fi.ModTime(),
+		size:    fi.Size(),
+	}
+	for _, g := range groups {
+		for _, addr := range g.Targets {
+			labels := make(map[string]string, len(g.Labels))
+			for k, v := range g.Labels {
+				labels[sanitizeLabelName(k)] = v
+			}
+			f.targets = append(f.targets, d.Config.target(addr, labels))
+		}
+	}
+	d.files[path] = f
+	return f, nil
+}
@@ -0,0 +1,109 @@
+package discovery_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/gather/discovery"
+)
+
+func TestFileDiscoverer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("a.json", `[{"targets": ["host1:9100"], "labels": {"env": "prod", "team.name": "ops"}}]`, now)
+	write("b.yml", `
+- targets: ["host2:9100", "host3:9100"]
+`, now)
+	write("ignored.txt", "", now)
+
+	d := discovery.NewFileDiscoverer(discovery.FileConfig{
+		TargetConfig: discovery.TargetConfig{
+			Org:    "org",
+			Bucket: "bucket",
+		},
+		Files: []string{
+			filepath.Join(dir, "*.json"),
+			filepath.Join(dir, "*.yml"),
+		},
+	})
+
+	target := func(addr string, labels map[string]string) platform.ScraperTarget {
+		return platform.ScraperTarget{
+			Name:       addr,
+			Type:       platform.PrometheusScraperType,
+			URL:        "http://" + addr + "/metrics",
+			OrgName:    "org",
+			BucketName: "bucket",
+			Labels:     labels,
+		}
+	}
+
+	ctx := context.Background()
+	got, err := d.Targets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []platform.ScraperTarget{
+		target("host1:9100", map[string]string{"env": "prod", "team_name": "ops"}),
+		target("host2:9100", map[string]string{}),
+		target("host3:9100", map[string]string{}),
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected targets -got/+want\ndiff %s", diff)
+	}
+
+	// a change to a file is picked up, and a removed file drops its targets.
+	write("a.json", `[{"targets": ["host4:9100"]}]`, now.Add(time.Minute))
+	if err := os.Remove(filepath.Join(dir, "b.yml")); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.Targets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []platform.ScraperTarget{
+		target("host4:9100", map[string]string{}),
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected targets after change -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestFileDiscoverer_InvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"targets": "host1"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d := discovery.NewFileDiscoverer(discovery.FileConfig{
+		Files: []string{filepath.Join(dir, "*.json")},
+	})
+	if _, err := d.Targets(context.Background()); err == nil {
+		t.Fatal("expected an error for an invalid target file")
+	}
+}
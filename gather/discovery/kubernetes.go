@@ -0,0 +1,202 @@
+package discovery
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/platform"
+)
+
+// pod annotations used to opt pods into scraping, as with prometheus.
+const (
+	scrapeAnnotation = "prometheus.io/scrape"
+	portAnnotation   = "prometheus.io/port"
+	pathAnnotation   = "prometheus.io/path"
+	schemeAnnotation = "prometheus.io/scheme"
+)
+
+// KubernetesConfig configures the discovery of pods from the Kubernetes API.
+type KubernetesConfig struct {
+	TargetConfig
+	// APIServer is the URL of the Kubernetes API server.
+	APIServer string `json:"apiServer"`
+	// Namespaces restricts discovery to some namespaces; all namespaces
+	// are searched when it is empty.
+	Namespaces []string `json:"namespaces"`
+	// BearerTokenFile is the file holding the token used to authenticate
+	// with the API server, e.g. the service account token of the pod.
+	BearerTokenFile    string `json:"bearerTokenFile"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+}
+
+// KubernetesDiscoverer discovers the running pods annotated with
+// prometheus.io/scrape: "true". The pod port, metrics path and scheme can be
+// overridden by the prometheus.io/port, prometheus.io/path and
+// prometheus.io/scheme annotations. The namespace, pod name and pod labels
+// are added to the targets as labels.
+type KubernetesDiscoverer struct {
+	Config KubernetesConfig
+	Client *http.Client
+
+	token string
+}
+
+// NewKubernetesDiscoverer returns a KubernetesDiscoverer for the configuration.
+func NewKubernetesDiscoverer(c KubernetesConfig) (*KubernetesDiscoverer, error) {
+	if c.APIServer == "" {
+		return nil, fmt.Errorf("kubernetes discovery requires an api server")
+	}
+	d := &KubernetesDiscoverer{
+		Config: c,
+		Client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: c.InsecureSkipVerify,
+				},
+			},
+		},
+	}
+	if c.BearerTokenFile != "" {
+		b, err := ioutil.ReadFile(c.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		d.token = strings.TrimSpace(string(b))
+	}
+	return d, nil
+}
+
+type podList struct {
+	Items []pod `json:"items"`
+}
+
+type pod struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Containers []struct {
+			Ports []struct {
+				ContainerPort int    `json:"containerPort"`
+				Protocol      string `json:"protocol"`
+			} `json:"ports"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase string `json:"phase"`
+		PodIP string `json:"podIP"`
+	} `json:"status"`
+}
+
+// Targets lists the pods from the API server and returns those to scrape.
+func (d *KubernetesDiscoverer) Targets(ctx context.Context) ([]platform.ScraperTarget, error) {
+	namespaces := d.Config.Namespaces
+	if len(namespaces) == 0 {
+		// the empty namespace lists the pods of all namespaces.
+		namespaces = []string{""}
+	}
+
+	var targets []platform.ScraperTarget
+	for _, ns := range namespaces {
+		pods, err := d.listPods(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pods {
+			if t, ok := d.podTarget(p); ok {
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets, nil
+}
+
+func (d *KubernetesDiscoverer) listPods(ctx context.Context, namespace string) ([]pod, error) {
+	u, err := url.Parse(d.Config.APIServer)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		u.Path = path.Join(u.Path, "/api/v1/pods")
+	} else {
+		u.Path = path.Join(u.Path, "/api/v1/namespaces", namespace, "pods")
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to list kubernetes pods: %s", resp.Status)
+	}
+
+	var list podList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// podTarget returns the target for a pod, if the pod is to be scraped.
+func (d *KubernetesDiscoverer) podTarget(p pod) (platform.ScraperTarget, bool) {
+	annotations := p.Metadata.Annotations
+	if annotations[scrapeAnnotation] != "true" || p.Status.Phase != "Running" || p.Status.PodIP == "" {
+		return platform.ScraperTarget{}, false
+	}
+
+	port := annotations[portAnnotation]
+	if port == "" {
+		for _, c := range p.Spec.Containers {
+			for _, cp := range c.Ports {
+				if port == "" && (cp.Protocol == "" || cp.Protocol == "TCP") {
+					port = strconv.Itoa(cp.ContainerPort)
+				}
+			}
+		}
+	}
+	if port == "" {
+		return platform.ScraperTarget{}, false
+	}
+
+	tc := d.Config.TargetConfig
+	if v := annotations[pathAnnotation]; v != "" {
+		tc.MetricsPath = v
+	}
+	if v := annotations[schemeAnnotation]; v != "" {
+		tc.Scheme = v
+	}
+
+	labels := make(map[string]string, len(p.Metadata.Labels)+2)
+	for k, v := range p.Metadata.Labels {
+		labels[sanitizeLabelName(k)] = v
+	}
+	labels["kubernetes_namespace"] = p.Metadata.Namespace
+	labels["kubernetes_pod_name"] = p.Metadata.Name
+
+	t := tc.target(net.JoinHostPort(p.Status.PodIP, port), labels)
+	t.Name = p.Metadata.Namespace + "/" + p.Metadata.Name
+	return t, true
+}
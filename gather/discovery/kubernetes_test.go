@@ -0,0 +1,147 @@
+package discovery_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/gather/discovery"
+)
+
+const podsResponse = `{
+  "kind": "PodList",
+  "items": [
+    {
+      "metadata": {
+        "name": "web-1",
+        "namespace": "default",
+        "labels": {"app.kubernetes.io/name": "web"},
+        "annotations": {"prometheus.io/scrape": "true", "prometheus.io/port": "8080", "prometheus.io/path": "/stats"}
+      },
+      "spec": {"containers": [{"ports": [{"containerPort": 80, "protocol": "TCP"}]}]},
+      "status": {"phase": "Running", "podIP": "10.0.0.1"}
+    },
+    {
+      "metadata": {
+        "name": "db-1",
+        "namespace": "default",
+        "annotations": {"prometheus.io/scrape": "true"}
+      },
+      "spec": {"containers": [{"ports": [{"containerPort": 9187}]}]},
+      "status": {"phase": "Running", "podIP": "10.0.0.2"}
+    },
+    {
+      "metadata": {
+        "name": "pending-1",
+        "namespace": "default",
+        "annotations": {"prometheus.io/scrape": "true", "prometheus.io/port": "8080"}
+      },
+      "status": {"phase": "Pending"}
+    },
+    {
+      "metadata": {
+        "name": "unannotated-1",
+        "namespace": "default"
+      },
+      "spec": {"containers": [{"ports": [{"containerPort": 80}]}]},
+      "status": {"phase": "Running", "podIP": "10.0.0.3"}
+    }
+  ]
+}`
+
+func TestKubernetesDiscoverer(t *testing.T) {
+	var gotPath, gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(podsResponse))
+	}))
+	defer api.Close()
+
+	tokenFile, err := ioutil.TempFile("", "kubernetes-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tokenFile.Name())
+	tokenFile.WriteString("secret-token\n")
+	tokenFile.Close()
+
+	d, err := discovery.NewKubernetesDiscoverer(discovery.KubernetesConfig{
+		TargetConfig: discovery.TargetConfig{
+			Org:    "org",
+			Bucket: "bucket",
+		},
+		APIServer:       api.URL,
+		Namespaces:      []string{"default"},
+		BearerTokenFile: tokenFile.Name(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.Targets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v1/namespaces/default/pods" {
+		t.Errorf("unexpected api path %q", gotPath)
+	}
+	if gotAuth != "Bearer secret-token" {
+		t.Errorf("unexpected authorization header %q", gotAuth)
+	}
+
+	want := []platform.ScraperTarget{
+		{
+			Name:       "default/web-1",
+			Type:       platform.PrometheusScraperType,
+			URL:        "http://10.0.0.1:8080/stats",
+			OrgName:    "org",
+			BucketName: "bucket",
+			Labels: map[string]string{
+				"app_kubernetes_io_name": "web",
+				"kubernetes_namespace":   "default",
+				"kubernetes_pod_name":    "web-1",
+			},
+		},
+		{
+			Name:       "default/db-1",
+			Type:       platform.PrometheusScraperType,
+			URL:        "http://10.0.0.2:9187/metrics",
+			OrgName:    "org",
+			BucketName: "bucket",
+			Labels: map[string]string{
+				"kubernetes_namespace": "default",
+				"kubernetes_pod_name":  "db-1",
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected targets -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestKubernetesDiscoverer_APIError(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/pods" {
+			t.Errorf("unexpected api path %q", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer api.Close()
+
+	d, err := discovery.NewKubernetesDiscoverer(discovery.KubernetesConfig{
+		APIServer: api.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Targets(context.Background()); err == nil {
+		t.Fatal("expected an error when the api server denies the request")
+	}
+}
@@ -4,22 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/influxdata/platform"
-	"github.com/influxdata/platform/nats"
+	"github.com/influxdata/platform/queue"
 	"go.uber.org/zap"
 )
 
-// handler implents nats Handler interface.
+// handler implements queue Handler interface.
 type handler struct {
 	Scraper   Scraper
-	Publisher nats.Publisher
-	Logger    *zap.Logger
+	Publisher queue.Publisher
+	// Status records the outcome of each scrape; it is optional.
+	Status platform.ScraperTargetStatusService
+	Logger *zap.Logger
 }
 
 // Process consumes scraper target from scraper target queue,
 // call the scraper to gather, and publish to metrics queue.
-func (h *handler) Process(s nats.Subscription, m nats.Message) {
+func (h *handler) Process(s queue.Subscription, m queue.Message) {
 	defer m.Ack()
 
 	req := new(platform.ScraperTarget)
@@ -29,15 +32,29 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 		return
 	}
 
+	start := time.Now()
 	ms, err := h.Scraper.Gather(context.TODO(), *req)
+	status := &platform.ScraperTargetStatus{
+		Health:             platform.ScraperTargetHealthUp,
+		LastScrape:         start.UTC(),
+		LastScrapeDuration: time.Since(start),
+		SampleCount:        len(ms),
+	}
 	if err != nil {
 		h.Logger.Error("unable to gather", zap.Error(err))
-		return
+		status.Health = platform.ScraperTargetHealthDown
+		status.LastError = err.Error()
 	}
+	h.recordStatus(req.ID, status)
+
+	// the scrape status metrics are written even when the scrape failed,
+	// so that a target being down can be alerted on.
+	ms = append(ms, statusMetrics(*req, status)...)
 
 	// send metrics to storage queue
 	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(ms); err != nil {
+	c := MetricsCollection{OrgName: req.OrgName, BucketName: req.BucketName, Metrics: ms}
+	if err := json.NewEncoder(buf).Encode(c); err != nil {
 		h.Logger.Error("unable to marshal json", zap.Error(err))
 		return
 	}
@@ -48,3 +65,12 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 	}
 
 }
+
+func (h *handler) recordStatus(id platform.ID, status *platform.ScraperTargetStatus) {
+	if h.Status == nil || !id.Valid() {
+		return
+	}
+	if err := h.Status.PutTargetStatus(context.TODO(), id, status); err != nil {
+		h.Logger.Error("unable to record scraper target status", zap.Error(err))
+	}
+}
@@ -0,0 +1,117 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
+	platformtesting "github.com/influxdata/platform/testing"
+	"go.uber.org/zap"
+)
+
+type recordingStorage chan []Metrics
+
+func (s recordingStorage) Record(c MetricsCollection) error {
+	s <- c.Metrics
+	return nil
+}
+
+func TestHandler_ScrapeStatus(t *testing.T) {
+	ts := httptest.NewServer(&mockHTTPHandler{
+		responseMap: map[string]string{
+			"/metrics": sampleRespSmall,
+		},
+	})
+	defer ts.Close()
+
+	cases := []struct {
+		name    string
+		url     string
+		health  platform.ScraperTargetHealth
+		up      float64
+		samples float64
+		hasErr  bool
+	}{
+		{
+			name:    "target up",
+			url:     ts.URL + "/metrics",
+			health:  platform.ScraperTargetHealthUp,
+			up:      1,
+			samples: 1,
+		},
+		{
+			name:   "target down",
+			url:    ts.URL + "/missing",
+			health: platform.ScraperTargetHealthDown,
+			up:     0,
+			hasErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			publisher, subscriber := mock.NewNats()
+			storage := &mockStorage{}
+			records := make(recordingStorage, 1)
+			subscriber.Subscribe(MetricsSubject, "", &StorageHandler{
+				Logger:  zap.NewNop(),
+				Storage: records,
+			})
+			subscriber.Subscribe(promTargetSubject, "", &handler{
+				Scraper:   new(prometheusScraper),
+				Publisher: publisher,
+				Status:    storage,
+				Logger:    zap.NewNop(),
+			})
+
+			target := platform.ScraperTarget{
+				ID:   platformtesting.MustIDBase16("3a0d0a6365646120"),
+				Name: "target",
+				Type: platform.PrometheusScraperType,
+				URL:  c.url,
+			}
+			buf := new(bytes.Buffer)
+			if err := json.NewEncoder(buf).Encode(target); err != nil {
+				t.Fatal(err)
+			}
+			if err := publisher.Publish(promTargetSubject, buf); err != nil {
+				t.Fatal(err)
+			}
+
+			ms := <-records
+			got := make(map[string]float64)
+			for _, m := range ms {
+				switch m.Name {
+				case upMetricName, scrapeSamplesMetricName:
+					if m.Tags["target_id"] != target.ID.String() {
+						t.Errorf("%s has target_id tag %q, want %q", m.Name, m.Tags["target_id"], target.ID.String())
+					}
+					got[m.Name] = m.Fields["gauge"].(float64)
+				}
+			}
+			if got[upMetricName] != c.up {
+				t.Errorf("up metric is %v, want %v", got[upMetricName], c.up)
+			}
+			if got[scrapeSamplesMetricName] != c.samples {
+				t.Errorf("scrape_samples_scraped metric is %v, want %v", got[scrapeSamplesMetricName], c.samples)
+			}
+
+			status, err := storage.GetTargetStatus(context.Background(), target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Health != c.health {
+				t.Errorf("target health is %q, want %q", status.Health, c.health)
+			}
+			if (status.LastError != "") != c.hasErr {
+				t.Errorf("target last error is %q, want error %v", status.LastError, c.hasErr)
+			}
+			if status.LastScrape.IsZero() {
+				t.Error("target last scrape time is not set")
+			}
+		})
+	}
+}
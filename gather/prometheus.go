@@ -27,7 +27,24 @@ func (p *prometheusScraper) Gather(ctx context.Context, target platform.ScraperT
 	}
 	defer resp.Body.Close()
 
-	return p.parse(resp.Body, resp.Header)
+	ms, err = p.parse(resp.Body, resp.Header)
+	if err != nil {
+		return ms, err
+	}
+	addTargetLabels(ms, target.Labels)
+	return ms, nil
+}
+
+// addTargetLabels adds the labels of the target as tags to the metrics.
+// Labels exposed by the target itself take precedence.
+func addTargetLabels(ms []Metrics, labels map[string]string) {
+	for _, m := range ms {
+		for k, v := range labels {
+			if _, ok := m.Tags[k]; !ok {
+				m.Tags[k] = v
+			}
+		}
+	}
 }
 
 func (p *prometheusScraper) parse(r io.Reader, header http.Header) ([]Metrics, error) {
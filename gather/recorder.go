@@ -0,0 +1,49 @@
+package gather
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// PointWriter is a Storage writing the metrics to the bucket of the
+// organization of their target.
+type PointWriter struct {
+	OrganizationService platform.OrganizationService
+	BucketService       platform.BucketService
+	Writer              storage.PointsWriter
+}
+
+// Record writes the metrics of c as points of its bucket.
+func (s *PointWriter) Record(c MetricsCollection) error {
+	ctx := context.TODO()
+	o, err := s.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &c.OrgName})
+	if err != nil {
+		return err
+	}
+	if err := platform.CheckOrganizationActive(o); err != nil {
+		return err
+	}
+	b, err := s.BucketService.FindBucket(ctx, platform.BucketFilter{OrganizationID: &o.ID, Name: &c.BucketName})
+	if err != nil {
+		return err
+	}
+
+	ps := make([]models.Point, 0, len(c.Metrics))
+	for _, m := range c.Metrics {
+		p, err := models.NewPoint(m.Name, models.NewTags(m.Tags), m.Fields, time.Unix(0, m.Timestamp))
+		if err != nil {
+			return err
+		}
+		ps = append(ps, p)
+	}
+	ps, err = tsdb.ExplodePoints(o.ID, b.ID, ps)
+	if err != nil {
+		return err
+	}
+	return s.Writer.WritePoints(ps)
+}
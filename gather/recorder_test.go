@@ -0,0 +1,66 @@
+package gather
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+type pointsWriter []models.Point
+
+func (w *pointsWriter) WritePoints(ps []models.Point) error {
+	*w = append(*w, ps...)
+	return nil
+}
+
+func TestPointWriter_Record(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{OrganizationID: o.ID, Name: "metrics"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	var w pointsWriter
+	s := &PointWriter{OrganizationService: svc, BucketService: svc, Writer: &w}
+	c := MetricsCollection{
+		OrgName:    "acme",
+		BucketName: "metrics",
+		Metrics: []Metrics{{
+			Name:      upMetricName,
+			Tags:      map[string]string{"target_name": "target"},
+			Fields:    map[string]interface{}{"gauge": float64(1)},
+			Timestamp: 1000,
+			Type:      MetricTypeGauge,
+		}},
+	}
+	if err := s.Record(c); err != nil {
+		t.Fatalf("unexpected error recording metrics: %v", err)
+	}
+	if len(w) != 1 {
+		t.Fatalf("expected 1 point, got %d", len(w))
+	}
+	p := w[0]
+	if want := tsdb.EncodeName(o.ID, b.ID); string(p.Name()) != string(want[:]) {
+		t.Fatalf("point wasn't written to the bucket")
+	}
+	if m := p.Tags().Get(tsdb.MeasurementTagKeyBytes); string(m) != upMetricName {
+		t.Fatalf("unexpected measurement %q", m)
+	}
+	if tag := p.Tags().Get([]byte("target_name")); string(tag) != "target" || p.UnixNano() != 1000 {
+		t.Fatalf("unexpected point %v", p)
+	}
+
+	c.BucketName = "missing"
+	if err := s.Record(c); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected a not found error for a missing bucket, got %v", err)
+	}
+}
@@ -8,11 +8,12 @@ import (
 	"time"
 
 	"github.com/influxdata/platform"
-	"github.com/influxdata/platform/nats"
+	"github.com/influxdata/platform/gather/discovery"
+	"github.com/influxdata/platform/queue"
 	"go.uber.org/zap"
 )
 
-// nats subjects
+// queue subjects
 const (
 	MetricsSubject    = "metrics"
 	promTargetSubject = "promTarget"
@@ -21,13 +22,17 @@ const (
 // Scheduler is struct to run scrape jobs.
 type Scheduler struct {
 	Targets platform.ScraperTargetStoreService
+	// Status records the last scrape status of each target.
+	Status platform.ScraperTargetStatusService
+	// Discoverers provide targets in addition to the stored ones.
+	Discoverers []discovery.Discoverer
 	// Interval is between each metrics gathering event.
 	Interval time.Duration
 	// Timeout is the maxisium time duration allowed by each TCP request
 	Timeout time.Duration
 
 	// Publisher will send the gather requests and gathered metrics to the queue.
-	Publisher nats.Publisher
+	Publisher queue.Publisher
 
 	Logger *zap.Logger
 
@@ -39,8 +44,9 @@ func NewScheduler(
 	numScrapers int,
 	l *zap.Logger,
 	targets platform.ScraperTargetStoreService,
-	p nats.Publisher,
-	s nats.Subscriber,
+	status platform.ScraperTargetStatusService,
+	p queue.Publisher,
+	s queue.Subscriber,
 	interval time.Duration,
 	timeout time.Duration,
 ) (*Scheduler, error) {
@@ -52,6 +58,7 @@ func NewScheduler(
 	}
 	scheduler := &Scheduler{
 		Targets:   targets,
+		Status:    status,
 		Interval:  interval,
 		Timeout:   timeout,
 		Publisher: p,
@@ -63,6 +70,7 @@ func NewScheduler(
 		err := s.Subscribe(promTargetSubject, "", &handler{
 			Scraper:   new(prometheusScraper),
 			Publisher: p,
+			Status:    status,
 			Logger:    l,
 		})
 		if err != nil {
@@ -74,7 +82,7 @@ func NewScheduler(
 }
 
 // Run will retrieve scraper targets from the target storage,
-// and publish them to nats job queue for gather.
+// and publish them to the job queue for gather.
 func (s *Scheduler) Run(ctx context.Context) error {
 	go func(s *Scheduler, ctx context.Context) {
 		for {
@@ -102,6 +110,7 @@ func (s *Scheduler) run(ctx context.Context) error {
 				s.Logger.Error("cannot list targets", zap.Error(err))
 				continue
 			}
+			targets = append(targets, s.discoverTargets(ctx)...)
 			for _, target := range targets {
 				if err := requestScrape(target, s.Publisher); err != nil {
 					s.Logger.Error("json encoding error", zap.Error(err))
@@ -111,7 +120,22 @@ func (s *Scheduler) run(ctx context.Context) error {
 	}
 }
 
-func requestScrape(t platform.ScraperTarget, publisher nats.Publisher) error {
+// discoverTargets returns the targets of all discoverers. A failing
+// discoverer is logged and skipped so it doesn't hold up the others.
+func (s *Scheduler) discoverTargets(ctx context.Context) []platform.ScraperTarget {
+	var targets []platform.ScraperTarget
+	for _, d := range s.Discoverers {
+		ts, err := d.Targets(ctx)
+		if err != nil {
+			s.Logger.Error("cannot discover targets", zap.Error(err))
+			continue
+		}
+		targets = append(targets, ts...)
+	}
+	return targets
+}
+
+func requestScrape(t platform.ScraperTarget, publisher queue.Publisher) error {
 	buf := new(bytes.Buffer)
 	err := json.NewEncoder(buf).Encode(t)
 	if err != nil {
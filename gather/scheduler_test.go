@@ -2,6 +2,7 @@ package gather
 
 import (
 	"context"
+	"errors"
 	"net/http/httptest"
 	"os"
 	"testing"
@@ -9,9 +10,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/gather/discovery"
 	influxlogger "github.com/influxdata/platform/logger"
 	"github.com/influxdata/platform/mock"
 	platformtesting "github.com/influxdata/platform/testing"
+	"go.uber.org/zap"
 )
 
 func TestScheduler(t *testing.T) {
@@ -46,7 +49,7 @@ func TestScheduler(t *testing.T) {
 	})
 
 	scheduler, err := NewScheduler(10, logger,
-		storage, publisher, subscriber, time.Millisecond, time.Microsecond)
+		storage, storage, publisher, subscriber, time.Millisecond, time.Microsecond)
 
 	go func() {
 		err = scheduler.run(ctx)
@@ -83,10 +86,22 @@ func TestScheduler(t *testing.T) {
 	}
 
 	for _, v := range storage.Metrics {
+		switch v.Name {
+		case upMetricName, scrapeDurationMetricName, scrapeSamplesMetricName:
+			continue
+		}
 		if diff := cmp.Diff(v, want, metricsCmpOption); diff != "" {
 			t.Fatalf("scraper parse metrics want %v, got %v", want, v)
 		}
 	}
+
+	status, err := storage.GetTargetStatus(ctx, platformtesting.MustIDBase16("3a0d0a6365646120"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Health != platform.ScraperTargetHealthUp || status.SampleCount != 1 || status.LastError != "" {
+		t.Fatalf("unexpected scraper target status %+v", status)
+	}
 	ts.Close()
 }
 
@@ -95,3 +110,27 @@ const sampleRespSmall = `
 # TYPE go_goroutines gauge
 go_goroutines 36
 `
+
+type stubDiscoverer struct {
+	targets []platform.ScraperTarget
+	err     error
+}
+
+func (d stubDiscoverer) Targets(ctx context.Context) ([]platform.ScraperTarget, error) {
+	return d.targets, d.err
+}
+
+func TestScheduler_DiscoverTargets(t *testing.T) {
+	s := &Scheduler{
+		Logger: zap.NewNop(),
+		Discoverers: []discovery.Discoverer{
+			stubDiscoverer{targets: []platform.ScraperTarget{{Name: "a"}, {Name: "b"}}},
+			stubDiscoverer{err: errors.New("unreachable")},
+			stubDiscoverer{targets: []platform.ScraperTarget{{Name: "c"}}},
+		},
+	}
+	want := []platform.ScraperTarget{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if diff := cmp.Diff(s.discoverTargets(context.Background()), want); diff != "" {
+		t.Fatalf("unexpected discovered targets -got/+want\ndiff %s", diff)
+	}
+}
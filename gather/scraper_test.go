@@ -189,12 +189,13 @@ type mockStorage struct {
 	TotalGatherJobs chan struct{}
 	Metrics         map[int64]Metrics
 	Targets         []platform.ScraperTarget
+	Statuses        map[platform.ID]platform.ScraperTargetStatus
 }
 
-func (s *mockStorage) Record(ms []Metrics) error {
+func (s *mockStorage) Record(c MetricsCollection) error {
 	s.Lock()
 	defer s.Unlock()
-	for _, m := range ms {
+	for _, m := range c.Metrics {
 		s.Metrics[m.Timestamp] = m
 	}
 	s.TotalGatherJobs <- struct{}{}
@@ -258,7 +259,7 @@ func (s *mockStorage) UpdateTarget(ctx context.Context, update *platform.Scraper
 	defer s.Unlock()
 
 	for k, v := range s.Targets {
-		if v.ID.String() == string(update.ID) {
+		if v.ID == update.ID {
 			s.Targets[k] = *update
 			break
 		}
@@ -267,6 +268,28 @@ func (s *mockStorage) UpdateTarget(ctx context.Context, update *platform.Scraper
 	return update, err
 }
 
+func (s *mockStorage) GetTargetStatus(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	status, ok := s.Statuses[id]
+	if !ok {
+		return &platform.ScraperTargetStatus{Health: platform.ScraperTargetHealthUnknown}, nil
+	}
+	return &status, nil
+}
+
+func (s *mockStorage) PutTargetStatus(ctx context.Context, id platform.ID, status *platform.ScraperTargetStatus) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.Statuses == nil {
+		s.Statuses = make(map[platform.ID]platform.ScraperTargetStatus)
+	}
+	s.Statuses[id] = *status
+	return nil
+}
+
 type mockHTTPHandler struct {
 	unauthorized bool
 	noContent    bool
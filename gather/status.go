@@ -0,0 +1,48 @@
+package gather
+
+import (
+	"github.com/influxdata/platform"
+)
+
+// names of the synthetic metrics written after each scrape.
+const (
+	upMetricName             = "up"
+	scrapeDurationMetricName = "scrape_duration_seconds"
+	scrapeSamplesMetricName  = "scrape_samples_scraped"
+)
+
+// statusMetrics returns the synthetic metrics describing a scrape of target,
+// following the prometheus conventions: up is 1 when the scrape succeeded
+// and 0 otherwise.
+func statusMetrics(target platform.ScraperTarget, status *platform.ScraperTargetStatus) []Metrics {
+	up := float64(0)
+	if status.Health == platform.ScraperTargetHealthUp {
+		up = 1
+	}
+	ts := status.LastScrape.UnixNano()
+	gauge := func(name string, v float64) Metrics {
+		tags := make(map[string]string, len(target.Labels)+2)
+		for k, v := range target.Labels {
+			tags[k] = v
+		}
+		tags["target_name"] = target.Name
+		// discovered targets aren't stored, so they don't have an id.
+		if target.ID.Valid() {
+			tags["target_id"] = target.ID.String()
+		}
+		return Metrics{
+			Name: name,
+			Tags: tags,
+			Fields: map[string]interface{}{
+				"gauge": v,
+			},
+			Timestamp: ts,
+			Type:      MetricTypeGauge,
+		}
+	}
+	return []Metrics{
+		gauge(upMetricName, up),
+		gauge(scrapeDurationMetricName, status.LastScrapeDuration.Seconds()),
+		gauge(scrapeSamplesMetricName, float64(status.SampleCount)),
+	}
+}
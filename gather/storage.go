@@ -4,32 +4,32 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/influxdata/platform/nats"
+	"github.com/influxdata/platform/queue"
 	"go.uber.org/zap"
 )
 
 // Storage stores the metrics of a time based.
 type Storage interface {
-	//Subscriber nats.Subscriber
-	Record([]Metrics) error
+	//Subscriber queue.Subscriber
+	Record(MetricsCollection) error
 }
 
-// StorageHandler implements nats.Handler interface.
+// StorageHandler implements queue.Handler interface.
 type StorageHandler struct {
 	Storage Storage
 	Logger  *zap.Logger
 }
 
 // Process consumes job queue, and use storage to record.
-func (h *StorageHandler) Process(s nats.Subscription, m nats.Message) {
+func (h *StorageHandler) Process(s queue.Subscription, m queue.Message) {
 	defer m.Ack()
-	ms := make([]Metrics, 0)
-	err := json.Unmarshal(m.Data(), &ms)
+	var c MetricsCollection
+	err := json.Unmarshal(m.Data(), &c)
 	if err != nil {
 		h.Logger.Error(fmt.Sprintf("storage handler process err: %v", err))
 		return
 	}
-	err = h.Storage.Record(ms)
+	err = h.Storage.Record(c)
 	if err != nil {
 		h.Logger.Error(fmt.Sprintf("storage handler store err: %v", err))
 	}
@@ -0,0 +1,71 @@
+package platform
+
+import "context"
+
+// Group is a set of users that can be mapped to resources, as a user
+// would be, so that its members share the permissions on those resources.
+type Group struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+}
+
+// ops for group errors.
+const (
+	OpFindGroupByID = "FindGroupByID"
+	OpFindGroup     = "FindGroup"
+	OpFindGroups    = "FindGroups"
+	OpCreateGroup   = "CreateGroup"
+	OpUpdateGroup   = "UpdateGroup"
+	OpDeleteGroup   = "DeleteGroup"
+)
+
+// GroupService represents a service for managing group data.
+type GroupService interface {
+	// FindGroupByID returns a single group by ID.
+	FindGroupByID(ctx context.Context, id ID) (*Group, error)
+
+	// FindGroup returns the first group that matches filter.
+	FindGroup(ctx context.Context, filter GroupFilter) (*Group, error)
+
+	// FindGroups returns a list of groups that match filter and the total count of matching groups.
+	// Additional options provide pagination & sorting.
+	FindGroups(ctx context.Context, filter GroupFilter, opt ...FindOptions) ([]*Group, int, error)
+
+	// CreateGroup creates a new group and sets g.ID with the new identifier.
+	CreateGroup(ctx context.Context, g *Group) error
+
+	// UpdateGroup updates a single group with changeset.
+	// Returns the new group state after update.
+	UpdateGroup(ctx context.Context, id ID, upd GroupUpdate) (*Group, error)
+
+	// DeleteGroup removes a group by ID, along with its resource mappings
+	// and memberships.
+	DeleteGroup(ctx context.Context, id ID) error
+}
+
+// GroupUpdate represents updates to a group.
+// Only fields which are set are updated.
+type GroupUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Valid returns an error if the update would leave the group invalid.
+func (u GroupUpdate) Valid() error {
+	if u.Name != nil && *u.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "group name is required",
+		}
+	}
+	return nil
+}
+
+// GroupFilter represents a set of filters that restrict the returned results.
+type GroupFilter struct {
+	ID             *ID
+	Name           *string
+	OrganizationID *ID
+}
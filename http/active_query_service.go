@@ -0,0 +1,272 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/query"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	activeQueriesPath   = "/api/v2/queries"
+	activeQueriesIDPath = "/api/v2/queries/:id"
+)
+
+// ActiveQueryHandler represents an HTTP API handler for the queries in
+// flight.
+//
+// Operators see and cancel the queries of every organization. The others see the queries of the organizations they belong
+// to, directly or through their groups, and cancel those of the
+// organizations they own and the ones submitted with their token.
+type ActiveQueryHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	ActiveQueryService         query.ActiveQueryService
+	UserResourceMappingService platform.UserResourceMappingService
+}
+
+// NewActiveQueryHandler returns a new instance of ActiveQueryHandler.
+func NewActiveQueryHandler() *ActiveQueryHandler {
+	h := &ActiveQueryHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", activeQueriesPath, h.handleGetActiveQueries)
+	h.HandlerFunc("DELETE", activeQueriesIDPath, h.handleDeleteActiveQuery)
+	return h
+}
+
+type activeQueriesResponse struct {
+	Queries []*query.ActiveQuery `json:"queries"`
+}
+
+// handleGetActiveQueries is the HTTP handler for the GET /api/v2/queries
+// route.
+func (h *ActiveQueryHandler) handleGetActiveQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetActiveQueriesRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	orgs, err := h.orgs(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if orgs != nil && filter.OrganizationID != nil {
+		if _, ok := orgs[*filter.OrganizationID]; !ok {
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EForbidden,
+				Msg:  "only the members of an organization can list its queries",
+			}, w)
+			return
+		}
+	}
+
+	qs, err := h.ActiveQueryService.FindActiveQueries(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := activeQueriesResponse{Queries: []*query.ActiveQuery{}}
+	for _, q := range qs {
+		if _, ok := orgs[q.OrganizationID]; orgs == nil || ok {
+			res.Queries = append(res.Queries, q)
+		}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetActiveQueriesRequest(ctx context.Context, r *http.Request) (query.ActiveQueryFilter, error) {
+	var filter query.ActiveQueryFilter
+	if s := r.URL.Query().Get("orgID"); s != "" {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			return filter, &platform.Error{
+				Code: platform.EInvalid,
+				Err:  err,
+			}
+		}
+		filter.OrganizationID = id
+	}
+	return filter, nil
+}
+
+// handleDeleteActiveQuery is the HTTP handler for the
+// DELETE /api/v2/queries/:id route, canceling the query.
+func (h *ActiveQueryHandler) handleDeleteActiveQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := platform.IDFromString(httprouter.ParamsFromContext(ctx).ByName("id"))
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	orgs, err := h.orgs(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	q, err := h.ActiveQueryService.FindActiveQueryByID(ctx, *id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	submitted := q.AuthorizationID.Valid() && q.AuthorizationID == a.Identifier()
+	if role, ok := orgs[q.OrganizationID]; orgs != nil && !submitted && role != platform.Owner {
+		// the queries of the other organizations aren't disclosed.
+		if !ok {
+			EncodeError(ctx, &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  "query not found",
+			}, w)
+			return
+		}
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "only the owners of the organization or the token that submitted the query can cancel it",
+		}, w)
+		return
+	}
+
+	if err := h.ActiveQueryService.CancelActiveQuery(ctx, *id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// orgs returns the roles of the authorizer of the request on the
+// organizations whose queries it can see, or nil if it can see all of them.
+func (h *ActiveQueryHandler) orgs(ctx context.Context) (map[platform.ID]platform.UserType, error) {
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isOperator(a) {
+		return nil, nil
+	}
+
+	ms, err := platform.UserMappings(ctx, h.UserResourceMappingService, a.GetUserID())
+	if err != nil {
+		return nil, err
+	}
+	orgs := map[platform.ID]platform.UserType{}
+	for _, m := range ms {
+		if m.ResourceType != platform.OrgResourceType || orgs[m.ResourceID] == platform.Owner {
+			continue
+		}
+		orgs[m.ResourceID] = m.UserType
+	}
+	return orgs, nil
+}
+
+// ActiveQueryService connects to Influx via HTTP using tokens to list and
+// cancel the queries in flight.
+type ActiveQueryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// FindActiveQueries returns the queries in flight matching filter.
+func (s *ActiveQueryService) FindActiveQueries(ctx context.Context, filter query.ActiveQueryFilter) ([]*query.ActiveQuery, error) {
+	u, err := newURL(s.Addr, activeQueriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if filter.OrganizationID != nil {
+		params := req.URL.Query()
+		params.Set("orgID", filter.OrganizationID.String())
+		req.URL.RawQuery = params.Encode()
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var res activeQueriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Queries, nil
+}
+
+// FindActiveQueryByID returns a single query in flight by ID.
+func (s *ActiveQueryService) FindActiveQueryByID(ctx context.Context, id platform.ID) (*query.ActiveQuery, error) {
+	qs, err := s.FindActiveQueries(ctx, query.ActiveQueryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range qs {
+		if q.ID == id {
+			return q, nil
+		}
+	}
+	return nil, &platform.Error{
+		Code: platform.ENotFound,
+		Op:   query.OpFindActiveQueryByID,
+		Msg:  "query not found",
+	}
+}
+
+// CancelActiveQuery cancels a query in flight.
+func (s *ActiveQueryService) CancelActiveQuery(ctx context.Context, id platform.ID) error {
+	u, err := newURL(s.Addr, path.Join(activeQueriesPath, id.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp, true)
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/query"
+)
+
+// fakeActiveQueryService holds the queries in flight by ID.
+type fakeActiveQueryService map[platform.ID]*query.ActiveQuery
+
+func (s fakeActiveQueryService) FindActiveQueries(ctx context.Context, filter query.ActiveQueryFilter) ([]*query.ActiveQuery, error) {
+	var qs []*query.ActiveQuery
+	for _, q := range s {
+		if filter.OrganizationID == nil || *filter.OrganizationID == q.OrganizationID {
+			qs = append(qs, q)
+		}
+	}
+	sort.Slice(qs, func(i, j int) bool {
+		return qs[i].ID < qs[j].ID
+	})
+	return qs, nil
+}
+
+func (s fakeActiveQueryService) FindActiveQueryByID(ctx context.Context, id platform.ID) (*query.ActiveQuery, error) {
+	q, ok := s[id]
+	if !ok {
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: "query not found"}
+	}
+	return q, nil
+}
+
+func (s fakeActiveQueryService) CancelActiveQuery(ctx context.Context, id platform.ID) error {
+	if _, ok := s[id]; !ok {
+		return &platform.Error{Code: platform.ENotFound, Msg: "query not found"}
+	}
+	delete(s, id)
+	return nil
+}
+
+func TestActiveQueryService(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	member, owner, myOrg, otherOrg, owners := platform.ID(10), platform.ID(11), platform.ID(20), platform.ID(30), platform.ID(40)
+	// the owner owns the org through a group.
+	for _, m := range []*platform.UserResourceMapping{
+		{ResourceID: myOrg, ResourceType: platform.OrgResourceType, UserID: member, UserType: platform.Member},
+		{ResourceID: owners, ResourceType: platform.GroupResourceType, UserID: owner, UserType: platform.Member},
+		{ResourceID: myOrg, ResourceType: platform.OrgResourceType, UserID: owners, UserType: platform.Owner, PrincipalType: platform.GroupPrincipal},
+	} {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queries := fakeActiveQueryService{
+		1: {ID: 1, OrganizationID: myOrg, Query: "from()", State: "executing"},
+		2: {ID: 2, OrganizationID: otherOrg, Query: "from()", State: query.QueuedState},
+		3: {ID: 3, OrganizationID: myOrg, UserID: member, AuthorizationID: 100, Query: "from()", State: "executing"},
+	}
+	h := NewActiveQueryHandler()
+	h.ActiveQueryService = queries
+	h.UserResourceMappingService = svc
+
+	var auth platform.Authorizer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), auth)))
+	}))
+	defer server.Close()
+	client := &ActiveQueryService{Addr: server.URL}
+
+	auth = &platform.Authorization{ID: 100, Status: platform.Active, UserID: member}
+	qs, err := client.FindActiveQueries(ctx, query.ActiveQueryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 2 || qs[0].ID != 1 || qs[1].ID != 3 || qs[0].Query != "from()" {
+		t.Fatalf("expected only the queries of the member's org, got %v", qs)
+	}
+	if _, err := client.FindActiveQueries(ctx, query.ActiveQueryFilter{OrganizationID: &otherOrg}); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error listing the queries of another org, got %v", err)
+	}
+	if err := client.CancelActiveQuery(ctx, 2); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the query of another org not to be found, got %v", err)
+	}
+	if err := client.CancelActiveQuery(ctx, 1); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error canceling the query of another token, got %v", err)
+	}
+	if err := client.CancelActiveQuery(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	auth = &platform.Authorization{ID: 101, Status: platform.Active, UserID: owner}
+	if qs, err := client.FindActiveQueries(ctx, query.ActiveQueryFilter{OrganizationID: &myOrg}); err != nil || len(qs) != 1 {
+		t.Fatalf("expected the query of the org of the owner's group, got %v, %v", qs, err)
+	}
+	if err := client.CancelActiveQuery(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	auth = &platform.Authorization{
+		Status:      platform.Active,
+		Permissions: []platform.Permission{platform.CreateUserPermission},
+	}
+	qs, err = client.FindActiveQueries(ctx, query.ActiveQueryFilter{OrganizationID: &otherOrg})
+	if err != nil || len(qs) != 1 || qs[0].ID != 2 {
+		t.Fatalf("expected the query of the other org, got %v, %v", qs, err)
+	}
+	if err := client.CancelActiveQuery(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 0 {
+		t.Fatalf("expected all queries to be canceled, got %v", queries)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
+	"github.com/influxdata/platform/bundle"
 	"github.com/influxdata/platform/chronograf/server"
 	"github.com/influxdata/platform/query"
 	"github.com/influxdata/platform/storage"
@@ -16,6 +18,7 @@ type APIHandler struct {
 	BucketHandler        *BucketHandler
 	UserHandler          *UserHandler
 	OrgHandler           *OrgHandler
+	GroupHandler         *GroupHandler
 	AuthorizationHandler *AuthorizationHandler
 	DashboardHandler     *DashboardHandler
 	AssetHandler         *AssetHandler
@@ -25,10 +28,15 @@ type APIHandler struct {
 	MacroHandler         *MacroHandler
 	TaskHandler          *TaskHandler
 	TelegrafHandler      *TelegrafHandler
+	ScraperHandler       *ScraperHandler
 	QueryHandler         *FluxHandler
 	WriteHandler         *WriteHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
+	AuditHandler         *AuditHandler
+	BootstrapHandler     *BootstrapHandler
+	BundleHandler        *BundleHandler
+	ActiveQueryHandler   *ActiveQueryHandler
 }
 
 // APIBackend is all services and associated parameters required to construct
@@ -41,10 +49,13 @@ type APIBackend struct {
 
 	PointsWriter                    storage.PointsWriter
 	AuthorizationService            platform.AuthorizationService
+	AuthorizationUsageService       platform.AuthorizationUsageService
 	BucketService                   platform.BucketService
+	BucketLifecycleService          platform.BucketLifecycleService
 	SessionService                  platform.SessionService
 	UserService                     platform.UserService
 	OrganizationService             platform.OrganizationService
+	GroupService                    platform.GroupService
 	UserResourceMappingService      platform.UserResourceMappingService
 	LabelService                    platform.LabelService
 	DashboardService                platform.DashboardService
@@ -52,16 +63,27 @@ type APIBackend struct {
 	BucketOperationLogService       platform.BucketOperationLogService
 	UserOperationLogService         platform.UserOperationLogService
 	OrganizationOperationLogService platform.OrganizationOperationLogService
+	OrganizationDeletionService     platform.OrganizationDeletionService
+	AuditLogService                 platform.AuditLogService
 	ViewService                     platform.ViewService
 	SourceService                   platform.SourceService
 	MacroService                    platform.MacroService
 	BasicAuthService                platform.BasicAuthService
 	OnboardingService               platform.OnboardingService
 	ProxyQueryService               query.ProxyQueryService
+	ActiveQueryService              query.ActiveQueryService
 	TaskService                     platform.TaskService
 	TelegrafService                 platform.TelegrafConfigStore
 	ScraperTargetStoreService       platform.ScraperTargetStoreService
+	ScraperTargetStatusService      platform.ScraperTargetStatusService
 	ChronografService               *server.Service
+	// OAuthConfig enables signin through external identity providers.
+	OAuthConfig *OAuthConfig
+	// SigninLimiter limits the rate of the signin attempts of each client.
+	SigninLimiter *RateLimiter
+	// BootstrapTaskService creates the tasks of the bootstrap configs, which
+	// only operators apply, without checking the permissions of the context.
+	BootstrapTaskService platform.TaskService
 }
 
 // NewAPIHandler constructs all api handlers beneath it and returns an APIHandler
@@ -70,23 +92,42 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.SessionHandler = NewSessionHandler()
 	h.SessionHandler.BasicAuthService = b.BasicAuthService
 	h.SessionHandler.SessionService = b.SessionService
+	h.SessionHandler.SigninLimiter = b.SigninLimiter
 	h.SessionHandler.Logger = b.Logger.With(zap.String("handler", "basicAuth"))
+	h.SessionHandler.UserService = b.UserService
+	h.SessionHandler.UserResourceMappingService = b.UserResourceMappingService
+	if b.OAuthConfig != nil {
+		if err := b.OAuthConfig.configure(h.SessionHandler); err != nil {
+			b.Logger.Error("failed to configure oauth providers", zap.Error(err))
+		}
+	}
 
 	h.BucketHandler = NewBucketHandler(b.UserResourceMappingService, b.LabelService)
 	h.BucketHandler.BucketService = b.BucketService
 	h.BucketHandler.BucketOperationLogService = b.BucketOperationLogService
 	h.BucketHandler.UserService = b.UserService
+	h.BucketHandler.BucketLifecycleService = b.BucketLifecycleService
 
 	h.OrgHandler = NewOrgHandler(b.UserResourceMappingService, b.LabelService)
 	h.OrgHandler.OrganizationService = b.OrganizationService
 	h.OrgHandler.BucketService = b.BucketService
 	h.OrgHandler.OrganizationOperationLogService = b.OrganizationOperationLogService
 	h.OrgHandler.UserService = b.UserService
+	h.OrgHandler.OrganizationDeletionService = b.OrganizationDeletionService
+
+	h.GroupHandler = NewGroupHandler(
+		b.Logger.With(zap.String("handler", "group")),
+		b.UserResourceMappingService,
+		b.UserService,
+		b.GroupService,
+	)
 
 	h.UserHandler = NewUserHandler()
 	h.UserHandler.UserService = b.UserService
 	h.UserHandler.BasicAuthService = b.BasicAuthService
 	h.UserHandler.UserOperationLogService = b.UserOperationLogService
+	h.UserHandler.UserResourceMappingService = b.UserResourceMappingService
+	h.UserHandler.OrganizationService = b.OrganizationService
 
 	h.DashboardHandler = NewDashboardHandler(b.UserResourceMappingService, b.LabelService)
 	h.DashboardHandler.DashboardService = b.DashboardService
@@ -125,6 +166,13 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 		b.TelegrafService,
 	)
 	h.TelegrafHandler.UserService = b.UserService
+	h.TelegrafHandler.AuthorizationService = b.AuthorizationService
+	h.TelegrafHandler.BucketService = b.BucketService
+
+	h.ScraperHandler = NewScraperHandler()
+	h.ScraperHandler.ScraperStorageService = b.ScraperTargetStoreService
+	h.ScraperHandler.ScraperTargetStatusService = b.ScraperTargetStatusService
+	h.ScraperHandler.Logger = b.Logger.With(zap.String("handler", "scraper"))
 
 	h.WriteHandler = NewWriteHandler(b.PointsWriter)
 	h.WriteHandler.OrganizationService = b.OrganizationService
@@ -136,6 +184,44 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.QueryHandler.Logger = b.Logger.With(zap.String("handler", "query"))
 	h.QueryHandler.ProxyQueryService = b.ProxyQueryService
 
+	h.ActiveQueryHandler = NewActiveQueryHandler()
+	h.ActiveQueryHandler.Logger = b.Logger.With(zap.String("handler", "activeQuery"))
+	h.ActiveQueryHandler.ActiveQueryService = b.ActiveQueryService
+	h.ActiveQueryHandler.UserResourceMappingService = b.UserResourceMappingService
+
+	h.AuditHandler = NewAuditHandler()
+	h.AuditHandler.AuditLogService = b.AuditLogService
+	h.AuditHandler.Logger = b.Logger.With(zap.String("handler", "audit"))
+
+	h.BootstrapHandler = NewBootstrapHandler()
+	h.BootstrapHandler.Logger = b.Logger.With(zap.String("handler", "bootstrap"))
+	h.BootstrapHandler.Applier = &bootstrap.Applier{
+		UserService:                b.UserService,
+		BasicAuthService:           b.BasicAuthService,
+		OrganizationService:        b.OrganizationService,
+		BucketService:              b.BucketService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		AuthorizationService:       b.AuthorizationService,
+		DashboardService:           b.DashboardService,
+		TaskService:                b.BootstrapTaskService,
+		TelegrafService:            b.TelegrafService,
+	}
+
+	h.BundleHandler = NewBundleHandler()
+	h.BundleHandler.Logger = b.Logger.With(zap.String("handler", "bundle"))
+	h.BundleHandler.UserResourceMappingService = b.UserResourceMappingService
+	h.BundleHandler.BundleService = &bundle.Service{
+		OrganizationService:        b.OrganizationService,
+		BucketService:              b.BucketService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		DashboardService:           b.DashboardService,
+		ViewService:                b.ViewService,
+		MacroService:               b.MacroService,
+		TaskService:                b.TaskService,
+		TelegrafService:            b.TelegrafService,
+		LabelService:               b.LabelService,
+	}
+
 	h.ChronografHandler = NewChronografHandler(b.ChronografService)
 
 	return h
@@ -144,15 +230,21 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"audit":          "/api/v2/audit",
 	"authorizations": "/api/v2/authorizations",
+	"bootstrap":      "/api/v2/bootstrap",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"export":         "/api/v2/export",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
-	"macros": "/api/v2/macros",
-	"me":     "/api/v2/me",
-	"orgs":   "/api/v2/orgs",
+	"groups":  "/api/v2/groups",
+	"import":  "/api/v2/import",
+	"macros":  "/api/v2/macros",
+	"me":      "/api/v2/me",
+	"orgs":    "/api/v2/orgs",
+	"queries": "/api/v2/queries",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -160,10 +252,11 @@ var apiLinks = map[string]interface{}{
 		"spec":        "/api/v2/query/spec",
 		"suggestions": "/api/v2/query/suggestions",
 	},
-	"setup":   "/api/v2/setup",
-	"signin":  "/api/v2/signin",
-	"signout": "/api/v2/signout",
-	"sources": "/api/v2/sources",
+	"scrapers": "/api/v2/scrapers",
+	"setup":    "/api/v2/setup",
+	"signin":   "/api/v2/signin",
+	"signout":  "/api/v2/signout",
+	"sources":  "/api/v2/sources",
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
@@ -197,7 +290,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/signin") || r.URL.Path == "/api/v2/signout" {
 		h.SessionHandler.ServeHTTP(w, r)
 		return
 	}
@@ -212,11 +305,27 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/queries") {
+		h.ActiveQueryHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/bootstrap") {
+		h.BootstrapHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/export") ||
+		strings.HasPrefix(r.URL.Path, "/api/v2/import") {
+		h.BundleHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
 		h.BucketHandler.ServeHTTP(w, r)
 		return
@@ -237,6 +346,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/groups") {
+		h.GroupHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/authorizations") {
 		h.AuthorizationHandler.ServeHTTP(w, r)
 		return
@@ -257,6 +371,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/scrapers") {
+		h.ScraperHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/telegrafs") {
 		h.TelegrafHandler.ServeHTTP(w, r)
 		return
@@ -272,6 +391,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/audit") {
+		h.AuditHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.ChronografHandler.ServeHTTP(w, r)
 		return
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/snowflake"
+)
+
+// RequestIDHeader is the header that identifies a request in the audit log.
+const RequestIDHeader = "X-Request-Id"
+
+// AuditingHandler is a middleware identifying the requests changing
+// resources, so that the changes recorded to the audit log by the services of
+// the audit package refer to the request that made them. It must be served
+// after the AuthenticationHandler so that the actor of the change is known.
+type AuditingHandler struct {
+	IDGenerator platform.IDGenerator
+
+	Handler http.Handler
+}
+
+// NewAuditingHandler returns a handler identifying the changes made by h.
+func NewAuditingHandler(h http.Handler) *AuditingHandler {
+	return &AuditingHandler{
+		IDGenerator: snowflake.NewIDGenerator(),
+		Handler:     h,
+	}
+}
+
+// ServeHTTP serves the request with its ID and path on context if it may
+// change resources.
+func (h *AuditingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST", "PUT", "PATCH", "DELETE":
+	default:
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = h.IDGenerator.ID().String()
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	ctx := platcontext.SetRequest(r.Context(), platcontext.Request{
+		ID:     requestID,
+		Method: r.Method,
+		Path:   r.URL.Path,
+	})
+	h.Handler.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/audit"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	auditPath       = "/api/v2/audit"
+	auditExportPath = "/api/v2/audit/export"
+)
+
+// AuditHandler represents an HTTP API handler for the audit log.
+type AuditHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	AuditLogService platform.AuditLogService
+}
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler() *AuditHandler {
+	h := &AuditHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", auditPath, h.handleGetAuditEvents)
+	h.HandlerFunc("GET", auditExportPath, h.handleExportAuditEvents)
+	return h
+}
+
+type auditEventsResponse struct {
+	Links  *platform.PagingLinks  `json:"links"`
+	Events []*platform.AuditEvent `json:"events"`
+}
+
+// authorizeAuditRead returns an error unless the authorizer of ctx, an
+// operator, may read the audit log, which spans all the organizations.
+func authorizeAuditRead(ctx context.Context) error {
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if !isOperator(a) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "reading the audit log requires the permission to create users",
+		}
+	}
+	return nil
+}
+
+// handleGetAuditEvents is the HTTP handler for the GET /api/v2/audit route.
+func (h *AuditHandler) handleGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeAuditRead(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetAuditEventsRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "getAuditEvents"), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	es, _, err := h.AuditLogService.FindAuditEvents(ctx, platform.AuditEventFilter(req.filter), req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := auditEventsResponse{
+		Links:  newPagingLinks(auditPath, req.opts, req.filter, len(es)),
+		Events: es,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleExportAuditEvents is the HTTP handler for the GET /api/v2/audit/export
+// route, returning the events as line protocol.
+func (h *AuditHandler) handleExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeAuditRead(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetAuditEventsRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "exportAuditEvents"), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+	// the export is only bounded by the filter.
+	if r.URL.Query().Get("limit") == "" {
+		req.opts.Limit = 0
+	}
+
+	es, _, err := h.AuditLogService.FindAuditEvents(ctx, platform.AuditEventFilter(req.filter), req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, err := audit.LineProtocol(es)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(b); err != nil {
+		logEncodingError(h.Logger, r, err)
+	}
+}
+
+// auditEventFilter is the filter of the audit log in the query params of a request.
+type auditEventFilter platform.AuditEventFilter
+
+// QueryParams implements platform.PagingFilter.
+func (f auditEventFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.Start != nil {
+		qp["start"] = []string{f.Start.Format(time.RFC3339Nano)}
+	}
+	if f.Stop != nil {
+		qp["stop"] = []string{f.Stop.Format(time.RFC3339Nano)}
+	}
+	if f.ResourceType != nil {
+		qp["resourceType"] = []string{string(*f.ResourceType)}
+	}
+	if f.ResourceID != nil {
+		qp["resourceID"] = []string{f.ResourceID.String()}
+	}
+	if f.ActorID != nil {
+		qp["actorID"] = []string{f.ActorID.String()}
+	}
+	return qp
+}
+
+type getAuditEventsRequest struct {
+	filter auditEventFilter
+	opts   platform.FindOptions
+}
+
+func decodeGetAuditEventsRequest(ctx context.Context, r *http.Request) (*getAuditEventsRequest, error) {
+	qp := r.URL.Query()
+	req := &getAuditEventsRequest{}
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+	if qp.Get("descending") == "" {
+		req.opts.Descending = platform.DefaultAuditLogFindOptions.Descending
+	}
+
+	for _, t := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"start", &req.filter.Start},
+		{"stop", &req.filter.Stop},
+	} {
+		v := qp.Get(t.name)
+		if v == "" {
+			continue
+		}
+		tm, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  t.name + " must be an RFC3339 time",
+			}
+		}
+		*t.dst = &tm
+	}
+
+	if rt := qp.Get("resourceType"); rt != "" {
+		resourceType := platform.ResourceType(rt)
+		req.filter.ResourceType = &resourceType
+	}
+
+	for _, id := range []struct {
+		name string
+		dst  **platform.ID
+	}{
+		{"resourceID", &req.filter.ResourceID},
+		{"actorID", &req.filter.ActorID},
+	} {
+		v := qp.Get(id.name)
+		if v == "" {
+			continue
+		}
+		i, err := platform.IDFromString(v)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  id.name + " is invalid",
+				Err:  err,
+			}
+		}
+		*id.dst = i
+	}
+
+	return req, nil
+}
+
+// AuditLogService connects to Influx via HTTP using tokens to read the audit log.
+type AuditLogService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.AuditLogService = (*AuditLogService)(nil)
+
+// LogAuditEvent is not supported over HTTP, the events are recorded by the
+// server as changes are made.
+func (s *AuditLogService) LogAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	return errors.New("not supported in HTTP audit log service")
+}
+
+// FindAuditEvents returns the audit events that match the filter.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter platform.AuditEventFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+	u, err := newURL(s.Addr, auditPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := u.Query()
+	for k, vs := range auditEventFilter(filter).QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	// the server returns the latest events by default.
+	query.Set("descending", "false")
+	if len(opt) > 0 {
+		query.Set("descending", strconv.FormatBool(opt[0].Descending))
+		if opt[0].Limit > 0 {
+			query.Set("limit", strconv.Itoa(opt[0].Limit))
+		}
+		if opt[0].Offset > 0 {
+			query.Set("offset", strconv.Itoa(opt[0].Offset))
+		}
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, 0, err
+	}
+
+	var res auditEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, 0, err
+	}
+
+	return res.Events, len(res.Events), nil
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/audit"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func TestAuditingHandler(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	auth := &platform.Authorization{ID: platform.ID(1), UserID: user.ID, Token: "secret"}
+
+	bucketSvc := &audit.BucketService{BucketService: svc, Recorder: audit.NewRecorder(svc)}
+	bucketHandler := NewBucketHandler(svc, svc)
+	bucketHandler.BucketService = bucketSvc
+	bucketHandler.UserService = svc
+	auditing := NewAuditingHandler(bucketHandler)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auditing.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), auth)))
+	})
+
+	do := func(method, path, body, requestID string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://any.url"+path, bytes.NewBufferString(body))
+		if requestID != "" {
+			r.Header.Set(RequestIDHeader, requestID)
+		}
+		h.ServeHTTP(w, r)
+		if w.Code/100 != 2 {
+			t.Fatalf("%s %s: unexpected status %d: %s", method, path, w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	w := do("POST", "/api/v2/buckets", `{"name":"b1","organizationID":"`+org.ID.String()+`"}`, "POST")
+	var b platform.Bucket
+	if err := json.Unmarshal(w.Body.Bytes(), &b); err != nil {
+		t.Fatal(err)
+	}
+	do("PATCH", "/api/v2/buckets/"+b.ID.String(), `{"name":"b2"}`, "PATCH")
+	do("GET", "/api/v2/buckets/"+b.ID.String(), "", "")
+	w = do("DELETE", "/api/v2/buckets/"+b.ID.String(), "", "")
+	deleteID := w.Header().Get(RequestIDHeader)
+	if deleteID == "" {
+		t.Fatal("expected the request to be given an ID")
+	}
+
+	es, _, err := svc.FindAuditEvents(ctx, platform.AuditEventFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Action     platform.AuditAction
+		RequestID  string
+		Method     string
+		Path       string
+		Changes    []string
+		HasBefore  bool
+		HasAfter   bool
+		ResourceID platform.ID
+		ActorID    platform.ID
+		Kind       string
+	}
+	var got []event
+	for _, e := range es {
+		got = append(got, event{
+			Action:     e.Action,
+			RequestID:  e.RequestID,
+			Method:     e.Method,
+			Path:       e.Path,
+			Changes:    e.Changes,
+			HasBefore:  len(e.Before) > 0,
+			HasAfter:   len(e.After) > 0,
+			ResourceID: e.ResourceID,
+			ActorID:    e.ActorID,
+			Kind:       e.AuthorizerKind,
+		})
+		if e.ResourceType != platform.BucketResourceType {
+			t.Errorf("unexpected resource type %q", e.ResourceType)
+		}
+	}
+	// events of the same request time are ordered by ID.
+	want := []event{
+		{Action: platform.AuditCreate, RequestID: "POST", Method: "POST", Path: "/api/v2/buckets", HasAfter: true, ResourceID: b.ID, ActorID: user.ID, Kind: "authorization"},
+		{Action: platform.AuditUpdate, RequestID: "PATCH", Method: "PATCH", Path: "/api/v2/buckets/" + b.ID.String(), Changes: []string{"name"}, HasBefore: true, HasAfter: true, ResourceID: b.ID, ActorID: user.ID, Kind: "authorization"},
+		{Action: platform.AuditDelete, RequestID: deleteID, Method: "DELETE", Path: "/api/v2/buckets/" + b.ID.String(), HasBefore: true, ResourceID: b.ID, ActorID: user.ID, Kind: "authorization"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func initAuditLogService(f platformtesting.AuditLogFields, t *testing.T) (platform.AuditLogService, string, func()) {
+	t.Helper()
+	svc := inmem.NewService()
+	svc.IDGenerator = f.IDGenerator
+	if f.NowFn != nil {
+		svc.WithTime(f.NowFn)
+	}
+
+	ctx := context.Background()
+	for _, e := range f.Events {
+		if err := svc.LogAuditEvent(ctx, e); err != nil {
+			t.Fatalf("failed to populate audit events")
+		}
+	}
+
+	handler := NewAuditHandler()
+	handler.AuditLogService = svc
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: []platform.Permission{platform.CreateUserPermission},
+		})))
+	}))
+	client := AuditLogService{
+		Addr: server.URL,
+	}
+	return &client, inmem.OpPrefix, server.Close
+}
+
+func TestAuditLogService_FindAuditEvents(t *testing.T) {
+	platformtesting.FindAuditEvents(initAuditLogService, t)
+}
+
+func TestAuditHandler_Forbidden(t *testing.T) {
+	svc := inmem.NewService()
+	handler := NewAuditHandler()
+	handler.AuditLogService = svc
+
+	writeOnly := &platform.Authorization{
+		Status:      platform.Active,
+		Permissions: []platform.Permission{platform.WriteBucketPermission(1)},
+	}
+	for _, path := range []string{auditPath, auditExportPath} {
+		r := httptest.NewRequest("GET", "http://any.url"+path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), writeOnly)))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("GET %s: expected status %d, got %d", path, http.StatusForbidden, w.Code)
+		}
+	}
+}
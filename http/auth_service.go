@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -35,6 +37,7 @@ func NewAuthorizationHandler() *AuthorizationHandler {
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleSetAuthorizationStatus)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	return h
 }
 
@@ -107,6 +110,17 @@ func decodePostAuthorizationRequest(ctx context.Context, r *http.Request) (*post
 		return nil, err
 	}
 
+	if a.ExpiresAt != nil && !a.ExpiresAt.After(time.Now()) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "authorization must expire in the future",
+		}
+	}
+	// usage and rotation are tracked by the service.
+	a.LastUsedAt = nil
+	a.PreviousToken = ""
+	a.PreviousTokenExpiresAt = nil
+
 	return &postAuthorizationRequest{
 		Authorization: a,
 	}, nil
@@ -169,6 +183,28 @@ func decodeGetAuthorizationsRequest(ctx context.Context, r *http.Request) (*getA
 		req.filter.ID = id
 	}
 
+	if expired := qp.Get("expired"); expired != "" {
+		b, err := strconv.ParseBool(expired)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "expired must be a boolean",
+			}
+		}
+		req.filter.Expired = b
+	}
+
+	if since := qp.Get("unusedSince"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "unusedSince must be an RFC3339 time",
+			}
+		}
+		req.filter.UnusedSince = &t
+	}
+
 	return req, nil
 }
 
@@ -323,6 +359,73 @@ func decodeDeleteAuthorizationRequest(ctx context.Context, r *http.Request) (*de
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationService.RotateAuthorization(ctx, req.ID, req.Grace)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuthResponse(a)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID    platform.ID
+	Grace time.Duration
+}
+
+type rotateAuthorizationBody struct {
+	// Grace is how long the previous token remains valid, e.g. 1h.
+	Grace string `json:"grace,omitempty"`
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, kerrors.InvalidDataf("url missing id")
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	req := &rotateAuthorizationRequest{ID: i}
+
+	b := &rotateAuthorizationBody{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(b); err != nil {
+			return nil, err
+		}
+	}
+	if b.Grace != "" {
+		d, err := time.ParseDuration(b.Grace)
+		if err != nil || d < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "grace must be a positive duration",
+			}
+		}
+		req.Grace = d
+	}
+
+	return req, nil
+}
+
 // AuthorizationService connects to Influx via HTTP using tokens to manage authorizations
 type AuthorizationService struct {
 	Addr               string
@@ -396,6 +499,14 @@ func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter pl
 		query.Add("user", *filter.User)
 	}
 
+	if filter.Expired {
+		query.Add("expired", "true")
+	}
+
+	if filter.UnusedSince != nil {
+		query.Add("unusedSince", filter.UnusedSince.Format(time.RFC3339))
+	}
+
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
@@ -527,6 +638,56 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 	return CheckError(resp, true)
 }
 
+// RotateAuthorization issues a new token for the authorization. The previous
+// token remains valid for the grace period.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	u, err := newURL(s.Addr, path.Join(authorizationIDPath(id), "rotate"))
+	if err != nil {
+		return nil, err
+	}
+
+	b := rotateAuthorizationBody{}
+	if grace > 0 {
+		b.Grace = grace.String()
+	}
+	octets, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var a authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return nil, err
+	}
+	return &a.Authorization, nil
+}
+
 func authorizationIDPath(id platform.ID) string {
 	return path.Join(authorizationPath, id.String())
 }
+
+// isOperator returns true if a is the authorizer of an operator. Operators
+// can create users, and so act as any of them, which lets them access the
+// resources of every organization.
+func isOperator(a platform.Authorizer) bool {
+	return a.Allowed(platform.CreateUserPermission)
+}
@@ -474,6 +474,9 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	svc := inmem.NewService()
 	svc.IDGenerator = f.IDGenerator
 	svc.TokenGenerator = f.TokenGenerator
+	if f.NowFn != nil {
+		svc.WithTime(f.NowFn)
+	}
 
 	ctx := context.Background()
 	for _, u := range f.Users {
@@ -519,6 +522,10 @@ func TestAuthorizationService_FindAuthorizations(t *testing.T) {
 	platformtesting.FindAuthorizations(initAuthorizationService, t)
 }
 
+func TestAuthorizationService_RotateAuthorization(t *testing.T) {
+	platformtesting.RotateAuthorization(initAuthorizationService, t)
+}
+
 func TestAuthorizationService_DeleteAuthorization(t *testing.T) {
 	platformtesting.DeleteAuthorization(initAuthorizationService, t)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/influxdata/platform"
 	platcontext "github.com/influxdata/platform/context"
@@ -18,6 +19,9 @@ type AuthenticationHandler struct {
 	AuthorizationService platform.AuthorizationService
 	SessionService       platform.SessionService
 
+	// AuthorizationUsageService, when set, records when tokens are used.
+	AuthorizationUsageService platform.AuthorizationUsageService
+
 	// This is only really used for it's lookup method the specific http
 	// hanlder used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -108,9 +112,35 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return ctx, err
 	}
 
+	now := time.Now()
+	if a.Expired(now) {
+		return ctx, fmt.Errorf("token expired")
+	}
+	if a.Status == platform.Inactive {
+		return ctx, fmt.Errorf("token is inactive")
+	}
+	h.recordUsage(a, now)
+
 	return platcontext.SetAuthorizer(ctx, a), nil
 }
 
+// lastUsedResolution is how stale the last use of a token may get before it
+// is written again, to avoid a write for every request.
+const lastUsedResolution = time.Minute
+
+// recordUsage updates the last use of a in the background.
+func (h *AuthenticationHandler) recordUsage(a *platform.Authorization, now time.Time) {
+	if h.AuthorizationUsageService == nil || !a.Stale(now.Add(-lastUsedResolution)) {
+		return
+	}
+
+	go func() {
+		if err := h.AuthorizationUsageService.SetAuthorizationLastUsed(context.Background(), a.ID, now); err != nil {
+			h.Logger.Info("failed to record authorization usage", zap.Error(err))
+		}
+	}()
+}
+
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (context.Context, error) {
 	k, err := decodeCookieSession(ctx, r)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
 	platformhttp "github.com/influxdata/platform/http"
@@ -99,6 +100,24 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusForbidden,
 			},
 		},
+		{
+			name: "token expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expiresAt := time.Now().Add(-time.Minute)
+						return &platform.Authorization{ExpiresAt: &expiresAt}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusForbidden,
+			},
+		},
 		{
 			name: "no auth provided",
 			fields: fields{
@@ -143,6 +162,42 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_RecordsUsage(t *testing.T) {
+	used := make(chan platform.ID, 1)
+	h := platformhttp.NewAuthenticationHandler()
+	h.AuthorizationService = &mock.AuthorizationService{
+		FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+			return &platform.Authorization{ID: platform.ID(1)}, nil
+		},
+	}
+	h.AuthorizationUsageService = usageFn(func(ctx context.Context, id platform.ID, at time.Time) error {
+		used <- id
+		return nil
+	})
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("POST", "http://any.url", nil)
+	platformhttp.SetToken("abc123", r)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	select {
+	case id := <-used:
+		if id != platform.ID(1) {
+			t.Fatalf("recorded usage of %s, want %s", id, platform.ID(1))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("usage of the authorization was not recorded")
+	}
+}
+
+type usageFn func(context.Context, platform.ID, time.Time) error
+
+func (fn usageFn) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, at time.Time) error {
+	return fn(ctx, id, at)
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	bootstrapPath = "/api/v2/bootstrap"
+)
+
+// BootstrapHandler represents an HTTP API handler applying bootstrap
+// configurations.
+type BootstrapHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	Applier *bootstrap.Applier
+}
+
+// NewBootstrapHandler returns a new instance of BootstrapHandler.
+func NewBootstrapHandler() *BootstrapHandler {
+	h := &BootstrapHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("POST", bootstrapPath, h.handlePostBootstrap)
+	return h
+}
+
+type bootstrapResponse struct {
+	Changes []bootstrap.Change `json:"changes"`
+}
+
+// handlePostBootstrap is the HTTP handler for the POST /api/v2/bootstrap
+// route. The changes are only computed if the dryRun parameter is true.
+func (h *BootstrapHandler) handlePostBootstrap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if !isOperator(a) {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "applying a bootstrap config requires the permission to create users",
+		}, w)
+		return
+	}
+
+	c, dryRun, err := decodePostBootstrapRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	apply := h.Applier.Apply
+	if dryRun {
+		apply = h.Applier.Diff
+	}
+	changes, err := apply(ctx, c)
+	for _, change := range changes {
+		h.Logger.Info("Applied bootstrap config", zap.Stringer("change", change), zap.Bool("dryRun", dryRun))
+	}
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	if changes == nil {
+		changes = []bootstrap.Change{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, bootstrapResponse{Changes: changes}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostBootstrapRequest(ctx context.Context, r *http.Request) (*bootstrap.Config, bool, error) {
+	var dryRun bool
+	if s := r.URL.Query().Get("dryRun"); s != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			return nil, false, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "dryRun must be true or false",
+			}
+		}
+	}
+
+	c := new(bootstrap.Config)
+	if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+		return nil, false, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if err := c.Valid(); err != nil {
+		return nil, false, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+	return c, dryRun, nil
+}
+
+// BootstrapService connects to Influx via HTTP using tokens to apply
+// bootstrap configurations.
+type BootstrapService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Apply applies the configuration and returns the changes made, or only
+// returns the changes to be made if dryRun is true.
+func (s *BootstrapService) Apply(ctx context.Context, c *bootstrap.Config, dryRun bool) ([]bootstrap.Change, error) {
+	u, err := newURL(s.Addr, bootstrapPath)
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("dryRun", strconv.FormatBool(dryRun))
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var res bootstrapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Changes, nil
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bootstrap"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+)
+
+func TestBootstrapService_Apply(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	h := NewBootstrapHandler()
+	h.Applier = &bootstrap.Applier{
+		UserService:                svc,
+		BasicAuthService:           svc,
+		OrganizationService:        svc,
+		BucketService:              svc,
+		UserResourceMappingService: svc,
+		AuthorizationService:       svc,
+		DashboardService:           svc,
+		TelegrafService:            svc,
+	}
+
+	var auth platform.Authorizer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), auth)))
+	}))
+	defer server.Close()
+	client := &BootstrapService{Addr: server.URL}
+
+	c := &bootstrap.Config{
+		Users: []bootstrap.UserConfig{{Name: "admin", Password: "supersecret"}},
+		Orgs: []bootstrap.OrgConfig{{
+			Name:    "acme",
+			Owners:  []string{"admin"},
+			Buckets: []bootstrap.BucketConfig{{Name: "telegraf"}},
+		}},
+	}
+
+	auth = &platform.Authorization{Status: platform.Active}
+	if _, err := client.Apply(ctx, c, false); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error without the permission to create users, got %v", err)
+	}
+
+	auth = &platform.Authorization{
+		Status:      platform.Active,
+		Permissions: []platform.Permission{platform.CreateUserPermission},
+	}
+	changes, err := client.Apply(ctx, c, true)
+	if err != nil {
+		t.Fatalf("unexpected error in dry run: %v", err)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes in dry run, got %v", changes)
+	}
+	if _, err := svc.FindOrganization(ctx, platform.OrganizationFilter{Name: &c.Orgs[0].Name}); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("dry run created org: %v", err)
+	}
+
+	if changes, err = client.Apply(ctx, c, false); err != nil || len(changes) != 4 {
+		t.Fatalf("unexpected apply %v, %v", changes, err)
+	}
+	if changes, err = client.Apply(ctx, c, false); err != nil || len(changes) != 0 {
+		t.Fatalf("unexpected reapply %v, %v", changes, err)
+	}
+
+	c.Orgs[0].Owners = []string{"nobody"}
+	if _, err := client.Apply(ctx, c, false); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error for an unknown owner, got %v", err)
+	}
+}
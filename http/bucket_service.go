@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/kit/errors"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -27,12 +28,17 @@ type BucketHandler struct {
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
+	// BucketLifecycleService lists and restores the deleted buckets, and
+	// the references to buckets; buckets are deleted right away if nil.
+	BucketLifecycleService platform.BucketLifecycleService
 }
 
 const (
 	bucketsPath             = "/api/v2/buckets"
 	bucketsIDPath           = "/api/v2/buckets/:id"
 	bucketsIDLogPath        = "/api/v2/buckets/:id/log"
+	bucketsIDReferencesPath = "/api/v2/buckets/:id/references"
+	bucketsIDRestorePath    = "/api/v2/buckets/:id/restore"
 	bucketsIDMembersPath    = "/api/v2/buckets/:id/members"
 	bucketsIDMembersIDPath  = "/api/v2/buckets/:id/members/:userID"
 	bucketsIDOwnersPath     = "/api/v2/buckets/:id/owners"
@@ -56,6 +62,8 @@ func NewBucketHandler(mappingService platform.UserResourceMappingService, labelS
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
+	h.HandlerFunc("GET", bucketsIDReferencesPath, h.handleGetBucketReferences)
+	h.HandlerFunc("POST", bucketsIDRestorePath, h.handlePostBucketRestore)
 
 	h.HandlerFunc("POST", bucketsIDMembersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.BucketResourceType, platform.Member))
 	h.HandlerFunc("GET", bucketsIDMembersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.BucketResourceType, platform.Member))
@@ -81,6 +89,7 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	DeletedAt           *time.Time      `json:"deletedAt,omitempty"`
 }
 
 // retentionRule is the retention rule action for a bucket.
@@ -111,6 +120,7 @@ func (b *bucket) toPlatform() (*platform.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		DeletedAt:           b.DeletedAt,
 	}, nil
 }
 
@@ -135,6 +145,7 @@ func newBucket(pb *platform.Bucket) *bucket {
 		Name:                pb.Name,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		DeletedAt:           pb.DeletedAt,
 	}
 }
 
@@ -320,6 +331,7 @@ func (h *BucketHandler) handleDeleteBucket(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	ctx = platcontext.SetBucketReferencePolicy(ctx, req.References)
 	if err := h.BucketService.DeleteBucket(ctx, req.BucketID); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -329,7 +341,8 @@ func (h *BucketHandler) handleDeleteBucket(w http.ResponseWriter, r *http.Reques
 }
 
 type deleteBucketRequest struct {
-	BucketID platform.ID
+	BucketID   platform.ID
+	References platform.BucketReferencePolicy
 }
 
 func decodeDeleteBucketRequest(ctx context.Context, r *http.Request) (*deleteBucketRequest, error) {
@@ -343,13 +356,28 @@ func decodeDeleteBucketRequest(ctx context.Context, r *http.Request) (*deleteBuc
 	if err := i.DecodeFromString(id); err != nil {
 		return nil, err
 	}
+	refs, err := decodeBucketReferencePolicy(r)
+	if err != nil {
+		return nil, err
+	}
 	req := &deleteBucketRequest{
-		BucketID: i,
+		BucketID:   i,
+		References: refs,
 	}
 
 	return req, nil
 }
 
+// decodeBucketReferencePolicy decodes what renaming or deleting a bucket does
+// about the resources referring to it.
+func decodeBucketReferencePolicy(r *http.Request) (platform.BucketReferencePolicy, error) {
+	p := platform.BucketReferencePolicy(r.URL.Query().Get("references"))
+	if p == "" {
+		return platform.BucketReferencesCheck, nil
+	}
+	return p, p.Valid()
+}
+
 // handleGetBuckets is the HTTP handler for the GET /api/v2/buckets route.
 func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -360,7 +388,15 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bs, _, err := h.BucketService.FindBuckets(ctx, req.filter, req.opts)
+	var bs []*platform.Bucket
+	if req.deleted {
+		// the deleted buckets are only listed on request, to be restored.
+		if h.BucketLifecycleService != nil {
+			bs, err = h.BucketLifecycleService.FindDeletedBuckets(ctx, req.filter)
+		}
+	} else {
+		bs, _, err = h.BucketService.FindBuckets(ctx, req.filter, req.opts)
+	}
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -373,8 +409,9 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 }
 
 type getBucketsRequest struct {
-	filter platform.BucketFilter
-	opts   platform.FindOptions
+	filter  platform.BucketFilter
+	opts    platform.FindOptions
+	deleted bool
 }
 
 func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsRequest, error) {
@@ -404,6 +441,16 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 		req.filter.Name = &name
 	}
 
+	if deleted := qp.Get("deleted"); deleted != "" {
+		var err error
+		if req.deleted, err = strconv.ParseBool(deleted); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "deleted must be true or false",
+			}
+		}
+	}
+
 	return req, nil
 }
 
@@ -417,6 +464,7 @@ func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	ctx = platcontext.SetBucketReferencePolicy(ctx, req.References)
 	b, err := h.BucketService.UpdateBucket(ctx, req.BucketID, req.Update)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -430,8 +478,9 @@ func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request
 }
 
 type patchBucketRequest struct {
-	Update   platform.BucketUpdate
-	BucketID platform.ID
+	Update     platform.BucketUpdate
+	BucketID   platform.ID
+	References platform.BucketReferencePolicy
 }
 
 func decodePatchBucketRequest(ctx context.Context, r *http.Request) (*patchBucketRequest, error) {
@@ -456,12 +505,84 @@ func decodePatchBucketRequest(ctx context.Context, r *http.Request) (*patchBucke
 		return nil, err
 	}
 
+	refs, err := decodeBucketReferencePolicy(r)
+	if err != nil {
+		return nil, err
+	}
+
 	return &patchBucketRequest{
-		Update:   *upd,
-		BucketID: i,
+		Update:     *upd,
+		BucketID:   i,
+		References: refs,
 	}, nil
 }
 
+type bucketReferencesResponse struct {
+	References []*platform.BucketReference `json:"references"`
+}
+
+// handleGetBucketReferences is the HTTP handler for the GET /api/v2/buckets/:id/references route.
+func (h *BucketHandler) handleGetBucketReferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeBucketLifecycleRequest(ctx, h.BucketLifecycleService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	refs, err := h.BucketLifecycleService.FindBucketReferences(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if refs == nil {
+		refs = []*platform.BucketReference{}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, bucketReferencesResponse{References: refs}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostBucketRestore is the HTTP handler for the POST /api/v2/buckets/:id/restore route.
+func (h *BucketHandler) handlePostBucketRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeBucketLifecycleRequest(ctx, h.BucketLifecycleService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketLifecycleService.RestoreBucket(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketResponse(b)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeBucketLifecycleRequest(ctx context.Context, s platform.BucketLifecycleService) (platform.ID, error) {
+	if s == nil {
+		return 0, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "buckets are deleted right away",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("id")); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
 const (
 	bucketPath = "/api/v2/buckets"
 )
@@ -631,7 +752,8 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *platform.Bucket) er
 }
 
 // UpdateBucket updates a single bucket with changeset.
-// Returns the new bucket state after update.
+// Returns the new bucket state after update. The bucket reference policy set
+// on ctx, if any, is sent along.
 func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
 	u, err := newURL(s.Addr, bucketIDPath(id))
 	if err != nil {
@@ -648,6 +770,7 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd pl
 	if err != nil {
 		return nil, err
 	}
+	setBucketReferencePolicy(ctx, req)
 
 	req.Header.Set("Content-Type", "application/json")
 	SetToken(s.Token, req)
@@ -671,7 +794,8 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd pl
 	return br.toPlatform()
 }
 
-// DeleteBucket removes a bucket by ID.
+// DeleteBucket removes a bucket by ID. The bucket reference policy set on
+// ctx, if any, is sent along.
 func (s *BucketService) DeleteBucket(ctx context.Context, id platform.ID) error {
 	u, err := newURL(s.Addr, bucketIDPath(id))
 	if err != nil {
@@ -682,6 +806,7 @@ func (s *BucketService) DeleteBucket(ctx context.Context, id platform.ID) error
 	if err != nil {
 		return err
 	}
+	setBucketReferencePolicy(ctx, req)
 	SetToken(s.Token, req)
 
 	hc := newClient(u.Scheme, s.InsecureSkipVerify)
@@ -692,6 +817,126 @@ func (s *BucketService) DeleteBucket(ctx context.Context, id platform.ID) error
 	return CheckError(resp, true)
 }
 
+// setBucketReferencePolicy sets the references query parameter of a request
+// renaming or deleting a bucket.
+func setBucketReferencePolicy(ctx context.Context, req *http.Request) {
+	query := req.URL.Query()
+	query.Set("references", string(platcontext.GetBucketReferencePolicy(ctx)))
+	req.URL.RawQuery = query.Encode()
+}
+
+var _ platform.BucketLifecycleService = (*BucketService)(nil)
+
+// FindBucketReferences returns the resources pointing at a bucket.
+func (s *BucketService) FindBucketReferences(ctx context.Context, id platform.ID) ([]*platform.BucketReference, error) {
+	u, err := newURL(s.Addr, path.Join(bucketIDPath(id), "references"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var rs bucketReferencesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rs); err != nil {
+		return nil, err
+	}
+	return rs.References, nil
+}
+
+// FindDeletedBuckets returns the deleted buckets matching filter that can
+// still be restored.
+func (s *BucketService) FindDeletedBuckets(ctx context.Context, filter platform.BucketFilter) ([]*platform.Bucket, error) {
+	u, err := newURL(s.Addr, bucketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			query.Add(k, v)
+		}
+	}
+	query.Set("deleted", "true")
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var bs bucketsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
+		return nil, err
+	}
+	buckets := make([]*platform.Bucket, 0, len(bs.Buckets))
+	for _, b := range bs.Buckets {
+		pb, err := b.bucket.toPlatform()
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, pb)
+	}
+	return buckets, nil
+}
+
+// RestoreBucket restores a deleted bucket before its data is purged.
+func (s *BucketService) RestoreBucket(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+	u, err := newURL(s.Addr, path.Join(bucketIDPath(id), "restore"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var br bucketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+	return br.toPlatform()
+}
+
 func bucketIDPath(id platform.ID) string {
 	return path.Join(bucketPath, id.String())
 }
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/influxdata/platform"
+	platformbucket "github.com/influxdata/platform/bucket"
+	platcontext "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/inmem"
 	"github.com/influxdata/platform/mock"
 	platformtesting "github.com/influxdata/platform/testing"
@@ -799,3 +801,62 @@ func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.B
 func TestBucketService(t *testing.T) {
 	platformtesting.BucketService(initBucketService, t)
 }
+
+func TestBucketService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	lifecycle := &platformbucket.Service{
+		BucketService:             svc,
+		OrganizationService:       svc,
+		ScraperTargetStoreService: svc,
+		GracePeriod:               time.Hour,
+	}
+
+	h := NewBucketHandler(svc, svc)
+	h.BucketService = lifecycle
+	h.BucketLifecycleService = lifecycle
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := &BucketService{Addr: server.URL}
+
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{OrganizationID: o.ID, Name: "telegraf"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	target := &platform.ScraperTarget{Name: "node", Type: platform.PrometheusScraperType, OrgName: "acme", BucketName: "telegraf"}
+	if err := svc.AddTarget(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := client.FindBucketReferences(ctx, b.ID)
+	if err != nil || len(refs) != 1 || refs[0].Kind != platform.ScraperBucketReference || refs[0].ID != target.ID {
+		t.Fatalf("unexpected references %v %v", refs, err)
+	}
+	if err := client.DeleteBucket(ctx, b.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict deleting a referenced bucket, got %v", err)
+	}
+
+	ignore := platcontext.SetBucketReferencePolicy(ctx, platform.BucketReferencesIgnore)
+	if err := client.DeleteBucket(ignore, b.ID); err != nil {
+		t.Fatalf("unexpected error deleting bucket: %v", err)
+	}
+	if bs, _, err := client.FindBuckets(ctx, platform.BucketFilter{}); err != nil || len(bs) != 0 {
+		t.Fatalf("expected the deleted bucket not to be listed: %v %v", bs, err)
+	}
+	deleted, err := client.FindDeletedBuckets(ctx, platform.BucketFilter{OrganizationID: &o.ID})
+	if err != nil || len(deleted) != 1 || deleted[0].DeletedAt == nil {
+		t.Fatalf("unexpected deleted buckets %v %v", deleted, err)
+	}
+
+	restored, err := client.RestoreBucket(ctx, b.ID)
+	if err != nil || restored.DeletedAt != nil {
+		t.Fatalf("unexpected restore %v %v", restored, err)
+	}
+	if _, err := client.RestoreBucket(ctx, b.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring a bucket that is not deleted, got %v", err)
+	}
+}
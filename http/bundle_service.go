@@ -0,0 +1,263 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bundle"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	exportPath = "/api/v2/export"
+	importPath = "/api/v2/import"
+)
+
+// BundleHandler represents an HTTP API handler exporting and importing the
+// resources of organizations.
+type BundleHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	BundleService              *bundle.Service
+	UserResourceMappingService platform.UserResourceMappingService
+}
+
+// NewBundleHandler returns a new instance of BundleHandler.
+func NewBundleHandler() *BundleHandler {
+	h := &BundleHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", exportPath, h.handleGetExport)
+	h.HandlerFunc("POST", importPath, h.handlePostImport)
+	return h
+}
+
+type importResponse struct {
+	Resources []bundle.ImportedResource `json:"resources"`
+}
+
+// handleGetExport is the HTTP handler for the GET /api/v2/export route.
+func (h *BundleHandler) handleGetExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := decodeBundleOrgID(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := h.authorizeOrg(ctx, orgID, false); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BundleService.Export(ctx, orgID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, b); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostImport is the HTTP handler for the POST /api/v2/import route.
+// The imported resources are owned by the user of the request.
+func (h *BundleHandler) handlePostImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, opts, err := decodePostImportRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := h.authorizeOrg(ctx, opts.OrgID, true); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	opts.UserID = a.GetUserID()
+
+	rs, err := h.BundleService.Import(ctx, b, opts)
+	for _, res := range rs {
+		h.Logger.Info("Imported bundle resource",
+			zap.String("kind", res.Kind),
+			zap.String("name", res.Name),
+			zap.String("action", string(res.Action)),
+			zap.Stringer("id", res.ID),
+		)
+	}
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if rs == nil {
+		rs = []bundle.ImportedResource{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, importResponse{Resources: rs}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// authorizeOrg returns an error unless the user of the authorizer of ctx is
+// a member of the organization, directly or through its groups, or an owner
+// if owner is true. Operators are allowed for any organization.
+func (h *BundleHandler) authorizeOrg(ctx context.Context, orgID platform.ID, owner bool) error {
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if isOperator(a) {
+		return nil
+	}
+
+	ms, err := platform.UserMappings(ctx, h.UserResourceMappingService, a.GetUserID())
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if m.ResourceType != platform.OrgResourceType || m.ResourceID != orgID {
+			continue
+		}
+		if !owner || m.UserType == platform.Owner {
+			return nil
+		}
+	}
+	if owner {
+		return &platform.Error{Code: platform.EForbidden, Msg: "importing requires to be an owner of the organization"}
+	}
+	return &platform.Error{Code: platform.EForbidden, Msg: "exporting requires to be a member of the organization"}
+}
+
+func decodeBundleOrgID(r *http.Request) (platform.ID, error) {
+	var orgID platform.ID
+	if err := orgID.DecodeFromString(r.URL.Query().Get("orgID")); err != nil {
+		return 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "orgID is invalid",
+			Err:  err,
+		}
+	}
+	return orgID, nil
+}
+
+func decodePostImportRequest(r *http.Request) (*bundle.Bundle, bundle.ImportOptions, error) {
+	var opts bundle.ImportOptions
+	orgID, err := decodeBundleOrgID(r)
+	if err != nil {
+		return nil, opts, err
+	}
+	opts.OrgID = orgID
+	opts.Conflict = bundle.ConflictStrategy(r.URL.Query().Get("conflict"))
+
+	b := new(bundle.Bundle)
+	if err := json.NewDecoder(r.Body).Decode(b); err != nil {
+		return nil, opts, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	return b, opts, nil
+}
+
+// BundleService connects to Influx via HTTP using tokens to export and
+// import the resources of organizations.
+type BundleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Export returns the bundle of the resources of an organization.
+func (s *BundleService) Export(ctx context.Context, orgID platform.ID) (*bundle.Bundle, error) {
+	u, err := newURL(s.Addr, exportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("orgID", orgID.String())
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	b := new(bundle.Bundle)
+	if err := json.NewDecoder(resp.Body).Decode(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Import imports a bundle into an organization and returns what was done
+// with its resources. The user of the token owns the imported resources.
+func (s *BundleService) Import(ctx context.Context, b *bundle.Bundle, opts bundle.ImportOptions) ([]bundle.ImportedResource, error) {
+	u, err := newURL(s.Addr, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("orgID", opts.OrgID.String())
+	if opts.Conflict != "" {
+		query.Set("conflict", string(opts.Conflict))
+	}
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var res importResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Resources, nil
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bundle"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+)
+
+func TestBundleService_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	h := NewBundleHandler()
+	h.BundleService = &bundle.Service{
+		OrganizationService:        svc,
+		BucketService:              svc,
+		UserResourceMappingService: svc,
+		DashboardService:           svc,
+		ViewService:                svc,
+		MacroService:               svc,
+		TaskService: &mock.TaskService{
+			FindTasksFn: func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error) {
+				return nil, 0, nil
+			},
+		},
+		TelegrafService: svc,
+		LabelService:    svc,
+	}
+	h.UserResourceMappingService = svc
+
+	u := &platform.User{Name: "admin"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	src := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	dst := &platform.Organization{Name: "staging"}
+	if err := svc.CreateOrganization(ctx, dst); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateBucket(ctx, &platform.Bucket{OrganizationID: src.ID, Name: "telegraf", RetentionPeriod: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	// the user owns the staging org through a group.
+	admins := platform.ID(99)
+	for _, m := range []*platform.UserResourceMapping{
+		{ResourceType: platform.OrgResourceType, ResourceID: src.ID, UserID: u.ID, UserType: platform.Member},
+		{ResourceType: platform.GroupResourceType, ResourceID: admins, UserID: u.ID, UserType: platform.Member},
+		{ResourceType: platform.OrgResourceType, ResourceID: dst.ID, UserID: admins, UserType: platform.Owner, PrincipalType: platform.GroupPrincipal},
+	} {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	auth := &platform.Authorization{Status: platform.Active, UserID: u.ID}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), auth)))
+	}))
+	defer server.Close()
+	client := &BundleService{Addr: server.URL}
+
+	b, err := client.Export(ctx, src.ID)
+	if err != nil {
+		t.Fatalf("unexpected error exporting: %v", err)
+	}
+	if b.Org != "acme" || len(b.Buckets) != 1 {
+		t.Fatalf("unexpected bundle %+v", b)
+	}
+
+	rs, err := client.Import(ctx, b, bundle.ImportOptions{OrgID: dst.ID})
+	if err != nil {
+		t.Fatalf("unexpected error importing: %v", err)
+	}
+	if len(rs) != 1 || rs[0].Action != bundle.Created || rs[0].BundleID != b.Buckets[0].ID {
+		t.Fatalf("unexpected imported resources %+v", rs)
+	}
+	name := "telegraf"
+	if _, err := svc.FindBucket(ctx, platform.BucketFilter{OrganizationID: &dst.ID, Name: &name}); err != nil {
+		t.Fatalf("bucket wasn't imported: %v", err)
+	}
+
+	if _, err := client.Import(ctx, b, bundle.ImportOptions{OrgID: dst.ID, Conflict: "merge"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error for an unknown conflict strategy, got %v", err)
+	}
+
+	// the user is only a member of the source organization.
+	if _, err := client.Import(ctx, b, bundle.ImportOptions{OrgID: src.ID}); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error importing as a member, got %v", err)
+	}
+
+	auth.UserID = platform.ID(1000)
+	if _, err := client.Export(ctx, src.ID); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error exporting as an outsider, got %v", err)
+	}
+	if _, err := client.Import(ctx, b, bundle.ImportOptions{OrgID: dst.ID}); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected a forbidden error importing as an outsider, got %v", err)
+	}
+
+	auth.Permissions = []platform.Permission{platform.CreateUserPermission}
+	if _, err := client.Export(ctx, src.ID); err != nil {
+		t.Fatalf("unexpected error exporting as an operator: %v", err)
+	}
+}
@@ -0,0 +1,815 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/platform"
+	kerrors "github.com/influxdata/platform/kit/errors"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// GroupHandler represents an HTTP API handler for groups.
+type GroupHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	GroupService               platform.GroupService
+	UserResourceMappingService platform.UserResourceMappingService
+	UserService                platform.UserService
+}
+
+const (
+	groupsPath              = "/api/v2/groups"
+	groupsIDPath            = "/api/v2/groups/:id"
+	groupsIDMembersPath     = "/api/v2/groups/:id/members"
+	groupsIDMembersIDPath   = "/api/v2/groups/:id/members/:userID"
+	groupsIDOwnersPath      = "/api/v2/groups/:id/owners"
+	groupsIDOwnersIDPath    = "/api/v2/groups/:id/owners/:userID"
+	groupsIDResourcesPath   = "/api/v2/groups/:id/resources"
+	groupsIDResourcesIDPath = "/api/v2/groups/:id/resources/:resourceID"
+)
+
+// NewGroupHandler returns a new instance of GroupHandler.
+func NewGroupHandler(
+	logger *zap.Logger,
+	mappingService platform.UserResourceMappingService,
+	userService platform.UserService,
+	groupSvc platform.GroupService,
+) *GroupHandler {
+	h := &GroupHandler{
+		Router: NewRouter(),
+		Logger: logger,
+
+		GroupService:               groupSvc,
+		UserResourceMappingService: mappingService,
+		UserService:                userService,
+	}
+
+	h.HandlerFunc("POST", groupsPath, h.handlePostGroup)
+	h.HandlerFunc("GET", groupsPath, h.handleGetGroups)
+	h.HandlerFunc("GET", groupsIDPath, h.handleGetGroup)
+	h.HandlerFunc("PATCH", groupsIDPath, h.handlePatchGroup)
+	h.HandlerFunc("DELETE", groupsIDPath, h.handleDeleteGroup)
+
+	h.HandlerFunc("POST", groupsIDMembersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.GroupResourceType, platform.Member))
+	h.HandlerFunc("GET", groupsIDMembersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.GroupResourceType, platform.Member))
+	h.HandlerFunc("DELETE", groupsIDMembersIDPath, newDeleteMemberHandler(h.UserResourceMappingService, platform.Member))
+
+	h.HandlerFunc("POST", groupsIDOwnersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.GroupResourceType, platform.Owner))
+	h.HandlerFunc("GET", groupsIDOwnersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.GroupResourceType, platform.Owner))
+	h.HandlerFunc("DELETE", groupsIDOwnersIDPath, newDeleteMemberHandler(h.UserResourceMappingService, platform.Owner))
+
+	h.HandlerFunc("POST", groupsIDResourcesPath, h.handlePostGroupResource)
+	h.HandlerFunc("GET", groupsIDResourcesPath, h.handleGetGroupResources)
+	h.HandlerFunc("DELETE", groupsIDResourcesIDPath, h.handleDeleteGroupResource)
+
+	return h
+}
+
+type groupResponse struct {
+	Links map[string]string `json:"links"`
+	platform.Group
+}
+
+func newGroupResponse(g *platform.Group) *groupResponse {
+	return &groupResponse{
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/groups/%s", g.ID),
+			"members":   fmt.Sprintf("/api/v2/groups/%s/members", g.ID),
+			"owners":    fmt.Sprintf("/api/v2/groups/%s/owners", g.ID),
+			"resources": fmt.Sprintf("/api/v2/groups/%s/resources", g.ID),
+			"org":       fmt.Sprintf("/api/v2/orgs/%s", g.OrganizationID),
+		},
+		Group: *g,
+	}
+}
+
+type groupsResponse struct {
+	Links  map[string]string `json:"links"`
+	Groups []*groupResponse  `json:"groups"`
+}
+
+func (r groupsResponse) ToPlatform() []*platform.Group {
+	gs := make([]*platform.Group, len(r.Groups))
+	for i := range r.Groups {
+		gs[i] = &r.Groups[i].Group
+	}
+	return gs
+}
+
+func newGroupsResponse(gs []*platform.Group) *groupsResponse {
+	res := &groupsResponse{
+		Links: map[string]string{
+			"self": groupsPath,
+		},
+		Groups: make([]*groupResponse, 0, len(gs)),
+	}
+	for _, g := range gs {
+		res.Groups = append(res.Groups, newGroupResponse(g))
+	}
+	return res
+}
+
+// groupResourceResponse is a resource a group is mapped to, along with the
+// role the group's members have on it.
+type groupResourceResponse struct {
+	ResourceType platform.ResourceType `json:"resourceType"`
+	ResourceID   platform.ID           `json:"resourceID"`
+	Role         platform.UserType     `json:"role"`
+}
+
+type groupResourcesResponse struct {
+	Links     map[string]string        `json:"links"`
+	Resources []*groupResourceResponse `json:"resources"`
+}
+
+func newGroupResourcesResponse(groupID platform.ID, ms []*platform.UserResourceMapping) *groupResourcesResponse {
+	res := &groupResourcesResponse{
+		Links: map[string]string{
+			"self":  fmt.Sprintf("/api/v2/groups/%s/resources", groupID),
+			"group": fmt.Sprintf("/api/v2/groups/%s", groupID),
+		},
+		Resources: make([]*groupResourceResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		res.Resources = append(res.Resources, &groupResourceResponse{
+			ResourceType: m.ResourceType,
+			ResourceID:   m.ResourceID,
+			Role:         m.UserType,
+		})
+	}
+	return res
+}
+
+// handlePostGroup is the HTTP handler for the POST /api/v2/groups route.
+func (h *GroupHandler) handlePostGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	g := &platform.Group{}
+	if err := json.NewDecoder(r.Body).Decode(g); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handlePostGroup",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.GroupService.CreateGroup(ctx, g); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newGroupResponse(g)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetGroups is the HTTP handler for the GET /api/v2/groups route.
+func (h *GroupHandler) handleGetGroups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetGroupsRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	gs, _, err := h.GroupService.FindGroups(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupsResponse(gs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetGroupsRequest(ctx context.Context, r *http.Request) (platform.GroupFilter, error) {
+	qp := r.URL.Query()
+	filter := platform.GroupFilter{}
+
+	if id := qp.Get("id"); id != "" {
+		i, err := platform.IDFromString(id)
+		if err != nil {
+			return filter, err
+		}
+		filter.ID = i
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		i, err := platform.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrganizationID = i
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// handleGetGroup is the HTTP handler for the GET /api/v2/groups/:id route.
+func (h *GroupHandler) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	g, err := h.GroupService.FindGroupByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupResponse(g)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchGroup is the HTTP handler for the PATCH /api/v2/groups/:id route.
+func (h *GroupHandler) handlePatchGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd platform.GroupUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handlePatchGroup",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	g, err := h.GroupService.UpdateGroup(ctx, id, upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupResponse(g)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteGroup is the HTTP handler for the DELETE /api/v2/groups/:id route.
+func (h *GroupHandler) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.GroupService.DeleteGroup(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type postGroupResourceRequest struct {
+	ResourceType platform.ResourceType `json:"resourceType"`
+	ResourceID   platform.ID           `json:"resourceID"`
+	Role         platform.UserType     `json:"role"`
+}
+
+// handlePostGroupResource is the HTTP handler for the POST /api/v2/groups/:id/resources route.
+func (h *GroupHandler) handlePostGroupResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req := &postGroupResourceRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handlePostGroupResource",
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.Role == "" {
+		req.Role = platform.Member
+	}
+
+	if _, err := h.GroupService.FindGroupByID(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	m := &platform.UserResourceMapping{
+		UserID:        id,
+		UserType:      req.Role,
+		ResourceType:  req.ResourceType,
+		ResourceID:    req.ResourceID,
+		PrincipalType: platform.GroupPrincipal,
+	}
+	if err := m.Validate(); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handlePostGroupResource",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, m); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, &groupResourceResponse{
+		ResourceType: m.ResourceType,
+		ResourceID:   m.ResourceID,
+		Role:         m.UserType,
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetGroupResources is the HTTP handler for the GET /api/v2/groups/:id/resources route.
+func (h *GroupHandler) handleGetGroupResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		UserID:        id,
+		PrincipalType: platform.GroupPrincipal,
+	})
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newGroupResourcesResponse(id, ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteGroupResource is the HTTP handler for the DELETE /api/v2/groups/:id/resources/:resourceID route.
+func (h *GroupHandler) handleDeleteGroupResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGroupIDParam(ctx, "id")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	resourceID, err := decodeGroupIDParam(ctx, "resourceID")
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.DeleteUserResourceMapping(ctx, resourceID, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeGroupIDParam(ctx context.Context, name string) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName(name)
+	if id == "" {
+		return 0, kerrors.InvalidDataf("url missing %s", name)
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// GroupService connects to Influx via HTTP using tokens to manage groups.
+type GroupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+	// OpPrefix is for not found errors.
+	OpPrefix string
+}
+
+var _ platform.GroupService = (*GroupService)(nil)
+
+// FindGroupByID gets a single group by id using HTTP.
+func (s *GroupService) FindGroupByID(ctx context.Context, id platform.ID) (*platform.Group, error) {
+	u, err := newURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var g groupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g.Group, nil
+}
+
+// FindGroup gets the first group matching the filter using HTTP.
+func (s *GroupService) FindGroup(ctx context.Context, filter platform.GroupFilter) (*platform.Group, error) {
+	gs, n, err := s.FindGroups(ctx, filter)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  s.OpPrefix + platform.OpFindGroup,
+		}
+	}
+
+	if n == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   s.OpPrefix + platform.OpFindGroup,
+			Msg:  "group not found",
+		}
+	}
+
+	return gs[0], nil
+}
+
+// FindGroups returns all groups that match the filter via HTTP.
+func (s *GroupService) FindGroups(ctx context.Context, filter platform.GroupFilter, opt ...platform.FindOptions) ([]*platform.Group, int, error) {
+	u, err := newURL(s.Addr, groupsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	qp := u.Query()
+	if filter.ID != nil {
+		qp.Add("id", filter.ID.String())
+	}
+	if filter.OrganizationID != nil {
+		qp.Add("orgID", filter.OrganizationID.String())
+	}
+	if filter.Name != nil {
+		qp.Add("name", *filter.Name)
+	}
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, 0, err
+	}
+
+	var gs groupsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gs); err != nil {
+		return nil, 0, err
+	}
+
+	groups := gs.ToPlatform()
+	return groups, len(groups), nil
+}
+
+// CreateGroup creates a group.
+func (s *GroupService) CreateGroup(ctx context.Context, g *platform.Group) error {
+	u, err := newURL(s.Addr, groupsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(g)
+}
+
+// UpdateGroup updates the group over HTTP.
+func (s *GroupService) UpdateGroup(ctx context.Context, id platform.ID, upd platform.GroupUpdate) (*platform.Group, error) {
+	u, err := newURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var g platform.Group
+	if err := json.NewDecoder(resp.Body).Decode(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// DeleteGroup removes group id over HTTP.
+func (s *GroupService) DeleteGroup(ctx context.Context, id platform.ID) error {
+	u, err := newURL(s.Addr, groupIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp, true)
+}
+
+// AddResource maps the group to a resource with the given role over HTTP.
+func (s *GroupService) AddResource(ctx context.Context, id platform.ID, rt platform.ResourceType, resourceID platform.ID, role platform.UserType) error {
+	u, err := newURL(s.Addr, path.Join(groupIDPath(id), "resources"))
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(postGroupResourceRequest{
+		ResourceType: rt,
+		ResourceID:   resourceID,
+		Role:         role,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusCreated, resp, true)
+}
+
+// FindResources returns the mappings of the group to resources over HTTP.
+func (s *GroupService) FindResources(ctx context.Context, id platform.ID) ([]*platform.UserResourceMapping, error) {
+	u, err := newURL(s.Addr, path.Join(groupIDPath(id), "resources"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var rs groupResourcesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rs); err != nil {
+		return nil, err
+	}
+
+	ms := make([]*platform.UserResourceMapping, 0, len(rs.Resources))
+	for _, r := range rs.Resources {
+		ms = append(ms, &platform.UserResourceMapping{
+			UserID:        id,
+			UserType:      r.Role,
+			ResourceType:  r.ResourceType,
+			ResourceID:    r.ResourceID,
+			PrincipalType: platform.GroupPrincipal,
+		})
+	}
+	return ms, nil
+}
+
+// RemoveResource removes the mapping of the group to a resource over HTTP.
+func (s *GroupService) RemoveResource(ctx context.Context, id, resourceID platform.ID) error {
+	u, err := newURL(s.Addr, path.Join(groupIDPath(id), "resources", resourceID.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp, true)
+}
+
+func groupIDPath(id platform.ID) string {
+	return path.Join(groupsPath, id.String())
+}
+
+// AddMember adds a user to the group with the given role over HTTP.
+func (s *GroupService) AddMember(ctx context.Context, id, userID platform.ID, role platform.UserType) error {
+	u, err := newURL(s.Addr, groupMembersPath(id, role))
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(platform.User{ID: userID})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusCreated, resp, true)
+}
+
+// FindMembers returns the users of the group with the given role over HTTP.
+func (s *GroupService) FindMembers(ctx context.Context, id platform.ID, role platform.UserType) ([]*platform.User, error) {
+	u, err := newURL(s.Addr, groupMembersPath(id, role))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var rs struct {
+		Users []*platform.User `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rs); err != nil {
+		return nil, err
+	}
+	return rs.Users, nil
+}
+
+// RemoveMember removes a user with the given role from the group over HTTP.
+func (s *GroupService) RemoveMember(ctx context.Context, id, userID platform.ID, role platform.UserType) error {
+	u, err := newURL(s.Addr, path.Join(groupMembersPath(id, role), userID.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp, true)
+}
+
+func groupMembersPath(id platform.ID, role platform.UserType) string {
+	return path.Join(groupIDPath(id), string(role)+"s")
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
+	platformtesting "github.com/influxdata/platform/testing"
+	"go.uber.org/zap"
+)
+
+type groupServices struct {
+	*GroupService
+	platform.UserResourceMappingService
+}
+
+func initGroupService(f platformtesting.GroupFields, t *testing.T) (platformtesting.GroupServices, string, func()) {
+	t.Helper()
+	svc := inmem.NewService()
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, g := range f.Groups {
+		if err := svc.PutGroup(ctx, g); err != nil {
+			t.Fatalf("failed to populate groups")
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings")
+		}
+	}
+
+	handler := NewGroupHandler(zap.NewNop(), svc, svc, svc)
+	server := httptest.NewServer(handler)
+	client := groupServices{
+		GroupService: &GroupService{
+			Addr:     server.URL,
+			OpPrefix: inmem.OpPrefix,
+		},
+		UserResourceMappingService: svc,
+	}
+
+	return client, inmem.OpPrefix, server.Close
+}
+
+func TestGroupService(t *testing.T) {
+	t.Parallel()
+	platformtesting.GroupService(initGroupService, t)
+}
+
+func TestGroupService_Members(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+
+	o := &platform.Organization{Name: "o1"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	g := &platform.Group{OrganizationID: o.ID, Name: "g1"}
+	if err := svc.CreateGroup(ctx, g); err != nil {
+		t.Fatal(err)
+	}
+	u := &platform.User{Name: "u1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewGroupHandler(zap.NewNop(), svc, svc, svc))
+	defer server.Close()
+	client := &GroupService{Addr: server.URL}
+
+	if err := client.AddMember(ctx, g.ID, u.ID, platform.Member); err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+
+	users, err := client.FindMembers(ctx, g.ID, platform.Member)
+	if err != nil {
+		t.Fatalf("failed to find members: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != u.ID || users[0].Name != "u1" {
+		t.Fatalf("unexpected members %+v", users)
+	}
+
+	owners, err := client.FindMembers(ctx, g.ID, platform.Owner)
+	if err != nil {
+		t.Fatalf("failed to find owners: %v", err)
+	}
+	if len(owners) != 0 {
+		t.Fatalf("expected no owners, got %+v", owners)
+	}
+
+	if err := client.RemoveMember(ctx, g.ID, u.ID, platform.Member); err != nil {
+		t.Fatalf("failed to remove member: %v", err)
+	}
+	users, err = client.FindMembers(ctx, g.ID, platform.Member)
+	if err != nil {
+		t.Fatalf("failed to find members: %v", err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("expected no members, got %+v", users)
+	}
+}
+
+func TestGroupService_Resources(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+
+	o := &platform.Organization{Name: "o1"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	g := &platform.Group{OrganizationID: o.ID, Name: "g1"}
+	if err := svc.CreateGroup(ctx, g); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewGroupHandler(zap.NewNop(), svc, svc, svc))
+	defer server.Close()
+	client := &GroupService{Addr: server.URL}
+
+	bucketID := platformtesting.MustIDBase16("020f755c3c082000")
+	if err := client.AddResource(ctx, g.ID, platform.BucketResourceType, bucketID, platform.Owner); err != nil {
+		t.Fatalf("failed to add resource: %v", err)
+	}
+
+	ms, err := client.FindResources(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("failed to find resources: %v", err)
+	}
+	if len(ms) != 1 || ms[0].ResourceID != bucketID || ms[0].UserType != platform.Owner || ms[0].Principal() != platform.GroupPrincipal {
+		t.Fatalf("unexpected resources %+v", ms)
+	}
+
+	if err := client.AddResource(ctx, g.ID, platform.GroupResourceType, g.ID, platform.Member); err == nil {
+		t.Fatal("expected mapping a group to a group to fail")
+	}
+
+	if err := client.RemoveResource(ctx, g.ID, bucketID); err != nil {
+		t.Fatalf("failed to remove resource: %v", err)
+	}
+	ms, err = client.FindResources(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("failed to find resources: %v", err)
+	}
+	if len(ms) != 0 {
+		t.Fatalf("expected no resources, got %+v", ms)
+	}
+}
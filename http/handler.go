@@ -109,7 +109,16 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			ext.RPCServerOption(wireContext),
 		)
 		serverSpan.LogFields(log.String("handler", h.name))
-		defer serverSpan.Finish()
+		ext.HTTPMethod.Set(serverSpan, r.Method)
+		ext.HTTPUrl.Set(serverSpan, r.URL.Path)
+		defer func() {
+			code := statusW.code()
+			ext.HTTPStatusCode.Set(serverSpan, uint16(code))
+			if code >= http.StatusInternalServerError {
+				ext.Error.Set(serverSpan, true)
+			}
+			serverSpan.Finish()
+		}()
 
 		r = r.WithContext(opentracing.ContextWithSpan(r.Context(), serverSpan))
 	}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform/kit/tracing"
+)
+
+func TestHandler_Tracing(t *testing.T) {
+	var spans bytes.Buffer
+	tracer := tracing.NewTracer(tracing.NewFileExporter(&spans))
+
+	var traceparent string
+	h := NewHandler("test")
+	h.Tracer = tracer
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the requests made while handling a request carry on its trace.
+		req := httptest.NewRequest("GET", "http://localhost/other", nil).WithContext(r.Context())
+		InjectTrace(req)
+		traceparent = req.Header.Get("traceparent")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	r := httptest.NewRequest("GET", "http://localhost/api/v2/buckets", nil)
+	r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if err := tracer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var span tracing.SpanData
+	s := bufio.NewScanner(&spans)
+	if !s.Scan() {
+		t.Fatal("expected the request to be traced")
+	}
+	if err := json.Unmarshal(s.Bytes(), &span); err != nil {
+		t.Fatal(err)
+	}
+	if span.TraceID.String() != "0af7651916cd43dd8448eb211c80319c" || span.ParentID.String() != "b7ad6b7169203331" {
+		t.Errorf("expected the span to continue the trace of the request, got %+v", span)
+	}
+	if span.OperationName != "test:/api/v2/buckets" || span.Tags["http.method"] != "GET" || span.Tags["error"] != true {
+		t.Errorf("unexpected span %+v", span)
+	}
+	if want := "00-0af7651916cd43dd8448eb211c80319c-" + span.SpanID.String() + "-01"; traceparent != want {
+		t.Errorf("expected the trace to be injected as %q, got %q", want, traceparent)
+	}
+
+	// a request without a trace starts one.
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost/api/v2/buckets", nil))
+	if strings.HasPrefix(traceparent, "00-0af7651916cd43dd8448eb211c80319c-") {
+		t.Errorf("expected a new trace, got %q", traceparent)
+	}
+}
@@ -28,12 +28,17 @@ type OrgHandler struct {
 	SecretService                   platform.SecretService
 	LabelService                    platform.LabelService
 	UserService                     platform.UserService
+	// OrganizationDeletionService deletes the organizations with their
+	// resources; they are deleted by the OrganizationService if nil.
+	OrganizationDeletionService platform.OrganizationDeletionService
 }
 
 const (
 	organizationsPath            = "/api/v2/orgs"
 	organizationsIDPath          = "/api/v2/orgs/:id"
 	organizationsIDLogPath       = "/api/v2/orgs/:id/log"
+	organizationsIDDeletionPath  = "/api/v2/orgs/:id/deletion"
+	organizationsIDRestorePath   = "/api/v2/orgs/:id/restore"
 	organizationsIDMembersPath   = "/api/v2/orgs/:id/members"
 	organizationsIDMembersIDPath = "/api/v2/orgs/:id/members/:userID"
 	organizationsIDOwnersPath    = "/api/v2/orgs/:id/owners"
@@ -61,6 +66,8 @@ func NewOrgHandler(mappingService platform.UserResourceMappingService,
 	h.HandlerFunc("GET", organizationsIDLogPath, h.handleGetOrgLog)
 	h.HandlerFunc("PATCH", organizationsIDPath, h.handlePatchOrg)
 	h.HandlerFunc("DELETE", organizationsIDPath, h.handleDeleteOrg)
+	h.HandlerFunc("GET", organizationsIDDeletionPath, h.handleGetOrgDeletion)
+	h.HandlerFunc("POST", organizationsIDRestorePath, h.handlePostOrgRestore)
 
 	h.HandlerFunc("POST", organizationsIDMembersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.OrgResourceType, platform.Member))
 	h.HandlerFunc("GET", organizationsIDMembersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.OrgResourceType, platform.Member))
@@ -240,14 +247,23 @@ func (h *OrgHandler) handleGetOrgs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newOrgsResponse(orgs)); err != nil {
+	// the deleted organizations are only listed on request, to be restored.
+	var found []*platform.Organization
+	for _, o := range orgs {
+		if (o.DeletedAt != nil) == req.deleted {
+			found = append(found, o)
+		}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgsResponse(found)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
 
 type getOrgsRequest struct {
-	filter platform.OrganizationFilter
+	filter  platform.OrganizationFilter
+	deleted bool
 }
 
 func decodeGetOrgsRequest(ctx context.Context, r *http.Request) (*getOrgsRequest, error) {
@@ -266,6 +282,16 @@ func decodeGetOrgsRequest(ctx context.Context, r *http.Request) (*getOrgsRequest
 		req.filter.Name = &name
 	}
 
+	if deleted := qp.Get("deleted"); deleted != "" {
+		var err error
+		if req.deleted, err = strconv.ParseBool(deleted); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "deleted must be true or false",
+			}
+		}
+	}
+
 	return req, nil
 }
 
@@ -279,7 +305,15 @@ func (h *OrgHandler) handleDeleteOrg(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.OrganizationService.DeleteOrganization(ctx, req.OrganizationID); err != nil {
+	switch {
+	case h.OrganizationDeletionService == nil:
+		err = h.OrganizationService.DeleteOrganization(ctx, req.OrganizationID)
+	case req.Purge:
+		_, err = h.OrganizationDeletionService.PurgeOrganization(ctx, req.OrganizationID)
+	default:
+		_, err = h.OrganizationDeletionService.ScheduleOrganizationDeletion(ctx, req.OrganizationID)
+	}
+	if err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
@@ -289,6 +323,9 @@ func (h *OrgHandler) handleDeleteOrg(w http.ResponseWriter, r *http.Request) {
 
 type deleteOrganizationRequest struct {
 	OrganizationID platform.ID
+	// Purge purges the organization without waiting for the grace period
+	// of deletions.
+	Purge bool
 }
 
 func decodeDeleteOrganizationRequest(ctx context.Context, r *http.Request) (*deleteOrganizationRequest, error) {
@@ -306,9 +343,78 @@ func decodeDeleteOrganizationRequest(ctx context.Context, r *http.Request) (*del
 		OrganizationID: i,
 	}
 
+	if purge := r.URL.Query().Get("purge"); purge != "" {
+		var err error
+		if req.Purge, err = strconv.ParseBool(purge); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "purge must be true or false",
+			}
+		}
+	}
+
 	return req, nil
 }
 
+// handleGetOrgDeletion is the HTTP handler for the GET /api/v2/orgs/:id/deletion route.
+func (h *OrgHandler) handleGetOrgDeletion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeOrgDeletionRequest(ctx, h.OrganizationDeletionService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	d, err := h.OrganizationDeletionService.FindOrganizationDeletion(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, d); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostOrgRestore is the HTTP handler for the POST /api/v2/orgs/:id/restore route.
+func (h *OrgHandler) handlePostOrgRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeOrgDeletionRequest(ctx, h.OrganizationDeletionService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationDeletionService.RestoreOrganization(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgResponse(o)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeOrgDeletionRequest(ctx context.Context, s platform.OrganizationDeletionService) (platform.ID, error) {
+	if s == nil {
+		return 0, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "organizations are deleted right away",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("id")); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
 // handlePatchOrg is the HTTP handler for the PATH /api/v2/orgs route.
 func (h *OrgHandler) handlePatchOrg(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -687,6 +793,114 @@ func (s *OrganizationService) DeleteOrganization(ctx context.Context, id platfor
 	return CheckErrorStatus(http.StatusNoContent, resp, true)
 }
 
+var _ platform.OrganizationDeletionService = (*OrganizationService)(nil)
+
+// ScheduleOrganizationDeletion deletes an organization, which can be
+// restored until the grace period of deletions has passed.
+func (s *OrganizationService) ScheduleOrganizationDeletion(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	if err := s.deleteOrganization(ctx, id, false); err != nil {
+		return nil, err
+	}
+	return s.FindOrganizationDeletion(ctx, id)
+}
+
+// PurgeOrganization purges an organization and its resources without
+// waiting for the grace period.
+func (s *OrganizationService) PurgeOrganization(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	if err := s.deleteOrganization(ctx, id, true); err != nil {
+		return nil, err
+	}
+	return s.FindOrganizationDeletion(ctx, id)
+}
+
+func (s *OrganizationService) deleteOrganization(ctx context.Context, id platform.ID, purge bool) error {
+	u, err := newURL(s.Addr, organizationIDPath(id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	query := req.URL.Query()
+	query.Set("purge", strconv.FormatBool(purge))
+	req.URL.RawQuery = query.Encode()
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return CheckErrorStatus(http.StatusNoContent, resp, true)
+}
+
+// RestoreOrganization restores a deleted organization during the grace
+// period of its deletion.
+func (s *OrganizationService) RestoreOrganization(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+	u, err := newURL(s.Addr, path.Join(organizationIDPath(id), "restore"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var o orgResponse
+	if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+		return nil, err
+	}
+	return &o.Organization, nil
+}
+
+// FindOrganizationDeletion returns the progress of the deletion of an
+// organization.
+func (s *OrganizationService) FindOrganizationDeletion(ctx context.Context, id platform.ID) (*platform.OrganizationDeletion, error) {
+	u, err := newURL(s.Addr, path.Join(organizationIDPath(id), "deletion"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var d platform.OrganizationDeletion
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
 func organizationIDPath(id platform.ID) string {
 	return path.Join(organizationPath, id.String())
 }
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/cascade"
 	"github.com/influxdata/platform/inmem"
 	"github.com/influxdata/platform/mock"
 	platformtesting "github.com/influxdata/platform/testing"
@@ -301,3 +303,52 @@ func TestSecretService_handleDeleteSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestOrganizationService_Deletion(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	h := NewOrgHandler(svc, svc)
+	h.OrganizationService = svc
+	h.OrganizationDeletionService = &cascade.Service{
+		OrganizationService:        svc,
+		BucketService:              svc,
+		UserService:                svc,
+		UserResourceMappingService: svc,
+		GracePeriod:                time.Hour,
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := &OrganizationService{Addr: server.URL}
+
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := client.ScheduleOrganizationDeletion(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("unexpected error deleting org: %v", err)
+	}
+	if d.Status != platform.OrganizationDeletionPending || d.OrgName != "acme" {
+		t.Fatalf("unexpected deletion %+v", d)
+	}
+	if orgs, _, err := client.FindOrganizations(ctx, platform.OrganizationFilter{}); err != nil || len(orgs) != 0 {
+		t.Fatalf("expected the deleted org not to be listed: %v %v", orgs, err)
+	}
+
+	restored, err := client.RestoreOrganization(ctx, o.ID)
+	if err != nil || restored.DeletedAt != nil {
+		t.Fatalf("unexpected restore %v %v", restored, err)
+	}
+	if _, err := client.RestoreOrganization(ctx, o.ID); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict restoring an org that is not deleted, got %v", err)
+	}
+
+	if d, err = client.PurgeOrganization(ctx, o.ID); err != nil || d.Status != platform.OrganizationDeletionComplete {
+		t.Fatalf("unexpected purge %+v %v", d, err)
+	}
+	if _, err := svc.FindOrganizationByID(ctx, o.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the org to be purged, got %v", err)
+	}
+}
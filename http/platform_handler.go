@@ -25,12 +25,18 @@ func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
 func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h := NewAuthenticationHandler()
 	h.Handler = NewAPIHandler(b)
+	if b.AuditLogService != nil {
+		h.Handler = NewAuditingHandler(h.Handler)
+	}
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
+	h.AuthorizationUsageService = b.AuthorizationUsageService
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
+	h.RegisterNoAuthRoute("GET", oauthSigninPath)
+	h.RegisterNoAuthRoute("GET", oauthCallbackPath)
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -19,6 +21,7 @@ import (
 	pcontext "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/kit/errors"
 	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/query/cache"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -76,6 +79,14 @@ func (h *FluxHandler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 		EncodeError(ctx, fmt.Errorf("unsupported dialect over HTTP %T", req.Dialect), w)
 		return
 	}
+
+	control, err := decodeCacheControl(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	ctx = cache.ContextWithControl(ctx, control)
+
 	hd.SetHeaders(w)
 
 	n, err := h.ProxyQueryService.Query(ctx, w, req)
@@ -92,6 +103,33 @@ func (h *FluxHandler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// decodeCacheControl returns how the query of a request uses the results
+// cache: no-cache bypasses it, and max-age sets how long the result is
+// cached.
+func decodeCacheControl(r *http.Request) (cache.Control, error) {
+	var c cache.Control
+	for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			c.Bypass = true
+		case strings.HasPrefix(directive, "max-age="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || secs < 0 {
+				return c, &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  fmt.Sprintf("invalid cache control %q", directive),
+				}
+			}
+			if secs == 0 {
+				c.Bypass = true
+			}
+			c.TTL = time.Duration(secs) * time.Second
+		}
+	}
+	return c, nil
+}
+
 type langRequest struct {
 	Query string `json:"query"`
 }
@@ -269,6 +307,9 @@ func (s *FluxService) Query(ctx context.Context, w io.Writer, r *query.ProxyRequ
 	if err != nil {
 		return 0, err
 	}
+	params := url.Values{}
+	params.Set(OrgID, r.Request.OrganizationID.String())
+	u.RawQuery = params.Encode()
 
 	qreq, err := QueryRequestFromProxyRequest(r)
 	if err != nil {
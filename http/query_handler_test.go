@@ -15,6 +15,7 @@ import (
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/query/cache"
 )
 
 func TestFluxService_Query(t *testing.T) {
@@ -34,6 +35,7 @@ func TestFluxService_Query(t *testing.T) {
 			token: "mytoken",
 			r: &query.ProxyRequest{
 				Request: query.Request{
+					OrganizationID: platform.ID(1),
 					Compiler: lang.FluxCompiler{
 						Query: "from()",
 					},
@@ -63,6 +65,9 @@ func TestFluxService_Query(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Query().Get(OrgID), tt.r.Request.OrganizationID.String(); got != want {
+					t.Errorf("FluxService.Query() orgID = %v, want %v", got, want)
+				}
 				w.WriteHeader(tt.status)
 				fmt.Fprintln(w, "howdy")
 			}))
@@ -291,3 +296,30 @@ var crlfPattern = regexp.MustCompile(`\r?\n`)
 func toCRLF(data string) string {
 	return crlfPattern.ReplaceAllString(data, "\r\n")
 }
+
+func Test_decodeCacheControl(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    cache.Control
+		wantErr bool
+	}{
+		{header: "", want: cache.Control{}},
+		{header: "no-cache", want: cache.Control{Bypass: true}},
+		{header: "public, max-age=30", want: cache.Control{TTL: 30 * time.Second}},
+		{header: "max-age=0", want: cache.Control{Bypass: true}},
+		{header: "max-age=soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := httptest.NewRequest("POST", fluxPath, nil)
+			r.Header.Set("Cache-Control", tt.header)
+			got, err := decodeCacheControl(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCacheControl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("decodeCacheControl() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of requests of each client.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*clientLimiter
+	pruned   time.Time
+}
+
+type clientLimiter struct {
+	*rate.Limiter
+	seen time.Time
+}
+
+// NewRateLimiter returns a limiter allowing each client limit requests per
+// second, with bursts of up to burst requests.
+func NewRateLimiter(limit rate.Limit, burst int) *RateLimiter {
+	return &RateLimiter{
+		limit:    limit,
+		burst:    burst,
+		limiters: map[string]*clientLimiter{},
+	}
+}
+
+// Allow returns true if the client of r may make a request now.
+func (l *RateLimiter) Allow(r *http.Request) bool {
+	key, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		key = r.RemoteAddr
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	cl, ok := l.limiters[key]
+	if !ok {
+		cl = &clientLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = cl
+	}
+	cl.seen = now
+	return cl.AllowN(now, 1)
+}
+
+// prune forgets the clients that have been idle long enough for their
+// limiter to be full again.
+func (l *RateLimiter) prune(now time.Time) {
+	refill := time.Minute
+	if l.limit > 0 && l.limit != rate.Inf {
+		refill = time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	}
+	if now.Sub(l.pruned) < refill {
+		return
+	}
+	for key, cl := range l.limiters {
+		if now.Sub(cl.seen) >= refill {
+			delete(l.limiters, key)
+		}
+	}
+	l.pruned = now
+}
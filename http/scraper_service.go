@@ -16,12 +16,13 @@ import (
 // ScraperHandler represents an HTTP API handler for scraper targets.
 type ScraperHandler struct {
 	*httprouter.Router
-	Logger                *zap.Logger
-	ScraperStorageService platform.ScraperTargetStoreService
+	Logger                     *zap.Logger
+	ScraperStorageService      platform.ScraperTargetStoreService
+	ScraperTargetStatusService platform.ScraperTargetStatusService
 }
 
 const (
-	targetPath = "/api/v2/scrapertargets"
+	targetPath = "/api/v2/scrapers"
 )
 
 // NewScraperHandler returns a new instance of ScraperHandler.
@@ -38,7 +39,7 @@ func NewScraperHandler() *ScraperHandler {
 	return h
 }
 
-// handlePostScraperTarget is HTTP handler for the POST /api/v2/scrapertargets route.
+// handlePostScraperTarget is HTTP handler for the POST /api/v2/scrapers route.
 func (h *ScraperHandler) handlePostScraperTarget(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -58,7 +59,7 @@ func (h *ScraperHandler) handlePostScraperTarget(w http.ResponseWriter, r *http.
 	}
 }
 
-// handleDeleteScraperTarget is the HTTP handler for the DELETE /api/v2/scrapertargets/:id route.
+// handleDeleteScraperTarget is the HTTP handler for the DELETE /api/v2/scrapers/:id route.
 func (h *ScraperHandler) handleDeleteScraperTarget(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -76,7 +77,7 @@ func (h *ScraperHandler) handleDeleteScraperTarget(w http.ResponseWriter, r *htt
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// handlePatchScraperTarget is the HTTP handler for the PATCH /api/v2/scrapertargets/:id route.
+// handlePatchScraperTarget is the HTTP handler for the PATCH /api/v2/scrapers/:id route.
 func (h *ScraperHandler) handlePatchScraperTarget(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -112,13 +113,15 @@ func (h *ScraperHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newTargetResponse(*target)); err != nil {
+	resp := newTargetResponse(*target)
+	resp.Status = h.targetStatus(ctx, target.ID)
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
 
-// handleGetScraperTargets is the HTTP handler for the GET /api/v2/scrapertargets route.
+// handleGetScraperTargets is the HTTP handler for the GET /api/v2/scrapers route.
 func (h *ScraperHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -128,12 +131,29 @@ func (h *ScraperHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newListTargetsResponse(targets)); err != nil {
+	resp := newListTargetsResponse(targets)
+	for i := range resp.Targets {
+		resp.Targets[i].Status = h.targetStatus(ctx, resp.Targets[i].ID)
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
 
+// targetStatus returns the last scrape status of a target, or nil when it is unavailable.
+func (h *ScraperHandler) targetStatus(ctx context.Context, id platform.ID) *platform.ScraperTargetStatus {
+	if h.ScraperTargetStatusService == nil {
+		return nil
+	}
+	status, err := h.ScraperTargetStatusService.GetTargetStatus(ctx, id)
+	if err != nil {
+		h.Logger.Info("unable to retrieve scraper target status", zap.String("id", id.String()), zap.Error(err))
+		return nil
+	}
+	return status
+}
+
 func decodeScraperTargetUpdateRequest(ctx context.Context, r *http.Request) (
 	*platform.ScraperTarget, error) {
 	update := &platform.ScraperTarget{}
@@ -358,6 +378,43 @@ func (s *ScraperService) GetTargetByID(ctx context.Context, id platform.ID) (*pl
 	return &targetResp.ScraperTarget, nil
 }
 
+// GetTargetStatus returns the status of the last scrape of a target.
+func (s *ScraperService) GetTargetStatus(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error) {
+	url, err := newURL(s.Addr, targetIDPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(url.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var targetResp targetResponse
+	if err := json.NewDecoder(resp.Body).Decode(&targetResp); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if targetResp.Status == nil {
+		return &platform.ScraperTargetStatus{
+			Health: platform.ScraperTargetHealthUnknown,
+		}, nil
+	}
+	return targetResp.Status, nil
+}
+
 func targetIDPath(id platform.ID) string {
 	return path.Join(targetPath, id.String())
 }
@@ -377,7 +434,8 @@ type targetLinks struct {
 
 type targetResponse struct {
 	platform.ScraperTarget
-	Links targetLinks `json:"links"`
+	Status *platform.ScraperTargetStatus `json:"status,omitempty"`
+	Links  targetLinks                   `json:"links"`
 }
 
 func newListTargetsResponse(targets []platform.ScraperTarget) getTargetsResponse {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/inmem"
 	platformtesting "github.com/influxdata/platform/testing"
@@ -37,3 +39,43 @@ func initScraperService(f platformtesting.TargetFields, t *testing.T) (platform.
 func TestScraperService(t *testing.T) {
 	platformtesting.ScraperService(initScraperService, t)
 }
+
+func TestScraperHandler_TargetStatus(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+	target := &platform.ScraperTarget{
+		ID:   platformtesting.MustIDBase16("020f755c3c082000"),
+		Name: "target1",
+		Type: platform.PrometheusScraperType,
+	}
+	if err := svc.PutTarget(ctx, target); err != nil {
+		t.Fatalf("failed to populate scraper targets: %v", err)
+	}
+	want := &platform.ScraperTargetStatus{
+		Health:             platform.ScraperTargetHealthDown,
+		LastScrape:         time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+		LastScrapeDuration: time.Second,
+		LastError:          "connection refused",
+	}
+	if err := svc.PutTargetStatus(ctx, target.ID, want); err != nil {
+		t.Fatalf("failed to populate scraper target status: %v", err)
+	}
+
+	handler := NewScraperHandler()
+	handler.ScraperStorageService = svc
+	handler.ScraperTargetStatusService = svc
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := ScraperService{
+		Addr:     server.URL,
+		OpPrefix: inmem.OpPrefix,
+	}
+	got, err := client.GetTargetStatus(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve scraper target status: %v", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("scraper target status is different -got/+want\ndiff %s", diff)
+	}
+}
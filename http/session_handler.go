@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf/oauth2"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -16,6 +17,20 @@ type SessionHandler struct {
 
 	BasicAuthService platform.BasicAuthService
 	SessionService   platform.SessionService
+	// SigninLimiter limits the rate of the signin attempts of each client,
+	// there is no limit if it is nil.
+	SigninLimiter *RateLimiter
+
+	// OAuthProviders are the external identity providers, keyed by name,
+	// that users can sign in with. OAuthStates signs the state of signins
+	// and OAuthTokens verifies the id tokens of the providers.
+	OAuthProviders             map[string]*OAuthProvider
+	OAuthStates                oauth2.Tokenizer
+	OAuthTokens                oauth2.Tokenizer
+	OAuthSuccessURL            string
+	OAuthFailureURL            string
+	UserService                platform.UserService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -26,6 +41,8 @@ func NewSessionHandler() *SessionHandler {
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
 	h.HandlerFunc("POST", "/api/v2/signout", h.handleSignout)
+	h.HandlerFunc("GET", oauthSigninPath, h.handleOAuthSignin)
+	h.HandlerFunc("GET", oauthCallbackPath, h.handleOAuthCallback)
 	return h
 }
 
@@ -33,6 +50,14 @@ func NewSessionHandler() *SessionHandler {
 func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if h.SigninLimiter != nil && !h.SigninLimiter.Allow(r) {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.ETooManyRequests,
+			Msg:  "too many signin attempts, try again later",
+		}, w)
+		return
+	}
+
 	req, err := decodeSigninRequest(ctx, r)
 	if err != nil {
 		h.Logger.Info("failed to decode request", zap.Error(err))
@@ -48,7 +73,7 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 
 	s, e := h.SessionService.CreateSession(ctx, req.Username)
 	if e != nil {
-		EncodeError(ctx, err, w)
+		EncodeError(ctx, e, w)
 		return
 	}
 
@@ -0,0 +1,597 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf"
+	"github.com/influxdata/platform/chronograf/oauth2"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+	xoauth2 "golang.org/x/oauth2"
+)
+
+const (
+	oauthSigninPath   = "/api/v2/signin/oauth/:provider"
+	oauthCallbackPath = "/api/v2/signin/oauth/:provider/callback"
+
+	// oauthNonceCookie binds the state of a signin to the browser that
+	// started it.
+	oauthNonceCookie = "oauth_nonce"
+)
+
+// OAuthProvider is an external identity provider, such as an OpenID Connect
+// issuer, that users can sign in with.
+type OAuthProvider struct {
+	oauth2.Provider
+
+	// UseIDToken reads the identity of the user from the OpenID id_token
+	// returned by the token exchange rather than from the provider's API.
+	// The provider must implement oauth2.ExtendedProvider.
+	UseIDToken bool
+	// ClientID and Issuer must be the audience and the issuer of id tokens.
+	ClientID string
+	Issuer   string
+	// GroupsClaim is the id_token claim that lists the groups of the user.
+	// If empty, the groups are the ones reported by the provider.
+	GroupsClaim string
+	// Mappings grant the user roles on organizations based on its groups.
+	Mappings []OAuthRoleMapping
+}
+
+// OAuthRoleMapping grants the users that belong to Group at the identity
+// provider the Role on an organization.
+type OAuthRoleMapping struct {
+	Group          string            `json:"group"`
+	OrganizationID platform.ID       `json:"orgID"`
+	Role           platform.UserType `json:"role"`
+}
+
+// OAuthConfig configures signin through external identity providers. It is
+// usually read from a YAML or JSON file.
+type OAuthConfig struct {
+	// Secret verifies HS256 id tokens, it is shared with the providers.
+	Secret string `json:"secret"`
+	// StateSecret signs the state of signins. It defaults to a random key,
+	// so it must be set if the signins of a user may be handled by several
+	// instances.
+	StateSecret string `json:"stateSecret"`
+	// JWKSURL is where the keys verifying RS256 id tokens are published.
+	JWKSURL string `json:"jwksURL"`
+	// SuccessURL and FailureURL are where users are redirected once the
+	// signin completes; without them the callback responds directly.
+	SuccessURL string `json:"successURL"`
+	FailureURL string `json:"failureURL"`
+
+	Providers []OAuthProviderConfig `json:"providers"`
+}
+
+// OAuthProviderConfig configures an identity provider.
+type OAuthProviderConfig struct {
+	// Type is either generic, for any OpenID Connect or OAuth2 provider, or github.
+	Type         string   `json:"type"`
+	Name         string   `json:"name"`
+	ClientID     string   `json:"clientID"`
+	ClientSecret string   `json:"clientSecret"`
+	Scopes       []string `json:"scopes"`
+	RedirectURL  string   `json:"redirectURL"`
+	AuthURL      string   `json:"authURL"`
+	TokenURL     string   `json:"tokenURL"`
+	// UserInfoURL and UserClaim locate the name of the user, usually its
+	// email, in the userinfo response or the id token.
+	UserInfoURL string `json:"userInfoURL"`
+	UserClaim   string `json:"userClaim"`
+	// Domains restricts signin to users with an email in one of the domains.
+	Domains []string `json:"domains"`
+	// Orgs restricts signin to members of one of the github organizations.
+	Orgs []string `json:"orgs"`
+
+	UseIDToken  bool               `json:"useIDToken"`
+	GroupsClaim string             `json:"groupsClaim"`
+	Mappings    []OAuthRoleMapping `json:"mappings"`
+	// Issuer is the iss claim of the id tokens, required with UseIDToken.
+	Issuer string `json:"issuer"`
+}
+
+// LoadOAuthConfig reads the identity provider configuration at path.
+func LoadOAuthConfig(path string) (*OAuthConfig, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := new(OAuthConfig)
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("invalid oauth config %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// OAuthProviders returns the configured identity providers keyed by name.
+func (c *OAuthConfig) OAuthProviders() (map[string]*OAuthProvider, error) {
+	ps := make(map[string]*OAuthProvider, len(c.Providers))
+	for _, pc := range c.Providers {
+		var p oauth2.Provider
+		switch pc.Type {
+		case "", "generic":
+			userClaim := pc.UserClaim
+			if userClaim == "" {
+				userClaim = "email"
+			}
+			p = &oauth2.Generic{
+				PageName:       pc.Name,
+				ClientID:       pc.ClientID,
+				ClientSecret:   pc.ClientSecret,
+				RequiredScopes: pc.Scopes,
+				Domains:        pc.Domains,
+				RedirectURL:    pc.RedirectURL,
+				AuthURL:        pc.AuthURL,
+				TokenURL:       pc.TokenURL,
+				APIURL:         pc.UserInfoURL,
+				APIKey:         userClaim,
+				Logger:         &chronograf.NoopLogger{},
+			}
+		case "github":
+			p = &oauth2.Github{
+				ClientID:     pc.ClientID,
+				ClientSecret: pc.ClientSecret,
+				Orgs:         pc.Orgs,
+				Logger:       &chronograf.NoopLogger{},
+			}
+		default:
+			return nil, fmt.Errorf("unknown oauth provider type %q", pc.Type)
+		}
+
+		if _, ok := ps[p.Name()]; ok {
+			return nil, fmt.Errorf("duplicate oauth provider %q", p.Name())
+		}
+		if pc.UseIDToken && pc.Issuer == "" {
+			return nil, fmt.Errorf("oauth provider %q uses id tokens but has no issuer", p.Name())
+		}
+		ps[p.Name()] = &OAuthProvider{
+			Provider:    p,
+			UseIDToken:  pc.UseIDToken,
+			ClientID:    pc.ClientID,
+			Issuer:      pc.Issuer,
+			GroupsClaim: pc.GroupsClaim,
+			Mappings:    pc.Mappings,
+		}
+	}
+	return ps, nil
+}
+
+// configure enables signin through the identity providers of c on h.
+func (c *OAuthConfig) configure(h *SessionHandler) error {
+	ps, err := c.OAuthProviders()
+	if err != nil {
+		return err
+	}
+	if c.Secret != "" && c.Secret == c.StateSecret {
+		return fmt.Errorf("the oauth state secret must differ from the id token secret")
+	}
+	secret, err := c.secret(c.Secret)
+	if err != nil {
+		return err
+	}
+	stateSecret, err := c.secret(c.StateSecret)
+	if err != nil {
+		return err
+	}
+	h.OAuthProviders = ps
+	h.OAuthStates = oauth2.NewJWT(stateSecret, "")
+	h.OAuthTokens = oauth2.NewJWT(secret, c.JWKSURL)
+	h.OAuthSuccessURL = c.SuccessURL
+	h.OAuthFailureURL = c.FailureURL
+	return nil
+}
+
+// secret returns s, or a random secret if s is empty.
+func (c *OAuthConfig) secret(s string) (string, error) {
+	if s != "" {
+		return s, nil
+	}
+	s, err := randomState()
+	if err != nil {
+		return "", fmt.Errorf("unable to generate the oauth secret: %v", err)
+	}
+	return s, nil
+}
+
+// oauthIdentity is the identity of a user as asserted by a provider.
+type oauthIdentity struct {
+	Name   string
+	Groups []string
+	// Email is set if the provider verified that it belongs to the user.
+	Email string
+}
+
+// handleOAuthSignin is the HTTP handler for the GET /api/v2/signin/oauth/:provider route.
+// It redirects the user to the provider with a signed state to prevent CSRF.
+// The state holds a nonce that is also set as a cookie, so that the signin
+// only completes in the browser that started it.
+func (h *SessionHandler) handleOAuthSignin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := h.findOAuthProvider(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	nonce, err := randomState()
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleOAuthSignin",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	now := oauth2.DefaultNowTime()
+	state, err := h.OAuthStates.Create(ctx, oauth2.Principal{
+		Subject:   nonce,
+		Issuer:    p.Name(),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(oauth2.TenMinutes),
+	})
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleOAuthSignin",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthNonceCookie,
+		Value:    nonce,
+		Path:     "/api/v2/signin/oauth",
+		MaxAge:   int(oauth2.TenMinutes.Seconds()),
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, p.Config().AuthCodeURL(string(state), xoauth2.AccessTypeOnline), http.StatusTemporaryRedirect)
+}
+
+// handleOAuthCallback is the HTTP handler for the GET /api/v2/signin/oauth/:provider/callback route.
+// It is called by the provider once the user has signed in; the user is created
+// or linked, granted the roles its groups map to and issued a session.
+func (h *SessionHandler) handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := h.findOAuthProvider(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	// the nonce is only good for a single signin.
+	http.SetCookie(w, &http.Cookie{
+		Name:   oauthNonceCookie,
+		Path:   "/api/v2/signin/oauth",
+		MaxAge: -1,
+	})
+
+	s, err := h.oauthSignin(ctx, p, r)
+	if err != nil {
+		h.Logger.Info("oauth signin failed", zap.String("provider", p.Name()), zap.Error(err))
+		if h.OAuthFailureURL != "" {
+			http.Redirect(w, r, h.OAuthFailureURL, http.StatusTemporaryRedirect)
+			return
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	encodeCookieSession(w, s)
+	if h.OAuthSuccessURL != "" {
+		http.Redirect(w, r, h.OAuthSuccessURL, http.StatusTemporaryRedirect)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SessionHandler) findOAuthProvider(ctx context.Context) (*OAuthProvider, error) {
+	name := httprouter.ParamsFromContext(ctx).ByName("provider")
+	if p, ok := h.OAuthProviders[name]; ok {
+		return p, nil
+	}
+	return nil, &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  fmt.Sprintf("oauth provider %q not found", name),
+	}
+}
+
+func (h *SessionHandler) oauthSignin(ctx context.Context, p *OAuthProvider, r *http.Request) (*platform.Session, error) {
+	op := "http/oauthSignin"
+
+	state := r.FormValue("state")
+	principal, err := h.OAuthStates.ValidPrincipal(ctx, oauth2.Token(state), oauth2.TenMinutes)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Op:   op,
+			Msg:  "invalid oauth state",
+			Err:  err,
+		}
+	}
+	// the state must have been issued for a signin with this provider, in
+	// this browser.
+	nonce, err := r.Cookie(oauthNonceCookie)
+	if err != nil || principal.Issuer != p.Name() || subtle.ConstantTimeCompare([]byte(nonce.Value), []byte(principal.Subject)) != 1 {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Op:   op,
+			Msg:  "invalid oauth state",
+		}
+	}
+
+	token, err := p.Config().Exchange(ctx, r.FormValue("code"))
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Op:   op,
+			Msg:  "unable to exchange code for token",
+			Err:  err,
+		}
+	}
+
+	id, err := h.oauthIdentity(ctx, p, token)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Op:   op,
+			Msg:  "unable to identify user",
+			Err:  err,
+		}
+	}
+
+	u, err := h.findOrCreateOAuthUser(ctx, p, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.mapOAuthGroups(ctx, p, u, id.Groups); err != nil {
+		return nil, err
+	}
+
+	return h.SessionService.CreateSession(ctx, u.Name)
+}
+
+func (h *SessionHandler) oauthIdentity(ctx context.Context, p *OAuthProvider, token *xoauth2.Token) (*oauthIdentity, error) {
+	raw, _ := token.Extra("id_token").(string)
+	if !p.UseIDToken || raw == "" {
+		client := p.Config().Client(ctx, token)
+		name, err := p.PrincipalID(client)
+		if err != nil {
+			return nil, err
+		}
+		group, err := p.Group(client)
+		if err != nil {
+			return nil, err
+		}
+		return &oauthIdentity{Name: name, Groups: splitOAuthGroups(group)}, nil
+	}
+
+	ep, ok := p.Provider.(oauth2.ExtendedProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support id tokens", p.Name())
+	}
+
+	// GetClaims only verifies the signature and the expiration of the token.
+	claims, err := h.OAuthTokens.GetClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !claimHas(claims["aud"], p.ClientID) {
+		return nil, fmt.Errorf("id token is not issued for client %q", p.ClientID)
+	}
+	if !claims.VerifyIssuer(p.Issuer, true) {
+		return nil, fmt.Errorf("id token is not issued by %q", p.Issuer)
+	}
+
+	name, err := ep.PrincipalIDFromClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	id := &oauthIdentity{Name: name}
+	if verified, _ := claims["email_verified"].(bool); verified {
+		id.Email, _ = claims["email"].(string)
+	}
+
+	if p.GroupsClaim != "" {
+		id.Groups = claimGroups(claims[p.GroupsClaim])
+		return id, nil
+	}
+
+	group, err := ep.GroupFromClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	id.Groups = splitOAuthGroups(group)
+	return id, nil
+}
+
+// findOrCreateOAuthUser returns the user linked to the identity, creating
+// the user on its first signin. An existing user of the same name is only
+// linked if the provider verified that the email of the user belongs to the
+// identity, otherwise anyone able to pick a name at a provider could take
+// over the user.
+func (h *SessionHandler) findOrCreateOAuthUser(ctx context.Context, p *OAuthProvider, id *oauthIdentity) (*platform.User, error) {
+	oauthID := p.Name() + ":" + id.Name
+
+	u, err := h.UserService.FindUser(ctx, platform.UserFilter{OAuthID: &oauthID})
+	if err == nil {
+		return u, nil
+	}
+	if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, err
+	}
+
+	u, err = h.UserService.FindUser(ctx, platform.UserFilter{Name: &id.Name})
+	switch {
+	case err == nil:
+		if u.OAuthID != "" || id.Email == "" || !strings.EqualFold(u.Email, id.Email) {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Op:   "http/findOrCreateOAuthUser",
+				Msg:  fmt.Sprintf("user %q already exists and isn't linked to the identity", id.Name),
+			}
+		}
+		return h.UserService.UpdateUser(ctx, u.ID, platform.UserUpdate{OAuthID: &oauthID})
+	case platform.ErrorCode(err) != platform.ENotFound:
+		return nil, err
+	}
+
+	u = &platform.User{Name: id.Name, Email: id.Email, OAuthID: oauthID}
+	if err := h.UserService.CreateUser(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// mapOAuthGroups grants the user the roles that its groups are mapped to,
+// skipping the organizations it already has a role on. The role a mapping
+// granted is revoked once the provider no longer reports the user in its
+// group, unless another group of the user is mapped to the organization.
+func (h *SessionHandler) mapOAuthGroups(ctx context.Context, p *OAuthProvider, u *platform.User, groups []string) error {
+	ms, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceType: platform.OrgResourceType,
+		UserID:       u.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	mapped := map[platform.ID]bool{}
+	for _, m := range p.Mappings {
+		if containsString(groups, m.Group) {
+			mapped[m.OrganizationID] = true
+		}
+	}
+
+	for _, m := range p.Mappings {
+		role := m.Role
+		if role == "" {
+			role = platform.Member
+		}
+
+		if !mapped[m.OrganizationID] {
+			urm := findOrgMapping(ms, m.OrganizationID)
+			if urm == nil || urm.UserType != role {
+				continue
+			}
+			if err := h.UserResourceMappingService.DeleteUserResourceMapping(ctx, m.OrganizationID, u.ID); err != nil {
+				return err
+			}
+			ms = removeOrgMapping(ms, m.OrganizationID)
+			continue
+		}
+
+		if !containsString(groups, m.Group) || findOrgMapping(ms, m.OrganizationID) != nil {
+			continue
+		}
+
+		urm := &platform.UserResourceMapping{
+			ResourceType: platform.OrgResourceType,
+			ResourceID:   m.OrganizationID,
+			UserID:       u.ID,
+			UserType:     role,
+		}
+		if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, urm); err != nil {
+			return err
+		}
+		ms = append(ms, urm)
+	}
+	return nil
+}
+
+// splitOAuthGroups splits the comma delimited groups returned by providers.
+func splitOAuthGroups(group string) []string {
+	var gs []string
+	for _, g := range strings.Split(group, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			gs = append(gs, g)
+		}
+	}
+	return gs
+}
+
+// claimGroups reads groups from an id_token claim, which is either a list
+// or a comma delimited string.
+func claimGroups(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return splitOAuthGroups(v)
+	case []interface{}:
+		gs := make([]string, 0, len(v))
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				gs = append(gs, s)
+			}
+		}
+		return gs
+	}
+	return nil
+}
+
+func findOrgMapping(ms []*platform.UserResourceMapping, orgID platform.ID) *platform.UserResourceMapping {
+	for _, m := range ms {
+		if m.ResourceID == orgID {
+			return m
+		}
+	}
+	return nil
+}
+
+func removeOrgMapping(ms []*platform.UserResourceMapping, orgID platform.ID) []*platform.UserResourceMapping {
+	kept := ms[:0]
+	for _, m := range ms {
+		if m.ResourceID != orgID {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// claimHas returns true if the claim v, a string or a list, contains s.
+func claimHas(v interface{}, s string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == s
+	case []interface{}:
+		for _, e := range v {
+			if e == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,453 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	gojwt "github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf"
+	"github.com/influxdata/platform/chronograf/oauth2"
+	platformhttp "github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"go.uber.org/zap"
+)
+
+const (
+	mockIdPSecret   = "idp-secret"
+	mockStateSecret = "state-secret"
+	mockIdPClientID = "platform"
+	mockIdPIssuer   = "https://idp.example.com"
+)
+
+// newMockIdP returns an identity provider that signs in every user as email,
+// in the given groups. The claims override the ones of the id tokens.
+func newMockIdP(t *testing.T, email string, groups []string, claims gojwt.MapClaims) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		c := gojwt.MapClaims{
+			"sub":            "1234",
+			"aud":            []string{mockIdPClientID},
+			"iss":            mockIdPIssuer,
+			"email":          email,
+			"email_verified": true,
+			"groups":         groups,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range claims {
+			c[k] = v
+		}
+		token := gojwt.NewWithClaims(gojwt.SigningMethodHS256, c)
+		idToken, err := token.SignedString([]byte(mockIdPSecret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "bearer",
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"email": email,
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func newOAuthSessionHandler(svc *inmem.Service, p *platformhttp.OAuthProvider) *platformhttp.SessionHandler {
+	h := platformhttp.NewSessionHandler()
+	h.Logger = zap.NewNop()
+	h.UserService = svc
+	h.UserResourceMappingService = svc
+	h.SessionService = &mock.SessionService{
+		CreateSessionFn: func(ctx context.Context, user string) (*platform.Session, error) {
+			return &platform.Session{Key: "session-" + user}, nil
+		},
+	}
+	h.OAuthStates = oauth2.NewJWT(mockStateSecret, "")
+	h.OAuthTokens = oauth2.NewJWT(mockIdPSecret, "")
+	h.OAuthProviders = map[string]*platformhttp.OAuthProvider{p.Name(): p}
+	return h
+}
+
+// oauthSignin goes through the signin redirect and the callback of h and
+// returns the response of the callback.
+func oauthSignin(t *testing.T, h http.Handler, provider string) *httptest.ResponseRecorder {
+	state, cookies := oauthState(t, h, provider)
+	return oauthCallback(h, provider, state, cookies)
+}
+
+// oauthState starts a signin with a provider and returns the state and the
+// cookies it sets.
+func oauthState(t *testing.T, h http.Handler, provider string) (string, []*http.Cookie) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oauth/"+provider, nil))
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("bad signin status code: got %d want %d", w.Code, http.StatusTemporaryRedirect)
+	}
+	loc, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := loc.Query().Get("state")
+	if state == "" {
+		t.Fatalf("missing state in redirect %s", loc)
+	}
+	return state, w.Result().Cookies()
+}
+
+func oauthCallback(h http.Handler, provider, state string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	q := url.Values{"code": {"abc"}, "state": {state}}
+	r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oauth/"+provider+"/callback?"+q.Encode(), nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func sessionCookie(w *httptest.ResponseRecorder) string {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "session" {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func TestSessionHandler_OAuthIDToken(t *testing.T) {
+	ctx := context.Background()
+	idp := newMockIdP(t, "alice@example.com", []string{"admins", "devs"}, nil)
+	defer idp.Close()
+
+	svc := inmem.NewService()
+	admins := &platform.Organization{Name: "admins"}
+	others := &platform.Organization{Name: "others"}
+	for _, o := range []*platform.Organization{admins, others} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := newOAuthSessionHandler(svc, &platformhttp.OAuthProvider{
+		Provider: &oauth2.Generic{
+			PageName: "mock",
+			AuthURL:  idp.URL + "/authorize",
+			TokenURL: idp.URL + "/token",
+			APIKey:   "email",
+			Logger:   &chronograf.NoopLogger{},
+		},
+		UseIDToken:  true,
+		ClientID:    mockIdPClientID,
+		Issuer:      mockIdPIssuer,
+		GroupsClaim: "groups",
+		Mappings: []platformhttp.OAuthRoleMapping{
+			{Group: "admins", OrganizationID: admins.ID, Role: platform.Owner},
+			{Group: "sales", OrganizationID: others.ID},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		w := oauthSignin(t, h, "mock")
+		if got, want := w.Code, http.StatusNoContent; got != want {
+			t.Fatalf("bad callback status code: got %d want %d: %s", got, want, w.Body.String())
+		}
+		if got, want := sessionCookie(w), "session-alice@example.com"; got != want {
+			t.Fatalf("bad session cookie: got %q want %q", got, want)
+		}
+	}
+
+	// signing in again links the existing user rather than creating another one.
+	users, _, err := svc.FindUsers(ctx, platform.UserFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "alice@example.com" {
+		t.Fatalf("unexpected users %+v", users)
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: users[0].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ResourceID != admins.ID || ms[0].UserType != platform.Owner {
+		t.Fatalf("unexpected mappings %+v", ms)
+	}
+}
+
+func TestSessionHandler_OAuthUserInfo(t *testing.T) {
+	ctx := context.Background()
+	idp := newMockIdP(t, "bob@corp.com", nil, nil)
+	defer idp.Close()
+
+	svc := inmem.NewService()
+	org := &platform.Organization{Name: "corp"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	// the userinfo of the provider doesn't verify emails, so the existing
+	// user must be linked explicitly.
+	existing := &platform.User{Name: "bob@corp.com", OAuthID: "mock:bob@corp.com"}
+	if err := svc.CreateUser(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	// the generic provider reports the email domain of the user as its group.
+	h := newOAuthSessionHandler(svc, &platformhttp.OAuthProvider{
+		Provider: &oauth2.Generic{
+			PageName: "mock",
+			AuthURL:  idp.URL + "/authorize",
+			TokenURL: idp.URL + "/token",
+			APIURL:   idp.URL + "/userinfo",
+			APIKey:   "email",
+			Logger:   &chronograf.NoopLogger{},
+		},
+		Mappings: []platformhttp.OAuthRoleMapping{
+			{Group: "corp.com", OrganizationID: org.ID},
+		},
+	})
+
+	w := oauthSignin(t, h, "mock")
+	if got, want := w.Code, http.StatusNoContent; got != want {
+		t.Fatalf("bad callback status code: got %d want %d: %s", got, want, w.Body.String())
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: existing.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ResourceID != org.ID || ms[0].UserType != platform.Member {
+		t.Fatalf("unexpected mappings %+v", ms)
+	}
+}
+
+func TestSessionHandler_OAuthInvalidState(t *testing.T) {
+	idp := newMockIdP(t, "eve@example.com", nil, nil)
+	defer idp.Close()
+
+	svc := inmem.NewService()
+	h := newOAuthSessionHandler(svc, &platformhttp.OAuthProvider{
+		Provider: &oauth2.Generic{
+			PageName: "mock",
+			AuthURL:  idp.URL + "/authorize",
+			TokenURL: idp.URL + "/token",
+			APIKey:   "email",
+			Logger:   &chronograf.NoopLogger{},
+		},
+		UseIDToken: true,
+		ClientID:   mockIdPClientID,
+		Issuer:     mockIdPIssuer,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oauth/mock/callback?code=abc&state=forged", nil))
+	if got, want := w.Code, http.StatusUnauthorized; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+
+	// a state issued for the signin with another provider is rejected.
+	h.OAuthProviders["other"] = mockIDTokenProvider(idp, "other")
+	state, cookies := oauthState(t, h, "other")
+	if got, want := oauthCallback(h, "mock", state, cookies).Code, http.StatusUnauthorized; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+
+	// a state is rejected in a browser that didn't start the signin.
+	state, _ = oauthState(t, h, "mock")
+	if got, want := oauthCallback(h, "mock", state, nil).Code, http.StatusUnauthorized; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+	_, cookies = oauthState(t, h, "mock")
+	if got, want := oauthCallback(h, "mock", state, cookies).Code, http.StatusUnauthorized; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+
+	// a state signed with the id token secret, known to the providers, is
+	// rejected.
+	now := time.Now()
+	forged, err := oauth2.NewJWT(mockIdPSecret, "").Create(context.Background(), oauth2.Principal{
+		Subject:   "nonce",
+		Issuer:    "mock",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies = []*http.Cookie{{Name: "oauth_nonce", Value: "nonce"}}
+	if got, want := oauthCallback(h, "mock", string(forged), cookies).Code, http.StatusUnauthorized; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oauth/unknown", nil))
+	if got, want := w.Code, http.StatusNotFound; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+}
+
+func mockIDTokenProvider(idp *httptest.Server, name string) *platformhttp.OAuthProvider {
+	return &platformhttp.OAuthProvider{
+		Provider: &oauth2.Generic{
+			PageName: name,
+			AuthURL:  idp.URL + "/authorize",
+			TokenURL: idp.URL + "/token",
+			APIKey:   "email",
+			Logger:   &chronograf.NoopLogger{},
+		},
+		UseIDToken: true,
+		ClientID:   mockIdPClientID,
+		Issuer:     mockIdPIssuer,
+	}
+}
+
+func TestSessionHandler_OAuthIDTokenClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims gojwt.MapClaims
+	}{
+		{name: "other audience", claims: gojwt.MapClaims{"aud": "other"}},
+		{name: "no audience", claims: gojwt.MapClaims{"aud": nil}},
+		{name: "other issuer", claims: gojwt.MapClaims{"iss": "https://evil.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newMockIdP(t, "alice@example.com", nil, tt.claims)
+			defer idp.Close()
+
+			svc := inmem.NewService()
+			h := newOAuthSessionHandler(svc, mockIDTokenProvider(idp, "mock"))
+			w := oauthSignin(t, h, "mock")
+			if got, want := w.Code, http.StatusUnauthorized; got != want {
+				t.Fatalf("bad callback status code: got %d want %d", got, want)
+			}
+			if users, _, _ := svc.FindUsers(context.Background(), platform.UserFilter{}); len(users) != 0 {
+				t.Fatalf("unexpected users %+v", users)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_OAuthLinkUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		claims gojwt.MapClaims
+		linked bool
+	}{
+		{name: "verified email", email: "alice@example.com", linked: true},
+		{name: "unverified email", email: "alice@example.com", claims: gojwt.MapClaims{"email_verified": false}},
+		{name: "other email", email: "alice@corp.com"},
+		{name: "no email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			idp := newMockIdP(t, "alice@example.com", nil, tt.claims)
+			defer idp.Close()
+
+			svc := inmem.NewService()
+			existing := &platform.User{Name: "alice@example.com", Email: tt.email}
+			if err := svc.CreateUser(ctx, existing); err != nil {
+				t.Fatal(err)
+			}
+
+			h := newOAuthSessionHandler(svc, mockIDTokenProvider(idp, "mock"))
+			w := oauthSignin(t, h, "mock")
+			u, err := svc.FindUserByID(ctx, existing.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.linked {
+				if got, want := w.Code, http.StatusUnprocessableEntity; got != want {
+					t.Fatalf("bad callback status code: got %d want %d", got, want)
+				}
+				if u.OAuthID != "" {
+					t.Fatalf("unexpected link of the user to %q", u.OAuthID)
+				}
+				return
+			}
+			if got, want := w.Code, http.StatusNoContent; got != want {
+				t.Fatalf("bad callback status code: got %d want %d: %s", got, want, w.Body.String())
+			}
+			if got, want := u.OAuthID, "mock:alice@example.com"; got != want {
+				t.Fatalf("bad link of the user: got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSessionHandler_OAuthRevokeGroups(t *testing.T) {
+	ctx := context.Background()
+	admin := newMockIdP(t, "alice@example.com", []string{"admins"}, nil)
+	defer admin.Close()
+	dev := newMockIdP(t, "alice@example.com", []string{"devs"}, nil)
+	defer dev.Close()
+
+	svc := inmem.NewService()
+	admins := &platform.Organization{Name: "admins"}
+	devs := &platform.Organization{Name: "devs"}
+	manual := &platform.Organization{Name: "manual"}
+	for _, o := range []*platform.Organization{admins, devs, manual} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := mockIDTokenProvider(admin, "mock")
+	p.GroupsClaim = "groups"
+	p.Mappings = []platformhttp.OAuthRoleMapping{
+		{Group: "admins", OrganizationID: admins.ID, Role: platform.Owner},
+		{Group: "devs", OrganizationID: devs.ID},
+	}
+	h := newOAuthSessionHandler(svc, p)
+	if got, want := oauthSignin(t, h, "mock").Code, http.StatusNoContent; got != want {
+		t.Fatalf("bad callback status code: got %d want %d", got, want)
+	}
+
+	name := "alice@example.com"
+	u, err := svc.FindUser(ctx, platform.UserFilter{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the roles granted otherwise than through the groups are kept.
+	if err := svc.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		ResourceType: platform.OrgResourceType,
+		ResourceID:   manual.ID,
+		UserID:       u.ID,
+		UserType:     platform.Member,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the user moved from the admins to the devs.
+	p.Provider.(*oauth2.Generic).TokenURL = dev.URL + "/token"
+	if got, want := oauthSignin(t, h, "mock").Code, http.StatusNoContent; got != want {
+		t.Fatalf("bad callback status code: got %d want %d", got, want)
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles := map[platform.ID]platform.UserType{}
+	for _, m := range ms {
+		roles[m.ResourceID] = m.UserType
+	}
+	want := map[platform.ID]platform.UserType{devs.ID: platform.Member, manual.ID: platform.Member}
+	if !reflect.DeepEqual(roles, want) {
+		t.Fatalf("unexpected roles %v, want %v", roles, want)
+	}
+}
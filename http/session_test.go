@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/platform"
 	platformhttp "github.com/influxdata/platform/http"
 	"github.com/influxdata/platform/mock"
+	"golang.org/x/time/rate"
 )
 
 func TestBasicAuthHandler_handleSignin(t *testing.T) {
@@ -61,6 +62,24 @@ func TestBasicAuthHandler_handleSignin(t *testing.T) {
 				code:   http.StatusNoContent,
 			},
 		},
+		{
+			name: "user is locked out",
+			fields: fields{
+				SessionService: &mock.SessionService{},
+				BasicAuthService: &mock.BasicAuthService{
+					ComparePasswordFn: func(context.Context, string, string) error {
+						return platform.ErrPasswordLocked
+					},
+				},
+			},
+			args: args{
+				user:     "user1",
+				password: "supersecret",
+			},
+			wants: wants{
+				code: http.StatusForbidden,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -86,3 +105,32 @@ func TestBasicAuthHandler_handleSignin(t *testing.T) {
 		})
 	}
 }
+
+func TestBasicAuthHandler_handleSignin_RateLimit(t *testing.T) {
+	h := platformhttp.NewSessionHandler()
+	h.SigninLimiter = platformhttp.NewRateLimiter(rate.Every(time.Hour), 2)
+	h.BasicAuthService = &mock.BasicAuthService{
+		ComparePasswordFn: func(context.Context, string, string) error {
+			return &platform.Error{Code: platform.EUnauthorized}
+		},
+	}
+
+	signin := func(remoteAddr string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signin", nil)
+		r.RemoteAddr = remoteAddr
+		r.SetBasicAuth("user1", "wrong")
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if got := signin("10.0.0.1:1234"); got != want {
+			t.Errorf("attempt %d: bad status code: got %d want %d", i, got, want)
+		}
+	}
+	// the limit is per client.
+	if got, want := signin("10.0.0.2:1234"), http.StatusUnauthorized; got != want {
+		t.Errorf("bad status code of another client: got %d want %d", got, want)
+	}
+}
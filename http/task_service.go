@@ -1032,6 +1032,15 @@ func (t TaskService) FindRuns(ctx context.Context, filter platform.RunFilter) ([
 	if filter.After != nil {
 		val.Set("after", filter.After.String())
 	}
+	if filter.Limit > 0 {
+		val.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.AfterTime != "" {
+		val.Set("afterTime", filter.AfterTime)
+	}
+	if filter.BeforeTime != "" {
+		val.Set("beforeTime", filter.BeforeTime)
+	}
 	u.RawQuery = val.Encode()
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -444,3 +444,35 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskService_FindRuns(t *testing.T) {
+	var got platform.RunFilter
+	h := NewTaskHandler(mock.NewUserResourceMappingService(), mock.NewLabelService(), logger.New(os.Stdout))
+	h.TaskService = &mock.TaskService{
+		FindRunsFn: func(ctx context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			got = f
+			return []*platform.Run{{ID: 2, TaskID: *f.Task, Status: "success"}}, 1, nil
+		},
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := TaskService{Addr: server.URL}
+
+	taskID, orgID := platform.ID(1), platform.ID(3)
+	runs, _, err := client.FindRuns(context.Background(), platform.RunFilter{
+		Task:       &taskID,
+		Org:        &orgID,
+		Limit:      10,
+		AfterTime:  "2018-12-01T00:00:00Z",
+		BeforeTime: "2018-12-02T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].ID != 2 {
+		t.Fatalf("unexpected runs %v", runs)
+	}
+	if got.Limit != 10 || got.AfterTime != "2018-12-01T00:00:00Z" || got.BeforeTime != "2018-12-02T00:00:00Z" {
+		t.Fatalf("expected the filters to be sent, got %+v", got)
+	}
+}
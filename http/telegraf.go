@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/platform"
 	pctx "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/kit/errors"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -24,6 +25,11 @@ type TelegrafHandler struct {
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
+
+	// AuthorizationService and BucketService are used to provision the
+	// write tokens of telegraf configs.
+	AuthorizationService platform.AuthorizationService
+	BucketService        platform.BucketService
 }
 
 const (
@@ -150,6 +156,10 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := h.renderToken(ctx, tc); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	mimeType := r.Header.Get("Accept")
 	switch mimeType {
@@ -164,6 +174,7 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 			return
 		}
 	default:
+		// application/toml, telegraf pulls its config with --config URL.
 		w.Header().Set("Content-Type", "application/toml; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(tc.TOML()))
@@ -233,7 +244,16 @@ func (h *TelegrafHandler) handlePostTelegraf(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	var a *platform.Authorization
+	if decodeProvisionToken(r) {
+		if a, err = h.provisionToken(ctx, tc, auth.GetUserID()); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
 	if err := h.TelegrafService.CreateTelegrafConfig(ctx, tc, auth.GetUserID(), now); err != nil {
+		h.revokeToken(ctx, a)
 		EncodeError(ctx, err, w)
 		return
 	}
@@ -260,11 +280,37 @@ func (h *TelegrafHandler) handlePutTelegraf(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	prev, err := h.TelegrafService.FindTelegrafConfigByID(ctx, tc.ID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	tc.AuthorizationID = prev.AuthorizationID
+
+	// provisioning a token again rotates it, removing the influxdb_v2
+	// outputs revokes it.
+	var a *platform.Authorization
+	revoke := false
+	if decodeProvisionToken(r) {
+		if a, err = h.provisionToken(ctx, tc, auth.GetUserID()); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		revoke = prev.AuthorizationID.Valid()
+	} else if tc.AuthorizationID.Valid() && !hasInfluxDBV2Output(tc) {
+		tc.AuthorizationID = platform.InvalidID()
+		revoke = true
+	}
+
 	tc, err = h.TelegrafService.UpdateTelegrafConfig(ctx, tc.ID, tc, auth.GetUserID(), now)
 	if err != nil {
+		h.revokeToken(ctx, a)
 		EncodeError(ctx, err, w)
 		return
 	}
+	if revoke {
+		h.revokeToken(ctx, &platform.Authorization{ID: prev.AuthorizationID})
+	}
 
 	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafResponse(tc)); err != nil {
 		logEncodingError(h.Logger, r, err)
@@ -280,13 +326,134 @@ func (h *TelegrafHandler) handleDeleteTelegraf(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, i)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err = h.TelegrafService.DeleteTelegrafConfig(ctx, i); err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
+	if tc.AuthorizationID.Valid() {
+		h.revokeToken(ctx, &platform.Authorization{ID: tc.AuthorizationID})
+	}
 
 	if err := encodeResponse(ctx, w, http.StatusNoContent, nil); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
 }
+
+// decodeProvisionToken returns true if the request asks for a write token to
+// be provisioned for the config.
+func decodeProvisionToken(r *http.Request) bool {
+	return r.URL.Query().Get("provisionToken") == "true"
+}
+
+// hasInfluxDBV2Output returns true if tc writes to an influxdb_v2 output.
+func hasInfluxDBV2Output(tc *platform.TelegrafConfig) bool {
+	for _, p := range tc.Plugins {
+		if _, ok := p.Config.(*outputs.InfluxDBV2); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// provisionToken creates an authorization allowed to write to the buckets of
+// the influxdb_v2 outputs of tc, and embeds its token in the outputs. The
+// authorizer of ctx must be allowed to write to the buckets itself.
+func (h *TelegrafHandler) provisionToken(ctx context.Context, tc *platform.TelegrafConfig, userID platform.ID) (*platform.Authorization, error) {
+	op := "provision telegraf token"
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var outs []*outputs.InfluxDBV2
+	var ps []platform.Permission
+	for _, p := range tc.Plugins {
+		o, ok := p.Config.(*outputs.InfluxDBV2)
+		if !ok {
+			continue
+		}
+		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
+			Name:         &o.Bucket,
+			Organization: &o.Organization,
+		})
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Msg:  fmt.Sprintf("unable to find bucket %q of organization %q", o.Bucket, o.Organization),
+				Err:  err,
+			}
+		}
+		perm := platform.WriteBucketPermission(b.ID)
+		if !auth.Allowed(perm) {
+			return nil, &platform.Error{
+				Code: platform.EForbidden,
+				Op:   op,
+				Msg:  fmt.Sprintf("not allowed to write to bucket %q of organization %q", o.Bucket, o.Organization),
+			}
+		}
+		outs = append(outs, o)
+		ps = append(ps, perm)
+	}
+	if len(outs) == 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  "telegraf config has no influxdb_v2 output to provision a token for",
+		}
+	}
+
+	a := &platform.Authorization{
+		UserID:      userID,
+		Description: fmt.Sprintf("telegraf config %s", tc.Name),
+		Permissions: ps,
+	}
+	if err := h.AuthorizationService.CreateAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	for _, o := range outs {
+		o.Token = a.Token
+	}
+	tc.AuthorizationID = a.ID
+	return a, nil
+}
+
+// renderToken embeds the current token of the provisioned authorization of
+// tc in its influxdb_v2 outputs, as the token embedded at provisioning is
+// stale once the authorization is rotated.
+func (h *TelegrafHandler) renderToken(ctx context.Context, tc *platform.TelegrafConfig) error {
+	if !tc.AuthorizationID.Valid() {
+		return nil
+	}
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, tc.AuthorizationID)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		// the authorization was deleted, the config can't write anymore.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, p := range tc.Plugins {
+		if o, ok := p.Config.(*outputs.InfluxDBV2); ok {
+			o.Token = a.Token
+		}
+	}
+	return nil
+}
+
+// revokeToken deletes a provisioned authorization. Failures are only logged,
+// the config change they're part of has already been made.
+func (h *TelegrafHandler) revokeToken(ctx context.Context, a *platform.Authorization) {
+	if a == nil {
+		return
+	}
+	if err := h.AuthorizationService.DeleteAuthorization(ctx, a.ID); err != nil && platform.ErrorCode(err) != platform.ENotFound {
+		h.Logger.Info("failed to revoke telegraf token", zap.Stringer("authorizationID", a.ID), zap.Error(err))
+	}
+}
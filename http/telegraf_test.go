@@ -0,0 +1,206 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/telegraf/plugins/outputs"
+	"go.uber.org/zap"
+)
+
+func TestTelegrafHandler_ProvisionToken(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+
+	u := &platform.User{Name: "u1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &platform.Organization{Name: "o1"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{Name: "b1", OrganizationID: o.ID}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewTelegrafHandler(zap.NewNop(), svc, svc, svc)
+	h.AuthorizationService = svc
+	h.BucketService = svc
+	serve := func(method, path, body, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Accept", accept)
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			UserID:      u.ID,
+			Status:      platform.Active,
+			Permissions: []platform.Permission{platform.WriteBucketPermission(b.ID)},
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	// ids can't be encoded before they're set, so requests are written as is.
+	config := `{
+		"name": "tc1",
+		"agent": {"collectionInterval": 10000},
+		"plugins": [
+			{"name": "cpu", "type": "input", "config": {}},
+			{"name": "influxdb_v2", "type": "output", "config": {
+				"urls": ["http://localhost:9999"], "organization": "o1", "bucket": "b1"
+			}}
+		]
+	}`
+	decode := func(w *httptest.ResponseRecorder) *platform.TelegrafConfig {
+		tc := new(platform.TelegrafConfig)
+		if err := json.NewDecoder(w.Body).Decode(tc); err != nil {
+			t.Fatal(err)
+		}
+		return tc
+	}
+	tokenOf := func(tc *platform.TelegrafConfig) string {
+		for _, p := range tc.Plugins {
+			if o, ok := p.Config.(*outputs.InfluxDBV2); ok {
+				return o.Token
+			}
+		}
+		return ""
+	}
+
+	w := serve("POST", "/api/v2/telegrafs?provisionToken=true", config, "application/json")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d creating config: %s", w.Code, w.Body.String())
+	}
+	tc := decode(w)
+	a, err := svc.FindAuthorizationByID(ctx, tc.AuthorizationID)
+	if err != nil {
+		t.Fatalf("provisioned authorization not found: %v", err)
+	}
+	if tokenOf(tc) != a.Token {
+		t.Fatalf("got output token %q, want %q", tokenOf(tc), a.Token)
+	}
+	if !a.Allowed(platform.WriteBucketPermission(b.ID)) || a.Allowed(platform.ReadBucketPermission(b.ID)) {
+		t.Fatalf("provisioned authorization should only write to the bucket, got %v", a.Permissions)
+	}
+
+	// agents pull the toml config.
+	w = serve("GET", "/api/v2/telegrafs/"+tc.ID.String(), "", "application/toml")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/toml") {
+		t.Fatalf("got content type %q, want application/toml", ct)
+	}
+	if !strings.Contains(w.Body.String(), `token = "`+a.Token+`"`) {
+		t.Fatalf("toml config doesn't embed the token:\n%s", w.Body.String())
+	}
+
+	// agents pull the current token once it is rotated.
+	if a, err = svc.RotateAuthorization(ctx, a.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	w = serve("GET", "/api/v2/telegrafs/"+tc.ID.String(), "", "application/toml")
+	if !strings.Contains(w.Body.String(), `token = "`+a.Token+`"`) {
+		t.Fatalf("toml config doesn't embed the rotated token:\n%s", w.Body.String())
+	}
+
+	// provisioning again rotates the token.
+	w = serve("PUT", "/api/v2/telegrafs/"+tc.ID.String()+"?provisionToken=true", config, "application/json")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d updating config: %s", w.Code, w.Body.String())
+	}
+	rotated := decode(w)
+	if rotated.AuthorizationID == tc.AuthorizationID || tokenOf(rotated) == a.Token {
+		t.Fatal("token was not rotated")
+	}
+	if _, err := svc.FindAuthorizationByID(ctx, tc.AuthorizationID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("rotated authorization wasn't revoked, got err %v", err)
+	}
+
+	// deleting the config revokes its token.
+	w = serve("DELETE", "/api/v2/telegrafs/"+tc.ID.String(), "", "application/json")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d deleting config: %s", w.Code, w.Body.String())
+	}
+	if _, err := svc.FindAuthorizationByID(ctx, rotated.AuthorizationID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("authorization wasn't revoked, got err %v", err)
+	}
+
+	// removing the influxdb_v2 output revokes the token.
+	w = serve("POST", "/api/v2/telegrafs?provisionToken=true", config, "application/json")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d creating config: %s", w.Code, w.Body.String())
+	}
+	tc = decode(w)
+	withoutOutput := `{
+		"name": "tc1",
+		"agent": {"collectionInterval": 10000},
+		"plugins": [{"name": "cpu", "type": "input", "config": {}}]
+	}`
+	w = serve("PUT", "/api/v2/telegrafs/"+tc.ID.String(), withoutOutput, "application/json")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d updating config: %s", w.Code, w.Body.String())
+	}
+	if updated := decode(w); updated.AuthorizationID.Valid() {
+		t.Fatalf("config still refers to authorization %s", updated.AuthorizationID)
+	}
+	if _, err := svc.FindAuthorizationByID(ctx, tc.AuthorizationID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("authorization of the removed output wasn't revoked, got err %v", err)
+	}
+}
+
+func TestTelegrafHandler_ProvisionTokenWithoutOutput(t *testing.T) {
+	svc := inmem.NewService()
+	h := NewTelegrafHandler(zap.NewNop(), svc, svc, svc)
+	h.AuthorizationService = svc
+	h.BucketService = svc
+
+	body := `{"name": "tc1", "plugins": [{"name": "cpu", "type": "input", "config": {}}]}`
+	r := httptest.NewRequest("POST", "/api/v2/telegrafs?provisionToken=true", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{UserID: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTelegrafHandler_ProvisionTokenForbidden(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "o1"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{Name: "b1", OrganizationID: o.ID}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewTelegrafHandler(zap.NewNop(), svc, svc, svc)
+	h.AuthorizationService = svc
+	h.BucketService = svc
+
+	// the caller can only read the bucket, so it can't mint a token writing to it.
+	body := `{"name": "tc1", "plugins": [{"name": "influxdb_v2", "type": "output", "config": {
+		"urls": ["http://localhost:9999"], "organization": "o1", "bucket": "b1"
+	}}]}`
+	r := httptest.NewRequest("POST", "/api/v2/telegrafs?provisionToken=true", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+		UserID:      1,
+		Status:      platform.Active,
+		Permissions: []platform.Permission{platform.ReadBucketPermission(b.ID)},
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+	if as, _, err := svc.FindAuthorizations(ctx, platform.AuthorizationFilter{}); err != nil || len(as) != 0 {
+		t.Fatalf("unexpected authorizations %v %v", as, err)
+	}
+}
@@ -135,6 +135,8 @@ func newGetMembersHandler(s platform.UserResourceMappingService, userService pla
 			ResourceID:   req.ResourceID,
 			ResourceType: resourceType,
 			UserType:     userType,
+			// groups mapped to the resource are listed by the groups API.
+			PrincipalType: platform.UserPrincipal,
 		}
 
 		opts := platform.FindOptions{}
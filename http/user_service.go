@@ -21,6 +21,10 @@ type UserHandler struct {
 	UserService             platform.UserService
 	UserOperationLogService platform.UserOperationLogService
 	BasicAuthService        platform.BasicAuthService
+	// UserResourceMappingService and OrganizationService resolve the orgs
+	// and the permissions of the user in the GET /api/v2/me route.
+	UserResourceMappingService platform.UserResourceMappingService
+	OrganizationService        platform.OrganizationService
 }
 
 const (
@@ -157,7 +161,7 @@ func decodePostUserRequest(ctx context.Context, r *http.Request) (*postUserReque
 	}, nil
 }
 
-// handleGetUser is the HTTP handler for the GET /api/v2/me.
+// handleGetMe is the HTTP handler for the GET /api/v2/me route.
 func (h *UserHandler) handleGetMe(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -167,26 +171,92 @@ func (h *UserHandler) handleGetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var id platform.ID
-	switch s := a.(type) {
-	case *platform.Session:
-		id = s.UserID
-	case *platform.Authorization:
-		id = s.UserID
+	u, err := h.UserService.FindUserByID(ctx, a.GetUserID())
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
 	}
 
-	b, err := h.UserService.FindUserByID(ctx, id)
+	res, err := h.newMeResponse(ctx, u)
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newUserResponse(b)); err != nil {
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
 }
 
+// meResponse is the user along with its orgs and the permissions it is
+// granted by its resource mappings.
+type meResponse struct {
+	userResponse
+	Orgs        []*meOrgResponse      `json:"orgs"`
+	Permissions []platform.Permission `json:"permissions"`
+}
+
+type meOrgResponse struct {
+	ID   platform.ID       `json:"id"`
+	Name string            `json:"name"`
+	Role platform.UserType `json:"role"`
+	// Default is true for the default org of the user.
+	Default bool `json:"default"`
+}
+
+func (h *UserHandler) newMeResponse(ctx context.Context, u *platform.User) (*meResponse, error) {
+	res := &meResponse{
+		userResponse: *newUserResponse(u),
+		Orgs:         []*meOrgResponse{},
+		Permissions:  []platform.Permission{},
+	}
+	if h.UserResourceMappingService == nil {
+		return res, nil
+	}
+
+	ms, err := platform.UserMappings(ctx, h.UserResourceMappingService, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := map[platform.ID]*meOrgResponse{}
+	seen := map[platform.Permission]bool{}
+	for _, m := range ms {
+		for _, p := range m.ToPermissions() {
+			if !seen[p] {
+				seen[p] = true
+				res.Permissions = append(res.Permissions, p)
+			}
+		}
+
+		if m.ResourceType != platform.OrgResourceType || h.OrganizationService == nil {
+			continue
+		}
+		if o, ok := orgs[m.ResourceID]; ok {
+			// the user is an owner if any of its mappings makes it one.
+			if m.UserType == platform.Owner {
+				o.Role = platform.Owner
+			}
+			continue
+		}
+		org, err := h.OrganizationService.FindOrganizationByID(ctx, m.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		o := &meOrgResponse{
+			ID:      org.ID,
+			Name:    org.Name,
+			Role:    m.UserType,
+			Default: org.ID == u.DefaultOrgID,
+		}
+		orgs[org.ID] = o
+		res.Orgs = append(res.Orgs, o)
+	}
+
+	return res, nil
+}
+
 // handleGetUser is the HTTP handler for the GET /api/v2/users/:id route.
 func (h *UserHandler) handleGetUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -355,6 +425,10 @@ func decodeGetUsersRequest(ctx context.Context, r *http.Request) (*getUsersReque
 		req.filter.Name = &name
 	}
 
+	if oauthID := qp.Get("oauthID"); oauthID != "" {
+		req.filter.OAuthID = &oauthID
+	}
+
 	return req, nil
 }
 
@@ -368,6 +442,13 @@ func (h *UserHandler) handlePatchUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Update.OAuthID != nil {
+		if err := authorizeOAuthLink(ctx); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
 	b, err := h.UserService.UpdateUser(ctx, req.UserID, req.Update)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -380,6 +461,23 @@ func (h *UserHandler) handlePatchUser(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// authorizeOAuthLink returns an error unless the authorizer of ctx, an
+// operator, may link users to external identities, which can then sign in
+// as the users.
+func authorizeOAuthLink(ctx context.Context) error {
+	a, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if !isOperator(a) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "linking a user to an external identity requires the permission to create users",
+		}
+	}
+	return nil
+}
+
 type patchUserRequest struct {
 	Update platform.UserUpdate
 	UserID platform.ID
@@ -522,6 +620,9 @@ func (s *UserService) FindUsers(ctx context.Context, filter platform.UserFilter,
 	if filter.Name != nil {
 		query.Add("name", *filter.Name)
 	}
+	if filter.OAuthID != nil {
+		query.Add("oauthID", *filter.OAuthID)
+	}
 
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
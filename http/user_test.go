@@ -2,10 +2,15 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/inmem"
 	platformtesting "github.com/influxdata/platform/testing"
 )
@@ -39,3 +44,100 @@ func TestUserService(t *testing.T) {
 	t.Parallel()
 	platformtesting.UserService(initUserService, t)
 }
+
+func TestUserHandler_handleGetMe(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+
+	org := &platform.Organization{Name: "org1"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	u := &platform.User{Name: "user1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		ResourceID:   org.ID,
+		ResourceType: platform.OrgResourceType,
+		UserID:       u.ID,
+		UserType:     platform.Owner,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateUser(ctx, u.ID, platform.UserUpdate{DefaultOrgID: &org.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewUserHandler()
+	h.UserService = svc
+	h.UserResourceMappingService = svc
+	h.OrganizationService = svc
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/me", nil)
+	r = r.WithContext(platcontext.SetAuthorizer(r.Context(), &platform.Session{UserID: u.ID}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d: %s", w.Code, w.Body.String())
+	}
+
+	var res meResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != u.ID || res.DefaultOrgID != org.ID {
+		t.Errorf("unexpected user %v", res.User)
+	}
+	wantOrgs := []*meOrgResponse{
+		{ID: org.ID, Name: "org1", Role: platform.Owner, Default: true},
+	}
+	if diff := cmp.Diff(res.Orgs, wantOrgs); diff != "" {
+		t.Errorf("orgs are different -got/+want\ndiff %s", diff)
+	}
+	orgResource := "org/" + org.ID.String()
+	var resources []string
+	for _, p := range res.Permissions {
+		resources = append(resources, p.String())
+	}
+	if len(res.Permissions) == 0 || !strings.Contains(strings.Join(resources, ","), orgResource) {
+		t.Errorf("expected permissions on %s, got %v", orgResource, resources)
+	}
+}
+
+func TestUserHandler_handlePatchUser_OAuthID(t *testing.T) {
+	svc := inmem.NewService()
+	ctx := context.Background()
+	u := &platform.User{Name: "user1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewUserHandler()
+	h.UserService = svc
+
+	patch := func(a platform.Authorizer) int {
+		r := httptest.NewRequest("PATCH", "http://any.url/api/v2/users/"+u.ID.String(), strings.NewReader(`{"oauthID": "github:user1"}`))
+		r = r.WithContext(platcontext.SetAuthorizer(r.Context(), a))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// the user can't link itself to another identity.
+	if got, want := patch(&platform.Session{UserID: u.ID}), http.StatusForbidden; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+	if got, _ := svc.FindUserByID(ctx, u.ID); got.OAuthID != "" {
+		t.Fatalf("unexpected link of the user to %q", got.OAuthID)
+	}
+
+	operator := &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{platform.CreateUserPermission}}
+	if got, want := patch(operator), http.StatusOK; got != want {
+		t.Fatalf("bad status code: got %d want %d", got, want)
+	}
+	if got, _ := svc.FindUserByID(ctx, u.ID); got.OAuthID != "github:user1" {
+		t.Fatalf("bad link of the user: got %q", got.OAuthID)
+	}
+}
@@ -97,6 +97,10 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 		org = o
 	}
+	if err := platform.CheckOrganizationActive(org); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	var bucket *platform.Bucket
 	if id, err := platform.IDFromString(req.Bucket); err == nil {
@@ -249,8 +253,40 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	return CheckError(resp)
+	if err := CheckError(resp, resp.Header.Get(PlatformErrorCodeHeader) != ""); err != nil {
+		if _, ok := err.(*platform.Error); ok {
+			return err
+		}
+		// keep the status of the errors that aren't platform errors, so that
+		// callers can tell rejected writes from transient failures.
+		return &platform.Error{
+			Code: writeErrorCode(resp.StatusCode),
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// writeErrorCode returns the platform error code of a write response status.
+func writeErrorCode(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return platform.ETooManyRequests
+	case status == http.StatusServiceUnavailable:
+		return platform.EUnavailable
+	case status == http.StatusUnauthorized:
+		return platform.EUnauthorized
+	case status == http.StatusForbidden:
+		return platform.EForbidden
+	case status == http.StatusNotFound:
+		return platform.ENotFound
+	case status/100 == 4:
+		return platform.EInvalid
+	default:
+		return platform.EInternal
+	}
 }
 
 func compressWithGzip(data io.Reader) (io.Reader, error) {
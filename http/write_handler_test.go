@@ -9,8 +9,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/models"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +73,52 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+type pointsWriter []models.Point
+
+func (w *pointsWriter) WritePoints(ps []models.Point) error {
+	*w = append(*w, ps...)
+	return nil
+}
+
+func TestWriteHandler_DeletedOrganization(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	b := &platform.Bucket{OrganizationID: o.ID, Name: "telegraf"}
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	var written pointsWriter
+	h := NewWriteHandler(&written)
+	h.OrganizationService = svc
+	h.BucketService = svc
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{platform.WriteBucketPermission(b.ID)}}
+		h.ServeHTTP(w, r.WithContext(platcontext.SetAuthorizer(r.Context(), auth)))
+	}))
+	defer server.Close()
+	s := &WriteService{Addr: server.URL}
+
+	if err := s.Write(ctx, o.ID, b.ID, strings.NewReader("m f=1")); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if len(written) != 1 {
+		t.Fatalf("expected 1 point written, got %d", len(written))
+	}
+
+	now := time.Now()
+	if _, err := svc.UpdateOrganization(ctx, o.ID, platform.OrganizationUpdate{DeletedAt: &now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(ctx, o.ID, b.ID, strings.NewReader("m f=2")); err == nil {
+		t.Fatal("expected an error writing to a deleted org")
+	}
+	if len(written) != 1 {
+		t.Fatal("points of a deleted org were written")
+	}
+}
@@ -0,0 +1,70 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.AuditLogService = (*Service)(nil)
+
+// LogAuditEvent records an audit event.
+func (s *Service) LogAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	e.ID = s.IDGenerator.ID()
+	if e.Time.IsZero() {
+		e.Time = s.time()
+	}
+	e.Time = e.Time.UTC()
+	s.auditLogKV.Store(e.ID.String(), *e)
+	return nil
+}
+
+// FindAuditEvents returns the audit events that match the filter.
+func (s *Service) FindAuditEvents(ctx context.Context, filter platform.AuditEventFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+	op := OpPrefix + platform.OpFindAuditEvents
+	var opts platform.FindOptions
+	if len(opt) > 0 {
+		opts = opt[0]
+	}
+
+	var err error
+	es := []*platform.AuditEvent{}
+	s.auditLogKV.Range(func(k, v interface{}) bool {
+		e, ok := v.(platform.AuditEvent)
+		if !ok {
+			err = &platform.Error{
+				Code: platform.EInternal,
+				Msg:  "value found in map is not an audit event",
+				Op:   op,
+			}
+			return false
+		}
+		if filter.Matches(&e) {
+			es = append(es, &e)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort.Slice(es, func(i, j int) bool {
+		if !es[i].Time.Equal(es[j].Time) {
+			return es[i].Time.Before(es[j].Time) != opts.Descending
+		}
+		return (es[i].ID < es[j].ID) != opts.Descending
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(es) {
+			return []*platform.AuditEvent{}, 0, nil
+		}
+		es = es[opts.Offset:]
+	}
+	if opts.Limit > 0 && len(es) > opts.Limit {
+		es = es[:opts.Limit]
+	}
+
+	return es, len(es), nil
+}
@@ -0,0 +1,28 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initAuditLogService(f platformtesting.AuditLogFields, t *testing.T) (platform.AuditLogService, string, func()) {
+	s := NewService()
+	s.IDGenerator = f.IDGenerator
+	if f.NowFn != nil {
+		s.WithTime(f.NowFn)
+	}
+	ctx := context.TODO()
+	for _, e := range f.Events {
+		if err := s.LogAuditEvent(ctx, e); err != nil {
+			t.Fatalf("failed to populate audit events")
+		}
+	}
+	return s, OpPrefix, func() {}
+}
+
+func TestAuditLogService(t *testing.T) {
+	platformtesting.AuditLogService(initAuditLogService, t)
+}
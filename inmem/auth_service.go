@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"context"
+	"time"
 
 	"github.com/influxdata/platform"
 )
@@ -89,26 +90,25 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, t string) (*plat
 	return as[0], nil
 }
 
-func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+func filterAuthorizationsFn(filter platform.AuthorizationFilter, now time.Time) func(a *platform.Authorization) bool {
+	fn := func(a *platform.Authorization) bool { return true }
 	if filter.ID != nil {
-		return func(a *platform.Authorization) bool {
+		fn = func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
 		}
-	}
-
-	if filter.Token != nil {
-		return func(a *platform.Authorization) bool {
-			return a.Token == *filter.Token
+	} else if filter.Token != nil {
+		fn = func(a *platform.Authorization) bool {
+			return a.Token == *filter.Token || a.HasPreviousToken(*filter.Token, now)
 		}
-	}
-
-	if filter.UserID != nil {
-		return func(a *platform.Authorization) bool {
+	} else if filter.UserID != nil {
+		fn = func(a *platform.Authorization) bool {
 			return a.UserID == *filter.UserID
 		}
 	}
 
-	return func(a *platform.Authorization) bool { return true }
+	return func(a *platform.Authorization) bool {
+		return fn(a) && filter.Matches(a, now)
+	}
 }
 
 // FindAuthorizations returns all authorizations matching the filter.
@@ -122,6 +122,9 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 				Op:  op,
 			}
 		}
+		if !filter.Matches(a, s.time()) {
+			return []*platform.Authorization{}, 0, nil
+		}
 
 		return []*platform.Authorization{a}, 1, nil
 	}
@@ -138,7 +141,7 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 		filter.UserID = &u.ID
 	}
 	var err error
-	filterF := filterAuthorizationsFn(filter)
+	filterF := filterAuthorizationsFn(filter, s.time())
 	s.authorizationKV.Range(func(k, v interface{}) bool {
 		a, ok := v.(platform.Authorization)
 		if !ok {
@@ -183,6 +186,13 @@ func (s *Service) CreateAuthorization(ctx context.Context, a *platform.Authoriza
 		}
 		a.UserID = u.ID
 	}
+	if u, err := s.loadUser(a.UserID); err == nil && !u.IsActive() {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  platform.ErrUserInactive,
+			Op:   op,
+		}
+	}
 	var err error
 	a.Token, err = s.TokenGenerator.Token()
 	if err != nil {
@@ -233,3 +243,49 @@ func (s *Service) SetAuthorizationStatus(ctx context.Context, id platform.ID, st
 	a.Status = status
 	return s.PutAuthorization(ctx, a)
 }
+
+// RotateAuthorization issues a new token for the authorization. The previous
+// token keeps authenticating until the grace period has passed.
+func (s *Service) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	op := OpPrefix + platform.OpRotateAuthorization
+	a, pe := s.loadAuthorization(ctx, id)
+	if pe != nil {
+		pe.Op = op
+		return nil, pe
+	}
+
+	token, err := s.TokenGenerator.Token()
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	a.PreviousToken = ""
+	a.PreviousTokenExpiresAt = nil
+	if grace > 0 {
+		expiresAt := s.time().Add(grace)
+		a.PreviousToken = a.Token
+		a.PreviousTokenExpiresAt = &expiresAt
+	}
+	a.Token = token
+
+	if err := s.PutAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetAuthorizationLastUsed sets when the authorization was last used.
+func (s *Service) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, at time.Time) error {
+	a, pe := s.loadAuthorization(ctx, id)
+	if pe != nil {
+		return pe
+	}
+	if a.LastUsedAt != nil && !at.After(*a.LastUsedAt) {
+		return nil
+	}
+	a.LastUsedAt = &at
+	return s.PutAuthorization(ctx, a)
+}
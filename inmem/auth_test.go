@@ -12,6 +12,9 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	s := NewService()
 	s.IDGenerator = f.IDGenerator
 	s.TokenGenerator = f.TokenGenerator
+	if f.NowFn != nil {
+		s.WithTime(f.NowFn)
+	}
 	ctx := context.TODO()
 	for _, u := range f.Users {
 		if err := s.PutUser(ctx, u); err != nil {
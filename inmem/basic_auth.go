@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"context"
+	"time"
 
 	"github.com/influxdata/platform"
 	"golang.org/x/crypto/bcrypt"
@@ -10,40 +11,106 @@ import (
 // HashCost is currently using bcrypt defaultCost
 const HashCost = bcrypt.DefaultCost
 
+// passwordHashers returns the hashers of the passwords, bcrypt with HashCost
+// unless others are configured.
+func (s *Service) passwordHashers() platform.PasswordHashers {
+	if len(s.PasswordHashers) > 0 {
+		return s.PasswordHashers
+	}
+	return platform.PasswordHashers{platform.BcryptHasher{Cost: HashCost}}
+}
+
 // SetPassword stores the password hash associated with a user.
 func (s *Service) SetPassword(ctx context.Context, name string, password string) error {
+	s.basicAuthMu.Lock()
+	defer s.basicAuthMu.Unlock()
+	return s.setPassword(ctx, name, password)
+}
+
+func (s *Service) setPassword(ctx context.Context, name string, password string) error {
+	if err := s.PasswordPolicy.Validate(password); err != nil {
+		return err
+	}
+
 	u, err := s.FindUser(ctx, platform.UserFilter{Name: &name})
 	if err != nil {
 		return err
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), HashCost)
+	hash, err := s.passwordHashers().Hash(password)
 	if err != nil {
 		return err
 	}
 
 	s.basicAuthKV.Store(u.ID.String(), hash)
+	// a new password lifts any lockout.
+	s.passwordAttemptsKV.Delete(u.ID.String())
 
 	return nil
 }
 
+// passwordAttempts are the failed attempts to sign in of a user.
+type passwordAttempts struct {
+	// failures is the number of consecutive failed attempts.
+	failures    int
+	lockedUntil time.Time
+}
+
 // ComparePassword compares a provided password with the stored password hash.
 func (s *Service) ComparePassword(ctx context.Context, name string, password string) error {
+	s.basicAuthMu.Lock()
+	defer s.basicAuthMu.Unlock()
+	return s.comparePassword(ctx, name, password)
+}
+
+func (s *Service) comparePassword(ctx context.Context, name string, password string) error {
 	u, err := s.FindUser(ctx, platform.UserFilter{Name: &name})
 	if err != nil {
 		return err
 	}
+
+	attempts := passwordAttempts{}
+	if a, ok := s.passwordAttemptsKV.Load(u.ID.String()); ok {
+		attempts = a.(passwordAttempts)
+	}
+	now := s.time()
+	if now.Before(attempts.lockedUntil) {
+		return platform.ErrPasswordLocked
+	}
+
 	hash, ok := s.basicAuthKV.Load(u.ID.String())
 	if !ok {
 		hash = []byte{}
 	}
 
-	return bcrypt.CompareHashAndPassword(hash.([]byte), []byte(password))
+	hashers := s.passwordHashers()
+	if err := hashers.Compare(hash.([]byte), password); err != nil {
+		attempts.failures++
+		if s.PasswordPolicy.Locks(attempts.failures) {
+			attempts.failures = 0
+			attempts.lockedUntil = now.Add(s.PasswordPolicy.LockoutDuration)
+		}
+		s.passwordAttemptsKV.Store(u.ID.String(), attempts)
+		return err
+	}
+	s.passwordAttemptsKV.Delete(u.ID.String())
+
+	if hashers.NeedsRehash(hash.([]byte)) {
+		rehash, err := hashers.Hash(password)
+		if err != nil {
+			return err
+		}
+		s.basicAuthKV.Store(u.ID.String(), rehash)
+	}
+
+	return nil
 }
 
 // CompareAndSetPassword replaces the old password with the new password if thee old password is correct.
 func (s *Service) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
-	if err := s.ComparePassword(ctx, name, old); err != nil {
+	s.basicAuthMu.Lock()
+	defer s.basicAuthMu.Unlock()
+	if err := s.comparePassword(ctx, name, old); err != nil {
 		return err
 	}
-	return s.SetPassword(ctx, name, new)
+	return s.setPassword(ctx, name, new)
 }
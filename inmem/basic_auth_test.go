@@ -29,3 +29,35 @@ func TestBasicAuth_CompareAndSet(t *testing.T) {
 	t.Parallel()
 	platformtesting.CompareAndSetPassword(initBasicAuthService, t)
 }
+
+func initBasicAuthPolicyService(f platformtesting.BasicAuthFields, t *testing.T) (platform.BasicAuthService, func()) {
+	s := NewService()
+	s.IDGenerator = f.IDGenerator
+	if f.NowFn != nil {
+		s.WithTime(f.NowFn)
+	}
+	s.PasswordPolicy = f.PasswordPolicy
+	s.PasswordHashers = f.PasswordHashers
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := s.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users")
+		}
+	}
+	return s, func() {}
+}
+
+func TestBasicAuth_PasswordPolicy(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordPolicy(initBasicAuthPolicyService, t)
+}
+
+func TestBasicAuth_PasswordLockout(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordLockout(initBasicAuthPolicyService, t)
+}
+
+func TestBasicAuth_PasswordRehash(t *testing.T) {
+	t.Parallel()
+	platformtesting.PasswordRehash(initBasicAuthPolicyService, t)
+}
@@ -209,11 +209,11 @@ func (s *Service) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 		}
 		b.OrganizationID = o.ID
 	}
-	filter := platform.BucketFilter{
-		Name:           &b.Name,
-		OrganizationID: &b.OrganizationID,
+	unique, err := s.uniqueBucketName(ctx, b)
+	if err != nil {
+		return err
 	}
-	if _, err := s.FindBucket(ctx, filter); err == nil {
+	if !unique {
 		return &platform.Error{
 			Code: platform.EConflict,
 			Op:   OpPrefix + platform.OpCreateBucket,
@@ -241,19 +241,40 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		}
 	}
 
-	if upd.Name != nil {
-		b.Name = *upd.Name
-	}
+	upd.Apply(b)
 
-	if upd.RetentionPeriod != nil {
-		b.RetentionPeriod = *upd.RetentionPeriod
+	// a restored bucket takes its name back, unless another bucket took it meanwhile.
+	if b.DeletedAt == nil {
+		unique, err := s.uniqueBucketName(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		if !unique {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Op:   OpPrefix + platform.OpUpdateBucket,
+				Msg:  fmt.Sprintf("bucket with name %s already exists", b.Name),
+			}
+		}
 	}
 
-	s.bucketKV.Store(b.ID.String(), b)
+	s.bucketKV.Store(b.ID.String(), *b)
 
 	return b, nil
 }
 
+// uniqueBucketName returns true unless another bucket of the organization of
+// b has its name. The names of deleted buckets are free.
+func (s *Service) uniqueBucketName(ctx context.Context, b *platform.Bucket) (bool, error) {
+	bs, err := s.filterBuckets(ctx, func(o *platform.Bucket) bool {
+		return o.ID != b.ID && o.DeletedAt == nil && o.OrganizationID == b.OrganizationID && o.Name == b.Name
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(bs) == 0, nil
+}
+
 // DeleteBucket removes a bucket by ID.
 func (s *Service) DeleteBucket(ctx context.Context, id platform.ID) error {
 	if _, err := s.FindBucketByID(ctx, id); err != nil {
@@ -0,0 +1,194 @@
+package inmem
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+)
+
+const (
+	errGroupNotFound = "group not found"
+)
+
+var _ platform.GroupService = (*Service)(nil)
+
+func (s *Service) loadGroup(id platform.ID) (*platform.Group, *platform.Error) {
+	i, ok := s.groupKV.Load(id.String())
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  errGroupNotFound,
+		}
+	}
+
+	g, ok := i.(platform.Group)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("type %T is not a group", i),
+		}
+	}
+	return &g, nil
+}
+
+// FindGroupByID returns a single group by ID.
+func (s *Service) FindGroupByID(ctx context.Context, id platform.ID) (*platform.Group, error) {
+	g, pe := s.loadGroup(id)
+	if pe != nil {
+		return nil, &platform.Error{
+			Op:  OpPrefix + platform.OpFindGroupByID,
+			Err: pe,
+		}
+	}
+	return g, nil
+}
+
+// FindGroup returns the first group that matches a filter.
+func (s *Service) FindGroup(ctx context.Context, filter platform.GroupFilter) (*platform.Group, error) {
+	op := OpPrefix + platform.OpFindGroup
+	gs, n, err := s.FindGroups(ctx, filter)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if n < 1 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   op,
+			Msg:  errGroupNotFound,
+		}
+	}
+	return gs[0], nil
+}
+
+// FindGroups returns a list of groups that match filter and the total count of matching groups.
+func (s *Service) FindGroups(ctx context.Context, filter platform.GroupFilter, opt ...platform.FindOptions) ([]*platform.Group, int, error) {
+	gs := []*platform.Group{}
+	s.groupKV.Range(func(k, v interface{}) bool {
+		g, ok := v.(platform.Group)
+		if !ok {
+			return true
+		}
+		if (filter.ID == nil || *filter.ID == g.ID) &&
+			(filter.Name == nil || *filter.Name == g.Name) &&
+			(filter.OrganizationID == nil || *filter.OrganizationID == g.OrganizationID) {
+			gs = append(gs, &g)
+		}
+		return true
+	})
+	return gs, len(gs), nil
+}
+
+// CreateGroup creates a new group and sets g.ID with the new identifier.
+func (s *Service) CreateGroup(ctx context.Context, g *platform.Group) error {
+	op := OpPrefix + platform.OpCreateGroup
+	if g.Name == "" {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Op:   op,
+			Msg:  "group name is required",
+		}
+	}
+	if _, pe := s.loadOrganization(g.OrganizationID); pe != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: pe,
+		}
+	}
+	if pe := s.uniqueGroupName(ctx, g); pe != nil {
+		pe.Op = op
+		return pe
+	}
+
+	g.ID = s.IDGenerator.ID()
+	s.groupKV.Store(g.ID.String(), *g)
+	return nil
+}
+
+// PutGroup puts a group without setting an ID.
+func (s *Service) PutGroup(ctx context.Context, g *platform.Group) error {
+	s.groupKV.Store(g.ID.String(), *g)
+	return nil
+}
+
+func (s *Service) uniqueGroupName(ctx context.Context, g *platform.Group) *platform.Error {
+	gs, _, _ := s.FindGroups(ctx, platform.GroupFilter{
+		Name:           &g.Name,
+		OrganizationID: &g.OrganizationID,
+	})
+	for _, other := range gs {
+		if other.ID != g.ID {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("group with name %s already exists", g.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateGroup updates a single group with changeset.
+func (s *Service) UpdateGroup(ctx context.Context, id platform.ID, upd platform.GroupUpdate) (*platform.Group, error) {
+	op := OpPrefix + platform.OpUpdateGroup
+	if err := upd.Valid(); err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	g, pe := s.loadGroup(id)
+	if pe != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: pe,
+		}
+	}
+
+	if upd.Name != nil {
+		g.Name = *upd.Name
+		if pe := s.uniqueGroupName(ctx, g); pe != nil {
+			pe.Op = op
+			return nil, pe
+		}
+	}
+	if upd.Description != nil {
+		g.Description = *upd.Description
+	}
+
+	s.groupKV.Store(g.ID.String(), *g)
+	return g, nil
+}
+
+// DeleteGroup removes a group by ID, along with its memberships and the
+// mappings of the group to resources.
+func (s *Service) DeleteGroup(ctx context.Context, id platform.ID) error {
+	op := OpPrefix + platform.OpDeleteGroup
+	if _, pe := s.loadGroup(id); pe != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: pe,
+		}
+	}
+	s.groupKV.Delete(id.String())
+
+	for _, f := range []platform.UserResourceMappingFilter{
+		{ResourceID: id, ResourceType: platform.GroupResourceType},
+		{UserID: id, PrincipalType: platform.GroupPrincipal},
+	} {
+		ms, err := s.filterUserResourceMappings(ctx, f.Matches)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		for _, m := range ms {
+			s.userResourceMappingKV.Delete(encodeUserResourceMappingKey(m.ResourceID, m.UserID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initGroupService(f platformtesting.GroupFields, t *testing.T) (platformtesting.GroupServices, string, func()) {
+	s := NewService()
+	s.IDGenerator = f.IDGenerator
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := s.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, g := range f.Groups {
+		if err := s.PutGroup(ctx, g); err != nil {
+			t.Fatalf("failed to populate groups")
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := s.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings")
+		}
+	}
+	return s, OpPrefix, func() {}
+}
+
+func TestGroupService(t *testing.T) {
+	platformtesting.GroupService(initGroupService, t)
+}
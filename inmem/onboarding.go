@@ -68,6 +68,10 @@ func (s *Service) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		}
 	}
 
+	if err := s.PasswordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
 	u := &platform.User{Name: req.User}
 	if err := s.CreateUser(ctx, u); err != nil {
 		return nil, err
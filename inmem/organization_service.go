@@ -204,9 +204,7 @@ func (s *Service) UpdateOrganization(ctx context.Context, id platform.ID, upd pl
 		}
 	}
 
-	if upd.Name != nil {
-		o.Name = *upd.Name
-	}
+	upd.Apply(o)
 
 	s.organizationKV.Store(o.ID.String(), o)
 
@@ -12,6 +12,7 @@ const (
 )
 
 var _ platform.ScraperTargetStoreService = (*Service)(nil)
+var _ platform.ScraperTargetStatusService = (*Service)(nil)
 
 func (s *Service) loadScraperTarget(id platform.ID) (*platform.ScraperTarget, *platform.Error) {
 	i, ok := s.scraperTargetKV.Load(id.String())
@@ -71,6 +72,7 @@ func (s *Service) RemoveTarget(ctx context.Context, id platform.ID) error {
 		}
 	}
 	s.scraperTargetKV.Delete(id.String())
+	s.scraperTargetStatusKV.Delete(id.String())
 	return nil
 }
 
@@ -118,3 +120,40 @@ func (s *Service) PutTarget(ctx context.Context, target *platform.ScraperTarget)
 	s.scraperTargetKV.Store(target.ID.String(), *target)
 	return nil
 }
+
+// GetTargetStatus retrieves the status of the last scrape of a target.
+func (s *Service) GetTargetStatus(ctx context.Context, id platform.ID) (*platform.ScraperTargetStatus, error) {
+	if _, pe := s.loadScraperTarget(id); pe != nil {
+		return nil, &platform.Error{
+			Op:  OpPrefix + platform.OpGetTargetStatus,
+			Err: pe,
+		}
+	}
+	i, ok := s.scraperTargetStatusKV.Load(id.String())
+	if !ok {
+		return &platform.ScraperTargetStatus{
+			Health: platform.ScraperTargetHealthUnknown,
+		}, nil
+	}
+	status, ok := i.(platform.ScraperTargetStatus)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   OpPrefix + platform.OpGetTargetStatus,
+			Msg:  fmt.Sprintf("type %T is not a scraper target status", i),
+		}
+	}
+	return &status, nil
+}
+
+// PutTargetStatus stores the status of the last scrape of a target.
+func (s *Service) PutTargetStatus(ctx context.Context, id platform.ID, status *platform.ScraperTargetStatus) error {
+	if _, pe := s.loadScraperTarget(id); pe != nil {
+		return &platform.Error{
+			Op:  OpPrefix + platform.OpPutTargetStatus,
+			Err: pe,
+		}
+	}
+	s.scraperTargetStatusKV.Store(id.String(), *status)
+	return nil
+}
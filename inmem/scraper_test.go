@@ -39,3 +39,12 @@ func TestScraperTargetStoreService_UpdateTarget(t *testing.T) {
 func TestScraperTargetStoreService_GetTargetByID(t *testing.T) {
 	platformtesting.GetTargetByID(initScraperTargetStoreService, t)
 }
+
+func initScraperTargetStatusService(f platformtesting.TargetFields, t *testing.T) (platform.ScraperTargetStatusService, string, func()) {
+	s, opPrefix, done := initScraperTargetStoreService(f, t)
+	return s.(*Service), opPrefix, done
+}
+
+func TestScraperTargetStatusService(t *testing.T) {
+	platformtesting.TargetStatus(initScraperTargetStatusService, t)
+}
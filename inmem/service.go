@@ -23,15 +23,27 @@ type Service struct {
 	macroKV               sync.Map
 	dbrpMappingKV         sync.Map
 	userResourceMappingKV sync.Map
+	groupKV               sync.Map
 	labelKV               sync.Map
 	scraperTargetKV       sync.Map
+	scraperTargetStatusKV sync.Map
 	telegrafConfigKV      sync.Map
 	onboardingKV          sync.Map
 	basicAuthKV           sync.Map
+	passwordAttemptsKV    sync.Map
+	auditLogKV            sync.Map
+
+	// basicAuthMu serializes the attempts to sign in of users.
+	basicAuthMu sync.Mutex
 
 	TokenGenerator platform.TokenGenerator
 	IDGenerator    platform.IDGenerator
 	time           func() time.Time
+
+	// PasswordPolicy are the rules of the passwords and of the sign in attempts.
+	PasswordPolicy platform.PasswordPolicy
+	// PasswordHashers hash the passwords, the first one hashes new passwords.
+	PasswordHashers platform.PasswordHashers
 }
 
 // NewService creates an instance of a Service.
@@ -62,21 +62,20 @@ func (s *Service) filterUserResourceMappings(ctx context.Context, fn func(m *pla
 
 func (s *Service) FindUserResourceMappings(ctx context.Context, filter platform.UserResourceMappingFilter, opt ...platform.FindOptions) ([]*platform.UserResourceMapping, int, error) {
 	if filter.ResourceID.Valid() && filter.UserID.Valid() {
+		if _, ok := s.userResourceMappingKV.Load(encodeUserResourceMappingKey(filter.ResourceID, filter.UserID)); !ok {
+			return []*platform.UserResourceMapping{}, 0, nil
+		}
 		m, err := s.FindUserResourceBy(ctx, filter.ResourceID, filter.UserID)
 		if err != nil {
 			return nil, 0, err
 		}
+		if !filter.Matches(m) {
+			return []*platform.UserResourceMapping{}, 0, nil
+		}
 		return []*platform.UserResourceMapping{m}, 1, nil
 	}
 
-	filterFunc := func(mapping *platform.UserResourceMapping) bool {
-		return (!filter.UserID.Valid() || (filter.UserID == mapping.UserID)) &&
-			(!filter.ResourceID.Valid() || (filter.ResourceID == mapping.ResourceID)) &&
-			(filter.UserType == "" || (filter.UserType == mapping.UserType)) &&
-			(filter.ResourceType == "" || (filter.ResourceType == mapping.ResourceType))
-	}
-
-	mappings, err := s.filterUserResourceMappings(ctx, filterFunc)
+	mappings, err := s.filterUserResourceMappings(ctx, filter.Matches)
 	if err != nil {
 		return nil, 0, err
 	}
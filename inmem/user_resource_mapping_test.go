@@ -31,3 +31,7 @@ func TestUserResourceMappingService_CreateUserResourceMapping(t *testing.T) {
 func TestUserResourceMappingService_DeleteUserResourceMapping(t *testing.T) {
 	platformtesting.DeleteUserResourceMapping(initUserResourceMappingService, t)
 }
+
+func TestUserResourceMappingService_UserPermissions(t *testing.T) {
+	platformtesting.UserPermissions(initUserResourceMappingService, t)
+}
@@ -87,10 +87,36 @@ func (s *Service) FindUser(ctx context.Context, filter platform.UserFilter) (*pl
 		return o, nil
 	}
 
+	if filter.OAuthID != nil {
+		var o *platform.User
+
+		err := s.forEachUser(ctx, func(u *platform.User) bool {
+			if u.OAuthID == *filter.OAuthID {
+				o = u
+				return false
+			}
+			return true
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if o == nil {
+			return nil, &platform.Error{
+				Code: platform.ENotFound,
+				Op:   op,
+				Msg:  "user not found",
+			}
+		}
+
+		return o, nil
+	}
+
 	return nil, &platform.Error{
 		Code: platform.EInvalid,
 		Op:   op,
-		Msg:  "expected filter to contain name",
+		Msg:  "expected filter to contain name or oauth id",
 	}
 }
 
@@ -108,7 +134,7 @@ func (s *Service) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 
 		return []*platform.User{o}, 1, nil
 	}
-	if filter.Name != nil {
+	if filter.Name != nil || filter.OAuthID != nil {
 		o, err := s.FindUser(ctx, filter)
 		if err != nil {
 			return nil, 0, &platform.Error{
@@ -136,6 +162,12 @@ func (s *Service) FindUsers(ctx context.Context, filter platform.UserFilter, opt
 
 // CreateUser will create an user into storage.
 func (s *Service) CreateUser(ctx context.Context, u *platform.User) error {
+	if err := platform.ValidateNewUser(u); err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  OpPrefix + platform.OpCreateUser,
+		}
+	}
 	if _, err := s.FindUser(ctx, platform.UserFilter{Name: &u.Name}); err == nil {
 		return &platform.Error{
 			Code: platform.EConflict,
@@ -156,31 +188,98 @@ func (s *Service) PutUser(ctx context.Context, o *platform.User) error {
 
 // UpdateUser update a user in storage.
 func (s *Service) UpdateUser(ctx context.Context, id platform.ID, upd platform.UserUpdate) (*platform.User, error) {
+	op := OpPrefix + platform.OpUpdateUser
+	if err := upd.Valid(); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
 	o, err := s.FindUserByID(ctx, id)
 	if err != nil {
 		return nil, &platform.Error{
 			Err: err,
-			Op:  OpPrefix + platform.OpUpdateUser,
+			Op:  op,
 		}
 	}
 
-	if upd.Name != nil {
-		o.Name = *upd.Name
+	if upd.DefaultOrgID != nil && upd.DefaultOrgID.Valid() {
+		find := func(f platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, error) {
+			ms, _, err := s.FindUserResourceMappings(ctx, f)
+			return ms, err
+		}
+		if err := platform.ValidateDefaultOrg(find, id, *upd.DefaultOrgID); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
 	}
 
-	s.userKV.Store(o.ID.String(), o)
+	deactivated := o.IsActive() && upd.Status != nil && *upd.Status == platform.Inactive
+	u := *o
+	upd.Apply(&u)
 
-	return o, nil
+	if deactivated {
+		if err := s.disableUsersAuthorizations(ctx, id); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+	}
+
+	s.userKV.Store(u.ID.String(), &u)
+
+	return &u, nil
 }
 
-// DeleteUser remove a user from storage.
+// disableUsersAuthorizations sets all the authorizations of a user inactive.
+func (s *Service) disableUsersAuthorizations(ctx context.Context, id platform.ID) error {
+	as, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{UserID: &id})
+	if err != nil {
+		return err
+	}
+	for _, a := range as {
+		if err := s.SetAuthorizationStatus(ctx, a.ID, platform.Inactive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteUser remove a user from storage, along with its authorizations,
+// password and resource mappings.
 func (s *Service) DeleteUser(ctx context.Context, id platform.ID) error {
+	op := OpPrefix + platform.OpDeleteUser
 	if _, err := s.FindUserByID(ctx, id); err != nil {
 		return &platform.Error{
 			Err: err,
-			Op:  OpPrefix + platform.OpDeleteUser,
+			Op:  op,
+		}
+	}
+
+	as, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{UserID: &id})
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+	for _, a := range as {
+		s.authorizationKV.Delete(a.ID.String())
+	}
+
+	if err := s.deleteUserResourceMapping(ctx, platform.UserResourceMappingFilter{UserID: id}); err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  op,
 		}
 	}
+
+	s.basicAuthKV.Delete(id.String())
+	s.passwordAttemptsKV.Delete(id.String())
 	s.userKV.Delete(id.String())
 	return nil
 }
@@ -24,3 +24,31 @@ func TestUserService(t *testing.T) {
 	t.Parallel()
 	platformtesting.UserService(initUserService, t)
 }
+
+func initUserLifecycleService(f platformtesting.UserLifecycleFields, t *testing.T) (platformtesting.UserLifecycleService, string, func()) {
+	s := NewService()
+	s.IDGenerator = f.IDGenerator
+	s.TokenGenerator = f.TokenGenerator
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := s.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users")
+		}
+	}
+	for _, a := range f.Authorizations {
+		if err := s.PutAuthorization(ctx, a); err != nil {
+			t.Fatalf("failed to populate authorizations")
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := s.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings")
+		}
+	}
+	return s, OpPrefix, func() {}
+}
+
+func TestUserService_Lifecycle(t *testing.T) {
+	t.Parallel()
+	platformtesting.UserLifecycle(initUserLifecycleService, t)
+}
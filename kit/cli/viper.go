@@ -80,6 +80,13 @@ func NewCommand(p *Program) *cobra.Command {
 			cmd.Flags().BoolVar(o.DestP.(*bool), o.Flag, o.Default.(bool), o.Desc)
 			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
 			*o.DestP.(*bool) = viper.GetBool(o.Flag)
+		case *float64:
+			if o.Default == nil {
+				o.Default = float64(0)
+			}
+			cmd.Flags().Float64Var(o.DestP.(*float64), o.Flag, o.Default.(float64), o.Desc)
+			viper.BindPFlag(o.Flag, cmd.Flags().Lookup(o.Flag))
+			*o.DestP.(*float64) = viper.GetFloat64(o.Flag)
 		case *time.Duration:
 			if o.Default == nil {
 				o.Default = time.Duration(0)
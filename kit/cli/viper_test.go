@@ -10,6 +10,7 @@ func ExampleNewCommand() {
 	var monitorHost string
 	var number int
 	var sleep bool
+	var ratio float64
 	var duration time.Duration
 	var stringSlice []string
 	cmd := NewCommand(&Program{
@@ -19,6 +20,7 @@ func ExampleNewCommand() {
 				fmt.Printf("%d\n", i)
 			}
 			fmt.Println(sleep)
+			fmt.Println(ratio)
 			fmt.Println(duration)
 			fmt.Println(stringSlice)
 			return nil
@@ -43,6 +45,12 @@ func ExampleNewCommand() {
 				Default: true,
 				Desc:    "whether to sleep",
 			},
+			{
+				DestP:   &ratio,
+				Flag:    "ratio",
+				Default: 0.5,
+				Desc:    "ratio of things",
+			},
 			{
 				DestP:   &duration,
 				Flag:    "duration",
@@ -66,6 +74,7 @@ func ExampleNewCommand() {
 	// 0
 	// 1
 	// true
+	// 0.5
 	// 1m0s
 	// [foo bar]
 }
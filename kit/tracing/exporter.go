@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Exporter sends the spans recorded to a tracing system.
+type Exporter interface {
+	// Export exports a batch of spans.
+	Export(spans []*SpanData) error
+	// Close releases the resources of the exporter.
+	Close() error
+}
+
+// FileExporter writes the spans as JSON, one span per line.
+type FileExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewFileExporter returns an exporter writing the spans to w, which it
+// closes if it is an io.Closer.
+func NewFileExporter(w io.Writer) *FileExporter {
+	return &FileExporter{w: w}
+}
+
+// OpenFileExporter returns an exporter appending the spans to a file.
+func OpenFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileExporter(f), nil
+}
+
+// Export writes spans to the file.
+func (e *FileExporter) Export(spans []*SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	w := bufio.NewWriter(e.w)
+	enc := json.NewEncoder(w)
+	for _, s := range spans {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Close closes the file.
+func (e *FileExporter) Close() error {
+	if c, ok := e.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
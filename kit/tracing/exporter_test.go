@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSpan(name string) *SpanData {
+	start := time.Unix(100, 0)
+	return &SpanData{
+		TraceID:       TraceID{15: 1},
+		SpanID:        SpanID{7: 2},
+		ParentID:      SpanID{7: 1},
+		OperationName: name,
+		Start:         start,
+		Finish:        start.Add(time.Millisecond),
+		Tags:          map[string]interface{}{"span.kind": "server", "error": true, "rows": 3},
+		Logs: []LogRecord{{
+			Time:   start,
+			Fields: map[string]interface{}{"event": "sorted"},
+		}},
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var req otlpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL, "influxd")
+	if err := e.Export([]*SpanData{testSpan("read")}); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if service := *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; service != "influxd" {
+		t.Errorf("unexpected service %q", service)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("unexpected spans %+v", spans)
+	}
+	s := spans[0]
+	if s.TraceID != "00000000000000000000000000000001" || s.ParentSpanID != "0000000000000001" || s.Name != "read" {
+		t.Errorf("unexpected span %+v", s)
+	}
+	if s.Kind != otlpSpanKindServer || s.Status == nil || s.Status.Code != otlpStatusCodeError {
+		t.Errorf("unexpected kind and status %+v", s)
+	}
+	if s.StartTimeUnixNano != "100000000000" || s.EndTimeUnixNano != "100001000000" {
+		t.Errorf("unexpected times %+v", s)
+	}
+	if a := s.Attributes[1]; a.Key != "rows" || *a.Value.IntValue != "3" {
+		t.Errorf("unexpected attribute %+v", a)
+	}
+	if len(s.Events) != 1 || s.Events[0].Name != "sorted" {
+		t.Errorf("unexpected events %+v", s.Events)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	e = NewOTLPExporter(missing.URL, "influxd")
+	if err := e.Export([]*SpanData{testSpan("read")}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected the error of the collector, got %v", err)
+	}
+}
+
+func TestJaegerExporter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	e, err := NewJaegerExporter(conn.LocalAddr().String(), "influxd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// the spans are split in packets no larger than what the agent reads.
+	large := testSpan("large")
+	large.Tags["data"] = strings.Repeat("x", jaegerMaxPacketSize/2)
+	if err := e.Export([]*SpanData{testSpan("read"), large, large}); err != nil {
+		t.Fatal(err)
+	}
+
+	var packets [][]byte
+	buf := make([]byte, 2*jaegerMaxPacketSize)
+	for i := 0; i < 2; i++ {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packets = append(packets, append([]byte(nil), buf[:n]...))
+	}
+	for _, p := range packets {
+		if len(p) > jaegerMaxPacketSize {
+			t.Errorf("packet of %d bytes is too large", len(p))
+		}
+		if !bytes.HasPrefix(p, []byte("\x82\x81")) || !bytes.Contains(p, []byte("emitBatch")) || !bytes.Contains(p, []byte("influxd")) {
+			t.Errorf("unexpected packet %q", p[:32])
+		}
+	}
+	if !bytes.Contains(packets[0], []byte("read")) || !bytes.Contains(packets[1], []byte("large")) {
+		t.Error("expected the spans to be sent in order")
+	}
+
+	huge := testSpan("huge")
+	huge.Tags["data"] = strings.Repeat("x", jaegerMaxPacketSize)
+	if err := e.Export([]*SpanData{huge}); err == nil {
+		t.Error("expected a span larger than a packet to be dropped")
+	}
+}
+
+func TestThriftWriter(t *testing.T) {
+	w := &thriftWriter{}
+	w.structBegin()
+	w.i32Field(1, -1)
+	w.i64Field(20, 1)
+	w.boolField(21, true)
+	w.stringField(22, "ab")
+	w.field(23, thriftList)
+	w.listBegin(thriftI32, 20)
+	w.structEnd()
+
+	want := []byte{
+		// field 1, i32 -1
+		0x15, 0x01,
+		// field 20 in long form, i64 1
+		0x06, 0x28, 0x02,
+		// field 21, bool true
+		0x11,
+		// field 22, binary "ab"
+		0x18, 0x02, 'a', 'b',
+		// field 23, list of 20 i32s
+		0x19, 0xf5, 0x14,
+		// stop
+		0x00,
+	}
+	if !bytes.Equal(w.Bytes(), want) {
+		t.Errorf("got % x, want % x", w.Bytes(), want)
+	}
+}
@@ -0,0 +1,226 @@
+package tracing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+)
+
+// DefaultJaegerAgentAddr is the address a Jaeger agent receives spans at by
+// default, with the thrift compact protocol.
+const DefaultJaegerAgentAddr = "localhost:6831"
+
+// jaegerMaxPacketSize is the largest packet the Jaeger agent reads.
+const jaegerMaxPacketSize = 65000
+
+// The tag and reference types of the Jaeger thrift model, see
+// https://github.com/jaegertracing/jaeger-idl/blob/master/thrift/jaeger.thrift.
+const (
+	jaegerTagString = 0
+	jaegerTagDouble = 1
+	jaegerTagBool   = 2
+	jaegerTagLong   = 3
+
+	jaegerRefFollowsFrom = 1
+
+	jaegerFlagSampled = 1
+)
+
+// JaegerExporter sends the spans to a Jaeger agent over UDP, as batches
+// encoded with the thrift compact protocol.
+type JaegerExporter struct {
+	conn    net.Conn
+	service string
+	seqID   int32
+}
+
+// NewJaegerExporter returns an exporter sending the spans of a service to
+// the Jaeger agent at addr.
+func NewJaegerExporter(addr, service string) (*JaegerExporter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &JaegerExporter{conn: conn, service: service}, nil
+}
+
+// Export sends spans to the agent, in as many packets as they need. The
+// spans too large for a packet are dropped.
+func (e *JaegerExporter) Export(spans []*SpanData) error {
+	// The overhead of a packet is its header and process, plus the bytes of
+	// the largest list header and of the end of its structs.
+	overhead := e.emitBatch(e.seqID, nil).Len() + 8
+
+	var (
+		batch   [][]byte
+		size    int
+		dropped int
+	)
+	for _, s := range spans {
+		b := encodeJaegerSpan(s)
+		if overhead+len(b) > jaegerMaxPacketSize {
+			dropped++
+			continue
+		}
+		if overhead+size+len(b) > jaegerMaxPacketSize {
+			if err := e.send(batch); err != nil {
+				return err
+			}
+			batch, size = nil, 0
+		}
+		batch = append(batch, b)
+		size += len(b)
+	}
+	if len(batch) > 0 {
+		if err := e.send(batch); err != nil {
+			return err
+		}
+	}
+	if dropped > 0 {
+		return fmt.Errorf("dropped %d spans larger than a packet", dropped)
+	}
+	return nil
+}
+
+func (e *JaegerExporter) send(spans [][]byte) error {
+	e.seqID++
+	_, err := e.conn.Write(e.emitBatch(e.seqID, spans).Bytes())
+	return err
+}
+
+// emitBatch encodes the call of the emitBatch method of the agent, with the
+// spans already encoded.
+func (e *JaegerExporter) emitBatch(seqID int32, spans [][]byte) *thriftWriter {
+	w := &thriftWriter{}
+	w.messageBegin("emitBatch", seqID)
+	w.structBegin()
+	w.field(1, thriftStruct) // batch
+	w.structBegin()
+	w.field(1, thriftStruct) // process
+	w.structBegin()
+	w.stringField(1, e.service)
+	w.structEnd()
+	w.field(2, thriftList) // spans
+	w.listBegin(thriftStruct, len(spans))
+	for _, s := range spans {
+		w.Write(s)
+	}
+	w.structEnd()
+	w.structEnd()
+	return w
+}
+
+// Close closes the connection to the agent.
+func (e *JaegerExporter) Close() error {
+	return e.conn.Close()
+}
+
+func encodeJaegerSpan(s *SpanData) []byte {
+	traceIDLow := int64(binary.BigEndian.Uint64(s.TraceID[8:]))
+	traceIDHigh := int64(binary.BigEndian.Uint64(s.TraceID[:8]))
+	parentID := int64(binary.BigEndian.Uint64(s.ParentID[:]))
+
+	w := &thriftWriter{}
+	w.structBegin()
+	w.i64Field(1, traceIDLow)
+	w.i64Field(2, traceIDHigh)
+	w.i64Field(3, int64(binary.BigEndian.Uint64(s.SpanID[:])))
+	if s.FollowsFrom {
+		w.i64Field(4, 0)
+		w.field(6, thriftList)
+		w.listBegin(thriftStruct, 1)
+		w.structBegin()
+		w.i32Field(1, jaegerRefFollowsFrom)
+		w.i64Field(2, traceIDLow)
+		w.i64Field(3, traceIDHigh)
+		w.i64Field(4, parentID)
+		w.structEnd()
+	} else {
+		w.i64Field(4, parentID)
+	}
+	w.stringField(5, s.OperationName)
+	w.i32Field(7, jaegerFlagSampled)
+	w.i64Field(8, s.Start.UnixNano()/int64(time.Microsecond))
+	w.i64Field(9, int64(s.Duration()/time.Microsecond))
+	if len(s.Tags) > 0 {
+		w.field(10, thriftList)
+		writeJaegerTags(w, s.Tags)
+	}
+	if len(s.Logs) > 0 {
+		w.field(11, thriftList)
+		w.listBegin(thriftStruct, len(s.Logs))
+		for _, l := range s.Logs {
+			w.structBegin()
+			w.i64Field(1, l.Time.UnixNano()/int64(time.Microsecond))
+			w.field(2, thriftList)
+			writeJaegerTags(w, l.Fields)
+			w.structEnd()
+		}
+	}
+	w.structEnd()
+	return w.Bytes()
+}
+
+// writeJaegerTags writes a list of tags, sorted by key.
+func writeJaegerTags(w *thriftWriter, tags map[string]interface{}) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w.listBegin(thriftStruct, len(keys))
+	for _, k := range keys {
+		w.structBegin()
+		w.stringField(1, k)
+		switch v := tags[k].(type) {
+		case string:
+			w.i32Field(2, jaegerTagString)
+			w.stringField(3, v)
+		case float32:
+			w.i32Field(2, jaegerTagDouble)
+			w.doubleField(4, float64(v))
+		case float64:
+			w.i32Field(2, jaegerTagDouble)
+			w.doubleField(4, v)
+		case bool:
+			w.i32Field(2, jaegerTagBool)
+			w.boolField(5, v)
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			w.i32Field(2, jaegerTagLong)
+			w.i64Field(6, toInt64(v))
+		default:
+			w.i32Field(2, jaegerTagString)
+			w.stringField(3, fmt.Sprint(v))
+		}
+		w.structEnd()
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,223 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// DefaultOTLPURL is the URL an OpenTelemetry collector receives traces at by default.
+const DefaultOTLPURL = "http://localhost:4318/v1/traces"
+
+// otlpTimeout bounds how long the collector takes to receive a batch.
+const otlpTimeout = 10 * time.Second
+
+// The kinds and status codes of OTLP spans.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+	otlpSpanKindProducer = 4
+	otlpSpanKindConsumer = 5
+
+	otlpStatusCodeError = 2
+)
+
+// OTLPExporter sends the spans to an OpenTelemetry collector with the
+// OTLP/HTTP protocol, encoded as JSON.
+type OTLPExporter struct {
+	url     string
+	service string
+	client  *http.Client
+}
+
+// NewOTLPExporter returns an exporter sending the spans of a service to
+// the collector at url.
+func NewOTLPExporter(url, service string) *OTLPExporter {
+	return &OTLPExporter{
+		url:     url,
+		service: service,
+		client:  &http.Client{Timeout: otlpTimeout},
+	}
+}
+
+// Export sends spans to the collector.
+func (e *OTLPExporter) Export(spans []*SpanData) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector responded %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// Close does nothing, the exporter holds no resources.
+func (e *OTLPExporter) Close() error { return nil }
+
+// The JSON mapping of the messages of the OTLP trace service.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Status            *otlpStatus    `json:"status,omitempty"`
+	}
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpStatus struct {
+		Code int `json:"code"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+func (e *OTLPExporter) request(spans []*SpanData) *otlpRequest {
+	ss := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		ss = append(ss, otlpSpanOf(s))
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{{Key: "service.name", Value: otlpValueOf(e.service)}},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: e.service},
+				Spans: ss,
+			}},
+		}},
+	}
+}
+
+func otlpSpanOf(s *SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.TraceID.String(),
+		SpanID:            s.SpanID.String(),
+		Name:              s.OperationName,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.Finish.UnixNano(), 10),
+		Attributes:        otlpAttributes(s.Tags),
+	}
+	if s.ParentID.Valid() {
+		span.ParentSpanID = s.ParentID.String()
+	}
+	switch s.Tags[string(ext.SpanKind)] {
+	case ext.SpanKindRPCServerEnum, string(ext.SpanKindRPCServerEnum):
+		span.Kind = otlpSpanKindServer
+	case ext.SpanKindRPCClientEnum, string(ext.SpanKindRPCClientEnum):
+		span.Kind = otlpSpanKindClient
+	case ext.SpanKindProducerEnum, string(ext.SpanKindProducerEnum):
+		span.Kind = otlpSpanKindProducer
+	case ext.SpanKindConsumerEnum, string(ext.SpanKindConsumerEnum):
+		span.Kind = otlpSpanKindConsumer
+	}
+	if failed, _ := s.Tags[string(ext.Error)].(bool); failed {
+		span.Status = &otlpStatus{Code: otlpStatusCodeError}
+	}
+	for _, l := range s.Logs {
+		name := "log"
+		if event, ok := l.Fields["event"].(string); ok {
+			name = event
+		}
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(l.Time.UnixNano(), 10),
+			Name:         name,
+			Attributes:   otlpAttributes(l.Fields),
+		})
+	}
+	return span
+}
+
+// otlpAttributes returns the attributes of tags, sorted by key.
+func otlpAttributes(tags map[string]interface{}) []otlpKeyValue {
+	if len(tags) == 0 {
+		return nil
+	}
+	attrs := make([]otlpKeyValue, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, otlpKeyValue{Key: k, Value: otlpValueOf(v)})
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+func otlpValueOf(v interface{}) otlpValue {
+	var value otlpValue
+	switch v := v.(type) {
+	case string:
+		value.StringValue = &v
+	case bool:
+		value.BoolValue = &v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprint(v)
+		value.IntValue = &s
+	case float32:
+		f := float64(v)
+		value.DoubleValue = &f
+	case float64:
+		value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return value
+}
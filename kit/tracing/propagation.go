@@ -0,0 +1,191 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// The headers of the W3C trace context, see https://www.w3.org/TR/trace-context/,
+// and of the W3C baggage, see https://www.w3.org/TR/baggage/.
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+	baggageHeader     = "baggage"
+
+	traceparentVersion = "00"
+	sampledFlag        = 0x01
+)
+
+// legacyTraceHeader is the header traces were propagated with before the
+// W3C trace context, it is still extracted so that older clients are traced.
+const legacyTraceHeader = "Zap-Trace-Span"
+
+// Inject writes the W3C trace context of a span to a text map or HTTP headers.
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	c, ok := sm.(SpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return opentracing.ErrUnsupportedFormat
+	}
+	w, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	w.Set(traceparentHeader, formatTraceparent(c))
+	if c.State != "" {
+		w.Set(tracestateHeader, c.State)
+	}
+	if len(c.baggage) > 0 {
+		w.Set(baggageHeader, formatBaggage(c.baggage))
+	}
+	return nil
+}
+
+// Extract reads the W3C trace context of a span from a text map or HTTP
+// headers. It returns opentracing.ErrSpanContextNotFound if there is none.
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	r, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+
+	var traceparent, tracestate, baggage, legacy string
+	if err := r.ForeachKey(func(k, v string) error {
+		switch {
+		case strings.EqualFold(k, traceparentHeader):
+			traceparent = v
+		case strings.EqualFold(k, tracestateHeader):
+			tracestate = v
+		case strings.EqualFold(k, baggageHeader):
+			baggage = v
+		case strings.EqualFold(k, legacyTraceHeader):
+			legacy = v
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var (
+		c   SpanContext
+		err error
+	)
+	switch {
+	case traceparent != "":
+		c, err = parseTraceparent(traceparent)
+		c.State = tracestate
+	case legacy != "":
+		c, err = parseLegacy(legacy)
+	default:
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if baggage != "" {
+		for k, v := range parseBaggage(baggage) {
+			c = c.withBaggageItem(k, v)
+		}
+	}
+	return c, nil
+}
+
+func formatTraceparent(c SpanContext) string {
+	var flags byte
+	if c.Sampled {
+		flags |= sampledFlag
+	}
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, c.TraceID, c.SpanID, flags)
+}
+
+// parseTraceparent parses a traceparent header, accepting the headers of
+// later versions as long as they start like those of the version it knows.
+func parseTraceparent(v string) (SpanContext, error) {
+	var c SpanContext
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	if parts[0] == traceparentVersion && len(parts) != 4 {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	var flags [1]byte
+	if c.TraceID.UnmarshalText([]byte(parts[1])) != nil ||
+		c.SpanID.UnmarshalText([]byte(parts[2])) != nil ||
+		decodeHex(flags[:], []byte(parts[3])) != nil {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	if !c.TraceID.Valid() || !c.SpanID.Valid() {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	c.Sampled = flags[0]&sampledFlag != 0
+	return c, nil
+}
+
+// parseLegacy parses the header of the zap tracer, whose IDs are 8 bytes.
+func parseLegacy(v string) (SpanContext, error) {
+	var c SpanContext
+	var raw struct {
+		TraceID string            `json:"trace_id"`
+		SpanID  string            `json:"span_id"`
+		Baggage map[string]string `json:"baggage"`
+	}
+	if err := json.Unmarshal([]byte(v), &raw); err != nil {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	if decodeHex(c.TraceID[8:], []byte(raw.TraceID)) != nil ||
+		c.SpanID.UnmarshalText([]byte(raw.SpanID)) != nil {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	if !c.TraceID.Valid() || !c.SpanID.Valid() {
+		return c, opentracing.ErrSpanContextCorrupted
+	}
+	c.Sampled = true
+	for k, v := range raw.Baggage {
+		c = c.withBaggageItem(k, v)
+	}
+	return c, nil
+}
+
+func formatBaggage(baggage map[string]string) string {
+	items := make([]string, 0, len(baggage))
+	for k, v := range baggage {
+		items = append(items, url.PathEscape(k)+"="+url.PathEscape(v))
+	}
+	return strings.Join(items, ",")
+}
+
+// parseBaggage parses a baggage header, ignoring the properties of the items
+// and the items it can't parse.
+func parseBaggage(v string) map[string]string {
+	baggage := make(map[string]string)
+	for _, item := range strings.Split(v, ",") {
+		if i := strings.IndexByte(item, ';'); i >= 0 {
+			item = item[:i]
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, err := url.PathUnescape(strings.TrimSpace(kv[0]))
+		if err != nil || k == "" {
+			continue
+		}
+		v, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+		baggage[k] = v
+	}
+	return baggage
+}
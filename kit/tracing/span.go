@@ -0,0 +1,286 @@
+// Package tracing records the spans of opentracing traces and exports them
+// to tracing systems, propagating the traces with the W3C trace context.
+package tracing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// Valid reports whether the ID is set.
+func (id TraceID) Valid() bool { return id != TraceID{} }
+
+// String returns the ID as hex.
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// MarshalText encodes the ID as hex.
+func (id TraceID) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText decodes the ID from hex.
+func (id *TraceID) UnmarshalText(b []byte) error { return decodeHex(id[:], b) }
+
+// SpanID identifies a span of a trace.
+type SpanID [8]byte
+
+// Valid reports whether the ID is set.
+func (id SpanID) Valid() bool { return id != SpanID{} }
+
+// String returns the ID as hex.
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// MarshalText encodes the ID as hex.
+func (id SpanID) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText decodes the ID from hex.
+func (id *SpanID) UnmarshalText(b []byte) error { return decodeHex(id[:], b) }
+
+func decodeHex(dst, b []byte) error {
+	if len(b) != hex.EncodedLen(len(dst)) {
+		return fmt.Errorf("id must be %d hex characters", hex.EncodedLen(len(dst)))
+	}
+	_, err := hex.Decode(dst, b)
+	return err
+}
+
+var _ opentracing.SpanContext = SpanContext{}
+
+// SpanContext implements opentracing.SpanContext, it is what is propagated
+// of a span to its children, including across processes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Sampled reports whether the spans of the trace are recorded.
+	Sampled bool
+	// State is the vendor specific state of the trace, propagated as is.
+	State string
+
+	baggage map[string]string
+}
+
+// ForeachBaggageItem calls handler for each baggage item until it returns false.
+func (c SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+// withBaggageItem returns a copy of the context with a baggage item set, as
+// the baggage may be shared with the contexts of other spans.
+func (c SpanContext) withBaggageItem(k, v string) SpanContext {
+	baggage := make(map[string]string, len(c.baggage)+1)
+	for k, v := range c.baggage {
+		baggage[k] = v
+	}
+	baggage[k] = v
+	c.baggage = baggage
+	return c
+}
+
+// SpanData is a finished span, as it is exported.
+type SpanData struct {
+	TraceID TraceID `json:"traceID"`
+	SpanID  SpanID  `json:"spanID"`
+	// ParentID is the span this span is a child of or follows from, it is
+	// zero for the root span of a trace.
+	ParentID    SpanID `json:"parentID"`
+	FollowsFrom bool   `json:"followsFrom,omitempty"`
+
+	OperationName string                 `json:"operationName"`
+	Start         time.Time              `json:"start"`
+	Finish        time.Time              `json:"finish"`
+	Tags          map[string]interface{} `json:"tags,omitempty"`
+	Logs          []LogRecord            `json:"logs,omitempty"`
+}
+
+// Duration returns how long the span lasted.
+func (d *SpanData) Duration() time.Duration {
+	return d.Finish.Sub(d.Start)
+}
+
+// LogRecord is what was logged to a span at a time.
+type LogRecord struct {
+	Time   time.Time              `json:"time"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+var _ opentracing.Span = (*Span)(nil)
+
+// Span implements opentracing.Span, all Spans must be created using the Tracer.
+type Span struct {
+	tracer *Tracer
+	// sampled is whether the span is recorded, as its context tells.
+	sampled bool
+
+	mu   sync.Mutex
+	ctx  SpanContext
+	data SpanData
+	done bool
+}
+
+// Finish finishes the span now.
+func (s *Span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions finishes the span, recording it if its trace is
+// sampled. Only the first call has effect.
+func (s *Span) FinishWithOptions(opts opentracing.FinishOptions) {
+	if opts.FinishTime.IsZero() {
+		opts.FinishTime = time.Now()
+	}
+
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return
+	}
+	s.done = true
+	s.data.Finish = opts.FinishTime
+	for _, r := range opts.LogRecords {
+		s.logLocked(r.Timestamp, r.Fields)
+	}
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sampled {
+		s.tracer.record(&data)
+	}
+}
+
+// Context returns the context of the span.
+func (s *Span) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx
+}
+
+// SetOperationName changes the name of the span.
+func (s *Span) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	s.data.OperationName = operationName
+	s.mu.Unlock()
+	return s
+}
+
+// SetTag sets a tag of the span.
+func (s *Span) SetTag(key string, value interface{}) opentracing.Span {
+	if !s.sampled {
+		return s
+	}
+	s.mu.Lock()
+	if s.data.Tags == nil {
+		s.data.Tags = make(map[string]interface{})
+	}
+	s.data.Tags[key] = value
+	s.mu.Unlock()
+	return s
+}
+
+// LogFields logs fields to the span now.
+func (s *Span) LogFields(fields ...log.Field) {
+	if !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	s.logLocked(time.Now(), fields)
+	s.mu.Unlock()
+}
+
+func (s *Span) logLocked(t time.Time, fields []log.Field) {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r := LogRecord{
+		Time:   t,
+		Fields: make(map[string]interface{}, len(fields)),
+	}
+	for _, f := range fields {
+		v := f.Value()
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		r.Fields[f.Key()] = v
+	}
+	s.data.Logs = append(s.data.Logs, r)
+}
+
+// LogKV logs alternating keys and values to the span now.
+func (s *Span) LogKV(keyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(keyValues...)
+	if err != nil {
+		s.LogFields(log.Error(err), log.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+// SetBaggageItem sets an item propagated to the descendants of the span.
+func (s *Span) SetBaggageItem(restrictedKey string, value string) opentracing.Span {
+	s.mu.Lock()
+	s.ctx = s.ctx.withBaggageItem(restrictedKey, value)
+	s.mu.Unlock()
+	return s
+}
+
+// BaggageItem returns an item of the baggage of the span.
+func (s *Span) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx.baggage[restrictedKey]
+}
+
+// Tracer returns the tracer that created the span.
+func (s *Span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent logs an event to the span.
+//
+// Deprecated: use LogFields or LogKV.
+func (s *Span) LogEvent(event string) {
+	s.LogFields(log.String("event", event))
+}
+
+// LogEventWithPayload logs an event and its payload to the span.
+//
+// Deprecated: use LogFields or LogKV.
+func (s *Span) LogEventWithPayload(event string, payload interface{}) {
+	s.LogFields(log.String("event", event), log.Object("payload", payload))
+}
+
+// Log logs data to the span.
+//
+// Deprecated: use LogFields or LogKV.
+func (s *Span) Log(data opentracing.LogData) {
+	fields := []log.Field{log.String("event", data.Event)}
+	if data.Payload != nil {
+		fields = append(fields, log.Object("payload", data.Payload))
+	}
+	if !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	s.logLocked(data.Timestamp, fields)
+	s.mu.Unlock()
+}
+
+// IsSampled reports whether a span is recorded by a Tracer. The spans too
+// fine-grained to be logged, such as those of every series read, are only
+// started in sampled traces.
+func IsSampled(span opentracing.Span) bool {
+	if span == nil {
+		return false
+	}
+	c, ok := span.Context().(SpanContext)
+	return ok && c.Sampled
+}
@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// The types of the thrift compact protocol, see
+// https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md.
+const (
+	thriftBoolTrue  = 0x01
+	thriftBoolFalse = 0x02
+	thriftI32       = 0x05
+	thriftI64       = 0x06
+	thriftDouble    = 0x07
+	thriftBinary    = 0x08
+	thriftList      = 0x09
+	thriftStruct    = 0x0c
+
+	thriftProtocolID    = 0x82
+	thriftVersion       = 0x01
+	thriftTypeShift     = 5
+	thriftMessageOneway = 0x04
+)
+
+// thriftWriter encodes values with the thrift compact protocol.
+type thriftWriter struct {
+	bytes.Buffer
+	// fields are the IDs of the last fields written of the structs being
+	// written, fields being encoded relatively to the previous one.
+	fields []int16
+}
+
+// messageBegin writes the header of a message calling a oneway method.
+func (w *thriftWriter) messageBegin(name string, seqID int32) {
+	w.WriteByte(thriftProtocolID)
+	w.WriteByte(thriftMessageOneway<<thriftTypeShift | thriftVersion)
+	w.varint(uint64(uint32(seqID)))
+	w.string(name)
+}
+
+func (w *thriftWriter) structBegin() {
+	w.fields = append(w.fields, 0)
+}
+
+func (w *thriftWriter) structEnd() {
+	w.WriteByte(0) // stop field
+	w.fields = w.fields[:len(w.fields)-1]
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.fields[len(w.fields)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) listBegin(typ byte, size int) {
+	if size < 15 {
+		w.WriteByte(byte(size)<<4 | typ)
+		return
+	}
+	w.WriteByte(0xf0 | typ)
+	w.varint(uint64(size))
+}
+
+func (w *thriftWriter) boolField(id int16, v bool) {
+	if v {
+		w.field(id, thriftBoolTrue)
+	} else {
+		w.field(id, thriftBoolFalse)
+	}
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(zigzag(v))
+}
+
+func (w *thriftWriter) doubleField(id int16, v float64) {
+	w.field(id, thriftDouble)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	w.Write(b[:])
+}
+
+func (w *thriftWriter) stringField(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.string(v)
+}
+
+func (w *thriftWriter) string(v string) {
+	w.varint(uint64(len(v)))
+	w.WriteString(v)
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.Write(b[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
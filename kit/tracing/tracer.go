@@ -0,0 +1,241 @@
+package tracing
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultFlushInterval is how often the spans recorded are exported by default.
+	DefaultFlushInterval = time.Second
+
+	// batchSize is the most spans exported at once; the spans are exported
+	// early when that many are recorded.
+	batchSize = 512
+	// maxQueuedSpans is the most spans waiting to be exported, the spans
+	// recorded past it are dropped.
+	maxQueuedSpans = 16 * batchSize
+)
+
+var _ opentracing.Tracer = (*Tracer)(nil)
+
+// Tracer implements opentracing.Tracer, it records the spans of the sampled
+// traces and exports them in batches.
+type Tracer struct {
+	exporter Exporter
+
+	// SampleRatio is the ratio of the traces started by the tracer that are
+	// sampled; the traces propagated to the tracer keep the sampling
+	// decision of their caller.
+	SampleRatio float64
+	// FlushInterval is how often the spans recorded are exported.
+	FlushInterval time.Duration
+	Logger        *zap.Logger
+
+	idMu sync.Mutex
+	rand *rand.Rand
+
+	mu      sync.Mutex
+	queued  []*SpanData
+	dropped int
+
+	exportMu sync.Mutex
+	flush    chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewTracer returns a tracer exporting the spans it records with e, and
+// sampling all the traces it starts.
+func NewTracer(e Exporter) *Tracer {
+	var seed int64
+	if err := binary.Read(crand.Reader, binary.BigEndian, &seed); err != nil {
+		seed = time.Now().UnixNano()
+	}
+	return &Tracer{
+		exporter:      e,
+		SampleRatio:   1,
+		FlushInterval: DefaultFlushInterval,
+		Logger:        zap.NewNop(),
+		rand:          rand.New(rand.NewSource(seed)),
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// Open starts exporting the spans recorded periodically.
+func (t *Tracer) Open() error {
+	t.wg.Add(1)
+	go t.run()
+	return nil
+}
+
+// Close exports the spans left and closes the exporter.
+func (t *Tracer) Close() error {
+	close(t.done)
+	t.wg.Wait()
+	if err := t.Flush(); err != nil {
+		t.exporter.Close()
+		return err
+	}
+	return t.exporter.Close()
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		case <-t.flush:
+		}
+		if err := t.Flush(); err != nil {
+			t.Logger.Info("Failed to export spans", zap.Error(err))
+		}
+	}
+}
+
+// Flush exports the spans recorded.
+func (t *Tracer) Flush() error {
+	t.mu.Lock()
+	spans, dropped := t.queued, t.dropped
+	t.queued, t.dropped = nil, 0
+	t.mu.Unlock()
+
+	if dropped > 0 {
+		t.Logger.Info("Dropped spans waiting to be exported", zap.Int("count", dropped))
+	}
+
+	t.exportMu.Lock()
+	defer t.exportMu.Unlock()
+	for len(spans) > 0 {
+		n := len(spans)
+		if n > batchSize {
+			n = batchSize
+		}
+		if err := t.exporter.Export(spans[:n]); err != nil {
+			return err
+		}
+		spans = spans[n:]
+	}
+	return nil
+}
+
+// record queues a finished span to be exported.
+func (t *Tracer) record(d *SpanData) {
+	t.mu.Lock()
+	if len(t.queued) >= maxQueuedSpans {
+		t.dropped++
+		t.mu.Unlock()
+		return
+	}
+	t.queued = append(t.queued, d)
+	full := len(t.queued) >= batchSize
+	t.mu.Unlock()
+
+	if full {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// StartSpan starts a span, in the trace of the first span it refers to or
+// in a new trace.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var o opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+	if o.StartTime.IsZero() {
+		o.StartTime = time.Now()
+	}
+
+	// A span prefers being the child of a span to following from one.
+	var (
+		parent      *SpanContext
+		followsFrom bool
+	)
+	for _, ref := range o.References {
+		c, ok := ref.ReferencedContext.(SpanContext)
+		if !ok || !c.TraceID.Valid() {
+			continue
+		}
+		if parent == nil || (followsFrom && ref.Type == opentracing.ChildOfRef) {
+			parent, followsFrom = &c, ref.Type == opentracing.FollowsFromRef
+		}
+	}
+
+	s := &Span{tracer: t}
+	if parent != nil {
+		s.ctx = SpanContext{
+			TraceID: parent.TraceID,
+			Sampled: parent.Sampled,
+			State:   parent.State,
+			baggage: parent.baggage,
+		}
+		s.data.ParentID = parent.SpanID
+		s.data.FollowsFrom = followsFrom
+	} else {
+		s.ctx.TraceID = t.newTraceID()
+		s.ctx.Sampled = t.sample(s.ctx.TraceID)
+	}
+	s.ctx.SpanID = t.newSpanID()
+	s.sampled = s.ctx.Sampled
+
+	s.data.TraceID = s.ctx.TraceID
+	s.data.SpanID = s.ctx.SpanID
+	s.data.OperationName = operationName
+	s.data.Start = o.StartTime
+	if s.sampled && len(o.Tags) > 0 {
+		s.data.Tags = make(map[string]interface{}, len(o.Tags))
+		for k, v := range o.Tags {
+			s.data.Tags[k] = v
+		}
+	}
+	return s
+}
+
+// sample reports whether a new trace is sampled, the decision depending on
+// its ID only.
+func (t *Tracer) sample(id TraceID) bool {
+	switch {
+	case t.SampleRatio >= 1:
+		return true
+	case t.SampleRatio <= 0:
+		return false
+	}
+	const precision = 1 << 53
+	return binary.BigEndian.Uint64(id[8:])>>11 < uint64(t.SampleRatio*precision)
+}
+
+func (t *Tracer) newTraceID() TraceID {
+	var id TraceID
+	t.idMu.Lock()
+	defer t.idMu.Unlock()
+	for !id.Valid() {
+		t.rand.Read(id[:])
+	}
+	return id
+}
+
+func (t *Tracer) newSpanID() SpanID {
+	var id SpanID
+	t.idMu.Lock()
+	defer t.idMu.Unlock()
+	for !id.Valid() {
+		t.rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,183 @@
+package tracing_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/influxdata/platform/kit/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// recorder is an exporter keeping the spans exported.
+type recorder struct {
+	spans []*tracing.SpanData
+}
+
+func (r *recorder) Export(spans []*tracing.SpanData) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *recorder) Close() error { return nil }
+
+func TestTracer_StartSpan(t *testing.T) {
+	r := &recorder{}
+	tracer := tracing.NewTracer(r)
+
+	parent := tracer.StartSpan("parent")
+	child := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()), opentracing.Tag{Key: "k", Value: 1})
+	follower := tracer.StartSpan("follower", opentracing.FollowsFrom(child.Context()))
+	follower.Finish()
+	child.LogFields(log.String("event", "done"))
+	child.Finish()
+	parent.Finish()
+	if len(r.spans) != 0 {
+		t.Fatal("expected the spans to be exported when flushed")
+	}
+	if err := tracer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(r.spans))
+	}
+	f, c, p := r.spans[0], r.spans[1], r.spans[2]
+	if p.ParentID.Valid() || c.ParentID != p.SpanID || f.ParentID != c.SpanID || !f.FollowsFrom {
+		t.Errorf("unexpected span relations %+v, %+v, %+v", p, c, f)
+	}
+	if c.TraceID != p.TraceID || f.TraceID != p.TraceID {
+		t.Error("expected the spans to be in the same trace")
+	}
+	if c.Tags["k"] != 1 || len(c.Logs) != 1 || c.Logs[0].Fields["event"] != "done" {
+		t.Errorf("unexpected tags and logs %v, %v", c.Tags, c.Logs)
+	}
+}
+
+func TestTracer_SampleRatio(t *testing.T) {
+	r := &recorder{}
+	tracer := tracing.NewTracer(r)
+	tracer.SampleRatio = 0
+
+	span := tracer.StartSpan("span")
+	h := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		t.Fatal(err)
+	}
+	span.Finish()
+	tracer.Flush()
+	if len(r.spans) != 0 || tracing.IsSampled(span) {
+		t.Error("expected the span not to be sampled")
+	}
+	if got := h.Get("traceparent"); got[len(got)-2:] != "00" {
+		t.Errorf("expected the trace to be propagated unsampled, got %q", got)
+	}
+
+	// the decision of the caller is kept.
+	h.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	wire, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	span = tracer.StartSpan("span", ext.RPCServerOption(wire))
+	span.Finish()
+	tracer.Flush()
+	if len(r.spans) != 1 || !tracing.IsSampled(span) {
+		t.Error("expected the span of a sampled trace to be sampled")
+	}
+}
+
+func TestTracer_Propagation(t *testing.T) {
+	tracer := tracing.NewTracer(&recorder{})
+
+	span := tracer.StartSpan("span")
+	span.SetBaggageItem("user", "a b")
+	state, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{
+		"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		"Tracestate":  {"vendor=value"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, want := got.(tracing.SpanContext), span.Context().(tracing.SpanContext)
+	if c.TraceID != want.TraceID || c.SpanID != want.SpanID || !c.Sampled {
+		t.Errorf("expected the context to be propagated, got %+v want %+v", c, want)
+	}
+	var baggage string
+	c.ForeachBaggageItem(func(k, v string) bool {
+		baggage = k + "=" + v
+		return true
+	})
+	if baggage != "user=a b" {
+		t.Errorf("expected the baggage to be propagated, got %q", baggage)
+	}
+	if s := state.(tracing.SpanContext); s.State != "vendor=value" || s.TraceID.String() != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("unexpected context %+v", s)
+	}
+
+	// the header of the zap tracer is still understood.
+	c2, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{
+		"Zap-Trace-Span": {`{"trace_id":"0000000000000001","span_id":"0000000000000002","baggage":{}}`},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := c2.(tracing.SpanContext).TraceID.String(); id != "00000000000000000000000000000001" {
+		t.Errorf("unexpected trace ID %s", id)
+	}
+
+	for _, traceparent := range []string{
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033-01",
+	} {
+		_, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{"Traceparent": {traceparent}}))
+		if err != opentracing.ErrSpanContextCorrupted {
+			t.Errorf("expected %q to be invalid, got %v", traceparent, err)
+		}
+	}
+	if _, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{})); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected no context, got %v", err)
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := tracing.NewTracer(tracing.NewFileExporter(&buf))
+	if err := tracer.Open(); err != nil {
+		t.Fatal(err)
+	}
+	parent := tracer.StartSpan("parent")
+	tracer.StartSpan("child", opentracing.ChildOf(parent.Context())).Finish()
+	parent.Finish()
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		var span tracing.SpanData
+		if err := json.Unmarshal(s.Bytes(), &span); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, span.OperationName)
+	}
+	if len(names) != 2 || names[0] != "child" || names[1] != "parent" {
+		t.Errorf("unexpected spans %v", names)
+	}
+}
@@ -187,6 +187,12 @@ func filterExamplesFn(filter platform.UserFilter) func(u *platform.User) bool {
 		}
 	}
 
+	if filter.OAuthID != nil {
+		return func(u *platform.User) bool {
+			return u.OAuthID == *filter.OAuthID
+		}
+	}
+
 	return func(u *platform.User) bool { return true }
 }
 
@@ -240,6 +246,10 @@ func (c *ExampleService) FindUsers(ctx context.Context, filter platform.UserFilt
 // CreateUser creates a platform example and sets b.ID.
 func (c *ExampleService) CreateUser(ctx context.Context, u *platform.User) error {
 	err := c.kv.Update(func(tx Tx) error {
+		if err := platform.ValidateNewUser(u); err != nil {
+			return err
+		}
+
 		unique := c.uniqueExampleName(ctx, tx, u)
 
 		if !unique {
@@ -363,6 +373,10 @@ func (c *ExampleService) UpdateUser(ctx context.Context, id platform.ID, upd pla
 }
 
 func (c *ExampleService) updateUser(ctx context.Context, tx Tx, id platform.ID, upd platform.UserUpdate) (*platform.User, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
 	u, err := c.findUserByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
@@ -379,8 +393,8 @@ func (c *ExampleService) updateUser(ctx context.Context, tx Tx, id platform.ID,
 		if err := idx.Delete(exampleIndexKey(u.Name)); err != nil {
 			return nil, err
 		}
-		u.Name = *upd.Name
 	}
+	upd.Apply(u)
 
 	if err := c.putUser(ctx, tx, u); err != nil {
 		return nil, err
@@ -5,7 +5,7 @@ import (
 	"io"
 	"sync"
 
-	"github.com/influxdata/platform/nats"
+	"github.com/influxdata/platform/queue"
 )
 
 // NatsServer is the mocked nats server based buffered channel.
@@ -25,7 +25,7 @@ func (s *NatsServer) initSubject(subject string) (chan io.Reader, error) {
 }
 
 // NewNats returns a mocked version of publisher, subscriber
-func NewNats() (nats.Publisher, nats.Subscriber) {
+func NewNats() (queue.Publisher, queue.Subscriber) {
 	server := &NatsServer{
 		queue: make(map[string]chan io.Reader),
 	}
@@ -58,14 +58,14 @@ type NatsSubscriber struct {
 	server *NatsServer
 }
 
-// Subscribe implements nats.Subscriber inteferface.
-func (s *NatsSubscriber) Subscribe(subject, group string, handler nats.Handler) error {
+// Subscribe implements queue.Subscriber inteferface.
+func (s *NatsSubscriber) Subscribe(subject, group string, handler queue.Handler) error {
 	ch, err := s.server.initSubject(subject)
 	if err != nil {
 		return err
 	}
 
-	go func(s *NatsSubscriber, subject string, handler nats.Handler) {
+	go func(s *NatsSubscriber, subject string, handler queue.Handler) {
 		for r := range ch {
 			handler.Process(&natsSubscription{subject: subject},
 				&natsMessage{
@@ -6,7 +6,7 @@ import (
 	"sync"
 	"testing"
 
-	"github.com/influxdata/platform/nats"
+	"github.com/influxdata/platform/queue"
 )
 
 // TestNats use the mocked nats publisher and subscriber
@@ -52,7 +52,7 @@ type fakeNatsHandler struct {
 	totalJobs chan struct{}
 }
 
-func (h *fakeNatsHandler) Process(s nats.Subscription, m nats.Message) {
+func (h *fakeNatsHandler) Process(s queue.Subscription, m queue.Message) {
 	h.Lock()
 	defer h.Unlock()
 	defer m.Ack()
@@ -1,17 +1,18 @@
 package nats
 
-import "go.uber.org/zap"
-
-type Handler interface {
-	// Process does something with a received subscription message, then acks it.
-	Process(s Subscription, m Message)
-}
+import (
+	"github.com/influxdata/platform/queue"
+	"go.uber.org/zap"
+)
 
+// LogHandler logs the messages it receives.
 type LogHandler struct {
 	Logger *zap.Logger
 }
 
-func (lh *LogHandler) Process(s Subscription, m Message) {
+var _ queue.Handler = (*LogHandler)(nil)
+
+func (lh *LogHandler) Process(s queue.Subscription, m queue.Message) {
 	lh.Logger.Info(string(m.Data()))
 	m.Ack()
 }
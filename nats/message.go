@@ -1,13 +1,11 @@
 package nats
 
 import (
+	"github.com/influxdata/platform/queue"
 	stan "github.com/nats-io/go-nats-streaming"
 )
 
-type Message interface {
-	Data() []byte
-	Ack() error
-}
+var _ queue.Message = (*message)(nil)
 
 type message struct {
 	m *stan.Msg
@@ -4,14 +4,12 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/influxdata/platform/queue"
 	stan "github.com/nats-io/go-nats-streaming"
 	"go.uber.org/zap"
 )
 
-type Publisher interface {
-	// Publish a new message to channel
-	Publish(subject string, r io.Reader) error
-}
+var _ queue.Publisher = (*AsyncPublisher)(nil)
 
 type AsyncPublisher struct {
 	ClientID   string
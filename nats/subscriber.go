@@ -1,21 +1,30 @@
 package nats
 
 import (
+	"time"
+
+	"github.com/influxdata/platform/queue"
 	stan "github.com/nats-io/go-nats-streaming"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-type Subscriber interface {
-	// Subscribe listens to a channel, handling messages with Handler
-	Subscribe(subject, group string, handler Handler) error
-}
+var _ queue.Subscriber = (*QueueSubscriber)(nil)
 
 type QueueSubscriber struct {
 	ClientID   string
 	Connection stan.Conn
+	// AckWait is how long a delivered message may go unacknowledged before
+	// it is redelivered; the server default is used when it is zero.
+	AckWait time.Duration
+
+	subs *queue.SubscriptionCollector
 }
 
 func NewQueueSubscriber(clientID string) *QueueSubscriber {
-	return &QueueSubscriber{ClientID: clientID}
+	return &QueueSubscriber{
+		ClientID: clientID,
+		subs:     queue.NewSubscriptionCollector(),
+	}
 }
 
 // Open creates and maintains a connection to NATS server
@@ -29,7 +38,7 @@ func (s *QueueSubscriber) Open() error {
 }
 
 type messageHandler struct {
-	handler Handler
+	handler queue.Handler
 	sub     subscription
 }
 
@@ -37,16 +46,27 @@ func (mh *messageHandler) handle(m *stan.Msg) {
 	mh.handler.Process(mh.sub, &message{m: m})
 }
 
-func (s *QueueSubscriber) Subscribe(subject, group string, handler Handler) error {
+func (s *QueueSubscriber) Subscribe(subject, group string, handler queue.Handler) error {
 	if s.Connection == nil {
 		return ErrNoNatsConnection
 	}
 
+	opts := []stan.SubscriptionOption{stan.DurableName(group), stan.SetManualAckMode(), stan.MaxInflight(25)}
+	if s.AckWait > 0 {
+		opts = append(opts, stan.AckWait(s.AckWait))
+	}
+
 	mh := messageHandler{handler: handler}
-	sub, err := s.Connection.QueueSubscribe(subject, group, mh.handle, stan.DurableName(group), stan.SetManualAckMode(), stan.MaxInflight(25))
+	sub, err := s.Connection.QueueSubscribe(subject, group, mh.handle, opts...)
 	if err != nil {
 		return err
 	}
 	mh.sub = subscription{sub: sub}
+	s.subs.Add(subject, group, mh.sub)
 	return nil
 }
+
+// PrometheusCollectors returns the metrics of the subscriptions.
+func (s *QueueSubscriber) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{s.subs}
+}
@@ -1,17 +1,11 @@
 package nats
 
-import stan "github.com/nats-io/go-nats-streaming"
+import (
+	"github.com/influxdata/platform/queue"
+	stan "github.com/nats-io/go-nats-streaming"
+)
 
-type Subscription interface {
-	// Pending returns the number of queued messages and queued bytes for this subscription.
-	Pending() (int64, int64, error)
-
-	// Delivered returns the number of delivered messages for this subscription.
-	Delivered() (int64, error)
-
-	// Close removes this subscriber
-	Close() error
-}
+var _ queue.Subscription = subscription{}
 
 type subscription struct {
 	sub stan.Subscription
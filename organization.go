@@ -1,11 +1,32 @@
 package platform
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 // Organization is an organization. 🎉
 type Organization struct {
 	ID   ID     `json:"id,omitempty"`
 	Name string `json:"name"`
+	// DeletedAt is when the organization was deleted. It is purged along
+	// with all its resources once the grace period of deletions has passed,
+	// unless it is restored before.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// CheckOrganizationActive returns a conflict error if the organization is
+// deleted. The data of a deleted organization isn't written nor queried, and
+// its tasks don't run, until it is restored or purged.
+func CheckOrganizationActive(o *Organization) error {
+	if o.DeletedAt == nil {
+		return nil
+	}
+	return &Error{
+		Code: EConflict,
+		Msg:  fmt.Sprintf("organization %q is deleted", o.Name),
+	}
 }
 
 // ops for orgs error and orgs op logs.
@@ -45,6 +66,25 @@ type OrganizationService interface {
 // Only fields which are set are updated.
 type OrganizationUpdate struct {
 	Name *string
+	// DeletedAt marks the organization as deleted at that time, or restores
+	// it if zero. Organizations are deleted through the
+	// OrganizationDeletionService, so it is not part of the API.
+	DeletedAt *time.Time `json:"-"`
+}
+
+// Apply applies the update to the organization.
+func (u OrganizationUpdate) Apply(o *Organization) {
+	if u.Name != nil {
+		o.Name = *u.Name
+	}
+	if u.DeletedAt != nil {
+		if u.DeletedAt.IsZero() {
+			o.DeletedAt = nil
+		} else {
+			t := *u.DeletedAt
+			o.DeletedAt = &t
+		}
+	}
 }
 
 // OrganizationFilter represents a set of filter that restrict the returned results.
@@ -0,0 +1,72 @@
+package platform
+
+import (
+	"context"
+	"time"
+)
+
+// OrganizationDeletion is the progress of the deletion of an organization
+// along with all its resources.
+type OrganizationDeletion struct {
+	OrgID   ID                         `json:"orgID"`
+	OrgName string                     `json:"orgName"`
+	Status  OrganizationDeletionStatus `json:"status"`
+	// DeletedAt is when the organization was deleted, and PurgeAt when it
+	// is, or was, purged unless restored before.
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+	// Resources counts the purged resources by kind, e.g. buckets.
+	Resources map[string]int `json:"resources,omitempty"`
+	// SeriesDeleted counts the series of the organization deleted from
+	// the storage engine so far.
+	SeriesDeleted uint64     `json:"seriesDeleted"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// OrganizationDeletionStatus is the stage of the deletion of an organization.
+type OrganizationDeletionStatus string
+
+const (
+	// OrganizationDeletionPending is the status of deleted organizations in
+	// their grace period, they can still be restored.
+	OrganizationDeletionPending OrganizationDeletionStatus = "pending"
+	// OrganizationDeletionPurging is the status of organizations whose
+	// resources are purged, and whose data is being deleted from storage.
+	OrganizationDeletionPurging OrganizationDeletionStatus = "purging"
+	// OrganizationDeletionComplete is the status of purged organizations.
+	OrganizationDeletionComplete OrganizationDeletionStatus = "complete"
+	// OrganizationDeletionFailed is the status of deletions that failed,
+	// deleting the organization again resumes them.
+	OrganizationDeletionFailed OrganizationDeletionStatus = "failed"
+)
+
+// ops for organization deletion errors.
+const (
+	OpScheduleOrganizationDeletion = "ScheduleOrganizationDeletion"
+	OpPurgeOrganization            = "PurgeOrganization"
+	OpRestoreOrganization          = "RestoreOrganization"
+	OpFindOrganizationDeletion     = "FindOrganizationDeletion"
+)
+
+// OrganizationDeletionService deletes organizations along with their
+// buckets and data, tasks, dashboards, telegraf configs, authorizations,
+// secrets, labels and user mappings.
+type OrganizationDeletionService interface {
+	// ScheduleOrganizationDeletion marks an organization as deleted; it is
+	// purged once the grace period has passed unless restored before.
+	ScheduleOrganizationDeletion(ctx context.Context, id ID) (*OrganizationDeletion, error)
+
+	// PurgeOrganization purges an organization and its resources without
+	// waiting for the grace period. Its data is deleted from storage in the
+	// background.
+	PurgeOrganization(ctx context.Context, id ID) (*OrganizationDeletion, error)
+
+	// RestoreOrganization restores an organization during the grace period
+	// of its deletion.
+	RestoreOrganization(ctx context.Context, id ID) (*Organization, error)
+
+	// FindOrganizationDeletion returns the progress of the deletion of an
+	// organization.
+	FindOrganizationDeletion(ctx context.Context, id ID) (*OrganizationDeletion, error)
+}
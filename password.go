@@ -0,0 +1,148 @@
+package platform
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordPolicy is the set of rules that passwords and sign in attempts
+// must follow. The zero value accepts any password and any number of
+// failed attempts.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters of a password.
+	MinLength int
+	// MinCharacterClasses is the minimum number of character classes
+	// (lowercase, uppercase, digits and symbols) a password must mix.
+	MinCharacterClasses int
+
+	// MaxFailedAttempts is the number of consecutive failed attempts after
+	// which a user is locked out, 0 allows unlimited attempts.
+	MaxFailedAttempts int
+	// LockoutDuration is how long a user is locked out for.
+	LockoutDuration time.Duration
+}
+
+// Validate returns an error if the password does not follow the policy.
+func (p PasswordPolicy) Validate(password string) error {
+	if n := len([]rune(password)); n < p.MinLength {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("password must be at least %d characters", p.MinLength),
+		}
+	}
+
+	if p.MinCharacterClasses > 0 {
+		var lower, upper, digit, symbol int
+		for _, r := range password {
+			switch {
+			case unicode.IsLower(r):
+				lower = 1
+			case unicode.IsUpper(r):
+				upper = 1
+			case unicode.IsDigit(r):
+				digit = 1
+			default:
+				symbol = 1
+			}
+		}
+		if lower+upper+digit+symbol < p.MinCharacterClasses {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("password must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.MinCharacterClasses),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Locks returns true if a user with failures consecutive failed attempts
+// should be locked out.
+func (p PasswordPolicy) Locks(failures int) bool {
+	return p.MaxFailedAttempts > 0 && failures >= p.MaxFailedAttempts
+}
+
+// ErrPasswordLocked is returned when a user tries to sign in while locked
+// out after too many failed attempts.
+var ErrPasswordLocked = &Error{
+	Code: EForbidden,
+	Msg:  "too many failed attempts, user is temporarily locked out",
+}
+
+// PasswordHasher hashes passwords with one algorithm. The hashes must be
+// self-describing, starting with an identifier of the algorithm and of its
+// parameters, so that they can be verified after the hasher is replaced.
+type PasswordHasher interface {
+	// Hash returns the hash of the password.
+	Hash(password string) ([]byte, error)
+	// Compare returns an error if hash is not the hash of password.
+	Compare(hash []byte, password string) error
+	// Identifies returns true if the hash was made with the algorithm of the hasher.
+	Identifies(hash []byte) bool
+	// Outdated returns true if the hash was made with other parameters than
+	// those of the hasher, such as a lower cost.
+	Outdated(hash []byte) bool
+}
+
+// PasswordHashers hashes passwords with the first of its hashers, and
+// verifies them with any of them, so that the algorithm or its cost can be
+// upgraded while passwords hashed by the previous ones are in use.
+type PasswordHashers []PasswordHasher
+
+// Hash returns the hash of the password made by the current hasher.
+func (hs PasswordHashers) Hash(password string) ([]byte, error) {
+	return hs[0].Hash(password)
+}
+
+// Compare returns an error if hash is not the hash of password.
+func (hs PasswordHashers) Compare(hash []byte, password string) error {
+	return hs[hs.hasher(hash)].Compare(hash, password)
+}
+
+// NeedsRehash returns true if the hash was not made by the current hasher
+// with its current parameters, and should be replaced on the next sign in.
+func (hs PasswordHashers) NeedsRehash(hash []byte) bool {
+	i := hs.hasher(hash)
+	return i != 0 || hs[i].Outdated(hash)
+}
+
+// hasher returns the index of the hasher that made the hash, or of the
+// current hasher if none did.
+func (hs PasswordHashers) hasher(hash []byte) int {
+	for i, h := range hs {
+		if h.Identifies(hash) {
+			return i
+		}
+	}
+	return 0
+}
+
+// BcryptHasher hashes passwords with bcrypt.
+type BcryptHasher struct {
+	Cost int
+}
+
+// Hash returns the bcrypt hash of the password.
+func (h BcryptHasher) Hash(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+}
+
+// Compare returns an error if hash is not the bcrypt hash of password.
+func (h BcryptHasher) Compare(hash []byte, password string) error {
+	return bcrypt.CompareHashAndPassword(hash, []byte(password))
+}
+
+// Identifies returns true for bcrypt hashes.
+func (h BcryptHasher) Identifies(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte("$2"))
+}
+
+// Outdated returns true if the hash has another cost than the hasher.
+func (h BcryptHasher) Outdated(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err != nil || cost != h.Cost
+}
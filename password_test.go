@@ -0,0 +1,59 @@
+package platform_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/platform"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHashers_NeedsRehash(t *testing.T) {
+	legacy := platform.BcryptHasher{Cost: bcrypt.MinCost}
+	current := platform.BcryptHasher{Cost: bcrypt.MinCost + 1}
+
+	legacyHash, err := legacy.Hash("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentHash, err := current.Hash("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		hashers platform.PasswordHashers
+		hash    []byte
+		want    bool
+	}{
+		{
+			name:    "hash of the current hasher",
+			hashers: platform.PasswordHashers{current},
+			hash:    currentHash,
+			want:    false,
+		},
+		{
+			name:    "hash with a lower cost",
+			hashers: platform.PasswordHashers{current},
+			hash:    legacyHash,
+			want:    true,
+		},
+		{
+			name:    "hash of an unknown algorithm",
+			hashers: platform.PasswordHashers{current},
+			hash:    []byte("$unknown$hello"),
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hashers.Compare(legacyHash, "hello"); err != nil {
+				t.Fatalf("expected legacy hash to be verified, got %v", err)
+			}
+			if got := tt.hashers.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/platform/models"
+	itoml "github.com/influxdata/platform/toml"
+)
+
+// Spec describes the data to generate: the series of each measurement, and
+// the values of their fields from Start to End, every Interval.
+//
+// A spec is read from TOML, for instance:
+//
+//	start    = 2018-10-01T00:00:00Z
+//	end      = 2018-10-02T00:00:00Z
+//	interval = "10s"
+//
+//	[[measurements]]
+//	name = "cpu"
+//
+//	  [[measurements.tags]]
+//	  key         = "host"
+//	  cardinality = 100
+//	  format      = "host-%s"
+//
+//	  [[measurements.fields]]
+//	  name         = "usage"
+//	  type         = "float"
+//	  distribution = "random"
+//	  scale        = 100.0
+type Spec struct {
+	Start        time.Time         `toml:"start"`
+	End          time.Time         `toml:"end"`
+	Interval     itoml.Duration    `toml:"interval"`
+	Measurements []MeasurementSpec `toml:"measurements"`
+}
+
+// MeasurementSpec describes a measurement, which has a series for every
+// combination of the values of its tags.
+type MeasurementSpec struct {
+	Name   string      `toml:"name"`
+	Tags   []TagSpec   `toml:"tags"`
+	Fields []FieldSpec `toml:"fields"`
+}
+
+// TagSpec describes a tag with Cardinality values. The values are the
+// numbers from 0, zero-padded to the same width, formatted with Format
+// which defaults to "value%s".
+type TagSpec struct {
+	Key         string `toml:"key"`
+	Cardinality int    `toml:"cardinality"`
+	Format      string `toml:"format"`
+}
+
+// The distributions of the values of a field.
+const (
+	// DistributionConstant repeats Value; it is the default.
+	DistributionConstant = "constant"
+	// DistributionRandom draws float and integer values from [0, Scale), and
+	// boolean values with even odds.
+	DistributionRandom = "random"
+	// DistributionCounter counts integer values from Value by Step, which
+	// defaults to 1.
+	DistributionCounter = "counter"
+)
+
+// FieldSpec describes a field of type float, integer, unsigned, string or
+// boolean, and the distribution of its values.
+type FieldSpec struct {
+	Name         string      `toml:"name"`
+	Type         string      `toml:"type"`
+	Distribution string      `toml:"distribution"`
+	Value        interface{} `toml:"value"`
+	Scale        float64     `toml:"scale"`
+	Step         int64       `toml:"step"`
+}
+
+// ParseSpec reads a spec in TOML from r and validates it.
+func ParseSpec(r io.Reader) (*Spec, error) {
+	var s Spec
+	if _, err := toml.DecodeReader(r, &s); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate returns an error if the spec doesn't describe any data or if a
+// field has values its type can't hold.
+func (s *Spec) Validate() error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if !s.End.After(s.Start) {
+		return fmt.Errorf("end must be after start")
+	}
+	if len(s.Measurements) == 0 {
+		return fmt.Errorf("no measurements")
+	}
+
+	for i := range s.Measurements {
+		m := &s.Measurements[i]
+		if m.Name == "" {
+			return fmt.Errorf("measurement %d has no name", i)
+		}
+		if len(m.Fields) == 0 {
+			return fmt.Errorf("measurement %q has no fields", m.Name)
+		}
+		keys := make(map[string]bool, len(m.Tags))
+		for _, t := range m.Tags {
+			if t.Key == "" {
+				return fmt.Errorf("measurement %q has a tag without key", m.Name)
+			} else if keys[t.Key] {
+				return fmt.Errorf("measurement %q has duplicate tag %q", m.Name, t.Key)
+			} else if t.Cardinality <= 0 {
+				return fmt.Errorf("tag %q of measurement %q must have a positive cardinality", t.Key, m.Name)
+			}
+			keys[t.Key] = true
+		}
+		names := make(map[string]bool, len(m.Fields))
+		for j := range m.Fields {
+			f := &m.Fields[j]
+			if f.Name == "" {
+				return fmt.Errorf("measurement %q has a field without name", m.Name)
+			} else if names[f.Name] {
+				return fmt.Errorf("measurement %q has duplicate field %q", m.Name, f.Name)
+			}
+			names[f.Name] = true
+			if err := f.validate(); err != nil {
+				return fmt.Errorf("field %q of measurement %q: %v", f.Name, m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// PointsPerSeries returns the number of values of every series.
+func (s *Spec) PointsPerSeries() int {
+	return int((s.End.Sub(s.Start) + time.Duration(s.Interval) - 1) / time.Duration(s.Interval))
+}
+
+// SeriesN returns the number of series of the spec, a series being the
+// values of a field for a set of tags.
+func (s *Spec) SeriesN() int {
+	var n int
+	for i := range s.Measurements {
+		m := &s.Measurements[i]
+		n += m.TagSetsN() * len(m.Fields)
+	}
+	return n
+}
+
+// TagSetsN returns the number of combinations of the values of the tags.
+func (m *MeasurementSpec) TagSetsN() int {
+	n := 1
+	for _, t := range m.Tags {
+		n *= t.Cardinality
+	}
+	return n
+}
+
+// NewTagsSequence returns a sequence of every combination of the values of
+// the tags, in order.
+func (m *MeasurementSpec) NewTagsSequence() TagsSequence {
+	keys := make([]string, len(m.Tags))
+	vals := make([]CountableSequence, len(m.Tags))
+	for i, t := range m.Tags {
+		format := t.Format
+		if format == "" {
+			format = "value%s"
+		}
+		keys[i] = t.Key
+		vals[i] = NewCounterByteSequence(format, 0, t.Cardinality)
+	}
+	return NewTagsValuesSequenceKeysValues(keys, vals)
+}
+
+// FieldType returns the type of the values of the field.
+func (f *FieldSpec) FieldType() models.FieldType {
+	switch f.Type {
+	case "float":
+		return models.Float
+	case "integer":
+		return models.Integer
+	case "unsigned":
+		return models.Unsigned
+	case "string":
+		return models.String
+	case "boolean":
+		return models.Boolean
+	}
+	return models.Empty
+}
+
+func (f *FieldSpec) validate() error {
+	typ := f.FieldType()
+	if typ == models.Empty {
+		return fmt.Errorf("unknown type %q", f.Type)
+	}
+
+	switch f.Distribution {
+	case "", DistributionConstant:
+		_, err := f.constant()
+		return err
+	case DistributionRandom:
+		switch typ {
+		case models.Float, models.Integer:
+			if f.Scale <= 0 || typ == models.Integer && int64(f.Scale) <= 0 {
+				return fmt.Errorf("random values need a positive scale")
+			}
+		case models.Boolean:
+		default:
+			return fmt.Errorf("random values must be float, integer or boolean")
+		}
+	case DistributionCounter:
+		if typ != models.Integer {
+			return fmt.Errorf("counter values must be integer")
+		}
+		if f.Value != nil {
+			if _, ok := f.Value.(int64); !ok {
+				return fmt.Errorf("counter must start from an integer")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", f.Distribution)
+	}
+	return nil
+}
+
+// constant returns the constant value of the field converted to its type,
+// or the zero value of the type if unset.
+func (f *FieldSpec) constant() (interface{}, error) {
+	switch f.FieldType() {
+	case models.Float:
+		switch v := f.Value.(type) {
+		case nil:
+			return float64(0), nil
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		}
+	case models.Integer:
+		switch v := f.Value.(type) {
+		case nil:
+			return int64(0), nil
+		case int64:
+			return v, nil
+		}
+	case models.Unsigned:
+		switch v := f.Value.(type) {
+		case nil:
+			return uint64(0), nil
+		case int64:
+			if v >= 0 {
+				return uint64(v), nil
+			}
+		}
+	case models.String:
+		switch v := f.Value.(type) {
+		case nil:
+			return "", nil
+		case string:
+			return v, nil
+		}
+	case models.Boolean:
+		switch v := f.Value.(type) {
+		case nil:
+			return false, nil
+		case bool:
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s value %v", f.Type, f.Value)
+}
+
+// NewValuesSequence returns the sequence of the values of a series of the
+// field described by s.
+func (f *FieldSpec) NewValuesSequence(s *Spec) ValuesSequence {
+	n, start, delta := s.PointsPerSeries(), s.Start, time.Duration(s.Interval)
+
+	switch f.Distribution {
+	case DistributionRandom:
+		switch f.FieldType() {
+		case models.Float:
+			return NewFloatRandomValuesSequence(n, start, delta, f.Scale)
+		case models.Integer:
+			return NewIntegerRandomValuesSequence(n, start, delta, int64(f.Scale))
+		case models.Boolean:
+			return NewBooleanRandomValuesSequence(n, start, delta)
+		}
+	case DistributionCounter:
+		v, _ := f.Value.(int64)
+		step := f.Step
+		if step == 0 {
+			step = 1
+		}
+		return NewIntegerCounterValuesSequence(n, start, delta, v, step)
+	}
+
+	v, err := f.constant()
+	if err != nil {
+		panic(err) // validated
+	}
+	switch v := v.(type) {
+	case float64:
+		return NewFloatConstantValuesSequence(n, start, delta, v)
+	case int64:
+		return NewIntegerConstantValuesSequence(n, start, delta, v)
+	case uint64:
+		return NewUnsignedConstantValuesSequence(n, start, delta, v)
+	case string:
+		return NewStringConstantValuesSequence(n, start, delta, v)
+	default:
+		return NewBooleanConstantValuesSequence(n, start, delta, v.(bool))
+	}
+}
@@ -0,0 +1,143 @@
+package gen_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/pkg/data/gen"
+)
+
+func TestParseSpec(t *testing.T) {
+	spec, err := gen.ParseSpec(strings.NewReader(`
+start    = 2018-10-01T00:00:00Z
+end      = 2018-10-01T00:00:25Z
+interval = "10s"
+
+[[measurements]]
+name = "cpu"
+
+  [[measurements.tags]]
+  key         = "host"
+  cardinality = 2
+  format      = "host-%s"
+
+  [[measurements.tags]]
+  key         = "dc"
+  cardinality = 3
+
+  [[measurements.fields]]
+  name         = "requests"
+  type         = "integer"
+  distribution = "counter"
+  value        = 10
+  step         = 5
+
+  [[measurements.fields]]
+  name  = "up"
+  type  = "boolean"
+  value = true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := spec.PointsPerSeries(); n != 3 {
+		t.Errorf("expected 3 points per series, got %d", n)
+	}
+	if n := spec.SeriesN(); n != 12 {
+		t.Errorf("expected 12 series, got %d", n)
+	}
+
+	m := &spec.Measurements[0]
+	var tags []string
+	for seq := m.NewTagsSequence(); seq.Next(); {
+		tags = append(tags, string(seq.Value().HashKey()))
+	}
+	if len(tags) != 6 || tags[0] != ",dc=value0,host=host-0" || tags[1] != ",dc=value0,host=host-1" || tags[5] != ",dc=value2,host=host-1" {
+		t.Errorf("unexpected tag sets %v", tags)
+	}
+
+	seq := m.Fields[0].NewValuesSequence(spec)
+	if !seq.Next() {
+		t.Fatal("expected values")
+	}
+	v := seq.Values().(*gen.IntegerArray)
+	start := spec.Start.UnixNano()
+	if len(v.Values) != 3 || v.Values[0] != 10 || v.Values[2] != 20 {
+		t.Errorf("unexpected values %v", v.Values)
+	}
+	if v.Timestamps[0] != start || v.Timestamps[2] != start+int64(20*time.Second) {
+		t.Errorf("unexpected timestamps %v", v.Timestamps)
+	}
+	if seq.Next() {
+		t.Error("expected a single block")
+	}
+
+	b := m.Fields[1].NewValuesSequence(spec)
+	if !b.Next() {
+		t.Fatal("expected values")
+	}
+	if v := b.Values().(*gen.BooleanArray); v.Timestamps[1] != start+int64(10*time.Second) || !v.Values[1] {
+		t.Errorf("unexpected values %v at %v", v.Values, v.Timestamps)
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "no interval",
+			spec: `start = 2018-10-01T00:00:00Z
+end = 2018-10-02T00:00:00Z`,
+			err: "interval must be positive",
+		},
+		{
+			name: "unknown type",
+			spec: `start = 2018-10-01T00:00:00Z
+end = 2018-10-02T00:00:00Z
+interval = "1m"
+[[measurements]]
+name = "m"
+  [[measurements.fields]]
+  name = "f"
+  type = "double"`,
+			err: `unknown type "double"`,
+		},
+		{
+			name: "invalid constant",
+			spec: `start = 2018-10-01T00:00:00Z
+end = 2018-10-02T00:00:00Z
+interval = "1m"
+[[measurements]]
+name = "m"
+  [[measurements.fields]]
+  name = "f"
+  type = "unsigned"
+  value = -1`,
+			err: "invalid unsigned value -1",
+		},
+		{
+			name: "random string",
+			spec: `start = 2018-10-01T00:00:00Z
+end = 2018-10-02T00:00:00Z
+interval = "1m"
+[[measurements]]
+name = "m"
+  [[measurements.fields]]
+  name = "f"
+  type = "string"
+  distribution = "random"`,
+			err: "random values must be float, integer or boolean",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gen.ParseSpec(strings.NewReader(tc.spec))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -58,7 +58,7 @@ func (g *FloatConstantValuesSequence) Next() bool {
 		d  = g.state.d
 	)
 	for i := 0; i < len(ts) && i < len(vs); i++ {
-		ts[i] = g.t
+		ts[i] = t
 		vs[i] = g.state.v
 		t += d
 	}
@@ -117,7 +117,7 @@ func (g *IntegerConstantValuesSequence) Next() bool {
 		d  = g.state.d
 	)
 	for i := 0; i < len(ts) && i < len(vs); i++ {
-		ts[i] = g.t
+		ts[i] = t
 		vs[i] = g.state.v
 		t += d
 	}
@@ -176,7 +176,7 @@ func (g *UnsignedConstantValuesSequence) Next() bool {
 		d  = g.state.d
 	)
 	for i := 0; i < len(ts) && i < len(vs); i++ {
-		ts[i] = g.t
+		ts[i] = t
 		vs[i] = g.state.v
 		t += d
 	}
@@ -235,7 +235,7 @@ func (g *StringConstantValuesSequence) Next() bool {
 		d  = g.state.d
 	)
 	for i := 0; i < len(ts) && i < len(vs); i++ {
-		ts[i] = g.t
+		ts[i] = t
 		vs[i] = g.state.v
 		t += d
 	}
@@ -294,7 +294,7 @@ func (g *BooleanConstantValuesSequence) Next() bool {
 		d  = g.state.d
 	)
 	for i := 0; i < len(ts) && i < len(vs); i++ {
-		ts[i] = g.t
+		ts[i] = t
 		vs[i] = g.state.v
 		t += d
 	}
@@ -58,3 +58,168 @@ func (g *FloatRandomValuesSequence) Next() bool {
 func (g *FloatRandomValuesSequence) Values() Values {
 	return &g.vals
 }
+
+type IntegerRandomValuesSequence struct {
+	buf   IntegerArray
+	vals  IntegerArray
+	n     int
+	t     int64
+	state struct {
+		n   int
+		t   int64
+		d   int64
+		max int64
+	}
+}
+
+// NewIntegerRandomValuesSequence returns a sequence of n random values in
+// [0, max), starting at start and spaced by delta.
+func NewIntegerRandomValuesSequence(n int, start time.Time, delta time.Duration, max int64) *IntegerRandomValuesSequence {
+	g := &IntegerRandomValuesSequence{
+		buf: *NewIntegerArrayLen(cursors.DefaultMaxPointsPerBlock),
+	}
+	g.state.n = n
+	g.state.t = start.UnixNano()
+	g.state.d = int64(delta)
+	g.state.max = max
+	g.Reset()
+	return g
+}
+
+func (g *IntegerRandomValuesSequence) Reset() {
+	g.n = g.state.n
+	g.t = g.state.t
+}
+
+func (g *IntegerRandomValuesSequence) Next() bool {
+	if g.n == 0 {
+		return false
+	}
+
+	c := min(g.n, cursors.DefaultMaxPointsPerBlock)
+	g.n -= c
+	g.vals.Timestamps = g.buf.Timestamps[:0]
+	g.vals.Values = g.buf.Values[:0]
+
+	for i := 0; i < c; i++ {
+		g.vals.Timestamps = append(g.vals.Timestamps, g.t)
+		g.vals.Values = append(g.vals.Values, rand.Int63n(g.state.max))
+		g.t += g.state.d
+	}
+	return true
+}
+
+func (g *IntegerRandomValuesSequence) Values() Values {
+	return &g.vals
+}
+
+type IntegerCounterValuesSequence struct {
+	buf   IntegerArray
+	vals  IntegerArray
+	n     int
+	t     int64
+	v     int64
+	state struct {
+		n    int
+		t    int64
+		d    int64
+		v    int64
+		step int64
+	}
+}
+
+// NewIntegerCounterValuesSequence returns a sequence of n values counting
+// from v by step, starting at start and spaced by delta.
+func NewIntegerCounterValuesSequence(n int, start time.Time, delta time.Duration, v, step int64) *IntegerCounterValuesSequence {
+	g := &IntegerCounterValuesSequence{
+		buf: *NewIntegerArrayLen(cursors.DefaultMaxPointsPerBlock),
+	}
+	g.state.n = n
+	g.state.t = start.UnixNano()
+	g.state.d = int64(delta)
+	g.state.v = v
+	g.state.step = step
+	g.Reset()
+	return g
+}
+
+func (g *IntegerCounterValuesSequence) Reset() {
+	g.n = g.state.n
+	g.t = g.state.t
+	g.v = g.state.v
+}
+
+func (g *IntegerCounterValuesSequence) Next() bool {
+	if g.n == 0 {
+		return false
+	}
+
+	c := min(g.n, cursors.DefaultMaxPointsPerBlock)
+	g.n -= c
+	g.vals.Timestamps = g.buf.Timestamps[:0]
+	g.vals.Values = g.buf.Values[:0]
+
+	for i := 0; i < c; i++ {
+		g.vals.Timestamps = append(g.vals.Timestamps, g.t)
+		g.vals.Values = append(g.vals.Values, g.v)
+		g.t += g.state.d
+		g.v += g.state.step
+	}
+	return true
+}
+
+func (g *IntegerCounterValuesSequence) Values() Values {
+	return &g.vals
+}
+
+type BooleanRandomValuesSequence struct {
+	buf   BooleanArray
+	vals  BooleanArray
+	n     int
+	t     int64
+	state struct {
+		n int
+		t int64
+		d int64
+	}
+}
+
+// NewBooleanRandomValuesSequence returns a sequence of n random values,
+// starting at start and spaced by delta.
+func NewBooleanRandomValuesSequence(n int, start time.Time, delta time.Duration) *BooleanRandomValuesSequence {
+	g := &BooleanRandomValuesSequence{
+		buf: *NewBooleanArrayLen(cursors.DefaultMaxPointsPerBlock),
+	}
+	g.state.n = n
+	g.state.t = start.UnixNano()
+	g.state.d = int64(delta)
+	g.Reset()
+	return g
+}
+
+func (g *BooleanRandomValuesSequence) Reset() {
+	g.n = g.state.n
+	g.t = g.state.t
+}
+
+func (g *BooleanRandomValuesSequence) Next() bool {
+	if g.n == 0 {
+		return false
+	}
+
+	c := min(g.n, cursors.DefaultMaxPointsPerBlock)
+	g.n -= c
+	g.vals.Timestamps = g.buf.Timestamps[:0]
+	g.vals.Values = g.buf.Values[:0]
+
+	for i := 0; i < c; i++ {
+		g.vals.Timestamps = append(g.vals.Timestamps, g.t)
+		g.vals.Values = append(g.vals.Values, rand.Int63()&1 == 1)
+		g.t += g.state.d
+	}
+	return true
+}
+
+func (g *BooleanRandomValuesSequence) Values() Values {
+	return &g.vals
+}
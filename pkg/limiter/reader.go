@@ -0,0 +1,42 @@
+package limiter
+
+import (
+	"context"
+	"io"
+)
+
+type Reader struct {
+	r       io.Reader
+	limiter Rate
+	burst   int
+	ctx     context.Context
+}
+
+// NewReader returns a reader that implements io.Reader with rate limiting.
+// The limiter use a token bucket approach and limits the rate to bytesPerSec
+// with a maximum burst of burstLimit.
+func NewReader(ctx context.Context, r io.Reader, bytesPerSec, burstLimit int) *Reader {
+	return &Reader{
+		r:       r,
+		limiter: NewRate(bytesPerSec, burstLimit),
+		burst:   burstLimit,
+		ctx:     ctx,
+	}
+}
+
+// Read reads at most burstLimit bytes into b.
+func (s *Reader) Read(b []byte) (int, error) {
+	if len(b) > s.burst {
+		b = b[:s.burst]
+	}
+
+	n, err := s.r.Read(b)
+	if n == 0 {
+		return n, err
+	}
+
+	if err := s.limiter.WaitN(s.ctx, n); err != nil {
+		return n, err
+	}
+	return n, err
+}
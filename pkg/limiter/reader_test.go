@@ -0,0 +1,32 @@
+package limiter_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/pkg/limiter"
+)
+
+func TestReader_Limited(t *testing.T) {
+	limit := 512 * 1024
+	r := limiter.NewReader(context.Background(), bytes.NewReader(bytes.Repeat([]byte{0}, 1024*1024)), limit, 64*1024)
+
+	start := time.Now()
+	n, err := io.Copy(ioutil.Discard, r)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Error("copy error: ", err)
+	}
+	if n != 1024*1024 {
+		t.Errorf("expected the whole input to be read, got %d bytes", n)
+	}
+
+	rate := float64(n) / elapsed.Seconds()
+	if rate > float64(limit) {
+		t.Errorf("rate limit mismatch: exp %f, got %f", float64(limit), rate)
+	}
+}
@@ -131,3 +131,17 @@ func (s *AuthorizationService) PrometheusCollectors() []prometheus.Collector {
 		s.requestDuration,
 	}
 }
+
+// RotateAuthorization issues a new token for the authorization, records function call latency, and counts function calls.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (a *platform.Authorization, err error) {
+	defer func(start time.Time) {
+		labels := prometheus.Labels{
+			"method": "RotateAuthorization",
+			"error":  fmt.Sprint(err != nil),
+		}
+		s.requestCount.With(labels).Add(1)
+		s.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, grace)
+}
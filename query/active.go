@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+)
+
+// States of the active queries, besides the states of the Flux controller.
+const (
+	// QueuedState is the state of the queries waiting for the other queries
+	// of their organization to finish.
+	QueuedState = "queued"
+)
+
+// ActiveQuery is a query queued or executing.
+type ActiveQuery struct {
+	ID             platform.ID `json:"id"`
+	OrganizationID platform.ID `json:"orgID"`
+	// UserID and AuthorizationID identify the token the query was submitted
+	// with, if any.
+	UserID          platform.ID `json:"userID,omitempty"`
+	AuthorizationID platform.ID `json:"authorizationID,omitempty"`
+	// Query is the text of the query, or the type of its compiler if it has
+	// none.
+	Query     string    `json:"query"`
+	StartedAt time.Time `json:"startedAt"`
+	State     string    `json:"state"`
+	// MemoryBytes is the most memory the query allocated so far.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// ActiveQueryFilter represents a set of filters that restrict the returned
+// active queries.
+type ActiveQueryFilter struct {
+	OrganizationID *platform.ID
+}
+
+// ops for active query errors.
+const (
+	OpFindActiveQueries   = "FindActiveQueries"
+	OpFindActiveQueryByID = "FindActiveQueryByID"
+	OpCancelActiveQuery   = "CancelActiveQuery"
+)
+
+// ActiveQueryService lists and cancels the queries in flight.
+type ActiveQueryService interface {
+	// FindActiveQueries returns the active queries matching filter, oldest
+	// first.
+	FindActiveQueries(ctx context.Context, filter ActiveQueryFilter) ([]*ActiveQuery, error)
+
+	// FindActiveQueryByID returns a single active query by ID.
+	FindActiveQueryByID(ctx context.Context, id platform.ID) (*ActiveQuery, error)
+
+	// CancelActiveQuery cancels a query, whether it is queued or executing.
+	CancelActiveQuery(ctx context.Context, id platform.ID) error
+}
+
+// CompilerQuery returns the text of the query compiled by c, or the type of
+// c if it has none.
+func CompilerQuery(c flux.Compiler) string {
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case *lang.FluxCompiler:
+		return c.Query
+	case nil:
+		return ""
+	}
+	return "<" + string(c.CompilerType()) + ">"
+}
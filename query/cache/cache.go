@@ -0,0 +1,147 @@
+// Package cache caches the results of Flux queries, so that the queries
+// repeated by dashboards and their viewers don't execute again until the
+// data they read changes.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Cache holds the results of queries, evicting the least recently used
+// results past its size.
+type Cache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+	// pending are the entries of the queries executing, which are not
+	// cached if the data they read changes meanwhile.
+	pending map[*entry]struct{}
+
+	metrics *metrics
+}
+
+// entry is the result of a query, along with what the query read.
+type entry struct {
+	key   string
+	orgID platform.ID
+	// buckets are the buckets the query read, or nil if it may have read any
+	// bucket of its organization.
+	buckets     map[platform.ID]bool
+	start, stop time.Time
+	expires     time.Time
+
+	result  []byte
+	changed bool
+}
+
+// reads reports whether the query of e read the bucket between min and max.
+func (e *entry) reads(orgID, bucketID platform.ID, min, max time.Time) bool {
+	if e.orgID != orgID {
+		return false
+	}
+	if e.buckets != nil && !e.buckets[bucketID] {
+		return false
+	}
+	return !max.Before(e.start) && !min.After(e.stop)
+}
+
+// New returns a cache holding at most maxBytes of results.
+func New(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		pending:  make(map[*entry]struct{}),
+		metrics:  newMetrics(),
+	}
+}
+
+// get returns the result cached for a key, if it hasn't expired at now.
+func (c *Cache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e.result, true
+}
+
+// begin records that the query of e is executing.
+func (c *Cache) begin(e *entry) {
+	c.mu.Lock()
+	c.pending[e] = struct{}{}
+	c.mu.Unlock()
+}
+
+// end caches the result of the query of e if it succeeded, unless the data
+// it read changed while it executed or the result doesn't fit.
+func (c *Cache) end(e *entry, result []byte, succeeded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, e)
+	if !succeeded || e.changed || int64(len(result)) > c.maxBytes {
+		return
+	}
+
+	if el, ok := c.entries[e.key]; ok {
+		c.remove(el)
+	}
+	e.result = result
+	c.entries[e.key] = c.lru.PushFront(e)
+	c.size += int64(len(result))
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+		c.metrics.evictions.Inc()
+	}
+	c.metrics.size.Set(float64(c.size))
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.result))
+	c.metrics.size.Set(float64(c.size))
+}
+
+// Invalidate drops the results of the queries that read a bucket between
+// min and max, after data was written there.
+func (c *Cache) Invalidate(orgID, bucketID platform.ID, min, max time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for e := range c.pending {
+		if e.reads(orgID, bucketID, min, max) {
+			e.changed = true
+		}
+	}
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*entry).reads(orgID, bucketID, min, max) {
+			c.remove(el)
+			c.metrics.invalidations.Inc()
+		}
+		el = next
+	}
+}
+
+// PrometheusCollectors satisifies the prom.PrometheusCollector interface.
+func (c *Cache) PrometheusCollectors() []prometheus.Collector {
+	return c.metrics.PrometheusCollectors()
+}
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/tsdb"
+)
+
+func TestAnalyze(t *testing.T) {
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	a, ok := analyze(`from(bucket:"telegraf")|>range(start:-1h)`, now)
+	if !ok {
+		t.Fatal("expected the query to be cacheable")
+	}
+	b, _ := analyze(`from(bucket: "telegraf")
+	|> range(start: -1h)`, now)
+	if a.query != b.query {
+		t.Errorf("expected queries formatted differently to be normalized the same, got %q and %q", a.query, b.query)
+	}
+	if len(a.buckets) != 1 || a.buckets[0] != "telegraf" {
+		t.Errorf("unexpected buckets %v", a.buckets)
+	}
+	if !a.start.Equal(now.Add(-time.Hour)) || !a.stop.Equal(now) {
+		t.Errorf("unexpected bounds %v, %v", a.start, a.stop)
+	}
+
+	// months and years aren't truncated to whole weeks.
+	a, _ = analyze(`from(bucket: "telegraf") |> range(start: -1y, stop: -1mo)`, now)
+	if month := 730*time.Hour + 30*time.Minute; !a.start.Equal(now.Add(-12*month)) || !a.stop.Equal(now.Add(-month)) {
+		t.Errorf("unexpected bounds %v, %v", a.start, a.stop)
+	}
+
+	a, _ = analyze(`from(bucketID: "0000000000000001") |> range(start: 2018-10-01T00:00:00Z, stop: 1d)`, now)
+	if len(a.bucketIDs) != 1 || a.start.Year() != 2018 || !a.stop.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("unexpected analysis %+v", a)
+	}
+
+	// the bounds of a range that isn't literal are unknown.
+	a, _ = analyze(`start = -1h
+from(bucket: "telegraf") |> range(start: start)`, now)
+	if !a.start.Equal(minTime) || !a.stop.Equal(maxTime) {
+		t.Errorf("expected unbounded times, got %v, %v", a.start, a.stop)
+	}
+
+	if _, ok := analyze(`from(bucket: "a") |> range(start: -1h) |> to(bucket: "b")`, now); ok {
+		t.Error("expected a query writing not to be cacheable")
+	}
+}
+
+// countingService writes the number of queries it executed.
+type countingService struct {
+	queries []string
+}
+
+func (s *countingService) Query(ctx context.Context, w io.Writer, req *query.ProxyRequest) (int64, error) {
+	s.queries = append(s.queries, req.Request.Compiler.(lang.FluxCompiler).Query)
+	n, err := fmt.Fprintf(w, "result %d", len(s.queries))
+	return int64(n), err
+}
+
+func TestProxyQueryService(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	telegraf := &platform.Bucket{Name: "telegraf", OrganizationID: o.ID}
+	other := &platform.Bucket{Name: "other", OrganizationID: o.ID}
+	for _, b := range []*platform.Bucket{telegraf, other} {
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2018, 11, 1, 12, 0, 5, 0, time.UTC)
+	c := New(1024)
+	queries := &countingService{}
+	s := &ProxyQueryService{
+		ProxyQueryService: queries,
+		Cache:             c,
+		BucketService:     svc,
+		TTL:               10 * time.Second,
+		Now:               func() time.Time { return now },
+	}
+	w := &PointsWriter{PointsWriter: &mock.PointsWriter{}, Cache: c}
+
+	run := func(ctx context.Context) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if _, err := s.Query(ctx, &buf, &query.ProxyRequest{
+			Request: query.Request{
+				OrganizationID: o.ID,
+				Compiler:       lang.FluxCompiler{Query: `from(bucket: "telegraf") |> range(start: -1m)`},
+			},
+			Dialect: csv.DefaultDialect(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	write := func(b *platform.Bucket, at time.Time) {
+		t.Helper()
+		p, err := models.NewPoint("cpu", nil, models.Fields{"v": 1.0}, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ps, err := tsdb.ExplodePoints(o.ID, b.ID, []models.Point{p})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WritePoints(ps); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := run(ctx); got != "result 1" {
+		t.Fatalf("unexpected result %q", got)
+	}
+	if got := run(ctx); got != "result 1" {
+		t.Fatalf("expected the result to be cached, got %q", got)
+	}
+	if q := queries.queries[0]; q != "option now = () =>\n\t(2018-11-01T12:00:00Z)\n\nfrom(bucket: \"telegraf\")\n\t|> range(start: -1m)" {
+		t.Errorf("expected the query to be evaluated at the start of the window, got %q", q)
+	}
+
+	if got := run(ContextWithControl(ctx, Control{Bypass: true})); got != "result 2" {
+		t.Fatalf("expected the cache to be bypassed, got %q", got)
+	}
+
+	// writes elsewhere keep the result.
+	write(other, now)
+	write(telegraf, now.Add(-time.Hour))
+	if got := run(ctx); got != "result 1" {
+		t.Fatalf("expected the result to be kept, got %q", got)
+	}
+	write(telegraf, now.Add(-5*time.Second))
+	if got := run(ctx); got != "result 3" {
+		t.Fatalf("expected the result to be invalidated, got %q", got)
+	}
+
+	now = now.Add(10 * time.Second)
+	if got := run(ctx); got != "result 4" {
+		t.Fatalf("expected the result to expire with its window, got %q", got)
+	}
+	if got := run(ContextWithControl(ctx, Control{TTL: time.Minute})); got != "result 5" {
+		t.Fatalf("expected a new window for another TTL, got %q", got)
+	}
+
+	// the TTL of requests is capped, so that they can't pin now.
+	s.MaxTTL = time.Minute
+	if got := run(ContextWithControl(ctx, Control{TTL: 24 * time.Hour})); got != "result 5" {
+		t.Fatalf("expected the TTL to be capped to the window of a minute, got %q", got)
+	}
+	now = now.Add(time.Minute)
+	if got := run(ContextWithControl(ctx, Control{TTL: 24 * time.Hour})); got != "result 6" {
+		t.Fatalf("expected the result to expire with the capped window, got %q", got)
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	c := New(10)
+	expires := time.Unix(100, 0)
+	for _, key := range []string{"a", "b", "c"} {
+		c.end(&entry{key: key, expires: expires}, []byte("12345"), true)
+	}
+	if _, ok := c.get("a", time.Unix(0, 0)); ok {
+		t.Error("expected the least recently used result to be evicted")
+	}
+	if _, ok := c.get("c", time.Unix(0, 0)); !ok {
+		t.Error("expected the last result to be cached")
+	}
+	c.end(&entry{key: "big", expires: expires}, make([]byte, 11), true)
+	if _, ok := c.get("big", time.Unix(0, 0)); ok {
+		t.Error("expected a result bigger than the cache not to be cached")
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// orgLabel is the metric label of the organization of a query.
+const orgLabel = "org"
+
+type metrics struct {
+	hits          *prometheus.CounterVec
+	misses        *prometheus.CounterVec
+	invalidations prometheus.Counter
+	evictions     prometheus.Counter
+	size          prometheus.Gauge
+}
+
+func newMetrics() *metrics {
+	const (
+		namespace = "query"
+		subsystem = "cache"
+	)
+
+	return &metrics{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "hits_total",
+			Help:      "Number of queries of an organization answered from the cache",
+		}, []string{orgLabel}),
+
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "misses_total",
+			Help:      "Number of cacheable queries of an organization executed",
+		}, []string{orgLabel}),
+
+		invalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "invalidations_total",
+			Help:      "Number of results dropped because data was written where their query read",
+		}),
+
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "evictions_total",
+			Help:      "Number of results dropped to make room for others",
+		}),
+
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "size_bytes",
+			Help:      "Size of the results cached",
+		}),
+	}
+}
+
+// PrometheusCollectors satisifies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.hits,
+		m.misses,
+		m.invalidations,
+		m.evictions,
+		m.size,
+	}
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// analysis is what a Flux query reads, as far as can be told without
+// executing it.
+type analysis struct {
+	// query is the normalized query, evaluated at the aligned now.
+	query string
+	// buckets are the names and IDs of the buckets read, or nil if they
+	// can't be told.
+	buckets   []string
+	bucketIDs []string
+	// start and stop bound the times read.
+	start, stop time.Time
+}
+
+var (
+	minTime = time.Unix(0, math.MinInt64).UTC()
+	maxTime = time.Unix(0, math.MaxInt64).UTC()
+)
+
+// analyze parses a Flux query to be evaluated at now. The query is
+// normalized so that queries formatted differently share their results, and
+// now is made explicit so that the results are the same wherever they are
+// computed. It returns false if the query can't be cached.
+func analyze(q string, now time.Time) (*analysis, bool) {
+	program, err := parser.NewAST(q)
+	if err != nil {
+		return nil, false
+	}
+
+	a := &analysis{
+		start: maxTime,
+		stop:  minTime,
+	}
+	bucketsKnown, rangeKnown, cacheable := true, true, true
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok {
+			return
+		}
+		callee, ok := call.Callee.(*ast.Identifier)
+		if !ok {
+			return
+		}
+		switch callee.Name {
+		case "to":
+			// the query writes, so it must execute every time.
+			cacheable = false
+		case "from":
+			bucket, bucketID := stringProperty(call, "bucket"), stringProperty(call, "bucketID")
+			switch {
+			case bucket != "":
+				a.buckets = append(a.buckets, bucket)
+			case bucketID != "":
+				a.bucketIDs = append(a.bucketIDs, bucketID)
+			default:
+				bucketsKnown = false
+			}
+		case "range":
+			start, ok := timeProperty(call, "start", now)
+			if !ok {
+				rangeKnown = false
+				return
+			}
+			stop, ok := now, true
+			if property(call, "stop") != nil {
+				stop, ok = timeProperty(call, "stop", now)
+			}
+			if !ok {
+				rangeKnown = false
+				return
+			}
+			if start.Before(a.start) {
+				a.start = start
+			}
+			if stop.After(a.stop) {
+				a.stop = stop
+			}
+		}
+	}), program)
+	if !cacheable {
+		return nil, false
+	}
+
+	if !bucketsKnown || (len(a.buckets) == 0 && len(a.bucketIDs) == 0) {
+		a.buckets, a.bucketIDs = nil, nil
+	}
+	if !rangeKnown || a.start.After(a.stop) {
+		a.start, a.stop = minTime, maxTime
+	}
+
+	program.Body = append([]ast.Statement{&ast.OptionStatement{
+		Assignment: &ast.VariableAssignment{
+			ID: &ast.Identifier{Name: "now"},
+			Init: &ast.FunctionExpression{
+				Body: &ast.DateTimeLiteral{Value: now},
+			},
+		},
+	}}, program.Body...)
+	a.query = ast.Format(program)
+	return a, true
+}
+
+// property returns the value of a property of the object argument of a call.
+func property(call *ast.CallExpression, key string) ast.Expression {
+	if len(call.Arguments) != 1 {
+		return nil
+	}
+	obj, ok := call.Arguments[0].(*ast.ObjectExpression)
+	if !ok {
+		return nil
+	}
+	for _, p := range obj.Properties {
+		if p.Key.Name == key {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+func stringProperty(call *ast.CallExpression, key string) string {
+	if s, ok := property(call, key).(*ast.StringLiteral); ok {
+		return s.Value
+	}
+	return ""
+}
+
+// timeProperty returns the time of a property that is either a date time or
+// a duration relative to now.
+func timeProperty(call *ast.CallExpression, key string, now time.Time) (time.Time, bool) {
+	switch v := property(call, key).(type) {
+	case *ast.DateTimeLiteral:
+		return v.Value, true
+	case *ast.DurationLiteral:
+		d, ok := duration(v)
+		return now.Add(d), ok
+	case *ast.UnaryExpression:
+		if lit, ok := v.Argument.(*ast.DurationLiteral); ok && v.Operator == ast.SubtractionOperator {
+			d, ok := duration(lit)
+			return now.Add(-d), ok
+		}
+	}
+	return time.Time{}, false
+}
+
+// duration returns the length of a duration literal, approximating months
+// and years the way Flux does.
+func duration(lit *ast.DurationLiteral) (time.Duration, bool) {
+	var d time.Duration
+	for _, v := range lit.Values {
+		mag, unit := v.Magnitude, v.Unit
+		switch unit {
+		case "y", "mo":
+			// months aren't a whole number of hours, so they are computed
+			// in float hours rather than truncated.
+			const hoursPerMonth = 365.25 / 12 * 24
+			months := float64(mag)
+			if unit == "y" {
+				months *= 12
+			}
+			d += time.Duration(months * hoursPerMonth * float64(time.Hour))
+			continue
+		case "w":
+			mag, unit = mag*7*24, "h"
+		case "d":
+			mag, unit = mag*24, "h"
+		}
+		dd, err := time.ParseDuration(strconv.FormatInt(mag, 10) + unit)
+		if err != nil {
+			return 0, false
+		}
+		d += dd
+	}
+	return d, true
+}
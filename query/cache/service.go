@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// Control is how a request uses the cache.
+type Control struct {
+	// Bypass executes the query even if its result is cached, and doesn't
+	// cache its result.
+	Bypass bool
+	// TTL is how long the result of the query is cached; the default TTL of
+	// the service applies if it is zero.
+	TTL time.Duration
+}
+
+type controlContextKey struct{}
+
+// ContextWithControl returns a new context with the control of the cache
+// for a request.
+func ContextWithControl(ctx context.Context, c Control) context.Context {
+	return context.WithValue(ctx, controlContextKey{}, c)
+}
+
+// ControlFromContext retrieves the control of the cache from a context.
+// If no control exists on the context the zero control is returned.
+func ControlFromContext(ctx context.Context) Control {
+	c, _ := ctx.Value(controlContextKey{}).(Control)
+	return c
+}
+
+var _ query.ProxyQueryService = (*ProxyQueryService)(nil)
+
+// ProxyQueryService answers the Flux queries from the cache when it can,
+// and caches the results of the others.
+//
+// The results are cached for windows of the TTL: the queries are evaluated
+// at the start of the window they are made in, so that all the queries of a
+// window share the same result.
+type ProxyQueryService struct {
+	ProxyQueryService query.ProxyQueryService
+	Cache             *Cache
+	BucketService     platform.BucketService
+
+	// TTL is how long results are cached by default; the results are only
+	// cached for the requests setting a TTL if it is zero.
+	TTL time.Duration
+	Now func() time.Time
+
+	// MaxTTL caps the TTL set by requests, which is also how long their
+	// queries are evaluated at the same now; defaults to DefaultMaxTTL.
+	MaxTTL time.Duration
+}
+
+// DefaultMaxTTL is the longest TTL that requests can set by default.
+const DefaultMaxTTL = time.Hour
+
+func (s *ProxyQueryService) maxTTL() time.Duration {
+	if s.MaxTTL == 0 {
+		return DefaultMaxTTL
+	}
+	return s.MaxTTL
+}
+
+func (s *ProxyQueryService) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// Query writes the result of a query to w, from the cache if it is cached.
+func (s *ProxyQueryService) Query(ctx context.Context, w io.Writer, req *query.ProxyRequest) (int64, error) {
+	control := ControlFromContext(ctx)
+	ttl := control.TTL
+	if max := s.maxTTL(); ttl > max {
+		ttl = max
+	}
+	if ttl == 0 {
+		ttl = s.TTL
+	}
+	var q string
+	switch c := req.Request.Compiler.(type) {
+	case lang.FluxCompiler:
+		q = c.Query
+	case *lang.FluxCompiler:
+		q = c.Query
+	}
+	if control.Bypass || ttl <= 0 || q == "" {
+		return s.ProxyQueryService.Query(ctx, w, req)
+	}
+
+	now := s.now()
+	window := now.Truncate(ttl)
+	a, ok := analyze(q, window)
+	if !ok {
+		return s.ProxyQueryService.Query(ctx, w, req)
+	}
+	orgID := req.Request.OrganizationID
+	key, err := cacheKey(orgID, req, a.query)
+	if err != nil {
+		return s.ProxyQueryService.Query(ctx, w, req)
+	}
+
+	if result, ok := s.Cache.get(key, now); ok {
+		s.Cache.metrics.hits.WithLabelValues(orgID.String()).Inc()
+		n, err := w.Write(result)
+		return int64(n), err
+	}
+	s.Cache.metrics.misses.WithLabelValues(orgID.String()).Inc()
+
+	e := &entry{
+		key:     key,
+		orgID:   orgID,
+		buckets: s.buckets(ctx, orgID, a),
+		start:   a.start,
+		stop:    a.stop,
+		expires: window.Add(ttl),
+	}
+	s.Cache.begin(e)
+	buf := &limitedBuffer{max: s.Cache.maxBytes}
+	r := *req
+	r.Request.Compiler = lang.FluxCompiler{Query: a.query}
+	n, err := s.ProxyQueryService.Query(ctx, io.MultiWriter(w, buf), &r)
+	s.Cache.end(e, buf.Bytes(), err == nil && !buf.full)
+	return n, err
+}
+
+// buckets returns the IDs of the buckets a query reads, or nil if they
+// can't all be found.
+func (s *ProxyQueryService) buckets(ctx context.Context, orgID platform.ID, a *analysis) map[platform.ID]bool {
+	if a.buckets == nil && a.bucketIDs == nil {
+		return nil
+	}
+
+	ids := make(map[platform.ID]bool, len(a.buckets)+len(a.bucketIDs))
+	for _, name := range a.buckets {
+		name := name
+		b, err := s.BucketService.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			Name:           &name,
+		})
+		if err != nil {
+			return nil
+		}
+		ids[b.ID] = true
+	}
+	for _, bucketID := range a.bucketIDs {
+		id, err := platform.IDFromString(bucketID)
+		if err != nil {
+			return nil
+		}
+		ids[*id] = true
+	}
+	return ids
+}
+
+// cacheKey identifies the result of a query of an organization in the
+// dialect of the request.
+func cacheKey(orgID platform.ID, req *query.ProxyRequest, q string) (string, error) {
+	dialect, err := json.Marshal(req.Dialect)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%T\n%s\n%s", orgID, req.Dialect, dialect, q)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// limitedBuffer buffers what is written to it until it is full.
+type limitedBuffer struct {
+	bytes.Buffer
+	max  int64
+	full bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.full || int64(b.Len()+len(p)) > b.max {
+		b.full = true
+		b.Reset()
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// PointsWriter invalidates the results of the queries reading where points
+// are written.
+type PointsWriter struct {
+	storage.PointsWriter
+	Cache *Cache
+}
+
+// WritePoints writes exploded points then invalidates the results of the
+// queries reading their buckets at their times.
+func (w *PointsWriter) WritePoints(points []models.Point) error {
+	// some points may be written even if the write fails.
+	err := w.PointsWriter.WritePoints(points)
+
+	type bounds struct{ min, max time.Time }
+	written := make(map[[16]byte]*bounds)
+	for _, p := range points {
+		var name [16]byte
+		if len(p.Name()) != len(name) {
+			continue
+		}
+		copy(name[:], p.Name())
+		t := p.Time()
+		if b, ok := written[name]; !ok {
+			written[name] = &bounds{min: t, max: t}
+		} else if t.Before(b.min) {
+			b.min = t
+		} else if t.After(b.max) {
+			b.max = t
+		}
+	}
+	for name, b := range written {
+		orgID, bucketID := tsdb.DecodeName(name)
+		w.Cache.Invalidate(orgID, bucketID, b.min, b.max)
+	}
+	return err
+}
@@ -3,54 +3,598 @@ package control
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/control"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/query"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 // orgLabel is the metric label to use in the controller
 const orgLabel = "org"
 
+// logQueueSize is the number of logs of queries done waiting to be written
+// by the QueryLogger; the logs of the queries done while the queue is full
+// are dropped.
+const logQueueSize = 1024
+
+// OrgLimits limits the queries of every organization; the zero limits are
+// unlimited.
+type OrgLimits struct {
+	// ConcurrencyQuota is the number of queries of an organization executing
+	// at once.
+	ConcurrencyQuota int
+	// QueueSize is the number of queries of an organization waiting to
+	// execute; the queries of an organization whose queue is full are
+	// rejected.
+	QueueSize int
+	// MemoryBytesQuota is the memory allocated by the executing queries of an
+	// organization above which its next queries wait for them to finish.
+	MemoryBytesQuota int64
+}
+
+// Config configures the controller.
+type Config struct {
+	control.Config
+
+	// OrgLimits limits the queries of every organization.
+	OrgLimits OrgLimits
+
+	// QueryLogger, if set, logs the statistics of every query once it is
+	// done. The logs are written in the background, in the order the queries
+	// are done.
+	QueryLogger query.Logger
+
+	// OrganizationService, if set, is used to reject the queries of the
+	// deleted organizations, including those of their tasks.
+	OrganizationService platform.OrganizationService
+}
+
+// fluxController executes the queries admitted by the Controller.
+type fluxController interface {
+	Query(ctx context.Context, compiler flux.Compiler) (flux.Query, error)
+	PrometheusCollectors() []prometheus.Collector
+	Shutdown(ctx context.Context) error
+}
+
 // Controller implements AsyncQueryService by consuming a control.Controller.
+//
+// The queries of an organization past its limits wait in a queue of the
+// organization. When queries finish, the organizations are served in turn,
+// so that the queries of one organization can't starve the others.
 type Controller struct {
-	c *control.Controller
+	c      fluxController
+	limits OrgLimits
+	// maxExecuting is the number of queries executing at once across
+	// organizations.
+	maxExecuting int
+
+	mu        sync.Mutex
+	lastID    platform.ID
+	executing int
+	orgs      map[platform.ID]*org
+	// waiting are the organizations with queued queries, in the order they
+	// are served.
+	waiting []*org
+
+	metrics *orgMetrics
+	// logs is the queue of the logs written by the queryLogger; it is nil
+	// once the controller is shut down.
+	logs     chan query.Log
+	logsDone chan struct{}
+
+	queryLogger query.Logger
+	orgService  platform.OrganizationService
+	logger      *zap.Logger
+}
+
+// org is the state of the queries of an organization.
+type org struct {
+	id        platform.ID
+	executing map[*orgQuery]struct{}
+	queue     []*orgQuery
+}
+
+// memory returns the bytes allocated by the executing queries of o.
+func (o *org) memory() int64 {
+	var n int64
+	for q := range o.executing {
+		if q.Query != nil {
+			n += q.Statistics().MaxAllocated
+		}
+	}
+	return n
 }
 
 // NewController creates a new Controller specific to platform.
-func New(config control.Config) *Controller {
+func New(config Config) *Controller {
 	config.MetricLabelKeys = append(config.MetricLabelKeys, orgLabel)
-	c := control.New(config)
-	return &Controller{c: c}
+	c := control.New(config.Config)
+	ctrl := newController(c, config.ConcurrencyQuota, config.OrgLimits)
+	ctrl.orgService = config.OrganizationService
+	if config.Logger != nil {
+		ctrl.logger = config.Logger
+	}
+	if config.QueryLogger != nil {
+		ctrl.setQueryLogger(config.QueryLogger)
+	}
+	return ctrl
+}
+
+func newController(c fluxController, maxExecuting int, limits OrgLimits) *Controller {
+	return &Controller{
+		c:            c,
+		limits:       limits,
+		maxExecuting: maxExecuting,
+		orgs:         make(map[platform.ID]*org),
+		metrics:      newOrgMetrics(),
+		logger:       zap.NewNop(),
+	}
 }
 
 // Query satisfies the AsyncQueryService while ensuring the request is propagated on the context.
+// It blocks while the query is queued, until it executes or ctx is done.
 func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query, error) {
+	if c.orgService != nil {
+		o, err := c.orgService.FindOrganizationByID(ctx, req.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if err := platform.CheckOrganizationActive(o); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set the request on the context so platform specific Flux operations can retrieve it later.
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
 	ctx = context.WithValue(ctx, orgLabel, req.OrganizationID.String())
+	// Collect the statistics of the data read by the query.
+	reads := new(query.ReadStatistics)
+	ctx = query.ContextWithReadStatistics(ctx, reads)
+	// The query is canceled through its context while it is queued.
+	ctx, cancel := context.WithCancel(ctx)
+	// Trace the query through its queueing, compilation and execution.
+	span, ctx := opentracing.StartSpanFromContext(ctx, "query")
+	span.SetTag("org_id", req.OrganizationID.String())
+
+	queueSpan, _ := opentracing.StartSpanFromContext(ctx, "query.queue")
+	oq, err := c.acquire(ctx, req, cancel)
+	queueSpan.Finish()
+	if err != nil {
+		cancel()
+		finishSpan(span, err)
+		return nil, err
+	}
+	span.SetTag("query_id", oq.id.String())
+
+	// The query executes with the context it is compiled with, so the
+	// compilation is traced alongside rather than around it.
+	compileSpan, _ := opentracing.StartSpanFromContext(ctx, "query.compile")
 	q, err := c.c.Query(ctx, req.Compiler)
+	finishSpan(compileSpan, err)
 	if err != nil {
+		cancel()
+		c.release(oq)
+		finishSpan(span, err)
 		// If the controller reports an error, it's usually because of a syntax error
 		// or other problem that the client must fix.
-		return q, &platform.Error{
+		return nil, &platform.Error{
 			Code: platform.EInvalid,
 			Msg:  err.Error(),
 		}
 	}
 
-	return q, nil
+	c.mu.Lock()
+	oq.Query = q
+	oq.reads = reads
+	oq.span = span
+	oq.executeSpan, _ = opentracing.StartSpanFromContext(ctx, "query.execute")
+	c.mu.Unlock()
+	return oq, nil
+}
+
+// finishSpan finishes a span, marking it as failed if err is not nil.
+func finishSpan(span opentracing.Span, err error) {
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(log.Error(err))
+	}
+	span.Finish()
+}
+
+// acquire waits for a query of an organization to be allowed to execute.
+func (c *Controller) acquire(ctx context.Context, req *query.Request, cancel context.CancelFunc) (*orgQuery, error) {
+	orgID := req.OrganizationID
+	c.mu.Lock()
+	o, ok := c.orgs[orgID]
+	if !ok {
+		o = &org{
+			id:        orgID,
+			executing: make(map[*orgQuery]struct{}),
+		}
+		c.orgs[orgID] = o
+	}
+
+	c.lastID++
+	q := &orgQuery{
+		id:        c.lastID,
+		req:       req,
+		startedAt: time.Now().UTC(),
+		cancel:    cancel,
+		c:         c,
+		org:       o,
+		ready:     make(chan struct{}),
+	}
+	if len(o.queue) == 0 && c.canExecute(o) {
+		c.execute(q)
+		c.mu.Unlock()
+		return q, nil
+	}
+
+	if c.limits.QueueSize > 0 && len(o.queue) >= c.limits.QueueSize {
+		c.metrics.rejected.WithLabelValues(orgID.String()).Inc()
+		c.mu.Unlock()
+		return nil, &platform.Error{
+			Code: platform.ETooManyRequests,
+			Msg:  fmt.Sprintf("too many queries queued for organization %s, at most %d can wait", orgID, c.limits.QueueSize),
+		}
+	}
+	o.queue = append(o.queue, q)
+	if len(o.queue) == 1 {
+		c.waiting = append(c.waiting, o)
+	}
+	c.metrics.queued.WithLabelValues(orgID.String()).Inc()
+	c.mu.Unlock()
+
+	select {
+	case <-q.ready:
+		return q, nil
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-q.ready:
+		// the query was dequeued meanwhile.
+		c.releaseLocked(q)
+	default:
+		c.dequeue(q)
+	}
+	return nil, ctx.Err()
+}
+
+// canExecute reports whether a query of o can execute now.
+func (c *Controller) canExecute(o *org) bool {
+	if c.maxExecuting > 0 && c.executing >= c.maxExecuting {
+		return false
+	}
+	if c.limits.ConcurrencyQuota > 0 && len(o.executing) >= c.limits.ConcurrencyQuota {
+		return false
+	}
+	return c.limits.MemoryBytesQuota == 0 || o.memory() < c.limits.MemoryBytesQuota
+}
+
+func (c *Controller) execute(q *orgQuery) {
+	q.org.executing[q] = struct{}{}
+	c.executing++
+	c.metrics.executing.WithLabelValues(q.org.id.String()).Inc()
+	close(q.ready)
+}
+
+// dequeue removes a query that stopped waiting from its queue.
+func (c *Controller) dequeue(q *orgQuery) {
+	o := q.org
+	for i, qq := range o.queue {
+		if qq == q {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			break
+		}
+	}
+	c.metrics.queued.WithLabelValues(o.id.String()).Dec()
+	if len(o.queue) == 0 {
+		c.removeWaiting(o)
+	}
+	c.forget(o)
+}
+
+func (c *Controller) removeWaiting(o *org) {
+	for i, w := range c.waiting {
+		if w == o {
+			c.waiting = append(c.waiting[:i], c.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// forget drops the state of an organization without queries.
+func (c *Controller) forget(o *org) {
+	if len(o.executing) == 0 && len(o.queue) == 0 {
+		delete(c.orgs, o.id)
+	}
+}
+
+func (c *Controller) release(q *orgQuery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.releaseLocked(q)
+}
+
+func (c *Controller) releaseLocked(q *orgQuery) {
+	if _, ok := q.org.executing[q]; !ok {
+		return
+	}
+	delete(q.org.executing, q)
+	c.executing--
+	c.metrics.executing.WithLabelValues(q.org.id.String()).Dec()
+	c.schedule()
+	c.forget(q.org)
+}
+
+// schedule executes the queued queries that can, serving the waiting
+// organizations in turn.
+func (c *Controller) schedule() {
+	for i := 0; i < len(c.waiting); {
+		if c.maxExecuting > 0 && c.executing >= c.maxExecuting {
+			return
+		}
+		o := c.waiting[i]
+		if !c.canExecute(o) {
+			i++
+			continue
+		}
+
+		q := o.queue[0]
+		o.queue = o.queue[1:]
+		c.metrics.queued.WithLabelValues(o.id.String()).Dec()
+		c.execute(q)
+
+		// the organization is served again after the others.
+		c.waiting = append(c.waiting[:i], c.waiting[i+1:]...)
+		if len(o.queue) > 0 {
+			c.waiting = append(c.waiting, o)
+		}
+	}
+}
+
+// orgQuery is a query of an organization, releasing its place once done.
+type orgQuery struct {
+	flux.Query
+
+	id        platform.ID
+	req       *query.Request
+	startedAt time.Time
+	cancel    context.CancelFunc
+	reads     *query.ReadStatistics
+	// span traces the query, and executeSpan its execution.
+	span        opentracing.Span
+	executeSpan opentracing.Span
+
+	c     *Controller
+	org   *org
+	ready chan struct{}
+	done  sync.Once
+}
+
+// Done frees the resources of the query, letting the next queued queries
+// execute.
+func (q *orgQuery) Done() {
+	q.done.Do(func() {
+		q.Query.Done()
+		q.cancel()
+		q.c.release(q)
+		q.c.log(q)
+		q.finishSpans()
+	})
+}
+
+// finishSpans finishes the spans of a query done, recording the statistics
+// of its execution.
+func (q *orgQuery) finishSpans() {
+	stats := q.Statistics()
+	reads := q.reads.Statistics()
+	q.executeSpan.SetTag("scanned_values", stats.ScannedValues+reads.ScannedValues)
+	q.executeSpan.SetTag("scanned_bytes", stats.ScannedBytes+reads.ScannedBytes)
+	q.executeSpan.SetTag("max_allocated", stats.MaxAllocated)
+	err := q.Err()
+	finishSpan(q.executeSpan, err)
+	finishSpan(q.span, err)
+}
+
+// setQueryLogger starts writing the logs of the queries done with l.
+func (c *Controller) setQueryLogger(l query.Logger) {
+	c.queryLogger = l
+	c.logs = make(chan query.Log, logQueueSize)
+	c.logsDone = make(chan struct{})
+	go c.writeLogs(c.logs)
+}
+
+// writeLogs writes the logs of the queries done until logs is closed.
+func (c *Controller) writeLogs(logs <-chan query.Log) {
+	defer close(c.logsDone)
+	for l := range logs {
+		if err := c.queryLogger.Log(l); err != nil {
+			c.logger.Info("Failed to log query", zap.String("org_id", l.OrganizationID.String()), zap.Error(err))
+		}
+	}
+}
+
+// log queues the statistics of a query done to be logged.
+func (c *Controller) log(q *orgQuery) {
+	if c.queryLogger == nil {
+		return
+	}
+
+	// The statistics of the query only cover what it outputs, so add those of
+	// what it read.
+	stats := q.Statistics()
+	reads := q.reads.Statistics()
+	stats.ScannedValues += reads.ScannedValues
+	stats.ScannedBytes += reads.ScannedBytes
+
+	log := query.Log{
+		Time:           time.Now(),
+		OrganizationID: q.org.id,
+		Error:          q.Err(),
+		ProxyRequest:   &query.ProxyRequest{Request: *q.req},
+		Statistics:     stats,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case c.logs <- log:
+	default:
+		c.logger.Info("Dropped query log, the queue is full or the controller is shut down", zap.String("org_id", q.org.id.String()))
+	}
+}
+
+// active describes the query; it must be called with the lock of the
+// controller held.
+func (q *orgQuery) active() *query.ActiveQuery {
+	aq := &query.ActiveQuery{
+		ID:             q.id,
+		OrganizationID: q.org.id,
+		Query:          query.CompilerQuery(q.req.Compiler),
+		StartedAt:      q.startedAt,
+	}
+	if a := q.req.Authorization; a != nil {
+		aq.UserID = a.UserID
+		aq.AuthorizationID = a.ID
+	}
+
+	switch _, executing := q.org.executing[q]; {
+	case !executing:
+		aq.State = query.QueuedState
+	case q.Query == nil:
+		aq.State = control.Compiling.String()
+	default:
+		aq.State = control.Executing.String()
+		if s, ok := q.Query.(interface{ State() control.State }); ok {
+			aq.State = s.State().String()
+		}
+		aq.MemoryBytes = q.Statistics().MaxAllocated
+	}
+	return aq
+}
+
+// FindActiveQueries returns the queries queued or executing, oldest first.
+func (c *Controller) FindActiveQueries(ctx context.Context, filter query.ActiveQueryFilter) ([]*query.ActiveQuery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	aqs := []*query.ActiveQuery{}
+	for _, o := range c.orgs {
+		if filter.OrganizationID != nil && *filter.OrganizationID != o.id {
+			continue
+		}
+		for q := range o.executing {
+			aqs = append(aqs, q.active())
+		}
+		for _, q := range o.queue {
+			aqs = append(aqs, q.active())
+		}
+	}
+	sort.Slice(aqs, func(i, j int) bool {
+		return aqs[i].ID < aqs[j].ID
+	})
+	return aqs, nil
+}
+
+// FindActiveQueryByID returns a query queued or executing.
+func (c *Controller) FindActiveQueryByID(ctx context.Context, id platform.ID) (*query.ActiveQuery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q, err := c.findQuery(id)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  query.OpFindActiveQueryByID,
+			Err: err,
+		}
+	}
+	return q.active(), nil
+}
+
+// CancelActiveQuery cancels a query: a queued query stops waiting, and an
+// executing query stops executing.
+func (c *Controller) CancelActiveQuery(ctx context.Context, id platform.ID) error {
+	c.mu.Lock()
+	q, err := c.findQuery(id)
+	if err != nil {
+		c.mu.Unlock()
+		return &platform.Error{
+			Op:  query.OpCancelActiveQuery,
+			Err: err,
+		}
+	}
+	fq := q.Query
+	c.mu.Unlock()
+
+	q.cancel()
+	if fq != nil {
+		fq.Cancel()
+	}
+	return nil
+}
+
+// findQuery returns the query queued or executing by ID; it must be called
+// with the lock held.
+func (c *Controller) findQuery(id platform.ID) (*orgQuery, error) {
+	for _, o := range c.orgs {
+		for q := range o.executing {
+			if q.id == id {
+				return q, nil
+			}
+		}
+		for _, q := range o.queue {
+			if q.id == id {
+				return q, nil
+			}
+		}
+	}
+	return nil, &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  "query not found",
+	}
 }
 
 // PrometheusCollectors satisifies the prom.PrometheusCollector interface.
 func (c *Controller) PrometheusCollectors() []prometheus.Collector {
-	return c.c.PrometheusCollectors()
+	return append(c.c.PrometheusCollectors(), c.metrics.PrometheusCollectors()...)
 }
 
-// Shutdown shuts down the underlying Controller.
+// Shutdown shuts down the underlying Controller, then waits for the logs of
+// the queries done to be written until ctx is done.
 func (c *Controller) Shutdown(ctx context.Context) error {
-	return c.c.Shutdown(ctx)
+	err := c.c.Shutdown(ctx)
+
+	c.mu.Lock()
+	logs := c.logs
+	c.logs = nil
+	c.mu.Unlock()
+	if logs == nil {
+		return err
+	}
+	close(logs)
+
+	select {
+	case <-c.logsDone:
+		return err
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+		return err
+	}
 }
@@ -0,0 +1,378 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/kit/tracing"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/query"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fakeController executes queries right away, allocating memory bytes and
+// reading scanned values.
+type fakeController struct {
+	memory  int64
+	scanned int
+}
+
+func (c *fakeController) Query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
+	if s := query.ReadStatisticsFromContext(ctx); s != nil {
+		s.Add(flux.Statistics{ScannedValues: c.scanned})
+	}
+	return &fakeQuery{memory: c.memory}, nil
+}
+
+func (c *fakeController) PrometheusCollectors() []prometheus.Collector { return nil }
+
+func (c *fakeController) Shutdown(ctx context.Context) error { return nil }
+
+type fakeQuery struct {
+	flux.Query
+	memory   int64
+	canceled bool
+}
+
+func (q *fakeQuery) Done() {}
+
+func (q *fakeQuery) Cancel() { q.canceled = true }
+
+func (q *fakeQuery) Err() error { return nil }
+
+func (q *fakeQuery) Statistics() flux.Statistics {
+	return flux.Statistics{MaxAllocated: q.memory}
+}
+
+func request(orgID platform.ID) *query.Request {
+	return &query.Request{
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: "from()"},
+	}
+}
+
+// queue starts a query of an organization waiting to execute, whose
+// result is sent on the returned channel once it executes, or nil if it is
+// canceled.
+func queue(t *testing.T, c *Controller, orgID platform.ID) <-chan flux.Query {
+	t.Helper()
+	ch := make(chan flux.Query, 1)
+	go func() {
+		q, err := c.Query(context.Background(), request(orgID))
+		if err != nil && err != context.Canceled {
+			t.Error(err)
+		}
+		ch <- q
+	}()
+	// wait for the query to be queued.
+	for {
+		c.mu.Lock()
+		o := c.orgs[orgID]
+		n := 0
+		if o != nil {
+			n = len(o.queue)
+		}
+		c.mu.Unlock()
+		if n > 0 {
+			return ch
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func queued(c *Controller, orgID platform.ID) float64 {
+	var m dto.Metric
+	if err := c.metrics.queued.WithLabelValues(orgID.String()).Write(&m); err != nil {
+		panic(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestController_OrgQueue(t *testing.T) {
+	c := newController(&fakeController{}, 0, OrgLimits{ConcurrencyQuota: 1, QueueSize: 1})
+	orgID := platform.ID(1)
+
+	q, err := c.Query(context.Background(), request(orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := queue(t, c, orgID)
+	if n := queued(c, orgID); n != 1 {
+		t.Fatalf("expected 1 query queued, got %v", n)
+	}
+
+	if _, err := c.Query(context.Background(), request(orgID)); platform.ErrorCode(err) != platform.ETooManyRequests {
+		t.Fatalf("expected the query to be rejected, got %v", err)
+	}
+	var m dto.Metric
+	if err := c.metrics.rejected.WithLabelValues(orgID.String()).Write(&m); err != nil || m.GetCounter().GetValue() != 1 {
+		t.Fatalf("expected 1 rejected query, got %v", m.GetCounter().GetValue())
+	}
+
+	// the queries of other organizations aren't limited.
+	other, err := c.Query(context.Background(), request(platform.ID(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Done()
+
+	q.Done()
+	select {
+	case q := <-next:
+		q.Done()
+	case <-time.After(time.Second):
+		t.Fatal("expected the queued query to execute")
+	}
+	if n := queued(c, orgID); n != 0 {
+		t.Fatalf("expected no queries queued, got %v", n)
+	}
+	if len(c.orgs) != 0 {
+		t.Fatalf("expected the organizations to be forgotten, got %d", len(c.orgs))
+	}
+}
+
+func TestController_Fairness(t *testing.T) {
+	c := newController(&fakeController{}, 1, OrgLimits{})
+	busy, quiet := platform.ID(1), platform.ID(2)
+
+	q, err := c.Query(context.Background(), request(busy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy1 := queue(t, c, busy)
+	busy2 := queue(t, c, busy)
+	quiet1 := queue(t, c, quiet)
+
+	// the organizations are served in turn.
+	q.Done()
+	q = <-busy1
+	q.Done()
+	select {
+	case q = <-quiet1:
+	case q = <-busy2:
+		t.Fatal("expected the quiet organization to be served before the busy one")
+	}
+	q.Done()
+	(<-busy2).Done()
+}
+
+func TestController_OrgMemory(t *testing.T) {
+	c := newController(&fakeController{memory: 100}, 0, OrgLimits{MemoryBytesQuota: 100})
+	orgID := platform.ID(1)
+
+	q, err := c.Query(context.Background(), request(orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := queue(t, c, orgID)
+	q.Done()
+	(<-next).Done()
+}
+
+func TestController_Canceled(t *testing.T) {
+	c := newController(&fakeController{}, 0, OrgLimits{ConcurrencyQuota: 1})
+	orgID := platform.ID(1)
+
+	q, err := c.Query(context.Background(), request(orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		_, err := c.Query(ctx, request(orgID))
+		errC <- err
+	}()
+	for queued(c, orgID) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errC; err != context.Canceled {
+		t.Fatalf("expected the query to be canceled, got %v", err)
+	}
+	if n := queued(c, orgID); n != 0 {
+		t.Fatalf("expected no queries queued, got %v", n)
+	}
+	q.Done()
+}
+
+func TestController_ActiveQueries(t *testing.T) {
+	c := newController(&fakeController{memory: 10}, 0, OrgLimits{ConcurrencyQuota: 1})
+	orgID := platform.ID(1)
+
+	req := request(orgID)
+	req.Authorization = &platform.Authorization{ID: 3, UserID: 4}
+	q, err := c.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := queue(t, c, orgID)
+
+	other := platform.ID(2)
+	aqs, err := c.FindActiveQueries(context.Background(), query.ActiveQueryFilter{OrganizationID: &other})
+	if err != nil || len(aqs) != 0 {
+		t.Fatalf("expected no queries of the other organization, got %v, %v", aqs, err)
+	}
+	aqs, err = c.FindActiveQueries(context.Background(), query.ActiveQueryFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aqs) != 2 {
+		t.Fatalf("expected 2 active queries, got %d", len(aqs))
+	}
+	if aq := aqs[0]; aq.State != "executing" || aq.Query != "from()" || aq.MemoryBytes != 10 || aq.UserID != 4 || aq.AuthorizationID != 3 {
+		t.Fatalf("unexpected executing query %+v", aq)
+	}
+	if aq := aqs[1]; aq.State != query.QueuedState || aq.MemoryBytes != 0 {
+		t.Fatalf("unexpected queued query %+v", aq)
+	}
+
+	// canceling the queued query stops it from waiting.
+	if err := c.CancelActiveQuery(context.Background(), aqs[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if q := <-next; q != nil {
+		t.Fatal("expected the queued query to be canceled")
+	}
+	if _, err := c.FindActiveQueryByID(context.Background(), aqs[1].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the canceled query to be gone, got %v", err)
+	}
+
+	if err := c.CancelActiveQuery(context.Background(), aqs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if !q.(*orgQuery).Query.(*fakeQuery).canceled {
+		t.Fatal("expected the executing query to be canceled")
+	}
+	q.Done()
+	if err := c.CancelActiveQuery(context.Background(), aqs[0].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected the finished query to be gone, got %v", err)
+	}
+}
+
+type queryLogger struct {
+	logs []query.Log
+	// block, if set, blocks the writes until it is closed.
+	block chan struct{}
+}
+
+func (l *queryLogger) Log(q query.Log) error {
+	if l.block != nil {
+		<-l.block
+	}
+	l.logs = append(l.logs, q)
+	return nil
+}
+
+func TestController_QueryLogger(t *testing.T) {
+	c := newController(&fakeController{memory: 10, scanned: 5}, 0, OrgLimits{})
+	logger := &queryLogger{block: make(chan struct{})}
+	c.setQueryLogger(logger)
+	orgID := platform.ID(1)
+
+	q, err := c.Query(context.Background(), request(orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the query is done without waiting for its log to be written.
+	q.Done()
+	q.Done()
+	close(logger.block)
+
+	// the logs queued are written before the controller shuts down.
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.logs) != 1 {
+		t.Fatalf("expected the query to be logged once, got %d", len(logger.logs))
+	}
+	l := logger.logs[0]
+	if l.OrganizationID != orgID || l.Statistics.MaxAllocated != 10 || l.Statistics.ScannedValues != 5 {
+		t.Errorf("unexpected log %+v", l)
+	}
+	if got := query.CompilerQuery(l.ProxyRequest.Request.Compiler); got != "from()" {
+		t.Errorf("unexpected logged query %q", got)
+	}
+
+	// the queries done once shut down are not logged.
+	q, err = c.Query(context.Background(), request(orgID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Done()
+	if len(logger.logs) != 1 {
+		t.Fatalf("expected the query not to be logged, got %d logs", len(logger.logs))
+	}
+}
+
+func TestController_DeletedOrganization(t *testing.T) {
+	c := newController(&fakeController{}, 0, OrgLimits{})
+	deletedAt := time.Now()
+	c.orgService = &mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			if id == 2 {
+				return &platform.Organization{ID: id, Name: "deleted", DeletedAt: &deletedAt}, nil
+			}
+			return &platform.Organization{ID: id, Name: "active"}, nil
+		},
+	}
+
+	q, err := c.Query(context.Background(), request(1))
+	if err != nil {
+		t.Fatalf("unexpected error querying an active org: %v", err)
+	}
+	q.Done()
+
+	if _, err := c.Query(context.Background(), request(2)); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected a conflict error querying a deleted org, got %v", err)
+	}
+	if c.orgs[2] != nil {
+		t.Fatal("query of a deleted org was queued")
+	}
+}
+
+func TestController_Tracing(t *testing.T) {
+	var spans bytes.Buffer
+	tracer := tracing.NewTracer(tracing.NewFileExporter(&spans))
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	opentracing.SetGlobalTracer(tracer)
+
+	c := newController(&fakeController{}, 0, OrgLimits{})
+	parent := tracer.StartSpan("request")
+	q, err := c.Query(opentracing.ContextWithSpan(context.Background(), parent), request(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Done()
+	parent.Finish()
+	if err := tracer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]tracing.SpanData)
+	dec := json.NewDecoder(&spans)
+	for dec.More() {
+		var s tracing.SpanData
+		if err := dec.Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		byName[s.OperationName] = s
+	}
+	root := byName["query"]
+	if root.ParentID != byName["request"].SpanID {
+		t.Errorf("expected the query to be traced in the trace of its request, got %+v", byName)
+	}
+	for _, name := range []string{"query.queue", "query.compile", "query.execute"} {
+		if s, ok := byName[name]; !ok || s.ParentID != root.SpanID {
+			t.Errorf("expected a %s span child of the query span, got %+v", name, s)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package control
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// orgMetrics holds the metrics of the queries of the organizations.
+type orgMetrics struct {
+	executing *prometheus.GaugeVec
+	queued    *prometheus.GaugeVec
+	rejected  *prometheus.CounterVec
+}
+
+func newOrgMetrics() *orgMetrics {
+	const (
+		namespace = "query"
+		subsystem = "control"
+	)
+
+	return &orgMetrics{
+		executing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_executing",
+			Help:      "Number of queries of an organization executing",
+		}, []string{orgLabel}),
+
+		queued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_queued",
+			Help:      "Number of queries of an organization waiting to execute",
+		}, []string{orgLabel}),
+
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_rejected_total",
+			Help:      "Number of queries of an organization rejected because its queue was full",
+		}, []string{orgLabel}),
+	}
+}
+
+// PrometheusCollectors satisifies the prom.PrometheusCollector interface.
+func (m *orgMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.executing,
+		m.queued,
+		m.rejected,
+	}
+}
@@ -0,0 +1,188 @@
+// Package export encodes the results of Flux queries for scripting, as JSON
+// lines or as line protocol.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/platform/models"
+)
+
+// Column labels of the results having a meaning in line protocol.
+const (
+	measurementColLabel = "_measurement"
+	fieldColLabel       = "_field"
+)
+
+// rowFunc is called with every row of the tables of the results.
+type rowFunc func(w io.Writer, result string, table int, cols []flux.ColMeta, key flux.GroupKey, cr flux.ColReader, i int) error
+
+// encode calls fn with every row of results, numbering the tables of every
+// result from zero.
+func encode(w io.Writer, results flux.ResultIterator, fn rowFunc) (int64, error) {
+	defer results.Release()
+	wc := &iocounter.Writer{Writer: w}
+
+	for results.More() {
+		res := results.Next()
+		table := 0
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			defer func() { table++ }()
+			return tbl.Do(func(cr flux.ColReader) error {
+				for i := 0; i < cr.Len(); i++ {
+					if err := fn(wc, res.Name(), table, tbl.Cols(), tbl.Key(), cr, i); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}); err != nil {
+			return wc.Count(), err
+		}
+	}
+	return wc.Count(), results.Err()
+}
+
+// value returns the value of column j of row i.
+func value(cr flux.ColReader, c flux.ColMeta, j, i int) (interface{}, error) {
+	switch c.Type {
+	case flux.TBool:
+		return cr.Bools(j)[i], nil
+	case flux.TInt:
+		return cr.Ints(j)[i], nil
+	case flux.TUInt:
+		return cr.UInts(j)[i], nil
+	case flux.TFloat:
+		return cr.Floats(j)[i], nil
+	case flux.TString:
+		return cr.Strings(j)[i], nil
+	case flux.TTime:
+		return cr.Times(j)[i].Time().UTC(), nil
+	default:
+		return nil, fmt.Errorf("unsupported column type: %s", c.Type)
+	}
+}
+
+// JSONLinesEncoder encodes results as one JSON object per row, holding the
+// name of the result, the index of the table in the result, and the value
+// of every column by label. Times are formatted as RFC3339 with nanoseconds.
+type JSONLinesEncoder struct{}
+
+// NewJSONLinesEncoder returns a new JSONLinesEncoder.
+func NewJSONLinesEncoder() *JSONLinesEncoder {
+	return new(JSONLinesEncoder)
+}
+
+// Encode writes results to w, one row per line.
+func (e *JSONLinesEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	var buf bytes.Buffer
+	return encode(w, results, func(w io.Writer, result string, table int, cols []flux.ColMeta, key flux.GroupKey, cr flux.ColReader, i int) error {
+		buf.Reset()
+		fmt.Fprintf(&buf, `{"result":%q,"table":%d`, result, table)
+		for j, c := range cols {
+			v, err := value(cr, c, j, i)
+			if err != nil {
+				return err
+			}
+			label, err := json.Marshal(c.Label)
+			if err != nil {
+				return err
+			}
+			octets, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			buf.WriteByte(',')
+			buf.Write(label)
+			buf.WriteByte(':')
+			buf.Write(octets)
+		}
+		buf.WriteString("}\n")
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// LineProtocolEncoder encodes results as line protocol, one point per row.
+//
+// The measurement of a point is the _measurement column, which is required.
+// Its tags are the other string columns of the group key, except _field,
+// _start and _stop, and its time is the _time column, or now if there is
+// none.
+// A row with _field and _value columns has a single field; otherwise, as
+// after a pivot, all the columns outside of the group key but _time are
+// fields.
+type LineProtocolEncoder struct{}
+
+// NewLineProtocolEncoder returns a new LineProtocolEncoder.
+func NewLineProtocolEncoder() *LineProtocolEncoder {
+	return new(LineProtocolEncoder)
+}
+
+// Encode writes results to w, one point per line.
+func (e *LineProtocolEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	return encode(w, results, func(w io.Writer, result string, table int, cols []flux.ColMeta, key flux.GroupKey, cr flux.ColReader, i int) error {
+		measurement := execute.ColIdx(measurementColLabel, cols)
+		if measurement < 0 || cols[measurement].Type != flux.TString {
+			return fmt.Errorf("line protocol requires a string %s column", measurementColLabel)
+		}
+		fieldCol := execute.ColIdx(fieldColLabel, cols)
+		valueCol := execute.ColIdx(execute.DefaultValueColLabel, cols)
+		pivoted := fieldCol < 0 || valueCol < 0 || cols[fieldCol].Type != flux.TString
+
+		var t time.Time
+		tags := map[string]string{}
+		fields := models.Fields{}
+		for j, c := range cols {
+			switch c.Label {
+			case measurementColLabel, execute.DefaultStartColLabel, execute.DefaultStopColLabel:
+				continue
+			case execute.DefaultTimeColLabel:
+				if c.Type == flux.TTime {
+					t = cr.Times(j)[i].Time()
+					continue
+				}
+			}
+
+			if key.HasCol(c.Label) {
+				if c.Type == flux.TString && (pivoted || j != fieldCol) {
+					tags[c.Label] = cr.Strings(j)[i]
+				}
+				continue
+			}
+			if !pivoted && j != valueCol {
+				continue
+			}
+
+			v, err := value(cr, c, j, i)
+			if err != nil {
+				return err
+			}
+			if vt, ok := v.(time.Time); ok {
+				v = vt.UnixNano()
+			}
+			label := c.Label
+			if !pivoted {
+				label = cr.Strings(fieldCol)[i]
+			}
+			fields[label] = v
+		}
+
+		if t.IsZero() {
+			t = time.Now()
+		}
+		p, err := models.NewPoint(cr.Strings(measurement)[i], models.NewTags(tags), fields, t)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, p.String()+"\n")
+		return err
+	})
+}
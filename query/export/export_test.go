@@ -0,0 +1,91 @@
+package export_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/platform/query/export"
+)
+
+func results() flux.ResultIterator {
+	r := executetest.NewResult([]*executetest.Table{
+		{
+			KeyCols: []string{"_start", "_stop", "_measurement", "_field", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_start", Type: flux.TTime},
+				{Label: "_stop", Type: flux.TTime},
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(0), execute.Time(100), execute.Time(10), "cpu", "usage_user", "a", 1.5},
+				{execute.Time(0), execute.Time(100), execute.Time(20), "cpu", "usage_user", "a", 2.0},
+			},
+		},
+		{
+			KeyCols: []string{"_measurement", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "free", Type: flux.TInt},
+				{Label: "status", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(30), "mem", "b", int64(42), "ok"},
+			},
+		},
+	})
+	r.Nm = "_result"
+	return flux.NewSliceResultIterator([]flux.Result{r})
+}
+
+func TestJSONLinesEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := export.NewJSONLinesEncoder().Encode(&buf, results())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"result":"_result","table":0,"_start":"1970-01-01T00:00:00Z","_stop":"1970-01-01T00:00:00.0000001Z","_time":"1970-01-01T00:00:00.00000001Z","_measurement":"cpu","_field":"usage_user","host":"a","_value":1.5}
+{"result":"_result","table":0,"_start":"1970-01-01T00:00:00Z","_stop":"1970-01-01T00:00:00.0000001Z","_time":"1970-01-01T00:00:00.00000002Z","_measurement":"cpu","_field":"usage_user","host":"a","_value":2}
+{"result":"_result","table":1,"_time":"1970-01-01T00:00:00.00000003Z","_measurement":"mem","host":"b","free":42,"status":"ok"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected JSON lines:\n%s\nwant:\n%s", got, want)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes written, got %d", buf.Len(), n)
+	}
+}
+
+func TestLineProtocolEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := export.NewLineProtocolEncoder().Encode(&buf, results()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `cpu,host=a usage_user=1.5 10
+cpu,host=a usage_user=2 20
+mem,host=b free=42i,status="ok" 30
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected line protocol:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLineProtocolEncoder_NoMeasurement(t *testing.T) {
+	r := executetest.NewResult([]*executetest.Table{{
+		ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TFloat}},
+		Data:    [][]interface{}{{1.0}},
+	}})
+	if _, err := export.NewLineProtocolEncoder().Encode(&bytes.Buffer{}, flux.NewSliceResultIterator([]flux.Result{r})); err == nil {
+		t.Fatal("expected an error encoding a result without measurement")
+	}
+}
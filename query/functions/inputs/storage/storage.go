@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/flux/functions"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/query"
 	"github.com/pkg/errors"
 )
 
@@ -122,7 +123,11 @@ func (s *source) run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		s.stats = s.stats.Add(tables.Statistics())
+		stats := tables.Statistics()
+		s.stats = s.stats.Add(stats)
+		if rs := query.ReadStatisticsFromContext(ctx); rs != nil {
+			rs.Add(stats)
+		}
 		for _, t := range s.ts {
 			if err := t.UpdateWatermark(s.id, mark); err != nil {
 				return err
@@ -0,0 +1,178 @@
+// Package monitor writes the statistics of the queries of every organization
+// into a bucket of the organization, so that teams can analyze the
+// performance of their own queries.
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// Measurement is the measurement of the points of query statistics.
+	Measurement = "queries"
+
+	// DefaultBucketName is the name of the bucket of an organization the
+	// statistics of its queries are written to.
+	DefaultBucketName = "_monitoring"
+
+	// DefaultRetentionPeriod is the retention period of the buckets created
+	// for the statistics.
+	DefaultRetentionPeriod = 7 * 24 * time.Hour
+)
+
+var _ query.Logger = (*QueryLogger)(nil)
+
+// QueryLogger logs the statistics of queries as points of the organization
+// that made them, and logs the slow queries.
+type QueryLogger struct {
+	BucketService platform.BucketService
+	PointsWriter  storage.PointsWriter
+	Logger        *zap.Logger
+
+	// BucketName is the name of the bucket of an organization the statistics
+	// are written to. The bucket is created with RetentionPeriod if missing;
+	// the statistics are not written if it is empty.
+	BucketName      string
+	RetentionPeriod time.Duration
+
+	// SlowQueryDuration and SlowQueryMemoryBytes are the thresholds above
+	// which a query is slow; a zero threshold is disabled.
+	SlowQueryDuration    time.Duration
+	SlowQueryMemoryBytes int64
+}
+
+// NewQueryLogger returns a QueryLogger writing into the default bucket of
+// every organization.
+func NewQueryLogger(bucketSvc platform.BucketService, w storage.PointsWriter) *QueryLogger {
+	return &QueryLogger{
+		BucketService:   bucketSvc,
+		PointsWriter:    w,
+		Logger:          zap.NewNop(),
+		BucketName:      DefaultBucketName,
+		RetentionPeriod: DefaultRetentionPeriod,
+	}
+}
+
+// Log writes the statistics of a query, logging it first if it is slow.
+func (l *QueryLogger) Log(q query.Log) error {
+	slow := l.slow(q.Statistics.TotalDuration, q.Statistics.MaxAllocated)
+	if slow {
+		l.Logger.Warn("Slow query",
+			zap.String("org_id", q.OrganizationID.String()),
+			zap.String("query", queryText(q)),
+			zap.Duration("duration", q.Statistics.TotalDuration),
+			zap.Int64("memory_bytes", q.Statistics.MaxAllocated),
+			zap.Int("scanned_values", q.Statistics.ScannedValues),
+			zap.Int("scanned_bytes", q.Statistics.ScannedBytes))
+	}
+
+	if l.BucketName == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	b, err := l.bucket(ctx, q.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	p, err := point(q, slow)
+	if err != nil {
+		return err
+	}
+	exploded, err := tsdb.ExplodePoints(b.OrganizationID, b.ID, []models.Point{p})
+	if err != nil {
+		return err
+	}
+	return l.PointsWriter.WritePoints(exploded)
+}
+
+func (l *QueryLogger) slow(d time.Duration, memory int64) bool {
+	return (l.SlowQueryDuration > 0 && d >= l.SlowQueryDuration) ||
+		(l.SlowQueryMemoryBytes > 0 && memory >= l.SlowQueryMemoryBytes)
+}
+
+// bucket returns the bucket of an organization the statistics are written
+// to, creating it if missing.
+func (l *QueryLogger) bucket(ctx context.Context, orgID platform.ID) (*platform.Bucket, error) {
+	filter := platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &l.BucketName,
+	}
+	b, err := l.BucketService.FindBucket(ctx, filter)
+	if err == nil || platform.ErrorCode(err) != platform.ENotFound {
+		return b, err
+	}
+
+	b = &platform.Bucket{
+		OrganizationID:  orgID,
+		Name:            l.BucketName,
+		RetentionPeriod: l.RetentionPeriod,
+	}
+	if err := l.BucketService.CreateBucket(ctx, b); err != nil {
+		// another query may have created the bucket meanwhile.
+		if platform.ErrorCode(err) == platform.EConflict {
+			return l.BucketService.FindBucket(ctx, filter)
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// point returns the statistics of a query as a point. The durations are in
+// nanoseconds.
+func point(q query.Log, slow bool) (models.Point, error) {
+	tags := map[string]string{
+		"status": "success",
+		"slow":   "false",
+	}
+	if q.Error != nil {
+		tags["status"] = "error"
+	}
+	if slow {
+		tags["slow"] = "true"
+	}
+
+	s := q.Statistics
+	fields := models.Fields{
+		"query":           queryText(q),
+		"totalDuration":   int64(s.TotalDuration),
+		"compileDuration": int64(s.CompileDuration),
+		"queueDuration":   int64(s.QueueDuration),
+		"planDuration":    int64(s.PlanDuration),
+		"requeueDuration": int64(s.RequeueDuration),
+		"executeDuration": int64(s.ExecuteDuration),
+		"memoryBytes":     s.MaxAllocated,
+		"scannedValues":   int64(s.ScannedValues),
+		"scannedBytes":    int64(s.ScannedBytes),
+	}
+	if q.ResponseSize > 0 {
+		fields["responseBytes"] = q.ResponseSize
+	}
+	if q.Error != nil {
+		fields["error"] = q.Error.Error()
+	}
+	if q.ProxyRequest != nil {
+		if a := q.ProxyRequest.Request.Authorization; a != nil {
+			fields["userID"] = a.UserID.String()
+			fields["authorizationID"] = a.ID.String()
+		}
+	}
+
+	return models.NewPoint(Measurement, models.NewTags(tags), fields, q.Time)
+}
+
+func queryText(q query.Log) string {
+	if q.ProxyRequest == nil {
+		return ""
+	}
+	return query.CompilerQuery(q.ProxyRequest.Request.Compiler)
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/tsdb"
+)
+
+func TestQueryLogger_Log(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "acme"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &mock.PointsWriter{}
+	l := NewQueryLogger(svc, w)
+	l.SlowQueryDuration = time.Second
+
+	q := query.Log{
+		Time:           time.Unix(1, 0),
+		OrganizationID: o.ID,
+		ProxyRequest: &query.ProxyRequest{
+			Request: query.Request{
+				OrganizationID: o.ID,
+				Compiler:       lang.FluxCompiler{Query: "from()"},
+			},
+		},
+		Statistics: flux.Statistics{
+			TotalDuration: 2 * time.Second,
+			ScannedValues: 10,
+		},
+	}
+	if err := l.Log(q); err != nil {
+		t.Fatal(err)
+	}
+	q.Statistics.TotalDuration = time.Millisecond
+	q.Error = errors.New("expected error")
+	if err := l.Log(q); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := svc.FindBucket(ctx, platform.BucketFilter{OrganizationID: &o.ID, Name: &l.BucketName})
+	if err != nil {
+		t.Fatalf("expected the monitoring bucket to be created: %v", err)
+	}
+	if b.RetentionPeriod != DefaultRetentionPeriod {
+		t.Errorf("unexpected retention period %v", b.RetentionPeriod)
+	}
+	if bs, _, err := svc.FindBuckets(ctx, platform.BucketFilter{OrganizationID: &o.ID}); err != nil || len(bs) != 1 {
+		t.Fatalf("expected a single bucket, got %v, %v", bs, err)
+	}
+
+	// the points are exploded, one per field.
+	values := make(map[string]map[string]interface{})
+	for _, p := range w.Points {
+		tags := p.Tags()
+		key := string(tags.Get([]byte("status"))) + "," + string(tags.Get([]byte("slow")))
+		if values[key] == nil {
+			values[key] = make(map[string]interface{})
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range fields {
+			values[key][k] = v
+		}
+		if m := string(tags.Get(tsdb.MeasurementTagKeyBytes)); m != Measurement {
+			t.Errorf("unexpected measurement %q", m)
+		}
+	}
+
+	slow := values["success,true"]
+	if slow["query"] != "from()" || slow["totalDuration"] != int64(2*time.Second) || slow["scannedValues"] != int64(10) {
+		t.Errorf("unexpected slow query fields %v", slow)
+	}
+	failed := values["error,false"]
+	if failed["error"] != "expected error" || failed["totalDuration"] != int64(time.Millisecond) {
+		t.Errorf("unexpected failed query fields %v", failed)
+	}
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/platform"
+)
+
+// ParamsIdentifier is the name of the record holding the parameters of a
+// query, e.g. params.bucket.
+const ParamsIdentifier = "params"
+
+// WithParams returns a Flux script assigning params to a record named params
+// ahead of the script, so that it refers to them as params.<key>.
+//
+// The values parsing as a single integer, float, duration or date time
+// literal, optionally negated, and true or false keep their type; any other
+// value is quoted as a string, so that it can't inject Flux into the script.
+func WithParams(flux string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return flux, nil
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if !isIdentifier(k) {
+			return "", &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("query parameter %q is not a valid identifier", k),
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := make([]string, 0, len(keys))
+	for _, k := range keys {
+		props = append(props, k+": "+paramLiteral(params[k]))
+	}
+	return ParamsIdentifier + " = {" + strings.Join(props, ", ") + "}\n" + flux, nil
+}
+
+// isIdentifier reports whether s is a Flux identifier.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// paramLiteral returns the Flux literal of a parameter value.
+func paramLiteral(v string) string {
+	switch v {
+	case "true", "false":
+		return v
+	}
+
+	if p, err := parser.NewAST(v); err == nil && len(p.Body) == 1 {
+		if s, ok := p.Body[0].(*ast.ExpressionStatement); ok && isScalarLiteral(s.Expression) {
+			return ast.Format(s.Expression)
+		}
+	}
+	return quote(v)
+}
+
+func isScalarLiteral(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.DurationLiteral, *ast.DateTimeLiteral:
+		return true
+	case *ast.UnaryExpression:
+		if e.Operator != ast.SubtractionOperator {
+			return false
+		}
+		switch e.Argument.(type) {
+		case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.DurationLiteral:
+			return true
+		}
+	}
+	return false
+}
+
+// quoter escapes the characters the Flux parser unescapes in strings.
+var quoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quote(s string) string {
+	return `"` + quoter.Replace(s) + `"`
+}
@@ -0,0 +1,75 @@
+package query_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/query"
+)
+
+func TestWithParams(t *testing.T) {
+	script := `from(bucket: params.bucket) |> range(start: params.start)`
+	flux, err := query.WithParams(script, map[string]string{
+		"bucket":  `telegraf") |> drop(columns: ["x"]) |> yield(name: "`,
+		"start":   "-1h",
+		"limit":   "10",
+		"ratio":   "0.5",
+		"since":   "2018-10-02T00:00:00Z",
+		"enabled": "true",
+		"host":    "a\\b\n",
+		"expr":    "1 + 1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := parser.NewAST(flux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Body) != 2 {
+		t.Fatalf("expected the params assignment and the script, got %s", flux)
+	}
+	params := p.Body[0].(*ast.VariableAssignment).Init.(*ast.ObjectExpression)
+	want := map[string]string{
+		"bucket":  "StringLiteral",
+		"start":   "UnaryExpression",
+		"limit":   "IntegerLiteral",
+		"ratio":   "FloatLiteral",
+		"since":   "DateTimeLiteral",
+		"enabled": "Identifier",
+		"host":    "StringLiteral",
+		"expr":    "StringLiteral",
+	}
+	if len(params.Properties) != len(want) {
+		t.Fatalf("expected %d params, got %s", len(want), flux)
+	}
+	for _, prop := range params.Properties {
+		if typ := prop.Value.Type(); typ != want[prop.Key.Name] {
+			t.Errorf("expected param %s to be a %s, got %s", prop.Key.Name, want[prop.Key.Name], typ)
+		}
+		if prop.Key.Name == "bucket" {
+			if v := prop.Value.(*ast.StringLiteral).Value; v != `telegraf") |> drop(columns: ["x"]) |> yield(name: "` {
+				t.Errorf("unexpected bucket %q", v)
+			}
+		}
+		if prop.Key.Name == "host" {
+			if v := prop.Value.(*ast.StringLiteral).Value; v != "a\\b\n" {
+				t.Errorf("unexpected host %q", v)
+			}
+		}
+	}
+	if !strings.HasSuffix(flux, "\n"+script) {
+		t.Errorf("expected the script to be kept, got %s", flux)
+	}
+}
+
+func TestWithParams_InvalidKey(t *testing.T) {
+	_, err := query.WithParams("", map[string]string{"x) |> drop(": "1"})
+	if platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid parameter, got %v", err)
+	}
+}
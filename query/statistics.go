@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/flux"
+)
+
+// ReadStatistics accumulates the statistics of the data a query reads from
+// storage. The sources of a query add what they read to the ReadStatistics
+// on its context, if any.
+type ReadStatistics struct {
+	mu    sync.Mutex
+	stats flux.Statistics
+}
+
+// Add adds the statistics of a read.
+func (s *ReadStatistics) Add(stats flux.Statistics) {
+	s.mu.Lock()
+	s.stats = s.stats.Add(stats)
+	s.mu.Unlock()
+}
+
+// Statistics returns the sum of the statistics added.
+func (s *ReadStatistics) Statistics() flux.Statistics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+type readStatisticsContextKey struct{}
+
+// ContextWithReadStatistics returns a new context with a reference to the
+// read statistics of a query.
+func ContextWithReadStatistics(ctx context.Context, s *ReadStatistics) context.Context {
+	return context.WithValue(ctx, readStatisticsContextKey{}, s)
+}
+
+// ReadStatisticsFromContext retrieves the *ReadStatistics from a context.
+// If no read statistics exist on the context nil is returned.
+func ReadStatisticsFromContext(ctx context.Context) *ReadStatistics {
+	s, _ := ctx.Value(readStatisticsContextKey{}).(*ReadStatistics)
+	return s
+}
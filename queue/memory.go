@@ -0,0 +1,310 @@
+package queue
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultAckWait is how long a delivered message may go unacknowledged
+// before it is redelivered.
+const DefaultAckWait = 30 * time.Second
+
+// ErrQueueClosed is returned when publishing to or subscribing to a closed queue.
+var ErrQueueClosed = errors.New("queue is closed")
+
+// Memory is an in-process queue. Messages aren't persisted across restarts,
+// so it is meant for tests and for buffering that can afford to lose
+// messages when the process stops.
+//
+// Each group subscribed to a subject receives every message published to it,
+// and each message is delivered to one member of the group at a time.
+// Messages published to a subject before any group subscribed to it are
+// delivered to the first group that does.
+type Memory struct {
+	// AckWait is how long a delivered message may go unacknowledged before
+	// it is redelivered. It must be set before the first subscription.
+	AckWait time.Duration
+
+	mu       sync.Mutex
+	subjects map[string]*memSubject
+	closed   bool
+	done     chan struct{}
+	started  bool
+
+	metrics *memoryMetrics
+	subs    *SubscriptionCollector
+}
+
+var _ Publisher = (*Memory)(nil)
+var _ Subscriber = (*Memory)(nil)
+
+// NewMemory returns a new in-process queue.
+func NewMemory() *Memory {
+	return &Memory{
+		AckWait:  DefaultAckWait,
+		subjects: make(map[string]*memSubject),
+		done:     make(chan struct{}),
+		metrics:  newMemoryMetrics(),
+		subs:     NewSubscriptionCollector(),
+	}
+}
+
+type memSubject struct {
+	name   string
+	groups map[string]*memGroup
+	// unclaimed holds the messages published before any group subscribed.
+	unclaimed []*memMessage
+}
+
+type memGroup struct {
+	subject *memSubject
+	cond    *sync.Cond
+
+	pending   []*memMessage
+	inflight  map[*memMessage]struct{}
+	delivered int64
+}
+
+type memMessage struct {
+	data     []byte
+	attempt  int
+	deadline time.Time
+}
+
+// Publish adds a message to every group subscribed to subject.
+func (q *Memory) Publish(subject string, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	s := q.subject(subject)
+	q.metrics.published.WithLabelValues(subject).Inc()
+	if len(s.groups) == 0 {
+		s.unclaimed = append(s.unclaimed, &memMessage{data: data})
+		q.metrics.unacked.WithLabelValues(subject).Inc()
+		return nil
+	}
+	for _, g := range s.groups {
+		g.pending = append(g.pending, &memMessage{data: data})
+		q.metrics.unacked.WithLabelValues(subject).Inc()
+		g.cond.Signal()
+	}
+	return nil
+}
+
+// Subscribe adds handler as a member of group on subject. The messages of
+// the group are delivered to its members one at a time.
+func (q *Memory) Subscribe(subject, group string, handler Handler) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+	if !q.started {
+		q.started = true
+		go q.redeliver()
+	}
+
+	s := q.subject(subject)
+	g, ok := s.groups[group]
+	if !ok {
+		g = &memGroup{
+			subject:  s,
+			cond:     sync.NewCond(&q.mu),
+			inflight: make(map[*memMessage]struct{}),
+		}
+		if len(s.groups) == 0 {
+			g.pending, s.unclaimed = s.unclaimed, nil
+		}
+		s.groups[group] = g
+	}
+
+	sub := &memSubscription{q: q, group: g}
+	q.mu.Unlock()
+
+	// the collector calls into subscriptions, so it mustn't be used with q.mu held.
+	q.subs.Add(subject, group, sub)
+	go q.deliver(sub, handler)
+	return nil
+}
+
+// Close stops delivering messages. Messages that weren't acknowledged are lost.
+func (q *Memory) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	close(q.done)
+	for _, s := range q.subjects {
+		for _, g := range s.groups {
+			g.cond.Broadcast()
+		}
+	}
+	return nil
+}
+
+// PrometheusCollectors returns the metrics of the queue.
+func (q *Memory) PrometheusCollectors() []prometheus.Collector {
+	return append(q.metrics.PrometheusCollectors(), q.subs)
+}
+
+// subject returns the named subject, creating it if needed. q.mu must be held.
+func (q *Memory) subject(name string) *memSubject {
+	s, ok := q.subjects[name]
+	if !ok {
+		s = &memSubject{
+			name:   name,
+			groups: make(map[string]*memGroup),
+		}
+		q.subjects[name] = s
+	}
+	return s
+}
+
+// deliver hands the messages of the group to the handler of sub until sub or
+// the queue is closed.
+func (q *Memory) deliver(sub *memSubscription, handler Handler) {
+	g := sub.group
+	for {
+		q.mu.Lock()
+		for len(g.pending) == 0 && !q.closed && !sub.closed {
+			g.cond.Wait()
+		}
+		if q.closed || sub.closed {
+			q.mu.Unlock()
+			return
+		}
+		m := g.pending[0]
+		g.pending = g.pending[1:]
+		m.attempt++
+		m.deadline = time.Now().Add(q.AckWait)
+		g.inflight[m] = struct{}{}
+		d := &memDelivery{q: q, group: g, msg: m, attempt: m.attempt}
+		q.mu.Unlock()
+
+		handler.Process(sub, d)
+	}
+}
+
+// redeliver periodically moves the messages that weren't acknowledged in
+// time back to the front of their group.
+func (q *Memory) redeliver() {
+	interval := q.AckWait / 4
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case now := <-ticker.C:
+			q.mu.Lock()
+			for _, s := range q.subjects {
+				for _, g := range s.groups {
+					var expired []*memMessage
+					for m := range g.inflight {
+						if now.After(m.deadline) {
+							expired = append(expired, m)
+						}
+					}
+					if len(expired) == 0 {
+						continue
+					}
+					for _, m := range expired {
+						delete(g.inflight, m)
+					}
+					g.pending = append(expired, g.pending...)
+					q.metrics.redelivered.WithLabelValues(s.name).Add(float64(len(expired)))
+					g.cond.Broadcast()
+				}
+			}
+			q.mu.Unlock()
+		}
+	}
+}
+
+type memDelivery struct {
+	q       *Memory
+	group   *memGroup
+	msg     *memMessage
+	attempt int
+}
+
+func (d *memDelivery) Data() []byte {
+	return d.msg.data
+}
+
+// Ack acknowledges the message. Acknowledging a message after it was
+// redelivered has no effect.
+func (d *memDelivery) Ack() error {
+	d.q.mu.Lock()
+	defer d.q.mu.Unlock()
+
+	if _, ok := d.group.inflight[d.msg]; !ok || d.msg.attempt != d.attempt {
+		return nil
+	}
+	delete(d.group.inflight, d.msg)
+	d.group.delivered++
+	subject := d.group.subject.name
+	d.q.metrics.unacked.WithLabelValues(subject).Dec()
+	return nil
+}
+
+type memSubscription struct {
+	q      *Memory
+	group  *memGroup
+	closed bool
+}
+
+// Pending returns the number of messages and bytes of the group waiting to
+// be delivered.
+func (s *memSubscription) Pending() (int64, int64, error) {
+	s.q.mu.Lock()
+	defer s.q.mu.Unlock()
+
+	var n int64
+	for _, m := range s.group.pending {
+		n += int64(len(m.data))
+	}
+	return int64(len(s.group.pending)), n, nil
+}
+
+// Delivered returns the number of messages of the group acknowledged.
+func (s *memSubscription) Delivered() (int64, error) {
+	s.q.mu.Lock()
+	defer s.q.mu.Unlock()
+	return s.group.delivered, nil
+}
+
+// Close stops delivering messages to the handler of the subscription. The
+// messages of the group are retained for its other or future members.
+func (s *memSubscription) Close() error {
+	s.q.mu.Lock()
+	if s.closed {
+		s.q.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.group.cond.Broadcast()
+	s.q.mu.Unlock()
+
+	s.q.subs.Remove(s)
+	return nil
+}
@@ -0,0 +1,216 @@
+package queue_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/kit/prom"
+	"github.com/influxdata/platform/kit/prom/promtest"
+	"github.com/influxdata/platform/queue"
+	"go.uber.org/zap"
+)
+
+// recorder is a handler sending the data of the messages it receives on a channel.
+type recorder struct {
+	ack bool
+	ch  chan string
+}
+
+func newRecorder(ack bool) *recorder {
+	return &recorder{ack: ack, ch: make(chan string, 100)}
+}
+
+func (r *recorder) Process(s queue.Subscription, m queue.Message) {
+	if r.ack {
+		m.Ack()
+	}
+	r.ch <- string(m.Data())
+}
+
+func (r *recorder) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case data := <-r.ch:
+		return data
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return ""
+	}
+}
+
+func (r *recorder) none(t *testing.T) {
+	t.Helper()
+	select {
+	case data := <-r.ch:
+		t.Fatalf("unexpected message %q", data)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func publish(t *testing.T, q queue.Publisher, subject string, msgs ...string) {
+	t.Helper()
+	for _, m := range msgs {
+		if err := q.Publish(subject, strings.NewReader(m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMemory_Groups(t *testing.T) {
+	q := queue.NewMemory()
+	defer q.Close()
+
+	a := newRecorder(true)
+	b := newRecorder(true)
+	if err := q.Subscribe("subject", "a", a); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Subscribe("subject", "b", b); err != nil {
+		t.Fatal(err)
+	}
+	publish(t, q, "subject", "1", "2")
+	publish(t, q, "other", "3")
+
+	// every group receives every message of the subject, in order.
+	for _, r := range []*recorder{a, b} {
+		if got := r.next(t); got != "1" {
+			t.Fatalf("got message %q, want 1", got)
+		}
+		if got := r.next(t); got != "2" {
+			t.Fatalf("got message %q, want 2", got)
+		}
+		r.none(t)
+	}
+}
+
+func TestMemory_GroupMembers(t *testing.T) {
+	q := queue.NewMemory()
+	defer q.Close()
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	done := make(chan struct{}, 10)
+	h := handlerFunc(func(s queue.Subscription, m queue.Message) {
+		m.Ack()
+		mu.Lock()
+		seen[string(m.Data())]++
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	for i := 0; i < 3; i++ {
+		if err := q.Subscribe("subject", "group", h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	publish(t, q, "subject", "1", "2", "3", "4")
+	for i := 0; i < 4; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for messages")
+		}
+	}
+
+	// each message is delivered to a single member of the group.
+	mu.Lock()
+	defer mu.Unlock()
+	for _, m := range []string{"1", "2", "3", "4"} {
+		if seen[m] != 1 {
+			t.Errorf("message %s was delivered %d times", m, seen[m])
+		}
+	}
+}
+
+func TestMemory_Redelivery(t *testing.T) {
+	q := queue.NewMemory()
+	q.AckWait = 10 * time.Millisecond
+	defer q.Close()
+
+	r := newRecorder(false)
+	if err := q.Subscribe("subject", "group", r); err != nil {
+		t.Fatal(err)
+	}
+	publish(t, q, "subject", "1")
+
+	// an unacknowledged message is delivered again.
+	if got := r.next(t); got != "1" {
+		t.Fatalf("got message %q, want 1", got)
+	}
+	if got := r.next(t); got != "1" {
+		t.Fatalf("got redelivered message %q, want 1", got)
+	}
+
+	reg := prom.NewRegistry()
+	reg.WithLogger(zap.NewNop())
+	reg.MustRegister(q.PrometheusCollectors()...)
+	mfs := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mfs, "queue_memory_redelivered_total", map[string]string{"subject": "subject"})
+	if got := m.GetCounter().GetValue(); got < 1 {
+		t.Fatalf("expected redeliveries to be counted, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mfs, "queue_memory_unacknowledged_messages", map[string]string{"subject": "subject"})
+	if got := m.GetGauge().GetValue(); got != 1 {
+		t.Fatalf("expected 1 unacknowledged message, got %v", got)
+	}
+}
+
+func TestMemory_Unclaimed(t *testing.T) {
+	q := queue.NewMemory()
+	defer q.Close()
+
+	// messages published before any subscription are kept for the first group.
+	publish(t, q, "subject", "1")
+	r := newRecorder(true)
+	if err := q.Subscribe("subject", "group", r); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.next(t); got != "1" {
+		t.Fatalf("got message %q, want 1", got)
+	}
+}
+
+func TestMemory_Metrics(t *testing.T) {
+	q := queue.NewMemory()
+	defer q.Close()
+
+	r := newRecorder(true)
+	if err := q.Subscribe("subject", "group", r); err != nil {
+		t.Fatal(err)
+	}
+	publish(t, q, "subject", "1", "2")
+	r.next(t)
+	r.next(t)
+
+	reg := prom.NewRegistry()
+	reg.WithLogger(zap.NewNop())
+	reg.MustRegister(q.PrometheusCollectors()...)
+	mfs := promtest.MustGather(t, reg)
+
+	labels := map[string]string{"subject": "subject", "group": "group"}
+	if got := promtest.MustFindMetric(t, mfs, "queue_delivered_messages_total", labels).GetCounter().GetValue(); got != 2 {
+		t.Errorf("got %v delivered messages, want 2", got)
+	}
+	if got := promtest.MustFindMetric(t, mfs, "queue_pending_messages", labels).GetGauge().GetValue(); got != 0 {
+		t.Errorf("got %v pending messages, want 0", got)
+	}
+	if got := promtest.MustFindMetric(t, mfs, "queue_memory_published_total", map[string]string{"subject": "subject"}).GetCounter().GetValue(); got != 2 {
+		t.Errorf("got %v published messages, want 2", got)
+	}
+}
+
+func TestMemory_Closed(t *testing.T) {
+	q := queue.NewMemory()
+	q.Close()
+	if err := q.Publish("subject", strings.NewReader("1")); err != queue.ErrQueueClosed {
+		t.Fatalf("got error %v publishing to a closed queue, want %v", err, queue.ErrQueueClosed)
+	}
+	if err := q.Subscribe("subject", "group", newRecorder(true)); err != queue.ErrQueueClosed {
+		t.Fatalf("got error %v subscribing to a closed queue, want %v", err, queue.ErrQueueClosed)
+	}
+}
+
+type handlerFunc func(s queue.Subscription, m queue.Message)
+
+func (f handlerFunc) Process(s queue.Subscription, m queue.Message) { f(s, m) }
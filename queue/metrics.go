@@ -0,0 +1,128 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace is the leading part of all published metrics for the queue.
+const namespace = "queue"
+
+// SubscriptionCollector reports the pending and delivered messages of
+// subscriptions, labeled by subject and group.
+type SubscriptionCollector struct {
+	mu   sync.Mutex
+	subs map[Subscription]subscriptionLabels
+
+	pendingDesc   *prometheus.Desc
+	deliveredDesc *prometheus.Desc
+}
+
+type subscriptionLabels struct {
+	subject string
+	group   string
+}
+
+// NewSubscriptionCollector returns a collector without subscriptions.
+func NewSubscriptionCollector() *SubscriptionCollector {
+	labels := []string{"subject", "group"}
+	return &SubscriptionCollector{
+		subs: make(map[Subscription]subscriptionLabels),
+		pendingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "pending_messages"),
+			"Number of messages waiting to be delivered to a subscription.",
+			labels, nil),
+		deliveredDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "delivered_messages_total"),
+			"Number of messages delivered to a subscription.",
+			labels, nil),
+	}
+}
+
+// Add starts reporting the metrics of s.
+func (c *SubscriptionCollector) Add(subject, group string, s Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subs[s] = subscriptionLabels{subject: subject, group: group}
+}
+
+// Remove stops reporting the metrics of s.
+func (c *SubscriptionCollector) Remove(s Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subs, s)
+}
+
+// Describe implements prometheus.Collector.
+func (c *SubscriptionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pendingDesc
+	ch <- c.deliveredDesc
+}
+
+// Collect implements prometheus.Collector. Members of the same group are
+// reported once, as they share their messages.
+func (c *SubscriptionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[subscriptionLabels]bool, len(c.subs))
+	for s, l := range c.subs {
+		if seen[l] {
+			continue
+		}
+		pending, _, err := s.Pending()
+		if err != nil {
+			continue
+		}
+		delivered, err := s.Delivered()
+		if err != nil {
+			continue
+		}
+		seen[l] = true
+		ch <- prometheus.MustNewConstMetric(c.pendingDesc, prometheus.GaugeValue, float64(pending), l.subject, l.group)
+		ch <- prometheus.MustNewConstMetric(c.deliveredDesc, prometheus.CounterValue, float64(delivered), l.subject, l.group)
+	}
+}
+
+const memorySubsystem = "memory" // sub-system associated with metrics of the in-process queue.
+
+// memoryMetrics are the metrics of the in-process queue.
+type memoryMetrics struct {
+	published   *prometheus.CounterVec
+	redelivered *prometheus.CounterVec
+	unacked     *prometheus.GaugeVec
+}
+
+func newMemoryMetrics() *memoryMetrics {
+	labels := []string{"subject"}
+	return &memoryMetrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: memorySubsystem,
+			Name:      "published_total",
+			Help:      "Number of messages published.",
+		}, labels),
+		redelivered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: memorySubsystem,
+			Name:      "redelivered_total",
+			Help:      "Number of messages redelivered because they weren't acknowledged in time.",
+		}, labels),
+		unacked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: memorySubsystem,
+			Name:      "unacknowledged_messages",
+			Help:      "Number of messages published but not yet acknowledged.",
+		}, labels),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *memoryMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.published,
+		m.redelivered,
+		m.unacked,
+	}
+}
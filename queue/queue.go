@@ -0,0 +1,49 @@
+// Package queue defines the durable internal message queue used to hand off
+// work between services, such as scrape jobs and buffered writes.
+//
+// Messages are published to a subject and delivered to one subscriber of each
+// group subscribed to that subject. A message must be acknowledged once it is
+// handled; messages that aren't acknowledged in time are redelivered.
+package queue
+
+import (
+	"io"
+)
+
+// Publisher publishes messages to a subject.
+type Publisher interface {
+	// Publish a new message to channel
+	Publish(subject string, r io.Reader) error
+}
+
+// Subscriber delivers the messages of a subject to a handler.
+type Subscriber interface {
+	// Subscribe listens to a channel, handling messages with Handler
+	Subscribe(subject, group string, handler Handler) error
+}
+
+// Handler handles the messages delivered to a subscription.
+type Handler interface {
+	// Process does something with a received subscription message, then acks it.
+	Process(s Subscription, m Message)
+}
+
+// Message is a message delivered to a handler.
+type Message interface {
+	Data() []byte
+	// Ack acknowledges the message was handled. A message that isn't
+	// acknowledged is redelivered.
+	Ack() error
+}
+
+// Subscription is the subscription of a handler to a subject.
+type Subscription interface {
+	// Pending returns the number of queued messages and queued bytes for this subscription.
+	Pending() (int64, int64, error)
+
+	// Delivered returns the number of delivered messages for this subscription.
+	Delivered() (int64, error)
+
+	// Close removes this subscriber
+	Close() error
+}
@@ -2,6 +2,7 @@ package platform
 
 import (
 	"context"
+	"time"
 )
 
 // ops for ScraperTarget Store
@@ -13,6 +14,12 @@ const (
 	OpUpdateTarget  = "UpdateTarget"
 )
 
+// ops for ScraperTargetStatus Store
+const (
+	OpGetTargetStatus = "GetTargetStatus"
+	OpPutTargetStatus = "PutTargetStatus"
+)
+
 // ScraperTarget is a target to scrape
 type ScraperTarget struct {
 	ID         ID          `json:"id,omitempty"`
@@ -21,6 +28,8 @@ type ScraperTarget struct {
 	URL        string      `json:"url"`
 	OrgName    string      `json:"org"`
 	BucketName string      `json:"bucket"`
+	// Labels are added as tags to every metric gathered from the target.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // ScraperTargetStoreService defines the crud service for ScraperTarget.
@@ -32,6 +41,37 @@ type ScraperTargetStoreService interface {
 	UpdateTarget(ctx context.Context, t *ScraperTarget) (*ScraperTarget, error)
 }
 
+// ScraperTargetHealth is the health of a scraper target as of its last scrape.
+type ScraperTargetHealth string
+
+// Scraper target health values.
+const (
+	// ScraperTargetHealthUnknown is reported for targets that haven't been scraped yet.
+	ScraperTargetHealthUnknown ScraperTargetHealth = "unknown"
+	// ScraperTargetHealthUp is reported when the last scrape succeeded.
+	ScraperTargetHealthUp ScraperTargetHealth = "up"
+	// ScraperTargetHealthDown is reported when the last scrape failed.
+	ScraperTargetHealthDown ScraperTargetHealth = "down"
+)
+
+// ScraperTargetStatus holds the diagnostics of the last scrape of a target.
+type ScraperTargetStatus struct {
+	Health             ScraperTargetHealth `json:"health"`
+	LastScrape         time.Time           `json:"lastScrape"`
+	LastScrapeDuration time.Duration       `json:"lastScrapeDuration"`
+	SampleCount        int                 `json:"sampleCount"`
+	LastError          string              `json:"lastError,omitempty"`
+}
+
+// ScraperTargetStatusService records and retrieves the last scrape status of scraper targets.
+type ScraperTargetStatusService interface {
+	// GetTargetStatus returns the status of the last scrape of the target.
+	// Targets that have never been scraped report ScraperTargetHealthUnknown.
+	GetTargetStatus(ctx context.Context, id ID) (*ScraperTargetStatus, error)
+	// PutTargetStatus stores the status of the last scrape of the target.
+	PutTargetStatus(ctx context.Context, id ID, s *ScraperTargetStatus) error
+}
+
 // ScraperTargetFilter represents a set of filter that restrict the returned results.
 type ScraperTargetFilter struct {
 	ID   *ID     `json:"id"`
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/queue"
+	"go.uber.org/zap"
+)
+
+// WriteBufferSubject is the queue subject of buffered writes.
+const WriteBufferSubject = "writes"
+
+// BufferedPointsWriter writes points to a PointsWriter, unless the writer is
+// under pressure, in which case the points are published to a queue. The
+// BufferedPointsWriter also handles the messages of that queue, writing the
+// buffered points once the pressure is relieved.
+//
+// Buffered points may be written after points that were written directly
+// later on, so a point buffered may overwrite a newer value of the same series
+// and timestamp.
+type BufferedPointsWriter struct {
+	PointsWriter PointsWriter
+	Publisher    queue.Publisher
+	// UnderPressure reports whether points should be buffered rather than
+	// written directly.
+	UnderPressure func() bool
+
+	Logger *zap.Logger
+}
+
+var _ PointsWriter = (*BufferedPointsWriter)(nil)
+var _ queue.Handler = (*BufferedPointsWriter)(nil)
+
+// NewBufferedPointsWriter returns a BufferedPointsWriter for w publishing to p.
+func NewBufferedPointsWriter(w PointsWriter, p queue.Publisher, underPressure func() bool) *BufferedPointsWriter {
+	return &BufferedPointsWriter{
+		PointsWriter:  w,
+		Publisher:     p,
+		UnderPressure: underPressure,
+		Logger:        zap.NewNop(),
+	}
+}
+
+// WritePoints writes the points, or buffers them if the writer is under pressure.
+func (w *BufferedPointsWriter) WritePoints(points []models.Point) error {
+	if !w.underPressure() {
+		return w.PointsWriter.WritePoints(points)
+	}
+
+	b, err := encodePoints(points)
+	if err != nil {
+		return err
+	}
+	return w.Publisher.Publish(WriteBufferSubject, bytes.NewReader(b))
+}
+
+// Process writes the points of a buffered write. The message isn't
+// acknowledged while the writer is under pressure, so that it is redelivered.
+func (w *BufferedPointsWriter) Process(s queue.Subscription, m queue.Message) {
+	points, err := decodePoints(m.Data())
+	if err != nil {
+		w.Logger.Error("Dropping invalid buffered write", zap.Error(err))
+		m.Ack()
+		return
+	}
+
+	if w.underPressure() {
+		return
+	}
+
+	if err := w.PointsWriter.WritePoints(points); err != nil {
+		if w.underPressure() {
+			return
+		}
+		w.Logger.Error("Failed to write buffered points", zap.Int("points", len(points)), zap.Error(err))
+	}
+	m.Ack()
+}
+
+func (w *BufferedPointsWriter) underPressure() bool {
+	return w.UnderPressure != nil && w.UnderPressure()
+}
+
+// encodePoints encodes points as a sequence of length prefixed binary points.
+func encodePoints(points []models.Point) ([]byte, error) {
+	var buf bytes.Buffer
+	var size [4]byte
+	for _, p := range points {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+		buf.Write(size[:])
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+var errShortBuffer = errors.New("buffered write is truncated")
+
+// decodePoints decodes the points encoded by encodePoints.
+func decodePoints(b []byte) ([]models.Point, error) {
+	var points []models.Point
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return nil, errShortBuffer
+		}
+		n := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if uint32(len(b)) < n {
+			return nil, errShortBuffer
+		}
+		p, err := models.NewPointFromBytes(b[:n])
+		if err != nil {
+			return nil, fmt.Errorf("invalid buffered point: %v", err)
+		}
+		points = append(points, p)
+		b = b[n:]
+	}
+	return points, nil
+}
@@ -0,0 +1,82 @@
+package storage_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/queue"
+	"github.com/influxdata/platform/storage"
+)
+
+func TestBufferedPointsWriter(t *testing.T) {
+	q := queue.NewMemory()
+	q.AckWait = 10 * time.Millisecond
+	defer q.Close()
+
+	var pressure int32 = 1
+	pw := &pointsWriter{}
+	w := storage.NewBufferedPointsWriter(pw, q, func() bool { return atomic.LoadInt32(&pressure) == 1 })
+	if err := q.Subscribe(storage.WriteBufferSubject, "storage", w); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := models.ParsePointsString("cpu,host=a value=1 1000\ncpu,host=b value=2 2000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	// points are buffered while under pressure.
+	time.Sleep(50 * time.Millisecond)
+	if n := len(pw.Points()); n != 0 {
+		t.Fatalf("got %d points written under pressure, want 0", n)
+	}
+
+	atomic.StoreInt32(&pressure, 0)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pw.Points()) < len(points) {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered points were not written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for i, got := range pw.Points() {
+		want := points[i]
+		if got.String() != want.String() {
+			t.Errorf("point %d: got %q, want %q", i, got.String(), want.String())
+		}
+	}
+
+	// points are written directly without pressure.
+	if err := w.WritePoints(points[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(pw.Points()); n != len(points)+1 {
+		t.Fatalf("got %d points, want %d", n, len(points)+1)
+	}
+}
+
+// pointsWriter records the points written to it.
+type pointsWriter struct {
+	mu     sync.Mutex
+	points []models.Point
+}
+
+func (w *pointsWriter) WritePoints(points []models.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, points...)
+	return nil
+}
+
+func (w *pointsWriter) Points() []models.Point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]models.Point(nil), w.points...)
+}
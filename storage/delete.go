@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync/atomic"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// DeleteOrganizationData deletes all the data of the buckets of an
+// organization. progress is called with the number of series deleted so
+// far as the deletion goes on.
+func (e *Engine) DeleteOrganizationData(ctx context.Context, orgID platform.ID, progress func(series uint64)) error {
+	return deleteOrganizationData(ctx, e, orgID, progress)
+}
+
+// DeleteBucketData deletes all the data of a bucket.
+func (e *Engine) DeleteBucketData(ctx context.Context, orgID, bucketID platform.ID) error {
+	return deleteBucketData(ctx, e, orgID, bucketID)
+}
+
+// deleteOrganizationData deletes the series whose measurement starts with
+// the ID of the organization, as the measurements are the exploded
+// org/bucket pairs.
+func deleteOrganizationData(ctx context.Context, engine Deleter, orgID platform.ID, progress func(series uint64)) error {
+	name := tsdb.EncodeName(orgID, 0)
+	prefix := name[:8]
+	return deleteSeries(ctx, engine, func(name []byte) bool {
+		return len(name) == platform.IDLength && bytes.HasPrefix(name, prefix)
+	}, progress)
+}
+
+// deleteBucketData deletes the series whose measurement is the exploded
+// org/bucket pair of the bucket.
+func deleteBucketData(ctx context.Context, engine Deleter, orgID, bucketID platform.ID) error {
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return deleteSeries(ctx, engine, func(name []byte) bool {
+		return bytes.Equal(name, encoded[:])
+	}, nil)
+}
+
+// deleteSeries deletes the whole range of the series whose measurement
+// matches. progress, if not nil, is called with the number of series
+// deleted so far. Unlike the deletions of the retention enforcer, it isn't
+// bounded by engineAPITimeout, as the data of an organization can take much
+// longer to delete.
+func deleteSeries(ctx context.Context, engine Deleter, match func(name []byte) bool, progress func(series uint64)) error {
+	cur, err := engine.CreateSeriesCursor(ctx, SeriesCursorRequest{}, nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	var seriesDeleted uint64
+	fn := func(name []byte, tags models.Tags) (int64, int64, bool) {
+		if !match(name) {
+			return 0, 0, false
+		}
+		n := atomic.AddUint64(&seriesDeleted, 1)
+		if progress != nil {
+			progress(n)
+		}
+		return math.MinInt64, math.MaxInt64, true
+	}
+	return engine.DeleteSeriesRangeWithPredicate(newSeriesIteratorAdapter(cur), fn)
+}
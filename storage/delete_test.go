@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+func TestDeleteOrganizationData(t *testing.T) {
+	orgID, otherOrgID := platform.ID(1), platform.ID(2)
+	deleted := tsdb.EncodeName(orgID, 10)
+	deleted2 := tsdb.EncodeName(orgID, 11)
+	kept := tsdb.EncodeName(otherOrgID, 10)
+	names := [][]byte{deleted[:], kept[:], deleted2[:], []byte("zyzwrong"), deleted[:]}
+
+	engine := NewTestEngine()
+	var got []string
+	engine.DeleteSeriesRangeWithPredicateFn = func(_ tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
+		for _, name := range names {
+			from, to, ok := fn(name, nil)
+			if !ok {
+				continue
+			}
+			if from != math.MinInt64 || to != math.MaxInt64 {
+				t.Errorf("expected the whole range of %x to be deleted, got %d to %d", name, from, to)
+			}
+			got = append(got, string(name))
+		}
+		return nil
+	}
+
+	var progress uint64
+	if err := deleteOrganizationData(context.Background(), engine, orgID, func(n uint64) { progress = n }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != string(deleted[:]) || got[1] != string(deleted2[:]) {
+		t.Fatalf("unexpected series deleted %x", got)
+	}
+	if progress != 3 {
+		t.Fatalf("expected progress of 3 series, got %d", progress)
+	}
+}
+
+func TestDeleteBucketData(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(10)
+	deleted := tsdb.EncodeName(orgID, bucketID)
+	otherBucket := tsdb.EncodeName(orgID, 11)
+	otherOrg := tsdb.EncodeName(2, bucketID)
+	names := [][]byte{deleted[:], otherBucket[:], otherOrg[:], deleted[:]}
+
+	engine := NewTestEngine()
+	var got int
+	engine.DeleteSeriesRangeWithPredicateFn = func(_ tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
+		for _, name := range names {
+			if _, _, ok := fn(name, nil); ok {
+				if string(name) != string(deleted[:]) {
+					t.Errorf("unexpected series deleted %x", name)
+				}
+				got++
+			}
+		}
+		return nil
+	}
+
+	if err := deleteBucketData(context.Background(), engine, orgID, bucketID); err != nil {
+		t.Fatal(err)
+	}
+	if got != 2 {
+		t.Fatalf("expected 2 series deleted, got %d", got)
+	}
+}
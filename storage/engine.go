@@ -364,6 +364,27 @@ func (e *Engine) WritePoints(points []models.Point) error {
 	return collection.PartialWriteError()
 }
 
+// writePressureRatio is the fraction of the cache-max-memory-size above
+// which the engine reports being under write pressure.
+const writePressureRatio = 0.9
+
+// UnderWritePressure returns true when the cache is close to its maximum size,
+// which happens when snapshot compactions can't keep up with the writes. Writes
+// are rejected once the cache is full.
+func (e *Engine) UnderWritePressure() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return false
+	}
+
+	max := e.engine.Cache.MaxSize()
+	if max == 0 {
+		return false
+	}
+	return float64(e.engine.Cache.Size()) >= writePressureRatio*float64(max)
+}
+
 // DeleteSeriesRangeWithPredicate deletes all series data iterated over if fn returns
 // true for that series.
 func (e *Engine) DeleteSeriesRangeWithPredicate(itr tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
@@ -14,6 +14,9 @@ import (
 	fstorage "github.com/influxdata/platform/query/functions/inputs/storage"
 	"github.com/influxdata/platform/storage/reads/datatypes"
 	"github.com/influxdata/platform/tsdb/cursors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
 )
 
 type storageTable interface {
@@ -62,7 +65,7 @@ type tableIterator struct {
 
 func (bi *tableIterator) Statistics() flux.Statistics { return bi.stats }
 
-func (bi *tableIterator) Do(f func(flux.Table) error) error {
+func (bi *tableIterator) Do(f func(flux.Table) error) (err error) {
 	src, err := bi.s.GetSource(bi.readSpec)
 	if err != nil {
 		return err
@@ -95,9 +98,22 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 		req.Aggregate = &datatypes.Aggregate{Type: agg}
 	}
 
+	span, ctx := opentracing.StartSpanFromContext(bi.ctx, "storage.read")
+	span.SetTag("group", req.Group.String())
+	span.SetTag("aggregate", bi.readSpec.AggregateMethod)
+	defer func() {
+		span.SetTag("scanned_values", bi.stats.ScannedValues)
+		span.SetTag("scanned_bytes", bi.stats.ScannedBytes)
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(log.Error(err))
+		}
+		span.Finish()
+	}()
+
 	switch {
 	case req.Group != datatypes.GroupAll:
-		rs, err := bi.s.GroupRead(bi.ctx, &req)
+		rs, err := bi.s.GroupRead(ctx, &req)
 		if err != nil {
 			return err
 		}
@@ -112,7 +128,7 @@ func (bi *tableIterator) Do(f func(flux.Table) error) error {
 		return bi.handleGroupRead(f, rs)
 
 	default:
-		rs, err := bi.s.Read(bi.ctx, &req)
+		rs, err := bi.s.Read(ctx, &req)
 		if err != nil {
 			return err
 		}
@@ -191,8 +207,9 @@ READ:
 			}
 		}
 
-		table.Close()
+		// the statistics of a table are gone once it is closed.
 		bi.stats = bi.stats.Add(table.Statistics())
+		table.Close()
 		table = nil
 	}
 	return rs.Err()
@@ -315,6 +332,7 @@ READ:
 			break READ
 		}
 
+		bi.stats = bi.stats.Add(table.Statistics())
 		table.Close()
 		table = nil
 
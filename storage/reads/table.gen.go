@@ -130,6 +130,8 @@ type floatGroupTable struct {
 	mu     sync.Mutex
 	gc     GroupCursor
 	cur    cursors.FloatArrayCursor
+	// stats are the statistics of the cursors already read.
+	stats flux.Statistics
 }
 
 func newFloatGroupTable(
@@ -223,6 +225,9 @@ RETRY:
 }
 
 func (t *floatGroupTable) advanceCursor() bool {
+	cs := t.cur.Stats()
+	t.stats.ScannedValues += cs.ScannedValues
+	t.stats.ScannedBytes += cs.ScannedBytes
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -247,13 +252,13 @@ func (t *floatGroupTable) advanceCursor() bool {
 
 func (t *floatGroupTable) Statistics() flux.Statistics {
 	if t.cur == nil {
-		return flux.Statistics{}
+		return t.stats
 	}
 	cs := t.cur.Stats()
-	return flux.Statistics{
+	return t.stats.Add(flux.Statistics{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
-	}
+	})
 }
 
 //
@@ -368,6 +373,8 @@ type integerGroupTable struct {
 	mu     sync.Mutex
 	gc     GroupCursor
 	cur    cursors.IntegerArrayCursor
+	// stats are the statistics of the cursors already read.
+	stats flux.Statistics
 }
 
 func newIntegerGroupTable(
@@ -461,6 +468,9 @@ RETRY:
 }
 
 func (t *integerGroupTable) advanceCursor() bool {
+	cs := t.cur.Stats()
+	t.stats.ScannedValues += cs.ScannedValues
+	t.stats.ScannedBytes += cs.ScannedBytes
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -485,13 +495,13 @@ func (t *integerGroupTable) advanceCursor() bool {
 
 func (t *integerGroupTable) Statistics() flux.Statistics {
 	if t.cur == nil {
-		return flux.Statistics{}
+		return t.stats
 	}
 	cs := t.cur.Stats()
-	return flux.Statistics{
+	return t.stats.Add(flux.Statistics{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
-	}
+	})
 }
 
 //
@@ -606,6 +616,8 @@ type unsignedGroupTable struct {
 	mu     sync.Mutex
 	gc     GroupCursor
 	cur    cursors.UnsignedArrayCursor
+	// stats are the statistics of the cursors already read.
+	stats flux.Statistics
 }
 
 func newUnsignedGroupTable(
@@ -699,6 +711,9 @@ RETRY:
 }
 
 func (t *unsignedGroupTable) advanceCursor() bool {
+	cs := t.cur.Stats()
+	t.stats.ScannedValues += cs.ScannedValues
+	t.stats.ScannedBytes += cs.ScannedBytes
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -723,13 +738,13 @@ func (t *unsignedGroupTable) advanceCursor() bool {
 
 func (t *unsignedGroupTable) Statistics() flux.Statistics {
 	if t.cur == nil {
-		return flux.Statistics{}
+		return t.stats
 	}
 	cs := t.cur.Stats()
-	return flux.Statistics{
+	return t.stats.Add(flux.Statistics{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
-	}
+	})
 }
 
 //
@@ -844,6 +859,8 @@ type stringGroupTable struct {
 	mu     sync.Mutex
 	gc     GroupCursor
 	cur    cursors.StringArrayCursor
+	// stats are the statistics of the cursors already read.
+	stats flux.Statistics
 }
 
 func newStringGroupTable(
@@ -937,6 +954,9 @@ RETRY:
 }
 
 func (t *stringGroupTable) advanceCursor() bool {
+	cs := t.cur.Stats()
+	t.stats.ScannedValues += cs.ScannedValues
+	t.stats.ScannedBytes += cs.ScannedBytes
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -961,13 +981,13 @@ func (t *stringGroupTable) advanceCursor() bool {
 
 func (t *stringGroupTable) Statistics() flux.Statistics {
 	if t.cur == nil {
-		return flux.Statistics{}
+		return t.stats
 	}
 	cs := t.cur.Stats()
-	return flux.Statistics{
+	return t.stats.Add(flux.Statistics{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
-	}
+	})
 }
 
 //
@@ -1082,6 +1102,8 @@ type booleanGroupTable struct {
 	mu     sync.Mutex
 	gc     GroupCursor
 	cur    cursors.BooleanArrayCursor
+	// stats are the statistics of the cursors already read.
+	stats flux.Statistics
 }
 
 func newBooleanGroupTable(
@@ -1175,6 +1197,9 @@ RETRY:
 }
 
 func (t *booleanGroupTable) advanceCursor() bool {
+	cs := t.cur.Stats()
+	t.stats.ScannedValues += cs.ScannedValues
+	t.stats.ScannedBytes += cs.ScannedBytes
 	t.cur.Close()
 	t.cur = nil
 	for t.gc.Next() {
@@ -1199,11 +1224,11 @@ func (t *booleanGroupTable) advanceCursor() bool {
 
 func (t *booleanGroupTable) Statistics() flux.Statistics {
 	if t.cur == nil {
-		return flux.Statistics{}
+		return t.stats
 	}
 	cs := t.cur.Stats()
-	return flux.Statistics{
+	return t.stats.Add(flux.Statistics{
 		ScannedValues: cs.ScannedValues,
 		ScannedBytes:  cs.ScannedBytes,
-	}
+	})
 }
@@ -104,12 +104,12 @@ func newFullStackAwareLogReaderWriter(t *testing.T) *fullStackAwareLogReaderWrit
 	}
 
 	if err := readservice.AddControllerConfigDependencies(
-		&cc, engine, svc, svc,
+		&cc, engine, engine, svc, svc,
 	); err != nil {
 		t.Fatal(err)
 	}
 
-	queryController := pcontrol.New(cc)
+	queryController := pcontrol.New(pcontrol.Config{Config: cc})
 
 	return &fullStackAwareLogReaderWriter{
 		PointLogWriter: backend.NewPointLogWriter(engine),
@@ -11,6 +11,8 @@ import (
 
 	"github.com/influxdata/platform"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -632,10 +634,14 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 
 	sp, spCtx := opentracing.StartSpanFromContext(ctx, "task.run.execution")
 	defer sp.Finish()
+	sp.SetTag("task_id", qr.TaskID.String())
+	sp.SetTag("run_id", qr.RunID.String())
+	sp.SetTag("now", time.Unix(qr.Now, 0).UTC().Format(time.RFC3339))
 
 	rp, err := r.executor.Execute(spCtx, qr)
 
 	if err != nil {
+		traceRunError(sp, err)
 		// TODO(mr): retry? and log error.
 		atomic.StoreUint32(r.state, runnerIdle)
 		r.updateRunState(qr, RunFail, runLogger)
@@ -664,6 +670,7 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	close(ready)
 	if err != nil {
 		if err == ErrRunCanceled {
+			sp.SetTag("canceled", true)
 			_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
 			r.updateRunState(qr, RunCanceled, runLogger)
 
@@ -673,6 +680,7 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 		}
 
 		runLogger.Info("Failed to wait for execution result", zap.Error(err))
+		traceRunError(sp, err)
 		// TODO(mr): retry?
 		r.updateRunState(qr, RunFail, runLogger)
 		atomic.StoreUint32(r.state, runnerIdle)
@@ -681,6 +689,7 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 
 	if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
 		runLogger.Info("Failed to finish run", zap.Error(err))
+		traceRunError(sp, err)
 		// TODO(mr): retry?
 		// Need to think about what it means if there was an error finishing a run.
 		atomic.StoreUint32(r.state, runnerIdle)
@@ -694,6 +703,12 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	r.startFromWorking(atomic.LoadInt64(r.ts.now))
 }
 
+// traceRunError marks the span of a run as failed.
+func traceRunError(sp opentracing.Span, err error) {
+	ext.Error.Set(sp, true)
+	sp.LogFields(log.Error(err))
+}
+
 func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
 	rlb := RunLogBase{
 		Task:            r.task,
@@ -47,6 +47,13 @@ type TelegrafConfig struct {
 	LastMod   time.Time
 	LastModBy ID
 
+	// AuthorizationID is the authorization provisioned for the config, whose
+	// token is embedded in its influxdb_v2 outputs.
+	AuthorizationID ID
+
+	// OrganizationID is the organization the config belongs to.
+	OrganizationID ID
+
 	Agent   TelegrafAgentConfig
 	Plugins []TelegrafPlugin
 }
@@ -123,6 +130,9 @@ type telegrafConfigEncode struct {
 	LastMod   time.Time `json:"lastModified"`
 	LastModBy ID        `json:"lastModifiedBy"`
 
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+	OrganizationID  ID `json:"orgID,omitempty"`
+
 	Agent TelegrafAgentConfig `json:"agent"`
 
 	Plugins []telegrafPluginEncode `json:"plugins"`
@@ -145,6 +155,9 @@ type telegrafConfigDecode struct {
 	LastMod   time.Time `json:"lastModified"`
 	LastModBy ID        `json:"lastModifiedBy"`
 
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+	OrganizationID  ID `json:"orgID,omitempty"`
+
 	Agent TelegrafAgentConfig `json:"agent"`
 
 	Plugins []telegrafPluginDecode `json:"plugins"`
@@ -177,6 +190,7 @@ const (
 	ErrNoTelegrafPlugins           = "there is no telegraf plugin in the config"
 	ErrUnsupportTelegrafPluginType = "unsupported telegraf plugin type %s"
 	ErrUnsupportTelegrafPluginName = "unsupported telegraf plugin %s, type %s"
+	ErrInvalidTelegrafPlugin       = "invalid telegraf plugin %s, type %s: %v"
 )
 
 // MarshalJSON implement the json.Marshaler interface.
@@ -190,6 +204,9 @@ func (tc *TelegrafConfig) MarshalJSON() ([]byte, error) {
 		LastMod:   tc.LastMod,
 		LastModBy: tc.LastModBy,
 		Plugins:   make([]telegrafPluginEncode, len(tc.Plugins)),
+
+		AuthorizationID: tc.AuthorizationID,
+		OrganizationID:  tc.OrganizationID,
 	}
 	for k, p := range tc.Plugins {
 		tce.Plugins[k] = telegrafPluginEncode{
@@ -243,7 +260,13 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 				}
 				continue
 			}
-			for _, configData := range configDataArray.([]map[string]interface{}) {
+			configs, ok := configDataArray.([]map[string]interface{})
+			if !ok {
+				return &Error{
+					Msg: fmt.Sprintf("bad config for telegraf plugin %s, type %s", name, tp),
+				}
+			}
+			for _, configData := range configs {
 				if err := tc.parseTOMLPluginConfig(tp, name, configData); err != nil {
 					return err
 				}
@@ -255,35 +278,66 @@ func (tc *TelegrafConfig) UnmarshalTOML(data interface{}) error {
 }
 
 func (tc *TelegrafConfig) parseTOMLPluginConfig(typ, name string, configData interface{}) error {
-	var ok bool
-	var tpFn func() plugins.Config
+	var tp plugins.Type
 	switch typ {
 	case "inputs":
-		tpFn, ok = availableInputPlugins[name]
+		tp = plugins.Input
 	case "outputs":
-		tpFn, ok = availableOutputPlugins[name]
+		tp = plugins.Output
+	case "processors":
+		tp = plugins.Processor
+	case "aggregators":
+		tp = plugins.Aggregator
 	default:
 		return &Error{
 			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginType, typ),
 		}
 	}
 
-	if !ok {
-		return &Error{
-			Msg: fmt.Sprintf(ErrUnsupportTelegrafPluginName, name, typ),
-		}
-	}
-	p := tpFn()
-
+	p := newTelegrafPluginConfig(tp, name)
 	if err := p.UnmarshalTOML(configData); err != nil {
 		return err
 	}
+	if err := validateTelegrafPlugin(p); err != nil {
+		return err
+	}
 	tc.Plugins = append(tc.Plugins, TelegrafPlugin{
 		Config: p,
 	})
 	return nil
 }
 
+// newTelegrafPluginConfig returns the typed config of the plugin, or a
+// generic config that keeps the settings as they are if there is none.
+func newTelegrafPluginConfig(typ plugins.Type, name string) plugins.Config {
+	var tpFn func() plugins.Config
+	switch typ {
+	case plugins.Input:
+		tpFn = availableInputPlugins[name]
+	case plugins.Output:
+		tpFn = availableOutputPlugins[name]
+	}
+	if tpFn != nil {
+		return tpFn()
+	}
+	return plugins.NewGeneric(typ, name)
+}
+
+// validateTelegrafPlugin checks the settings of the plugins that can be validated.
+func validateTelegrafPlugin(p plugins.Config) error {
+	v, ok := p.(plugins.Validator)
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf(ErrInvalidTelegrafPlugin, p.PluginName(), p.Type(), err),
+		}
+	}
+	return nil
+}
+
 // UnmarshalJSON implement the json.Unmarshaler interface.
 func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 	tcd := new(telegrafConfigDecode)
@@ -298,6 +352,9 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 		LastModBy: tcd.LastModBy,
 		Agent:     tcd.Agent,
 		Plugins:   make([]TelegrafPlugin, len(tcd.Plugins)),
+
+		AuthorizationID: tcd.AuthorizationID,
+		OrganizationID:  tcd.OrganizationID,
 	}
 	return decodePluginRaw(tcd, tc)
 }
@@ -305,67 +362,60 @@ func (tc *TelegrafConfig) UnmarshalJSON(b []byte) error {
 func decodePluginRaw(tcd *telegrafConfigDecode, tc *TelegrafConfig) (err error) {
 	op := "unmarshal telegraf config raw plugin"
 	for k, pr := range tcd.Plugins {
-		var tpFn func() plugins.Config
-		var config plugins.Config
-		var ok bool
-		switch pr.Type {
-		case plugins.Input:
-			tpFn, ok = availableInputPlugins[pr.Name]
-		case plugins.Output:
-			tpFn, ok = availableOutputPlugins[pr.Name]
-		default:
+		if pr.Type.Section() == "" {
 			return &Error{
 				Code: EInvalid,
 				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, pr.Type),
 				Op:   op,
 			}
 		}
-		if ok {
-			config = tpFn()
-			if err = json.Unmarshal(pr.Config, config); err != nil {
-				return &Error{
-					Code: EInvalid,
-					Err:  err,
-					Op:   op,
-				}
-			}
-			tc.Plugins[k] = TelegrafPlugin{
-				Comment: pr.Comment,
-				Config:  config,
+		config := newTelegrafPluginConfig(pr.Type, pr.Name)
+		if err = json.Unmarshal(pr.Config, config); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Err:  err,
+				Op:   op,
 			}
-			continue
 		}
-		return &Error{
-			Code: EInvalid,
-			Op:   op,
-			Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, pr.Name, pr.Type),
+		if err = validateTelegrafPlugin(config); err != nil {
+			err.(*Error).Op = op
+			return err
+		}
+		tc.Plugins[k] = TelegrafPlugin{
+			Comment: pr.Comment,
+			Config:  config,
 		}
-
 	}
 	return nil
 }
 
 var availableInputPlugins = map[string](func() plugins.Config){
-	"cpu":          func() plugins.Config { return &inputs.CPUStats{} },
-	"disk":         func() plugins.Config { return &inputs.DiskStats{} },
-	"diskio":       func() plugins.Config { return &inputs.DiskIO{} },
-	"docker":       func() plugins.Config { return &inputs.Docker{} },
-	"file":         func() plugins.Config { return &inputs.File{} },
-	"kernel":       func() plugins.Config { return &inputs.Kernel{} },
-	"kubernetes":   func() plugins.Config { return &inputs.Kubernetes{} },
-	"logparser":    func() plugins.Config { return &inputs.LogParserPlugin{} },
-	"mem":          func() plugins.Config { return &inputs.MemStats{} },
-	"net_response": func() plugins.Config { return &inputs.NetResponse{} },
-	"net":          func() plugins.Config { return &inputs.NetIOStats{} },
-	"ngnix":        func() plugins.Config { return &inputs.Nginx{} },
-	"processes":    func() plugins.Config { return &inputs.Processes{} },
-	"procstats":    func() plugins.Config { return &inputs.Procstat{} },
-	"prometheus":   func() plugins.Config { return &inputs.Prometheus{} },
-	"redis":        func() plugins.Config { return &inputs.Redis{} },
-	"swap":         func() plugins.Config { return &inputs.SwapStats{} },
-	"syslog":       func() plugins.Config { return &inputs.Syslog{} },
-	"system":       func() plugins.Config { return &inputs.SystemStats{} },
-	"tail":         func() plugins.Config { return &inputs.Tail{} },
+	"cpu":            func() plugins.Config { return &inputs.CPUStats{} },
+	"disk":           func() plugins.Config { return &inputs.DiskStats{} },
+	"diskio":         func() plugins.Config { return &inputs.DiskIO{} },
+	"docker":         func() plugins.Config { return &inputs.Docker{} },
+	"exec":           func() plugins.Config { return &inputs.Exec{} },
+	"file":           func() plugins.Config { return &inputs.File{} },
+	"http":           func() plugins.Config { return &inputs.HTTP{} },
+	"kafka_consumer": func() plugins.Config { return &inputs.KafkaConsumer{} },
+	"kernel":         func() plugins.Config { return &inputs.Kernel{} },
+	"kubernetes":     func() plugins.Config { return &inputs.Kubernetes{} },
+	"logparser":      func() plugins.Config { return &inputs.LogParserPlugin{} },
+	"mem":            func() plugins.Config { return &inputs.MemStats{} },
+	"mqtt_consumer":  func() plugins.Config { return &inputs.MQTTConsumer{} },
+	"net_response":   func() plugins.Config { return &inputs.NetResponse{} },
+	"net":            func() plugins.Config { return &inputs.NetIOStats{} },
+	"ngnix":          func() plugins.Config { return &inputs.Nginx{} },
+	"postgresql":     func() plugins.Config { return &inputs.Postgresql{} },
+	"processes":      func() plugins.Config { return &inputs.Processes{} },
+	"procstats":      func() plugins.Config { return &inputs.Procstat{} },
+	"prometheus":     func() plugins.Config { return &inputs.Prometheus{} },
+	"redis":          func() plugins.Config { return &inputs.Redis{} },
+	"snmp":           func() plugins.Config { return &inputs.SNMP{} },
+	"swap":           func() plugins.Config { return &inputs.SwapStats{} },
+	"syslog":         func() plugins.Config { return &inputs.Syslog{} },
+	"system":         func() plugins.Config { return &inputs.SystemStats{} },
+	"tail":           func() plugins.Config { return &inputs.Tail{} },
 }
 
 var availableOutputPlugins = map[string](func() plugins.Config){
@@ -0,0 +1,234 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Validator is implemented by the plugin configs that can check their
+// settings beyond decoding them.
+type Validator interface {
+	// Validate returns an error if the config can't be used by telegraf.
+	Validate() error
+}
+
+// Section returns the name of the toml table of the plugins of the type,
+// exp "inputs", or an empty string if the type is unknown.
+func (t Type) Section() string {
+	switch t {
+	case Input, Output, Processor, Aggregator:
+		return string(t) + "s"
+	}
+	return ""
+}
+
+// Generic is a passthrough config for the telegraf plugins that don't
+// have a typed model. Its settings are kept as they were parsed, so the
+// plugin table round-trips through TOML and JSON without losing settings.
+type Generic struct {
+	PluginType Type
+	Name       string
+	Settings   map[string]interface{}
+}
+
+// NewGeneric returns an empty Generic config for the named plugin.
+func NewGeneric(typ Type, name string) *Generic {
+	return &Generic{
+		PluginType: typ,
+		Name:       name,
+		Settings:   make(map[string]interface{}),
+	}
+}
+
+// Type is the plugin type.
+func (g *Generic) Type() Type {
+	return g.PluginType
+}
+
+// PluginName is the telegraf plugin name.
+func (g *Generic) PluginName() string {
+	return g.Name
+}
+
+// TOML encodes to toml string.
+func (g *Generic) TOML() string {
+	return strings.Join(encodeTable(g.PluginType, g.Name, g.Settings), "")
+}
+
+// encodeTable encodes the settings as the lines of the table of the plugin,
+// starting with its [[inputs.name]] header.
+func encodeTable(typ Type, name string, settings map[string]interface{}) []string {
+	section := typ.Section()
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = "  "
+	err := enc.Encode(map[string]interface{}{
+		section: map[string]interface{}{
+			name: []map[string]interface{}{tomlSettings(settings)},
+		},
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("# unable to encode %s.%s: %v\n", section, name, err)}
+	}
+
+	// drop the [inputs] header, the plugin tables are nested under it.
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if line == "" || line == "["+section+"]" {
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(line, "  ")+"\n")
+	}
+	return lines
+}
+
+// UnmarshalTOML decodes the parsed data to the object.
+func (g *Generic) UnmarshalTOML(data interface{}) error {
+	if data == nil {
+		g.Settings = make(map[string]interface{})
+		return nil
+	}
+	settings, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("bad config for %s plugin %s", g.PluginType, g.Name)
+	}
+	g.Settings = settings
+	return nil
+}
+
+// Settings are the settings of a typed plugin config that its model doesn't
+// declare. They are kept as they were parsed, so that the plugin table
+// round-trips through TOML and JSON without losing them.
+type Settings map[string]interface{}
+
+// UndeclaredSettings returns the settings of data whose keys aren't
+// declared, or nil if there are none.
+func UndeclaredSettings(data map[string]interface{}, declared ...string) Settings {
+	var s Settings
+	for k, v := range data {
+		if containsKey(declared, k) {
+			continue
+		}
+		if s == nil {
+			s = make(Settings)
+		}
+		s[k] = v
+	}
+	return s
+}
+
+func containsKey(keys []string, k string) bool {
+	for _, key := range keys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// TOML encodes the settings as the lines following the header of the table
+// of the plugin.
+func (s Settings) TOML(typ Type, name string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	lines := encodeTable(typ, name, s)
+	return strings.Join(lines[1:], "")
+}
+
+// UnmarshalJSON decodes the settings, keeping integers as integers.
+func (s *Settings) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var settings map[string]interface{}
+	if err := dec.Decode(&settings); err != nil {
+		return err
+	}
+	if settings != nil {
+		*s = jsonSettings(settings).(map[string]interface{})
+	}
+	return nil
+}
+
+// MarshalJSON encodes the settings of the plugin.
+func (g *Generic) MarshalJSON() ([]byte, error) {
+	if g.Settings == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(g.Settings)
+}
+
+// UnmarshalJSON decodes the settings of the plugin. Integers are kept as
+// integers, as telegraf tells them apart from floats.
+func (g *Generic) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var settings map[string]interface{}
+	if err := dec.Decode(&settings); err != nil {
+		return err
+	}
+	if settings == nil {
+		return errors.New("generic plugin config must be an object")
+	}
+	g.Settings = jsonSettings(settings).(map[string]interface{})
+	return nil
+}
+
+// jsonSettings converts the json numbers of decoded settings.
+func jsonSettings(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = jsonSettings(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonSettings(e)
+		}
+		return v
+	}
+	return v
+}
+
+// tomlSettings converts the arrays of objects decoded from json to arrays
+// of tables, which is how the toml encoder expects them.
+func tomlSettings(settings map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		out[k] = tomlValue(v)
+	}
+	return out
+}
+
+func tomlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return tomlSettings(v)
+	case []interface{}:
+		tables := make([]map[string]interface{}, 0, len(v))
+		for _, e := range v {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return v
+			}
+			tables = append(tables, tomlSettings(m))
+		}
+		if len(tables) == 0 {
+			return v
+		}
+		return tables
+	}
+	return v
+}
@@ -1,9 +1,54 @@
 package inputs
 
-import "github.com/influxdata/platform/telegraf/plugins"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
 
 type baseInput int
 
 func (b baseInput) Type() plugins.Type {
 	return plugins.Input
 }
+
+// stringArray returns the strings of a toml array, ok is false if v isn't an
+// array of strings.
+func stringArray(v interface{}) (ss []string, ok bool) {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	for _, e := range arr {
+		s, ok := e.(string)
+		if !ok {
+			return nil, false
+		}
+		ss = append(ss, s)
+	}
+	return ss, true
+}
+
+// quoteStrings returns the strings quoted for a toml array.
+func quoteStrings(ss []string) string {
+	s := make([]string, len(ss))
+	for k, v := range ss {
+		s[k] = strconv.Quote(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// validateDuration returns an error if the setting isn't a valid duration.
+// An empty setting uses the default of the plugin.
+func validateDuration(d, setting, plugin string) error {
+	if d == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(d); err != nil {
+		return fmt.Errorf("%s is not a valid duration for %s input plugin: %v", setting, plugin, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
+
+// Exec is based on telegraf Exec plugin.
+type Exec struct {
+	baseInput
+	Commands   []string `json:"commands"`
+	Timeout    string   `json:"timeout"`
+	DataFormat string   `json:"dataFormat"`
+	// Settings are the settings of the plugin that the model doesn't declare.
+	Settings plugins.Settings `json:"settings,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (e *Exec) PluginName() string {
+	return "exec"
+}
+
+// TOML encodes to toml string
+func (e *Exec) TOML() string {
+	timeout := e.Timeout
+	if timeout == "" {
+		timeout = "5s"
+	}
+	dataFormat := e.DataFormat
+	if dataFormat == "" {
+		dataFormat = "influx"
+	}
+	return fmt.Sprintf(`[[inputs.%s]]
+  ## Commands array
+  commands = [%s]
+
+  ## Timeout for each command to complete.
+  timeout = %s
+
+  ## Data format to consume.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
+  data_format = %s
+`, e.PluginName(), quoteStrings(e.Commands), strconv.Quote(timeout), strconv.Quote(dataFormat)) + e.Settings.TOML(plugins.Input, e.PluginName())
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (e *Exec) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad commands for exec input plugin")
+	}
+	if e.Commands, ok = stringArray(dataOK["commands"]); !ok {
+		return errors.New("commands is not an array for exec input plugin")
+	}
+	e.Timeout, _ = dataOK["timeout"].(string)
+	e.DataFormat, _ = dataOK["data_format"].(string)
+	e.Settings = plugins.UndeclaredSettings(dataOK, "commands", "timeout", "data_format")
+	return nil
+}
+
+// Validate checks the commands and timeout.
+func (e *Exec) Validate() error {
+	if len(e.Commands) == 0 {
+		return errors.New("exec input plugin requires at least one command")
+	}
+	return validateDuration(e.Timeout, "timeout", e.PluginName())
+}
@@ -0,0 +1,86 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
+
+// HTTP is based on telegraf HTTP plugin.
+type HTTP struct {
+	baseInput
+	URLs       []string `json:"urls"`
+	Method     string   `json:"method"`
+	Timeout    string   `json:"timeout"`
+	DataFormat string   `json:"dataFormat"`
+	// Settings are the settings of the plugin that the model doesn't declare.
+	Settings plugins.Settings `json:"settings,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (h *HTTP) PluginName() string {
+	return "http"
+}
+
+// TOML encodes to toml string
+func (h *HTTP) TOML() string {
+	method := h.Method
+	if method == "" {
+		method = "GET"
+	}
+	timeout := h.Timeout
+	if timeout == "" {
+		timeout = "5s"
+	}
+	dataFormat := h.DataFormat
+	if dataFormat == "" {
+		dataFormat = "influx"
+	}
+	return fmt.Sprintf(`[[inputs.%s]]
+  ## One or more URLs from which to read formatted metrics
+  urls = [%s]
+
+  ## HTTP method
+  method = %s
+
+  ## Amount of time allowed to complete the HTTP request
+  timeout = %s
+
+  ## Data format to consume.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
+  data_format = %s
+`, h.PluginName(), quoteStrings(h.URLs), strconv.Quote(method), strconv.Quote(timeout), strconv.Quote(dataFormat)) + h.Settings.TOML(plugins.Input, h.PluginName())
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (h *HTTP) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad urls for http input plugin")
+	}
+	if h.URLs, ok = stringArray(dataOK["urls"]); !ok {
+		return errors.New("urls is not an array for http input plugin")
+	}
+	h.Method, _ = dataOK["method"].(string)
+	h.Timeout, _ = dataOK["timeout"].(string)
+	h.DataFormat, _ = dataOK["data_format"].(string)
+	h.Settings = plugins.UndeclaredSettings(dataOK, "urls", "method", "timeout", "data_format")
+	return nil
+}
+
+// Validate checks the urls, method and timeout.
+func (h *HTTP) Validate() error {
+	if len(h.URLs) == 0 {
+		return errors.New("http input plugin requires at least one url")
+	}
+	switch h.Method {
+	case "", "GET", "POST", "PUT", "PATCH", "HEAD":
+	default:
+		return fmt.Errorf("unsupported method %q for http input plugin", h.Method)
+	}
+	return validateDuration(h.Timeout, "timeout", h.PluginName())
+}
@@ -477,6 +477,97 @@ func TestDecodeTOML(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "exec bad data not array",
+			want:    &Exec{},
+			wantErr: errors.New("commands is not an array for exec input plugin"),
+			input:   &Exec{},
+			data: map[string]interface{}{
+				"commands": "/usr/bin/collect",
+			},
+		},
+		{
+			name: "exec",
+			want: &Exec{
+				Commands:   []string{"/usr/bin/collect"},
+				Timeout:    "10s",
+				DataFormat: "json",
+			},
+			input: &Exec{},
+			data: map[string]interface{}{
+				"commands":    []interface{}{"/usr/bin/collect"},
+				"timeout":     "10s",
+				"data_format": "json",
+			},
+		},
+		{
+			name: "http",
+			want: &HTTP{
+				URLs:   []string{"http://localhost/metrics"},
+				Method: "POST",
+			},
+			input: &HTTP{},
+			data: map[string]interface{}{
+				"urls":   []interface{}{"http://localhost/metrics"},
+				"method": "POST",
+			},
+		},
+		{
+			name: "kafka_consumer",
+			want: &KafkaConsumer{
+				Brokers:       []string{"localhost:9092"},
+				Topics:        []string{"telegraf"},
+				ConsumerGroup: "g1",
+				Offset:        "newest",
+			},
+			input: &KafkaConsumer{},
+			data: map[string]interface{}{
+				"brokers":        []interface{}{"localhost:9092"},
+				"topics":         []interface{}{"telegraf"},
+				"consumer_group": "g1",
+				"offset":         "newest",
+			},
+		},
+		{
+			name: "mqtt_consumer",
+			want: &MQTTConsumer{
+				Servers: []string{"tcp://localhost:1883"},
+				Topics:  []string{"sensors/#"},
+				QoS:     1,
+			},
+			input: &MQTTConsumer{},
+			data: map[string]interface{}{
+				"servers": []interface{}{"tcp://localhost:1883"},
+				"topics":  []interface{}{"sensors/#"},
+				"qos":     int64(1),
+			},
+		},
+		{
+			name: "postgresql",
+			want: &Postgresql{
+				Address:   "host=localhost user=postgres",
+				Databases: []string{"app"},
+			},
+			input: &Postgresql{},
+			data: map[string]interface{}{
+				"address":   "host=localhost user=postgres",
+				"databases": []interface{}{"app"},
+			},
+		},
+		{
+			name: "snmp",
+			want: &SNMP{
+				Agents:    []string{"udp://127.0.0.1:161"},
+				Version:   3,
+				Community: "private",
+			},
+			input: &SNMP{},
+			data: map[string]interface{}{
+				"agents":    []interface{}{"udp://127.0.0.1:161"},
+				"version":   int64(3),
+				"community": "private",
+			},
+		},
 		{
 			name:  "kernel",
 			want:  &Kernel{},
@@ -747,3 +838,60 @@ func TestDecodeTOML(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		plugin  interface{ Validate() error }
+		wantErr string
+	}{
+		{
+			name:    "exec without commands",
+			plugin:  &Exec{},
+			wantErr: "exec input plugin requires at least one command",
+		},
+		{
+			name:    "exec bad timeout",
+			plugin:  &Exec{Commands: []string{"ls"}, Timeout: "5"},
+			wantErr: `timeout is not a valid duration for exec input plugin: time: missing unit in duration "5"`,
+		},
+		{
+			name:    "http bad method",
+			plugin:  &HTTP{URLs: []string{"http://localhost"}, Method: "TRACE"},
+			wantErr: `unsupported method "TRACE" for http input plugin`,
+		},
+		{
+			name:    "kafka_consumer bad offset",
+			plugin:  &KafkaConsumer{Brokers: []string{"b"}, Topics: []string{"t"}, Offset: "latest"},
+			wantErr: `offset must be "oldest" or "newest" for kafka_consumer input plugin, got "latest"`,
+		},
+		{
+			name:    "mqtt_consumer bad qos",
+			plugin:  &MQTTConsumer{Servers: []string{"s"}, Topics: []string{"t"}, QoS: 3},
+			wantErr: "qos must be 0, 1 or 2 for mqtt_consumer input plugin, got 3",
+		},
+		{
+			name:    "postgresql without address",
+			plugin:  &Postgresql{},
+			wantErr: "postgresql input plugin requires an address",
+		},
+		{
+			name:    "snmp bad version",
+			plugin:  &SNMP{Agents: []string{"a"}, Version: 4},
+			wantErr: "version must be 1, 2 or 3 for snmp input plugin, got 4",
+		},
+		{
+			name:   "snmp",
+			plugin: &SNMP{Agents: []string{"a"}},
+		},
+	}
+	for _, c := range cases {
+		err := c.plugin.Validate()
+		if c.wantErr == "" && err != nil {
+			t.Errorf("%s failed want err nil, got %v", c.name, err)
+		}
+		if c.wantErr != "" && (err == nil || err.Error() != c.wantErr) {
+			t.Errorf("%s failed want err %s, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
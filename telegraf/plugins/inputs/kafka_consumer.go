@@ -0,0 +1,96 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
+
+// KafkaConsumer is based on telegraf Kafka Consumer plugin.
+type KafkaConsumer struct {
+	baseInput
+	Brokers       []string `json:"brokers"`
+	Topics        []string `json:"topics"`
+	ConsumerGroup string   `json:"consumerGroup"`
+	Offset        string   `json:"offset"`
+	DataFormat    string   `json:"dataFormat"`
+	// Settings are the settings of the plugin that the model doesn't declare.
+	Settings plugins.Settings `json:"settings,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (k *KafkaConsumer) PluginName() string {
+	return "kafka_consumer"
+}
+
+// TOML encodes to toml string
+func (k *KafkaConsumer) TOML() string {
+	group := k.ConsumerGroup
+	if group == "" {
+		group = "telegraf_metrics_consumers"
+	}
+	offset := k.Offset
+	if offset == "" {
+		offset = "oldest"
+	}
+	dataFormat := k.DataFormat
+	if dataFormat == "" {
+		dataFormat = "influx"
+	}
+	return fmt.Sprintf(`[[inputs.%s]]
+  ## kafka servers
+  brokers = [%s]
+
+  ## topic(s) to consume
+  topics = [%s]
+
+  ## the name of the consumer group
+  consumer_group = %s
+
+  ## Offset (must be either "oldest" or "newest")
+  offset = %s
+
+  ## Data format to consume.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
+  data_format = %s
+`, k.PluginName(), quoteStrings(k.Brokers), quoteStrings(k.Topics), strconv.Quote(group), strconv.Quote(offset), strconv.Quote(dataFormat)) + k.Settings.TOML(plugins.Input, k.PluginName())
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (k *KafkaConsumer) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad brokers for kafka_consumer input plugin")
+	}
+	if k.Brokers, ok = stringArray(dataOK["brokers"]); !ok {
+		return errors.New("brokers is not an array for kafka_consumer input plugin")
+	}
+	if k.Topics, ok = stringArray(dataOK["topics"]); !ok {
+		return errors.New("topics is not an array for kafka_consumer input plugin")
+	}
+	k.ConsumerGroup, _ = dataOK["consumer_group"].(string)
+	k.Offset, _ = dataOK["offset"].(string)
+	k.DataFormat, _ = dataOK["data_format"].(string)
+	k.Settings = plugins.UndeclaredSettings(dataOK, "brokers", "topics", "consumer_group", "offset", "data_format")
+	return nil
+}
+
+// Validate checks the brokers, topics and offset.
+func (k *KafkaConsumer) Validate() error {
+	if len(k.Brokers) == 0 {
+		return errors.New("kafka_consumer input plugin requires at least one broker")
+	}
+	if len(k.Topics) == 0 {
+		return errors.New("kafka_consumer input plugin requires at least one topic")
+	}
+	switch k.Offset {
+	case "", "oldest", "newest":
+	default:
+		return fmt.Errorf(`offset must be "oldest" or "newest" for kafka_consumer input plugin, got %q`, k.Offset)
+	}
+	return nil
+}
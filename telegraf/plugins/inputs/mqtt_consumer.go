@@ -0,0 +1,96 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
+
+// MQTTConsumer is based on telegraf MQTT Consumer plugin.
+type MQTTConsumer struct {
+	baseInput
+	Servers    []string `json:"servers"`
+	Topics     []string `json:"topics"`
+	QoS        int      `json:"qos"`
+	ClientID   string   `json:"clientID"`
+	DataFormat string   `json:"dataFormat"`
+	// Settings are the settings of the plugin that the model doesn't declare.
+	Settings plugins.Settings `json:"settings,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (m *MQTTConsumer) PluginName() string {
+	return "mqtt_consumer"
+}
+
+// TOML encodes to toml string
+func (m *MQTTConsumer) TOML() string {
+	clientID := `  # client_id = ""`
+	if m.ClientID != "" {
+		clientID = "  client_id = " + strconv.Quote(m.ClientID)
+	}
+	dataFormat := m.DataFormat
+	if dataFormat == "" {
+		dataFormat = "influx"
+	}
+	return fmt.Sprintf(`[[inputs.%s]]
+  ## MQTT broker URLs to be used. The format should be scheme://host:port,
+  ## schema can be tcp, ssl, or ws.
+  servers = [%s]
+
+  ## Topics to subscribe to
+  topics = [%s]
+
+  ## QoS policy for messages
+  ##   0 = at most once
+  ##   1 = at least once
+  ##   2 = exactly once
+  qos = %d
+
+  ## If empty, a random client ID will be generated.
+%s
+
+  ## Data format to consume.
+  ## Each data format has its own unique set of configuration options, read
+  ## more about them here:
+  ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md
+  data_format = %s
+`, m.PluginName(), quoteStrings(m.Servers), quoteStrings(m.Topics), m.QoS, clientID, strconv.Quote(dataFormat)) + m.Settings.TOML(plugins.Input, m.PluginName())
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (m *MQTTConsumer) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad servers for mqtt_consumer input plugin")
+	}
+	if m.Servers, ok = stringArray(dataOK["servers"]); !ok {
+		return errors.New("servers is not an array for mqtt_consumer input plugin")
+	}
+	if m.Topics, ok = stringArray(dataOK["topics"]); !ok {
+		return errors.New("topics is not an array for mqtt_consumer input plugin")
+	}
+	if qos, ok := dataOK["qos"].(int64); ok {
+		m.QoS = int(qos)
+	}
+	m.ClientID, _ = dataOK["client_id"].(string)
+	m.DataFormat, _ = dataOK["data_format"].(string)
+	m.Settings = plugins.UndeclaredSettings(dataOK, "servers", "topics", "qos", "client_id", "data_format")
+	return nil
+}
+
+// Validate checks the servers, topics and qos.
+func (m *MQTTConsumer) Validate() error {
+	if len(m.Servers) == 0 {
+		return errors.New("mqtt_consumer input plugin requires at least one server")
+	}
+	if len(m.Topics) == 0 {
+		return errors.New("mqtt_consumer input plugin requires at least one topic")
+	}
+	if m.QoS < 0 || m.QoS > 2 {
+		return fmt.Errorf("qos must be 0, 1 or 2 for mqtt_consumer input plugin, got %d", m.QoS)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/platform/telegraf/plugins"
+)
+
+// SNMP is based on telegraf SNMP plugin.
+type SNMP struct {
+	baseInput
+	Agents    []string `json:"agents"`
+	Version   int      `json:"version"`
+	Community string   `json:"community"`
+	// Settings are the settings of the plugin that the model doesn't declare.
+	Settings plugins.Settings `json:"settings,omitempty"`
+}
+
+// PluginName is based on telegraf plugin name.
+func (s *SNMP) PluginName() string {
+	return "snmp"
+}
+
+// TOML encodes to toml string
+func (s *SNMP) TOML() string {
+	version := s.Version
+	if version == 0 {
+		version = 2
+	}
+	community := s.Community
+	if community == "" {
+		community = "public"
+	}
+	return fmt.Sprintf(`[[inputs.%s]]
+  ## Agent addresses to retrieve values from.
+  ##   example: agents = ["udp://127.0.0.1:161"]
+  agents = [%s]
+
+  ## SNMP version; can be 1, 2, or 3.
+  version = %d
+
+  ## SNMP community string.
+  community = %s
+`, s.PluginName(), quoteStrings(s.Agents), version, strconv.Quote(community)) + s.Settings.TOML(plugins.Input, s.PluginName())
+}
+
+// UnmarshalTOML decodes the parsed data to the object
+func (s *SNMP) UnmarshalTOML(data interface{}) error {
+	dataOK, ok := data.(map[string]interface{})
+	if !ok {
+		return errors.New("bad agents for snmp input plugin")
+	}
+	if s.Agents, ok = stringArray(dataOK["agents"]); !ok {
+		return errors.New("agents is not an array for snmp input plugin")
+	}
+	if version, ok := dataOK["version"].(int64); ok {
+		s.Version = int(version)
+	}
+	s.Community, _ = dataOK["community"].(string)
+	s.Settings = plugins.UndeclaredSettings(dataOK, "agents", "version", "community")
+	return nil
+}
+
+// Validate checks the agents and version.
+func (s *SNMP) Validate() error {
+	if len(s.Agents) == 0 {
+		return errors.New("snmp input plugin requires at least one agent")
+	}
+	if s.Version < 0 || s.Version > 3 {
+		return fmt.Errorf("version must be 1, 2 or 3 for snmp input plugin, got %d", s.Version)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/BurntSushi/toml"
@@ -24,7 +25,7 @@ var telegrafCmpOptions = cmp.Options{
 		inputs.File{},
 		outputs.File{},
 		outputs.InfluxDBV2{},
-		unsupportedPlugin{},
+		inputs.MQTTConsumer{},
 	),
 	cmp.Transformer("Sort", func(in []*TelegrafConfig) []*TelegrafConfig {
 		out := append([]*TelegrafConfig(nil), in...)
@@ -48,33 +49,13 @@ func (u *unsupportedPluginType) PluginName() string {
 }
 
 func (u *unsupportedPluginType) Type() plugins.Type {
-	return plugins.Aggregator
+	return plugins.Type("bad_type")
 }
 
 func (u *unsupportedPluginType) UnmarshalTOML(data interface{}) error {
 	return nil
 }
 
-type unsupportedPlugin struct {
-	Field string `json:"field"`
-}
-
-func (u *unsupportedPlugin) TOML() string {
-	return ""
-}
-
-func (u *unsupportedPlugin) PluginName() string {
-	return "kafka"
-}
-
-func (u *unsupportedPlugin) Type() plugins.Type {
-	return plugins.Output
-}
-
-func (u *unsupportedPlugin) UnmarshalTOML(data interface{}) error {
-	return nil
-}
-
 func TestTelegrafConfigJSONDecodeWithoutID(t *testing.T) {
 	s := `{
 		"name":"config 2",
@@ -209,27 +190,48 @@ func TestTelegrafConfigJSON(t *testing.T) {
 			},
 			err: &Error{
 				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "aggregator"),
+				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginType, "bad_type"),
 				Op:   "unmarshal telegraf config raw plugin",
 			},
 		},
 		{
-			name: "unsupported plugin",
+			name: "generic plugin",
+			cfg: &TelegrafConfig{
+				ID:        *id1,
+				Name:      "n1",
+				LastModBy: *id2,
+				Plugins: []TelegrafPlugin{
+					{
+						Config: &plugins.Generic{
+							PluginType: plugins.Output,
+							Name:       "kafka",
+							Settings: map[string]interface{}{
+								"brokers": []interface{}{"localhost:9092"},
+								"retries": int64(3),
+								"ratio":   0.5,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid plugin",
 			cfg: &TelegrafConfig{
 				ID:        *id1,
 				Name:      "n1",
 				LastModBy: *id2,
 				Plugins: []TelegrafPlugin{
 					{
-						Config: &unsupportedPlugin{
-							Field: "f2",
+						Config: &inputs.MQTTConsumer{
+							Servers: []string{"tcp://localhost:1883"},
 						},
 					},
 				},
 			},
 			err: &Error{
 				Code: EInvalid,
-				Msg:  fmt.Sprintf(ErrUnsupportTelegrafPluginName, "kafka", plugins.Output),
+				Msg:  fmt.Sprintf(ErrInvalidTelegrafPlugin, "mqtt_consumer", plugins.Input, "mqtt_consumer input plugin requires at least one topic"),
 				Op:   "unmarshal telegraf config raw plugin",
 			},
 		},
@@ -403,3 +405,130 @@ func TestTOML(t *testing.T) {
 		t.Fatalf("telegraf toml parsing issue, want %q, got %q", tc, tcr)
 	}
 }
+
+func TestTOMLGenericPlugins(t *testing.T) {
+	want := `[agent]
+  interval = "10s"
+
+[[inputs.http]]
+  urls = ["http://localhost:8080/metrics"]
+  method = "GET"
+  timeout = "5s"
+  data_format = "json"
+
+[[inputs.nvidia_smi]]
+  bin_path = "/usr/bin/nvidia-smi"
+  timeout = "5s"
+
+[[processors.rename]]
+  [[processors.rename.replace]]
+    measurement = "network_interface_throughput"
+    dest = "throughput"
+
+[[outputs.kafka]]
+  brokers = ["localhost:9092"]
+  max_retry = 3
+  [outputs.kafka.tagpass]
+    cpu = ["cpu0"]
+`
+	tc := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(want), tc); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s", err.Error())
+	}
+	if len(tc.Plugins) != 4 {
+		t.Fatalf("got %d plugins, want 4", len(tc.Plugins))
+	}
+
+	// round trip the config through json, as it is stored, and back to toml.
+	tc.ID, tc.LastModBy = 1, 1
+	b, err := json.Marshal(tc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(TelegrafConfig)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	tcr := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(got.TOML()), tcr); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s\n%s", err.Error(), got.TOML())
+	}
+	if diff := cmp.Diff(tcr.Plugins, tc.Plugins, cmpopts.IgnoreUnexported(inputs.HTTP{}), cmpopts.SortSlices(func(a, b TelegrafPlugin) bool {
+		return a.Config.PluginName() < b.Config.PluginName()
+	})); diff != "" {
+		t.Errorf("telegraf configs are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestTOMLUndeclaredSettings(t *testing.T) {
+	want := `[agent]
+  interval = "10s"
+
+[[inputs.snmp]]
+  agents = ["udp://127.0.0.1:161"]
+  version = 3
+  community = "public"
+  sec_level = "authPriv"
+  auth_password = "secret"
+  [[inputs.snmp.field]]
+    name = "uptime"
+    oid = "RFC1213-MIB::sysUpTime.0"
+  [[inputs.snmp.field]]
+    name = "source"
+    oid = "RFC1213-MIB::sysName.0"
+    is_tag = true
+
+[[inputs.http]]
+  urls = ["http://localhost:8080/metrics"]
+  method = "GET"
+  timeout = "5s"
+  data_format = "json"
+  json_query = "metrics"
+  [inputs.http.headers]
+    Authorization = "Bearer token"
+`
+	tc := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(want), tc); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s", err.Error())
+	}
+
+	// a GET then PUT of the config round trips it through json.
+	tc.ID, tc.LastModBy = 1, 1
+	b, err := json.Marshal(tc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(TelegrafConfig)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`auth_password = "secret"`, `[[inputs.snmp.field]]`, `json_query = "metrics"`, `[inputs.http.headers]`} {
+		if !strings.Contains(got.TOML(), s) {
+			t.Errorf("expected %s in toml\n%s", s, got.TOML())
+		}
+	}
+	tcr := new(TelegrafConfig)
+	if err := toml.Unmarshal([]byte(got.TOML()), tcr); err != nil {
+		t.Fatalf("telegraf toml parsing issue %s\n%s", err.Error(), got.TOML())
+	}
+	if diff := cmp.Diff(tcr.Plugins, tc.Plugins, cmpopts.IgnoreUnexported(inputs.HTTP{}, inputs.SNMP{}), cmpopts.SortSlices(func(a, b TelegrafPlugin) bool {
+		return a.Config.PluginName() < b.Config.PluginName()
+	})); diff != "" {
+		t.Errorf("telegraf configs are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestTOMLInvalidPlugin(t *testing.T) {
+	s := `[agent]
+  interval = "10s"
+
+[[inputs.exec]]
+  commands = ["/usr/bin/mycollector"]
+  timeout = "five seconds"
+`
+	tc := new(TelegrafConfig)
+	err := toml.Unmarshal([]byte(s), tc)
+	if err == nil || !strings.Contains(err.Error(), "timeout is not a valid duration for exec input plugin") {
+		t.Fatalf("got error %v, want invalid timeout", err)
+	}
+}
@@ -0,0 +1,328 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
+)
+
+const (
+	auditOneID   = "020f755c3c083000"
+	auditTwoID   = "020f755c3c083001"
+	auditThreeID = "020f755c3c083002"
+)
+
+// AuditLogFields will include the IDGenerator, the current time and the
+// events to log before each test.
+type AuditLogFields struct {
+	IDGenerator platform.IDGenerator
+	NowFn       func() time.Time
+	Events      []*platform.AuditEvent
+}
+
+// auditIDGenerator returns the ids in order.
+func auditIDGenerator(ids ...string) platform.IDGenerator {
+	i := 0
+	return mock.IDGenerator{
+		IDFn: func() platform.ID {
+			id := MustIDBase16(ids[i%len(ids)])
+			i++
+			return id
+		},
+	}
+}
+
+var (
+	auditTimeOne   = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+	auditTimeTwo   = auditTimeOne.Add(time.Minute)
+	auditTimeThree = auditTimeOne.Add(2 * time.Minute)
+)
+
+func auditEvents() []*platform.AuditEvent {
+	return []*platform.AuditEvent{
+		{
+			Time:           auditTimeOne,
+			ActorID:        MustIDBase16(userOneID),
+			AuthorizerKind: "authorization",
+			Action:         platform.AuditCreate,
+			ResourceType:   platform.BucketResourceType,
+			ResourceID:     MustIDBase16(bucketOneID),
+			After:          []byte(`{"name":"b1"}`),
+		},
+		{
+			Time:           auditTimeTwo,
+			ActorID:        MustIDBase16(userTwoID),
+			AuthorizerKind: "session",
+			Action:         platform.AuditUpdate,
+			ResourceType:   platform.BucketResourceType,
+			ResourceID:     MustIDBase16(bucketOneID),
+			Before:         []byte(`{"name":"b1"}`),
+			After:          []byte(`{"name":"b2"}`),
+			Changes:        []string{"name"},
+		},
+		{
+			Time:           auditTimeThree,
+			ActorID:        MustIDBase16(userOneID),
+			AuthorizerKind: "authorization",
+			Action:         platform.AuditDelete,
+			ResourceType:   platform.DashboardResourceType,
+			ResourceID:     MustIDBase16(dashOneID),
+		},
+	}
+}
+
+// AuditLogService tests all the service functions.
+func AuditLogService(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, string, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(AuditLogFields, *testing.T) (platform.AuditLogService, string, func()),
+			t *testing.T)
+	}{
+		{
+			name: "LogAuditEvent",
+			fn:   LogAuditEvent,
+		},
+		{
+			name: "FindAuditEvents",
+			fn:   FindAuditEvents,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// LogAuditEvent testing
+func LogAuditEvent(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		event *platform.AuditEvent
+	}
+	type wants struct {
+		err    error
+		events []*platform.AuditEvent
+	}
+
+	tests := []struct {
+		name   string
+		fields AuditLogFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "log event without a time",
+			fields: AuditLogFields{
+				IDGenerator: mock.NewIDGenerator(auditOneID, t),
+				NowFn:       func() time.Time { return auditTimeOne },
+			},
+			args: args{
+				event: &platform.AuditEvent{
+					ActorID:      MustIDBase16(userOneID),
+					Action:       platform.AuditCreate,
+					ResourceType: platform.OrgResourceType,
+					ResourceID:   MustIDBase16(orgOneID),
+					RequestID:    "req1",
+				},
+			},
+			wants: wants{
+				events: []*platform.AuditEvent{
+					{
+						ID:           MustIDBase16(auditOneID),
+						Time:         auditTimeOne,
+						ActorID:      MustIDBase16(userOneID),
+						Action:       platform.AuditCreate,
+						ResourceType: platform.OrgResourceType,
+						ResourceID:   MustIDBase16(orgOneID),
+						RequestID:    "req1",
+					},
+				},
+			},
+		},
+		{
+			name: "log event with a time",
+			fields: AuditLogFields{
+				IDGenerator: mock.NewIDGenerator(auditOneID, t),
+				NowFn:       func() time.Time { return auditTimeOne },
+			},
+			args: args{
+				event: &platform.AuditEvent{
+					Time:         auditTimeTwo,
+					Action:       platform.AuditDelete,
+					ResourceType: platform.UserResourceType,
+					ResourceID:   MustIDBase16(userTwoID),
+				},
+			},
+			wants: wants{
+				events: []*platform.AuditEvent{
+					{
+						ID:           MustIDBase16(auditOneID),
+						Time:         auditTimeTwo,
+						Action:       platform.AuditDelete,
+						ResourceType: platform.UserResourceType,
+						ResourceID:   MustIDBase16(userTwoID),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.TODO()
+
+			err := s.LogAuditEvent(ctx, tt.args.event)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			events, _, err := s.FindAuditEvents(ctx, platform.AuditEventFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve audit events: %v", err)
+			}
+			if diff := cmp.Diff(events, tt.wants.events); diff != "" {
+				t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindAuditEvents testing
+func FindAuditEvents(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter platform.AuditEventFilter
+		opts   platform.FindOptions
+	}
+	type wants struct {
+		err error
+		ids []string
+	}
+
+	fields := func() AuditLogFields {
+		return AuditLogFields{
+			IDGenerator: auditIDGenerator(auditOneID, auditTwoID, auditThreeID),
+			Events:      auditEvents(),
+		}
+	}
+	bucketType := platform.BucketResourceType
+	bucketID := MustIDBase16(bucketOneID)
+	actorID := MustIDBase16(userOneID)
+
+	tests := []struct {
+		name   string
+		fields AuditLogFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find all events",
+			fields: fields(),
+			wants: wants{
+				ids: []string{auditOneID, auditTwoID, auditThreeID},
+			},
+		},
+		{
+			name:   "find latest events",
+			fields: fields(),
+			args: args{
+				opts: platform.FindOptions{Descending: true, Limit: 2},
+			},
+			wants: wants{
+				ids: []string{auditThreeID, auditTwoID},
+			},
+		},
+		{
+			name:   "find events with an offset",
+			fields: fields(),
+			args: args{
+				opts: platform.FindOptions{Offset: 1},
+			},
+			wants: wants{
+				ids: []string{auditTwoID, auditThreeID},
+			},
+		},
+		{
+			name:   "find events in a time range",
+			fields: fields(),
+			args: args{
+				filter: platform.AuditEventFilter{
+					Start: &auditTimeTwo,
+					Stop:  &auditTimeThree,
+				},
+			},
+			wants: wants{
+				ids: []string{auditTwoID},
+			},
+		},
+		{
+			name:   "find latest events in a time range",
+			fields: fields(),
+			args: args{
+				filter: platform.AuditEventFilter{
+					Start: &auditTimeOne,
+					Stop:  &auditTimeThree,
+				},
+				opts: platform.FindOptions{Descending: true},
+			},
+			wants: wants{
+				ids: []string{auditTwoID, auditOneID},
+			},
+		},
+		{
+			name:   "find events of a resource",
+			fields: fields(),
+			args: args{
+				filter: platform.AuditEventFilter{
+					ResourceType: &bucketType,
+					ResourceID:   &bucketID,
+				},
+			},
+			wants: wants{
+				ids: []string{auditOneID, auditTwoID},
+			},
+		},
+		{
+			name:   "find events of an actor",
+			fields: fields(),
+			args: args{
+				filter: platform.AuditEventFilter{
+					ActorID: &actorID,
+				},
+			},
+			wants: wants{
+				ids: []string{auditOneID, auditThreeID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.TODO()
+
+			events, _, err := s.FindAuditEvents(ctx, tt.args.filter, tt.args.opts)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			ids := []string{}
+			for _, e := range events {
+				ids = append(ids, e.ID.String())
+			}
+			if diff := cmp.Diff(ids, tt.wants.ids); diff != "" {
+				t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
@@ -37,8 +38,17 @@ type AuthorizationFields struct {
 	TokenGenerator platform.TokenGenerator
 	Authorizations []*platform.Authorization
 	Users          []*platform.User
+	NowFn          func() time.Time
 }
 
+var (
+	authNow       = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+	authHourAgo   = authNow.Add(-time.Hour)
+	authHourLater = authNow.Add(time.Hour)
+)
+
+func authNowFn() time.Time { return authNow }
+
 // AuthorizationService tests all the service functions.
 func AuthorizationService(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()), t *testing.T,
@@ -68,6 +78,10 @@ func AuthorizationService(
 			name: "DeleteAuthorization",
 			fn:   DeleteAuthorization,
 		},
+		{
+			name: "RotateAuthorization",
+			fn:   RotateAuthorization,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -477,6 +491,81 @@ func FindAuthorizationByToken(
 				},
 			},
 		},
+		{
+			name: "find authorization by rotated token within grace period",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:                     MustIDBase16(authOneID),
+						UserID:                 MustIDBase16(userOneID),
+						Token:                  "rand2",
+						PreviousToken:          "rand1",
+						PreviousTokenExpiresAt: &authHourLater,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:                     MustIDBase16(authOneID),
+					UserID:                 MustIDBase16(userOneID),
+					Status:                 platform.Active,
+					User:                   "cooluser",
+					Token:                  "rand2",
+					PreviousToken:          "rand1",
+					PreviousTokenExpiresAt: &authHourLater,
+					Permissions: []platform.Permission{
+						platform.CreateUserPermission,
+					},
+				},
+			},
+		},
+		{
+			name: "find authorization by rotated token after grace period",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:                     MustIDBase16(authOneID),
+						UserID:                 MustIDBase16(userOneID),
+						Token:                  "rand2",
+						PreviousToken:          "rand1",
+						PreviousTokenExpiresAt: &authHourAgo,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  "authorization not found",
+					Op:   platform.OpFindAuthorizationByToken,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -501,9 +590,11 @@ func FindAuthorizations(
 	t *testing.T,
 ) {
 	type args struct {
-		ID     platform.ID
-		UserID platform.ID
-		token  string
+		ID          platform.ID
+		UserID      platform.ID
+		token       string
+		expired     bool
+		unusedSince *time.Time
 	}
 
 	type wants struct {
@@ -714,6 +805,133 @@ func FindAuthorizations(
 				},
 			},
 		},
+		{
+			name: "find expired authorizations",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:        MustIDBase16(authOneID),
+						UserID:    MustIDBase16(userOneID),
+						Token:     "rand1",
+						ExpiresAt: &authHourAgo,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+					{
+						ID:        MustIDBase16(authTwoID),
+						UserID:    MustIDBase16(userOneID),
+						Token:     "rand2",
+						ExpiresAt: &authHourLater,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+					{
+						ID:     MustIDBase16(authThreeID),
+						UserID: MustIDBase16(userOneID),
+						Token:  "rand3",
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				expired: true,
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:        MustIDBase16(authOneID),
+						UserID:    MustIDBase16(userOneID),
+						User:      "cooluser",
+						Token:     "rand1",
+						Status:    platform.Active,
+						ExpiresAt: &authHourAgo,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "find authorizations unused since",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:         MustIDBase16(authOneID),
+						UserID:     MustIDBase16(userOneID),
+						Token:      "rand1",
+						LastUsedAt: &authHourAgo,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+					{
+						ID:         MustIDBase16(authTwoID),
+						UserID:     MustIDBase16(userOneID),
+						Token:      "rand2",
+						LastUsedAt: &authNow,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+					{
+						ID:     MustIDBase16(authThreeID),
+						UserID: MustIDBase16(userOneID),
+						Token:  "rand3",
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				UserID:      MustIDBase16(userOneID),
+				unusedSince: &authNow,
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:         MustIDBase16(authOneID),
+						UserID:     MustIDBase16(userOneID),
+						User:       "cooluser",
+						Token:      "rand1",
+						Status:     platform.Active,
+						LastUsedAt: &authHourAgo,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+					{
+						ID:     MustIDBase16(authThreeID),
+						UserID: MustIDBase16(userOneID),
+						User:   "cooluser",
+						Token:  "rand3",
+						Status: platform.Active,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -732,6 +950,8 @@ func FindAuthorizations(
 			if tt.args.token != "" {
 				filter.Token = &tt.args.token
 			}
+			filter.Expired = tt.args.expired
+			filter.UnusedSince = tt.args.unusedSince
 
 			authorizations, _, err := s.FindAuthorizations(ctx, filter)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
@@ -900,3 +1120,176 @@ func DeleteAuthorization(
 		})
 	}
 }
+
+// RotateAuthorization testing
+func RotateAuthorization(
+	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		ID    platform.ID
+		grace time.Duration
+	}
+	type wants struct {
+		err           error
+		authorization *platform.Authorization
+	}
+
+	graceEnd := authNow.Add(10 * time.Minute)
+	tests := []struct {
+		name   string
+		fields AuthorizationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotate authorization with a grace period",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				TokenGenerator: &mock.TokenGenerator{
+					TokenFn: func() (string, error) {
+						return "rand2", nil
+					},
+				},
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:     MustIDBase16(authOneID),
+						UserID: MustIDBase16(userOneID),
+						Token:  "rand1",
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				ID:    MustIDBase16(authOneID),
+				grace: 10 * time.Minute,
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:                     MustIDBase16(authOneID),
+					UserID:                 MustIDBase16(userOneID),
+					User:                   "cooluser",
+					Status:                 platform.Active,
+					Token:                  "rand2",
+					PreviousToken:          "rand1",
+					PreviousTokenExpiresAt: &graceEnd,
+					Permissions: []platform.Permission{
+						platform.CreateUserPermission,
+					},
+				},
+			},
+		},
+		{
+			name: "rotate authorization without a grace period",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				TokenGenerator: &mock.TokenGenerator{
+					TokenFn: func() (string, error) {
+						return "rand3", nil
+					},
+				},
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:                     MustIDBase16(authOneID),
+						UserID:                 MustIDBase16(userOneID),
+						Token:                  "rand2",
+						PreviousToken:          "rand1",
+						PreviousTokenExpiresAt: &authHourLater,
+						Permissions: []platform.Permission{
+							platform.CreateUserPermission,
+						},
+					},
+				},
+			},
+			args: args{
+				ID: MustIDBase16(authOneID),
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:     MustIDBase16(authOneID),
+					UserID: MustIDBase16(userOneID),
+					User:   "cooluser",
+					Status: platform.Active,
+					Token:  "rand3",
+					Permissions: []platform.Permission{
+						platform.CreateUserPermission,
+					},
+				},
+			},
+		},
+		{
+			name: "rotate authorization using id that does not exist",
+			fields: AuthorizationFields{
+				NowFn: authNowFn,
+				TokenGenerator: &mock.TokenGenerator{
+					TokenFn: func() (string, error) {
+						return "rand2", nil
+					},
+				},
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:     MustIDBase16(authOneID),
+						UserID: MustIDBase16(userOneID),
+						Token:  "rand1",
+					},
+				},
+			},
+			args: args{
+				ID: MustIDBase16(authThreeID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  "authorization not found",
+					Op:   platform.OpRotateAuthorization,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.TODO()
+
+			authorization, err := s.RotateAuthorization(ctx, tt.args.ID, tt.args.grace)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(authorization, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorization is different -got/+want\ndiff %s", diff)
+			}
+			if tt.wants.authorization == nil {
+				return
+			}
+
+			found, err := s.FindAuthorizationByID(ctx, tt.args.ID)
+			if err != nil {
+				t.Fatalf("failed to retrieve authorization: %v", err)
+			}
+			if diff := cmp.Diff(found, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorization is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package testing
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
 )
 
@@ -169,3 +172,286 @@ func CompareAndSetPassword(
 	}
 
 }
+
+// BasicAuthFields will include the IDGenerator, the policy and the users
+// of the basic auth service.
+type BasicAuthFields struct {
+	IDGenerator     platform.IDGenerator
+	NowFn           func() time.Time
+	PasswordPolicy  platform.PasswordPolicy
+	PasswordHashers platform.PasswordHashers
+	Users           []*platform.User
+}
+
+var passwordTimeOne = time.Date(2018, 12, 1, 10, 0, 0, 0, time.UTC)
+
+// PasswordPolicy tests the validation of new passwords.
+func PasswordPolicy(
+	init func(BasicAuthFields, *testing.T) (platform.BasicAuthService, func()),
+	t *testing.T) {
+	policy := platform.PasswordPolicy{
+		MinLength:           8,
+		MinCharacterClasses: 3,
+	}
+	type args struct {
+		password string
+	}
+	type wants struct {
+		err error
+	}
+	tests := []struct {
+		name   string
+		fields BasicAuthFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "password follows the policy",
+			fields: BasicAuthFields{
+				PasswordPolicy: policy,
+				Users: []*platform.User{
+					{
+						Name: "user1",
+						ID:   MustIDBase16(oneID),
+					},
+				},
+			},
+			args: args{
+				password: "correct-Horse",
+			},
+		},
+		{
+			name: "password is too short",
+			fields: BasicAuthFields{
+				PasswordPolicy: policy,
+				Users: []*platform.User{
+					{
+						Name: "user1",
+						ID:   MustIDBase16(oneID),
+					},
+				},
+			},
+			args: args{
+				password: "aB3$",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  "password must be at least 8 characters",
+				},
+			},
+		},
+		{
+			name: "password mixes too few character classes",
+			fields: BasicAuthFields{
+				PasswordPolicy: policy,
+				Users: []*platform.User{
+					{
+						Name: "user1",
+						ID:   MustIDBase16(oneID),
+					},
+				},
+			},
+			args: args{
+				password: "correcthorse9",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  "password must mix at least 3 of lowercase letters, uppercase letters, digits and symbols",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.SetPassword(ctx, "user1", tt.args.password)
+			diffPlatformErrors(tt.name, err, tt.wants.err, "", t)
+			if err != nil {
+				return
+			}
+
+			if err := s.ComparePassword(ctx, "user1", tt.args.password); err != nil {
+				t.Fatalf("expected password to match, got %v", err)
+			}
+		})
+	}
+}
+
+// PasswordLockout tests the lockout of users after failed attempts to sign in.
+func PasswordLockout(
+	init func(BasicAuthFields, *testing.T) (platform.BasicAuthService, func()),
+	t *testing.T) {
+	policy := platform.PasswordPolicy{
+		MaxFailedAttempts: 3,
+		LockoutDuration:   15 * time.Minute,
+	}
+	mismatch := fmt.Errorf("crypto/bcrypt: hashedPassword is not the hash of the given password")
+	type attempt struct {
+		// after is the time elapsed since the password was set.
+		after    time.Duration
+		password string
+		err      error
+	}
+	tests := []struct {
+		name     string
+		policy   platform.PasswordPolicy
+		attempts []attempt
+	}{
+		{
+			name:   "unlimited attempts without a policy",
+			policy: platform.PasswordPolicy{},
+			attempts: []attempt{
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "hello"},
+			},
+		},
+		{
+			name:   "locked out after too many failed attempts",
+			policy: policy,
+			attempts: []attempt{
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "hello", err: platform.ErrPasswordLocked},
+				{after: 14 * time.Minute, password: "hello", err: platform.ErrPasswordLocked},
+			},
+		},
+		{
+			name:   "lockout expires",
+			policy: policy,
+			attempts: []attempt{
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{after: 15 * time.Minute, password: "hello"},
+			},
+		},
+		{
+			name:   "successful attempt resets the failures",
+			policy: policy,
+			attempts: []attempt{
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "hello"},
+				{password: "wrong", err: mismatch},
+				{password: "wrong", err: mismatch},
+				{password: "hello"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := passwordTimeOne
+			s, done := init(BasicAuthFields{
+				NowFn:          func() time.Time { return now },
+				PasswordPolicy: tt.policy,
+				Users: []*platform.User{
+					{
+						Name: "user1",
+						ID:   MustIDBase16(oneID),
+					},
+				},
+			}, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := s.SetPassword(ctx, "user1", "hello"); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			for i, a := range tt.attempts {
+				now = passwordTimeOne.Add(a.after)
+				err := s.ComparePassword(ctx, "user1", a.password)
+				if (err != nil) != (a.err != nil) {
+					t.Fatalf("attempt %d: expected error %v got %v", i, a.err, err)
+				}
+				if err != nil && err.Error() != a.err.Error() {
+					t.Fatalf("attempt %d: expected error %v got %v", i, a.err, err)
+				}
+			}
+		})
+	}
+}
+
+// versionedHasher is a hasher for tests, its hashes are prefixed with its
+// current version, and it records the hashes it compares.
+type versionedHasher struct {
+	version  *int
+	compared *[]string
+}
+
+func (h versionedHasher) prefix() string {
+	return fmt.Sprintf("$test%d$", *h.version)
+}
+
+func (h versionedHasher) Hash(password string) ([]byte, error) {
+	return []byte(h.prefix() + password), nil
+}
+
+func (h versionedHasher) Compare(hash []byte, password string) error {
+	*h.compared = append(*h.compared, string(hash))
+	i := strings.LastIndex(string(hash), "$")
+	if i < 0 || string(hash[i+1:]) != password {
+		return fmt.Errorf("password does not match")
+	}
+	return nil
+}
+
+func (h versionedHasher) Identifies(hash []byte) bool {
+	return strings.HasPrefix(string(hash), "$test")
+}
+
+func (h versionedHasher) Outdated(hash []byte) bool {
+	return !strings.HasPrefix(string(hash), h.prefix())
+}
+
+// PasswordRehash tests that outdated password hashes are replaced when users sign in.
+func PasswordRehash(
+	init func(BasicAuthFields, *testing.T) (platform.BasicAuthService, func()),
+	t *testing.T) {
+	version := 1
+	var compared []string
+	s, done := init(BasicAuthFields{
+		PasswordHashers: platform.PasswordHashers{
+			versionedHasher{version: &version, compared: &compared},
+		},
+		Users: []*platform.User{
+			{
+				Name: "user1",
+				ID:   MustIDBase16(oneID),
+			},
+		},
+	}, t)
+	defer done()
+	ctx := context.Background()
+
+	if err := s.SetPassword(ctx, "user1", "hello"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// upgrade the hasher, the existing hash is still verified and replaced.
+	version = 2
+	for i := 0; i < 2; i++ {
+		if err := s.ComparePassword(ctx, "user1", "hello"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if err := s.ComparePassword(ctx, "user1", "wrong"); err == nil {
+		t.Fatalf("expected mismatched password to fail")
+	}
+
+	want := []string{"$test1$hello", "$test2$hello", "$test2$hello"}
+	if diff := cmp.Diff(compared, want); diff != "" {
+		t.Errorf("compared hashes are different -got/+want\ndiff %s", diff)
+	}
+}
@@ -0,0 +1,368 @@
+package testing
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
+)
+
+const (
+	groupOneID = "020f755c3c083000"
+	groupTwoID = "020f755c3c083001"
+	groupOrgID = "020f755c3c083100"
+)
+
+var groupCmpOptions = cmp.Options{
+	cmp.Transformer("Sort", func(in []*platform.Group) []*platform.Group {
+		out := append([]*platform.Group(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID.String() > out[j].ID.String()
+		})
+		return out
+	}),
+}
+
+// GroupServices is the group service along with the mapping service the
+// group memberships and mappings are stored in.
+type GroupServices interface {
+	platform.GroupService
+	platform.UserResourceMappingService
+}
+
+// GroupFields will include the IDGenerator, and groups
+type GroupFields struct {
+	IDGenerator          platform.IDGenerator
+	Organizations        []*platform.Organization
+	Groups               []*platform.Group
+	UserResourceMappings []*platform.UserResourceMapping
+}
+
+// GroupService tests all the service functions.
+func GroupService(
+	init func(GroupFields, *testing.T) (GroupServices, string, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(GroupFields, *testing.T) (GroupServices, string, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateGroup",
+			fn:   CreateGroup,
+		},
+		{
+			name: "FindGroups",
+			fn:   FindGroups,
+		},
+		{
+			name: "UpdateGroup",
+			fn:   UpdateGroup,
+		},
+		{
+			name: "DeleteGroup",
+			fn:   DeleteGroup,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+func groupFields() GroupFields {
+	return GroupFields{
+		IDGenerator: mock.NewIDGenerator(groupTwoID, nil),
+		Organizations: []*platform.Organization{
+			{
+				ID:   MustIDBase16(groupOrgID),
+				Name: "org",
+			},
+		},
+		Groups: []*platform.Group{
+			{
+				ID:             MustIDBase16(groupOneID),
+				OrganizationID: MustIDBase16(groupOrgID),
+				Name:           "engineers",
+			},
+		},
+	}
+}
+
+// CreateGroup testing
+func CreateGroup(
+	init func(GroupFields, *testing.T) (GroupServices, string, func()),
+	t *testing.T,
+) {
+	type wants struct {
+		err    error
+		groups []*platform.Group
+	}
+
+	tests := []struct {
+		name  string
+		group *platform.Group
+		wants wants
+	}{
+		{
+			name: "create a group",
+			group: &platform.Group{
+				OrganizationID: MustIDBase16(groupOrgID),
+				Name:           "operators",
+				Description:    "on call",
+			},
+			wants: wants{
+				groups: []*platform.Group{
+					{
+						ID:             MustIDBase16(groupOneID),
+						OrganizationID: MustIDBase16(groupOrgID),
+						Name:           "engineers",
+					},
+					{
+						ID:             MustIDBase16(groupTwoID),
+						OrganizationID: MustIDBase16(groupOrgID),
+						Name:           "operators",
+						Description:    "on call",
+					},
+				},
+			},
+		},
+		{
+			name: "names are unique within an organization",
+			group: &platform.Group{
+				OrganizationID: MustIDBase16(groupOrgID),
+				Name:           "engineers",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EConflict,
+					Op:   platform.OpCreateGroup,
+					Msg:  "group with name engineers already exists",
+				},
+				groups: groupFields().Groups,
+			},
+		},
+		{
+			name: "the organization must exist",
+			group: &platform.Group{
+				OrganizationID: MustIDBase16(groupTwoID),
+				Name:           "operators",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpCreateGroup,
+					Msg:  "organization not found",
+				},
+				groups: groupFields().Groups,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(groupFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateGroup(ctx, tt.group)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			groups, _, err := s.FindGroups(ctx, platform.GroupFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve groups: %v", err)
+			}
+			if diff := cmp.Diff(groups, tt.wants.groups, groupCmpOptions...); diff != "" {
+				t.Errorf("groups are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindGroups testing
+func FindGroups(
+	init func(GroupFields, *testing.T) (GroupServices, string, func()),
+	t *testing.T,
+) {
+	orgID := MustIDBase16(groupOrgID)
+	otherOrgID := MustIDBase16(groupTwoID)
+	name := "engineers"
+
+	tests := []struct {
+		name   string
+		filter platform.GroupFilter
+		want   int
+	}{
+		{
+			name: "find all groups",
+			want: 1,
+		},
+		{
+			name:   "find groups of an organization",
+			filter: platform.GroupFilter{OrganizationID: &orgID},
+			want:   1,
+		},
+		{
+			name:   "find groups of another organization",
+			filter: platform.GroupFilter{OrganizationID: &otherOrgID},
+			want:   0,
+		},
+		{
+			name:   "find groups by name",
+			filter: platform.GroupFilter{Name: &name},
+			want:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, done := init(groupFields(), t)
+			defer done()
+
+			groups, n, err := s.FindGroups(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.want || len(groups) != tt.want {
+				t.Fatalf("got %d groups, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+// UpdateGroup testing
+func UpdateGroup(
+	init func(GroupFields, *testing.T) (GroupServices, string, func()),
+	t *testing.T,
+) {
+	name := "platform engineers"
+	description := "builds the platform"
+	empty := ""
+
+	tests := []struct {
+		name  string
+		upd   platform.GroupUpdate
+		want  *platform.Group
+		err   error
+		extra *platform.Group
+	}{
+		{
+			name: "update the name and description",
+			upd:  platform.GroupUpdate{Name: &name, Description: &description},
+			want: &platform.Group{
+				ID:             MustIDBase16(groupOneID),
+				OrganizationID: MustIDBase16(groupOrgID),
+				Name:           name,
+				Description:    description,
+			},
+		},
+		{
+			name: "the name is required",
+			upd:  platform.GroupUpdate{Name: &empty},
+			err: &platform.Error{
+				Code: platform.EInvalid,
+				Op:   platform.OpUpdateGroup,
+				Msg:  "group name is required",
+			},
+		},
+		{
+			name: "the name must be unique",
+			upd:  platform.GroupUpdate{Name: &name},
+			extra: &platform.Group{
+				OrganizationID: MustIDBase16(groupOrgID),
+				Name:           name,
+			},
+			err: &platform.Error{
+				Code: platform.EConflict,
+				Op:   platform.OpUpdateGroup,
+				Msg:  "group with name platform engineers already exists",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(groupFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			if tt.extra != nil {
+				if err := s.CreateGroup(ctx, tt.extra); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			g, err := s.UpdateGroup(ctx, MustIDBase16(groupOneID), tt.upd)
+			diffPlatformErrors(tt.name, err, tt.err, opPrefix, t)
+			if tt.want != nil {
+				if diff := cmp.Diff(g, tt.want); diff != "" {
+					t.Errorf("groups are different -got/+want\ndiff %s", diff)
+				}
+			}
+		})
+	}
+}
+
+// DeleteGroup testing
+func DeleteGroup(
+	init func(GroupFields, *testing.T) (GroupServices, string, func()),
+	t *testing.T,
+) {
+	groupID := MustIDBase16(groupOneID)
+	userID := MustIDBase16("020f755c3c083200")
+	bucketID := MustIDBase16("020f755c3c083300")
+
+	fields := groupFields()
+	fields.UserResourceMappings = []*platform.UserResourceMapping{
+		{
+			ResourceID:   groupID,
+			ResourceType: platform.GroupResourceType,
+			UserID:       userID,
+			UserType:     platform.Member,
+		},
+		{
+			ResourceID:    bucketID,
+			ResourceType:  platform.BucketResourceType,
+			UserID:        groupID,
+			UserType:      platform.Owner,
+			PrincipalType: platform.GroupPrincipal,
+		},
+		{
+			ResourceID:   bucketID,
+			ResourceType: platform.BucketResourceType,
+			UserID:       userID,
+			UserType:     platform.Member,
+		},
+	}
+
+	s, opPrefix, done := init(fields, t)
+	defer done()
+	ctx := context.Background()
+
+	if err := s.DeleteGroup(ctx, groupID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FindGroupByID(ctx, groupID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected group to be deleted, got err %v", err)
+	}
+
+	// only the mapping of the user to the bucket is left.
+	ms, _, err := s.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(ms, fields.UserResourceMappings[2:], mappingCmpOptions...); diff != "" {
+		t.Errorf("mappings are different -got/+want\ndiff %s", diff)
+	}
+
+	err = s.DeleteGroup(ctx, groupID)
+	diffPlatformErrors("delete a missing group", err, &platform.Error{
+		Code: platform.ENotFound,
+		Op:   platform.OpDeleteGroup,
+		Msg:  "group not found",
+	}, opPrefix, t)
+}
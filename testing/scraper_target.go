@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/platform"
@@ -556,3 +557,119 @@ func UpdateTarget(
 		})
 	}
 }
+
+// TargetStatus testing
+func TargetStatus(
+	init func(TargetFields, *testing.T) (platform.ScraperTargetStatusService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id     platform.ID
+		status *platform.ScraperTargetStatus
+	}
+	type wants struct {
+		err    error
+		status *platform.ScraperTargetStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields TargetFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "status of target never scraped",
+			fields: TargetFields{
+				Targets: []*platform.ScraperTarget{
+					{
+						ID:   MustIDBase16(targetOneID),
+						Name: "target1",
+					},
+				},
+			},
+			args: args{
+				id: MustIDBase16(targetOneID),
+			},
+			wants: wants{
+				status: &platform.ScraperTargetStatus{
+					Health: platform.ScraperTargetHealthUnknown,
+				},
+			},
+		},
+		{
+			name: "put and get status",
+			fields: TargetFields{
+				Targets: []*platform.ScraperTarget{
+					{
+						ID:   MustIDBase16(targetOneID),
+						Name: "target1",
+					},
+					{
+						ID:   MustIDBase16(targetTwoID),
+						Name: "target2",
+					},
+				},
+			},
+			args: args{
+				id: MustIDBase16(targetTwoID),
+				status: &platform.ScraperTargetStatus{
+					Health:             platform.ScraperTargetHealthDown,
+					LastScrape:         time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					LastScrapeDuration: 3 * time.Second,
+					LastError:          "connection refused",
+				},
+			},
+			wants: wants{
+				status: &platform.ScraperTargetStatus{
+					Health:             platform.ScraperTargetHealthDown,
+					LastScrape:         time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					LastScrapeDuration: 3 * time.Second,
+					LastError:          "connection refused",
+				},
+			},
+		},
+		{
+			name: "status of target not found",
+			fields: TargetFields{
+				Targets: []*platform.ScraperTarget{
+					{
+						ID:   MustIDBase16(targetOneID),
+						Name: "target1",
+					},
+				},
+			},
+			args: args{
+				id: MustIDBase16(targetThreeID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpGetTargetStatus,
+					Msg:  "scraper target is not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.TODO()
+
+			if tt.args.status != nil {
+				if err := s.PutTargetStatus(ctx, tt.args.id, tt.args.status); err != nil {
+					t.Fatalf("failed to put target status: %v", err)
+				}
+			}
+
+			status, err := s.GetTargetStatus(ctx, tt.args.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(status, tt.wants.status); diff != "" {
+				t.Errorf("target status is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
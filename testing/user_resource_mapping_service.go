@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -55,6 +56,10 @@ func UserResourceMappingService(
 			name: "DeleteUserResourceMapping",
 			fn:   DeleteUserResourceMapping,
 		},
+		{
+			name: "UserPermissions",
+			fn:   UserPermissions,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -474,3 +479,92 @@ func FindUserResourceMappings(
 		})
 	}
 }
+
+// UserPermissions tests that the permissions of a user are the union of its
+// own and of those of its groups.
+func UserPermissions(
+	init func(UserResourceFields, *testing.T) (platform.UserResourceMappingService, func()),
+	t *testing.T,
+) {
+	userID := MustIDBase16("020f755c3c082300")
+	groupID := MustIDBase16("020f755c3c082100")
+	otherGroupID := MustIDBase16("020f755c3c082101")
+	bucket1 := MustIDBase16(bucketOneID)
+	bucket2 := MustIDBase16(bucketTwoID)
+	dashboardID := MustIDBase16("020f755c3c082200")
+
+	s, done := init(UserResourceFields{
+		UserResourceMappings: []*platform.UserResourceMapping{
+			// the user owns bucket 1 and is a member of the group.
+			{
+				ResourceID:   bucket1,
+				ResourceType: platform.BucketResourceType,
+				UserID:       userID,
+				UserType:     platform.Owner,
+			},
+			{
+				ResourceID:   groupID,
+				ResourceType: platform.GroupResourceType,
+				UserID:       userID,
+				UserType:     platform.Member,
+			},
+			// the group is a member of buckets 1 and 2.
+			{
+				ResourceID:    bucket1,
+				ResourceType:  platform.BucketResourceType,
+				UserID:        groupID,
+				UserType:      platform.Member,
+				PrincipalType: platform.GroupPrincipal,
+			},
+			{
+				ResourceID:    bucket2,
+				ResourceType:  platform.BucketResourceType,
+				UserID:        groupID,
+				UserType:      platform.Member,
+				PrincipalType: platform.GroupPrincipal,
+			},
+			// the user isn't a member of the other group.
+			{
+				ResourceID:    dashboardID,
+				ResourceType:  platform.DashboardResourceType,
+				UserID:        otherGroupID,
+				UserType:      platform.Owner,
+				PrincipalType: platform.GroupPrincipal,
+			},
+		},
+	}, t)
+	defer done()
+
+	ps, err := platform.UserPermissions(context.Background(), s, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &platform.Authorization{Status: platform.Active, Permissions: ps}
+	for _, p := range []platform.Permission{
+		platform.WriteBucketPermission(bucket1),
+		platform.ReadBucketPermission(bucket1),
+		platform.ReadBucketPermission(bucket2),
+	} {
+		if !a.Allowed(p) {
+			t.Errorf("user should have permission %s", p)
+		}
+	}
+	if a.Allowed(platform.WriteBucketPermission(bucket2)) {
+		t.Errorf("user shouldn't be able to write to bucket 2")
+	}
+	for _, p := range ps {
+		if strings.Contains(p.String(), dashboardID.String()) {
+			t.Errorf("user shouldn't have permission %s of another group", p)
+		}
+	}
+
+	// permissions are unique.
+	seen := make(map[platform.Permission]bool)
+	for _, p := range ps {
+		if seen[p] {
+			t.Errorf("duplicate permission %s", p)
+		}
+		seen[p] = true
+	}
+}
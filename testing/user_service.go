@@ -110,8 +110,9 @@ func CreateUser(
 			wants: wants{
 				users: []*platform.User{
 					{
-						Name: "name1",
-						ID:   MustIDBase16(userOneID),
+						Name:   "name1",
+						ID:     MustIDBase16(userOneID),
+						Status: platform.Active,
 					},
 				},
 			},
@@ -139,8 +140,9 @@ func CreateUser(
 						Name: "user1",
 					},
 					{
-						ID:   MustIDBase16(userTwoID),
-						Name: "user2",
+						ID:     MustIDBase16(userTwoID),
+						Name:   "user2",
+						Status: platform.Active,
 					},
 				},
 			},
@@ -575,7 +577,8 @@ func FindUser(
 	t *testing.T,
 ) {
 	type args struct {
-		name string
+		name    string
+		oauthID string
 	}
 
 	type wants struct {
@@ -613,6 +616,32 @@ func FindUser(
 				},
 			},
 		},
+		{
+			name: "find user by oauth id",
+			fields: UserFields{
+				Users: []*platform.User{
+					{
+						ID:   MustIDBase16(userOneID),
+						Name: "abc",
+					},
+					{
+						ID:      MustIDBase16(userTwoID),
+						Name:    "xyz",
+						OAuthID: "idp:xyz@example.com",
+					},
+				},
+			},
+			args: args{
+				oauthID: "idp:xyz@example.com",
+			},
+			wants: wants{
+				user: &platform.User{
+					ID:      MustIDBase16(userTwoID),
+					Name:    "xyz",
+					OAuthID: "idp:xyz@example.com",
+				},
+			},
+		},
 		{
 			name: "user does not exist",
 			fields: UserFields{
@@ -640,6 +669,9 @@ func FindUser(
 			if tt.args.name != "" {
 				filter.Name = &tt.args.name
 			}
+			if tt.args.oauthID != "" {
+				filter.OAuthID = &tt.args.oauthID
+			}
 
 			user, err := s.FindUser(ctx, filter)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
@@ -744,3 +776,205 @@ func UpdateUser(
 		})
 	}
 }
+
+// UserLifecycleFields will include the users along with their authorizations
+// and resource mappings.
+type UserLifecycleFields struct {
+	IDGenerator          platform.IDGenerator
+	TokenGenerator       platform.TokenGenerator
+	Users                []*platform.User
+	Authorizations       []*platform.Authorization
+	UserResourceMappings []*platform.UserResourceMapping
+}
+
+// UserLifecycleService is the set of services that manage users and the
+// resources that depend on them.
+type UserLifecycleService interface {
+	platform.UserService
+	platform.AuthorizationService
+	platform.UserResourceMappingService
+}
+
+func userLifecycleFields(t *testing.T) UserLifecycleFields {
+	return UserLifecycleFields{
+		IDGenerator:    mock.NewIDGenerator(authThreeID, t),
+		TokenGenerator: mock.NewTokenGenerator("rand3", nil),
+		Users: []*platform.User{
+			{
+				ID:     MustIDBase16(userOneID),
+				Name:   "user1",
+				Status: platform.Active,
+			},
+			{
+				ID:     MustIDBase16(userTwoID),
+				Name:   "user2",
+				Status: platform.Active,
+			},
+		},
+		Authorizations: []*platform.Authorization{
+			{
+				ID:     MustIDBase16(authOneID),
+				UserID: MustIDBase16(userOneID),
+				Token:  "rand1",
+				Status: platform.Active,
+			},
+			{
+				ID:     MustIDBase16(authTwoID),
+				UserID: MustIDBase16(userTwoID),
+				Token:  "rand2",
+				Status: platform.Active,
+			},
+		},
+		UserResourceMappings: []*platform.UserResourceMapping{
+			{
+				ResourceID:   MustIDBase16(orgOneID),
+				ResourceType: platform.OrgResourceType,
+				UserID:       MustIDBase16(userOneID),
+				UserType:     platform.Member,
+			},
+		},
+	}
+}
+
+// UserLifecycle tests the profile of users, their deactivation and the
+// cleanup of their resources when they are deleted.
+func UserLifecycle(
+	init func(UserLifecycleFields, *testing.T) (UserLifecycleService, string, func()),
+	t *testing.T,
+) {
+	t.Run("update profile", func(t *testing.T) {
+		s, _, done := init(userLifecycleFields(t), t)
+		defer done()
+		ctx := context.Background()
+
+		email, displayName, orgID := "user1@example.com", "User One", MustIDBase16(orgOneID)
+		u, err := s.UpdateUser(ctx, MustIDBase16(userOneID), platform.UserUpdate{
+			Email:        &email,
+			DisplayName:  &displayName,
+			DefaultOrgID: &orgID,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		want := &platform.User{
+			ID:           MustIDBase16(userOneID),
+			Name:         "user1",
+			Email:        email,
+			DisplayName:  displayName,
+			Status:       platform.Active,
+			DefaultOrgID: orgID,
+		}
+		if diff := cmp.Diff(u, want, userCmpOptions...); diff != "" {
+			t.Errorf("user is different -got/+want\ndiff %s", diff)
+		}
+		found, err := s.FindUserByID(ctx, MustIDBase16(userOneID))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if diff := cmp.Diff(found, want, userCmpOptions...); diff != "" {
+			t.Errorf("found user is different -got/+want\ndiff %s", diff)
+		}
+	})
+
+	t.Run("default org must be one of the user", func(t *testing.T) {
+		s, opPrefix, done := init(userLifecycleFields(t), t)
+		defer done()
+		ctx := context.Background()
+
+		orgID := MustIDBase16(orgOneID)
+		_, err := s.UpdateUser(ctx, MustIDBase16(userTwoID), platform.UserUpdate{
+			DefaultOrgID: &orgID,
+		})
+		diffPlatformErrors("default org", err, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpUpdateUser,
+			Msg:  "user is not a member of its default organization",
+		}, opPrefix, t)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		s, opPrefix, done := init(userLifecycleFields(t), t)
+		defer done()
+		ctx := context.Background()
+
+		status := platform.Status("disabled")
+		_, err := s.UpdateUser(ctx, MustIDBase16(userOneID), platform.UserUpdate{
+			Status: &status,
+		})
+		diffPlatformErrors("invalid status", err, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   platform.OpUpdateUser,
+			Msg:  `user status must be "active" or "inactive"`,
+		}, opPrefix, t)
+	})
+
+	t.Run("deactivate user", func(t *testing.T) {
+		s, opPrefix, done := init(userLifecycleFields(t), t)
+		defer done()
+		ctx := context.Background()
+
+		status := platform.Inactive
+		if _, err := s.UpdateUser(ctx, MustIDBase16(userOneID), platform.UserUpdate{
+			Status: &status,
+		}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		as, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		statuses := map[platform.ID]platform.Status{}
+		for _, a := range as {
+			statuses[a.ID] = a.Status
+		}
+		wantStatuses := map[platform.ID]platform.Status{
+			MustIDBase16(authOneID): platform.Inactive,
+			MustIDBase16(authTwoID): platform.Active,
+		}
+		if diff := cmp.Diff(statuses, wantStatuses); diff != "" {
+			t.Errorf("authorization statuses are different -got/+want\ndiff %s", diff)
+		}
+
+		err = s.CreateAuthorization(ctx, &platform.Authorization{
+			UserID: MustIDBase16(userOneID),
+		})
+		diffPlatformErrors("create authorization", err, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   platform.OpCreateAuthorization,
+			Msg:  platform.ErrUserInactive,
+		}, opPrefix, t)
+	})
+
+	t.Run("delete user removes its resources", func(t *testing.T) {
+		s, _, done := init(userLifecycleFields(t), t)
+		defer done()
+		ctx := context.Background()
+
+		if err := s.DeleteUser(ctx, MustIDBase16(userOneID)); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		userID := MustIDBase16(userOneID)
+		as, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{UserID: &userID})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if len(as) != 0 {
+			t.Errorf("expected the authorizations of the user to be deleted, got %d", len(as))
+		}
+
+		ms, _, err := s.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: userID})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if len(ms) != 0 {
+			t.Errorf("expected the mappings of the user to be deleted, got %d", len(ms))
+		}
+
+		if _, err := s.FindAuthorizationByID(ctx, MustIDBase16(authTwoID)); err != nil {
+			t.Errorf("expected the authorizations of other users to be kept, got %v", err)
+		}
+	})
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/influxdata/platform/kit/tracing"
 	"github.com/influxdata/platform/models"
 	"github.com/influxdata/platform/pkg/metrics"
 	"github.com/influxdata/platform/query"
 	"github.com/influxdata/platform/tsdb"
 	"github.com/influxdata/platform/tsdb/cursors"
+	opentracing "github.com/opentracing/opentracing-go"
 )
 
 type arrayCursorIterator struct {
@@ -43,6 +45,13 @@ func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (
 		grp.GetCounter(numberOfRefCursorsCounter).Add(1)
 	}
 
+	if span := opentracing.SpanFromContext(ctx); tracing.IsSampled(span) {
+		span = opentracing.StartSpan("tsm1.cursor.create",
+			opentracing.ChildOf(span.Context()),
+			opentracing.Tag{Key: "field", Value: r.Field})
+		defer span.Finish()
+	}
+
 	var opt query.IteratorOptions
 	opt.Ascending = r.Ascending
 	opt.StartTime = r.StartTime
@@ -2,14 +2,34 @@ package platform
 
 import (
 	"context"
+	"fmt"
 )
 
 // User is a user. 🎉
 type User struct {
-	ID   ID     `json:"id,omitempty"`
-	Name string `json:"name"`
+	ID          ID     `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	// Status is active unless the user was deactivated. An inactive user
+	// can't sign in, and its authorizations and sessions are disabled.
+	Status Status `json:"status,omitempty"`
+	// DefaultOrgID is the organization the user works in by default, the
+	// user must be a member of it.
+	DefaultOrgID ID `json:"defaultOrgID,omitempty"`
+	// OAuthID links the user to an identity at an external provider, as
+	// "provider:name", which may then sign in as the user.
+	OAuthID string `json:"oauthID,omitempty"`
 }
 
+// IsActive returns true if the user was not deactivated.
+func (u *User) IsActive() bool {
+	return u.Status != Inactive
+}
+
+// ErrUserInactive is the error message returned when acting as an inactive user.
+const ErrUserInactive = "user is inactive"
+
 // Ops for user errors and op log.
 const (
 	OpFindUserByID = "FindUserByID"
@@ -40,7 +60,8 @@ type UserService interface {
 	// Returns the new user state after update.
 	UpdateUser(ctx context.Context, id ID, upd UserUpdate) (*User, error)
 
-	// Removes a user by ID.
+	// Removes a user by ID, along with its authorizations, sessions,
+	// password and resource mappings.
 	DeleteUser(ctx context.Context, id ID) error
 }
 
@@ -54,11 +75,110 @@ type BasicAuthService interface {
 // UserUpdate represents updates to a user.
 // Only fields which are set are updated.
 type UserUpdate struct {
-	Name *string `json:"name"`
+	Name        *string `json:"name"`
+	Email       *string `json:"email,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+	// Status deactivates the user when set to inactive, which also disables
+	// all its authorizations and expires its sessions. Reactivating the
+	// user leaves them disabled.
+	Status       *Status `json:"status,omitempty"`
+	DefaultOrgID *ID     `json:"defaultOrgID,omitempty"`
+	// OAuthID links the user to an external identity, or unlinks it if empty.
+	OAuthID *string `json:"oauthID,omitempty"`
+}
+
+// Valid returns an error if the update would leave the user invalid.
+func (u UserUpdate) Valid() error {
+	if u.Name != nil && *u.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "user name is required",
+		}
+	}
+	if u.Status != nil {
+		if err := validUserStatus(*u.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validUserStatus returns an error if s is not a status of users.
+func validUserStatus(s Status) error {
+	switch s {
+	case Active, Inactive:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("user status must be %q or %q", Active, Inactive),
+		}
+	}
+}
+
+// Apply applies the update to the user.
+func (u UserUpdate) Apply(usr *User) {
+	if u.Name != nil {
+		usr.Name = *u.Name
+	}
+	if u.Email != nil {
+		usr.Email = *u.Email
+	}
+	if u.DisplayName != nil {
+		usr.DisplayName = *u.DisplayName
+	}
+	if u.Status != nil {
+		usr.Status = *u.Status
+	}
+	if u.DefaultOrgID != nil {
+		usr.DefaultOrgID = *u.DefaultOrgID
+	}
+	if u.OAuthID != nil {
+		usr.OAuthID = *u.OAuthID
+	}
+}
+
+// ValidateNewUser returns an error if a user can't be created as is, and
+// marks it as active unless its status is set.
+func ValidateNewUser(u *User) error {
+	if u.Status == "" {
+		u.Status = Active
+	}
+	if err := validUserStatus(u.Status); err != nil {
+		return err
+	}
+	if u.DefaultOrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "the default organization of a user can only be set once the user is a member of it",
+		}
+	}
+	return nil
+}
+
+// ValidateDefaultOrg returns an error if the user is not a member of org,
+// directly or through one of its groups.
+func ValidateDefaultOrg(find func(UserResourceMappingFilter) ([]*UserResourceMapping, error), userID, orgID ID) error {
+	ms, err := FindUserMappings(find, userID)
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if m.ResourceType == OrgResourceType && m.ResourceID == orgID {
+			return nil
+		}
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  "user is not a member of its default organization",
+	}
 }
 
 // UserFilter represents a set of filter that restrict the returned results.
 type UserFilter struct {
 	ID   *ID
 	Name *string
+
+	// OAuthID finds the user linked to an identity at an external provider.
+	OAuthID *string
 }
@@ -21,6 +21,21 @@ const (
 	TelegrafResourceType  ResourceType = "telegraf"
 	TokenResourceType     ResourceType = "token"
 	UserResourceType      ResourceType = "user"
+	GroupResourceType     ResourceType = "group"
+	ScraperResourceType   ResourceType = "scraper"
+	MacroResourceType     ResourceType = "macro"
+	SourceResourceType    ResourceType = "source"
+)
+
+// PrincipalType is the kind of principal a resource is mapped to.
+type PrincipalType string
+
+// available principal types.
+const (
+	// UserPrincipal maps a user to a resource. It is the default.
+	UserPrincipal PrincipalType = "user"
+	// GroupPrincipal maps a group to a resource, for all of its members.
+	GroupPrincipal PrincipalType = "group"
 )
 
 // UserResourceMappingService maps the relationships between users and resources
@@ -35,12 +50,23 @@ type UserResourceMappingService interface {
 	DeleteUserResourceMapping(ctx context.Context, resourceID ID, userID ID) error
 }
 
-// UserResourceMapping represents a mapping of a resource to its user
+// UserResourceMapping represents a mapping of a resource to its user.
+// When the principal type is group, UserID is the ID of the group.
 type UserResourceMapping struct {
-	ResourceID   ID           `json:"resource_id"`
-	ResourceType ResourceType `json:"resource_type"`
-	UserID       ID           `json:"user_id"`
-	UserType     UserType     `json:"user_type"`
+	ResourceID    ID            `json:"resource_id"`
+	ResourceType  ResourceType  `json:"resource_type"`
+	UserID        ID            `json:"user_id"`
+	UserType      UserType      `json:"user_type"`
+	PrincipalType PrincipalType `json:"principal_type,omitempty"`
+}
+
+// Principal returns the principal type of the mapping, mappings without
+// one being user mappings.
+func (m UserResourceMapping) Principal() PrincipalType {
+	if m.PrincipalType == "" {
+		return UserPrincipal
+	}
+	return m.PrincipalType
 }
 
 // Validate reports any validation errors for the mapping.
@@ -55,25 +81,46 @@ func (m UserResourceMapping) Validate() error {
 		return errors.New("a valid user type is required")
 	}
 	switch m.ResourceType {
-	case DashboardResourceType, BucketResourceType, TaskResourceType, OrgResourceType, ViewResourceType, TelegrafResourceType:
+	case DashboardResourceType, BucketResourceType, TaskResourceType, OrgResourceType, ViewResourceType, TelegrafResourceType, GroupResourceType:
 	default:
 		return errors.New("a valid resource type is required")
 	}
+	switch m.Principal() {
+	case UserPrincipal:
+	case GroupPrincipal:
+		if m.ResourceType == GroupResourceType {
+			return errors.New("groups can't be members of groups")
+		}
+	default:
+		return errors.New("a valid principal type is required")
+	}
 	return nil
 }
 
 // UserResourceMapping represents a set of filters that restrict the returned results.
 type UserResourceMappingFilter struct {
-	ResourceID   ID
-	ResourceType ResourceType
-	UserID       ID
-	UserType     UserType
+	ResourceID    ID
+	ResourceType  ResourceType
+	UserID        ID
+	UserType      UserType
+	PrincipalType PrincipalType
+}
+
+// Matches returns true if the mapping matches the filter.
+func (f UserResourceMappingFilter) Matches(m *UserResourceMapping) bool {
+	return (!f.UserID.Valid() || f.UserID == m.UserID) &&
+		(!f.ResourceID.Valid() || f.ResourceID == m.ResourceID) &&
+		(f.UserType == "" || f.UserType == m.UserType) &&
+		(f.ResourceType == "" || f.ResourceType == m.ResourceType) &&
+		(f.PrincipalType == "" || f.PrincipalType == m.Principal())
 }
 
 var ownerActions = []action{WriteAction, CreateAction, DeleteAction}
 var memberActions = []action{ReadAction}
 
 // ToPermission converts a user resource mapping into a set of permissions.
+// The permissions are the same whether the resource is mapped to a user or to
+// a group, see UserPermissions for the permissions a user gets from its groups.
 func (m *UserResourceMapping) ToPermissions() []Permission {
 	// TODO(desa): we'll have to do something more fine-grained eventually
 	// but this should be good enough for now.
@@ -99,3 +146,73 @@ func (m *UserResourceMapping) ToPermissions() []Permission {
 
 	return ps
 }
+
+// UserPermissions returns the permissions of a user: the union of the
+// permissions on the resources mapped to the user and of those on the
+// resources mapped to the groups the user is a member of.
+func UserPermissions(ctx context.Context, s UserResourceMappingService, userID ID) ([]Permission, error) {
+	find := func(f UserResourceMappingFilter) ([]*UserResourceMapping, error) {
+		ms, _, err := s.FindUserResourceMappings(ctx, f)
+		return ms, err
+	}
+	return FindUserPermissions(find, userID)
+}
+
+// FindUserPermissions is UserPermissions looking the mappings up with find,
+// so that services can resolve permissions within a transaction.
+func FindUserPermissions(find func(UserResourceMappingFilter) ([]*UserResourceMapping, error), userID ID) ([]Permission, error) {
+	ms, err := FindUserMappings(find, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Permission]bool)
+	ps := []Permission{}
+	for _, m := range ms {
+		for _, p := range m.ToPermissions() {
+			if !seen[p] {
+				seen[p] = true
+				ps = append(ps, p)
+			}
+		}
+	}
+	return ps, nil
+}
+
+// UserMappings returns the mappings of the resources of a user: those mapped
+// to the user, and those mapped to the groups the user is a member of.
+func UserMappings(ctx context.Context, s UserResourceMappingService, userID ID) ([]*UserResourceMapping, error) {
+	find := func(f UserResourceMappingFilter) ([]*UserResourceMapping, error) {
+		ms, _, err := s.FindUserResourceMappings(ctx, f)
+		return ms, err
+	}
+	return FindUserMappings(find, userID)
+}
+
+// FindUserMappings is UserMappings looking the mappings up with find.
+func FindUserMappings(find func(UserResourceMappingFilter) ([]*UserResourceMapping, error), userID ID) ([]*UserResourceMapping, error) {
+	ms, err := find(UserResourceMappingFilter{
+		UserID:        userID,
+		PrincipalType: UserPrincipal,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*UserResourceMapping
+	for _, m := range ms {
+		if m.ResourceType != GroupResourceType {
+			continue
+		}
+		gms, err := find(UserResourceMappingFilter{
+			UserID:        m.ResourceID,
+			PrincipalType: GroupPrincipal,
+		})
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, gms...)
+	}
+
+	return append(ms, groups...), nil
+}
@@ -0,0 +1,245 @@
+package write
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform/models"
+)
+
+// Data types of the columns of CSV, as annotated by the #datatype row.
+const (
+	CSVMeasurement  = "measurement"
+	CSVTag          = "tag"
+	CSVIgnored      = "ignored"
+	CSVDouble       = "double"
+	CSVLong         = "long"
+	CSVUnsignedLong = "unsignedLong"
+	CSVBoolean      = "boolean"
+	CSVString       = "string"
+	// CSVDateTime is the time of the points, formatted as RFC3339 by
+	// default; "dateTime:number" is a timestamp in the precision of the
+	// write.
+	CSVDateTime = "dateTime"
+
+	// csvInferred fields are booleans, doubles or strings depending on their
+	// values.
+	csvInferred = ""
+)
+
+const csvDatatypeAnnotation = "#datatype"
+
+type csvColumn struct {
+	label    string
+	datatype string
+	format   string
+}
+
+// CSVReader reads CSV as line protocol, one point per row.
+//
+// The header row names the columns, and an optional #datatype annotation
+// row ahead of it gives their data types, e.g.
+//
+//	#datatype measurement,tag,double,dateTime:RFC3339
+//	m,host,usage,time
+//	cpu,a,0.5,2018-10-02T00:00:00Z
+//
+// Without annotation, the _measurement column is the measurement, _time
+// is the time formatted as RFC3339, and the other columns are fields whose
+// types are inferred from their values. The empty cells are skipped and the
+// rows without measurement or fields are invalid. Other rows starting with
+// # are comments.
+type CSVReader struct {
+	// OnError is called with the rows failing to convert to line protocol.
+	// Reading fails with the error it returns; it fails with the error of
+	// the row if OnError is nil.
+	OnError func(line int, row []string, err error) error
+
+	r         *csv.Reader
+	precision string
+	datatypes []string
+	cols      []csvColumn
+
+	buf bytes.Buffer
+	err error
+}
+
+// NewCSVReader returns a CSVReader reading r, whose timestamps are written
+// in precision.
+func NewCSVReader(r io.Reader, precision string) *CSVReader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &CSVReader{
+		r:         cr,
+		precision: precision,
+	}
+}
+
+// Read reads line protocol into p.
+func (r *CSVReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 && r.err == nil {
+		r.err = r.next()
+	}
+	if r.buf.Len() > 0 {
+		return r.buf.Read(p)
+	}
+	return 0, r.err
+}
+
+// next converts the next row of CSV to line protocol.
+func (r *CSVReader) next() error {
+	row, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	if strings.HasPrefix(row[0], "#") {
+		if strings.HasPrefix(row[0], csvDatatypeAnnotation) && r.cols == nil {
+			row[0] = strings.TrimSpace(strings.TrimPrefix(row[0], csvDatatypeAnnotation))
+			r.datatypes = row
+		}
+		return nil
+	}
+	if r.cols == nil {
+		return r.header(line, row)
+	}
+
+	p, err := r.point(row)
+	if err != nil {
+		err = fmt.Errorf("line %d: %v", line, err)
+		if r.OnError == nil {
+			return err
+		}
+		return r.OnError(line, row, err)
+	}
+	r.buf.WriteString(p.PrecisionString(r.precision))
+	r.buf.WriteByte('\n')
+	return nil
+}
+
+// header reads the columns of the header row.
+func (r *CSVReader) header(line int, row []string) error {
+	if r.datatypes != nil && len(r.datatypes) != len(row) {
+		return fmt.Errorf("line %d: %d data types for %d columns", line, len(r.datatypes), len(row))
+	}
+
+	r.cols = make([]csvColumn, len(row))
+	for i, label := range row {
+		col := csvColumn{label: label, datatype: csvInferred}
+		if r.datatypes != nil {
+			col.datatype = r.datatypes[i]
+			if j := strings.Index(col.datatype, ":"); j >= 0 {
+				col.datatype, col.format = col.datatype[:j], col.datatype[j+1:]
+			}
+		} else {
+			switch label {
+			case "_measurement":
+				col.datatype = CSVMeasurement
+			case "_time":
+				col.datatype = CSVDateTime
+			}
+		}
+
+		switch col.datatype {
+		case CSVMeasurement, CSVTag, CSVIgnored, CSVDouble, CSVLong, CSVUnsignedLong, CSVBoolean, CSVString, csvInferred:
+		case CSVDateTime:
+			switch col.format {
+			case "", "RFC3339", "number":
+			default:
+				return fmt.Errorf("line %d: unknown format %q of column %q", line, col.format, label)
+			}
+		default:
+			return fmt.Errorf("line %d: unknown data type %q of column %q", line, col.datatype, label)
+		}
+		r.cols[i] = col
+	}
+	return nil
+}
+
+// point returns the point of a row.
+func (r *CSVReader) point(row []string) (models.Point, error) {
+	if len(row) != len(r.cols) {
+		return nil, fmt.Errorf("%d values for %d columns", len(row), len(r.cols))
+	}
+
+	var (
+		name   string
+		t      time.Time
+		tags   = map[string]string{}
+		fields = models.Fields{}
+	)
+	for i, col := range r.cols {
+		v := row[i]
+		if v == "" {
+			continue
+		}
+
+		var err error
+		switch col.datatype {
+		case CSVIgnored:
+		case CSVMeasurement:
+			name = v
+		case CSVTag:
+			tags[col.label] = v
+		case CSVDateTime:
+			t, err = r.time(col, v)
+		default:
+			fields[col.label], err = fieldValue(col.datatype, v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %v", col.label, err)
+		}
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("no measurement")
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields")
+	}
+	return models.NewPoint(name, models.NewTags(tags), fields, t)
+}
+
+func (r *CSVReader) time(col csvColumn, v string) (time.Time, error) {
+	if col.format == "number" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, n*models.GetPrecisionMultiplier(r.precision)).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+func fieldValue(datatype, v string) (interface{}, error) {
+	switch datatype {
+	case CSVDouble:
+		return strconv.ParseFloat(v, 64)
+	case CSVLong:
+		return strconv.ParseInt(v, 10, 64)
+	case CSVUnsignedLong:
+		return strconv.ParseUint(v, 10, 64)
+	case CSVBoolean:
+		return strconv.ParseBool(v)
+	case CSVString:
+		return v, nil
+	}
+
+	switch v {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f, nil
+	}
+	return v, nil
+}
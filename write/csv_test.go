@@ -0,0 +1,95 @@
+package write
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestCSVReader(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		precision string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "annotated",
+			csv: `#datatype measurement,tag,double,long,unsignedLong,boolean,string,ignored,dateTime:RFC3339
+m,host,usage,count,total,up,status,note,time
+cpu,a,0.5,1,2,true,ok,x,1970-01-01T00:00:01Z
+cpu,,1,,,,,,1970-01-01T00:00:02Z
+`,
+			precision: "s",
+			want: `cpu,host=a count=1i,status="ok",total=2u,up=true,usage=0.5 1
+cpu usage=1 2
+`,
+		},
+		{
+			name: "number timestamps",
+			csv: `#datatype measurement,double,dateTime:number
+m,v,t
+cpu,1,1500
+`,
+			precision: "ms",
+			want:      "cpu v=1 1500\n",
+		},
+		{
+			name: "inferred",
+			csv: `# exported from somewhere
+_measurement,_time,host,up,v
+cpu,1970-01-01T00:00:00.000000001Z,a,false,1.5
+`,
+			precision: "ns",
+			want:      "cpu host=\"a\",up=false,v=1.5 1\n",
+		},
+		{
+			name: "no fields",
+			csv: `_measurement,v
+cpu,
+`,
+			precision: "ns",
+			wantErr:   true,
+		},
+		{
+			name: "unknown data type",
+			csv: `#datatype measurement,decimal
+m,v
+`,
+			precision: "ns",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ioutil.ReadAll(NewCSVReader(strings.NewReader(tt.csv), tt.precision))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CSVReader.Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("CSVReader.Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVReader_OnError(t *testing.T) {
+	r := NewCSVReader(strings.NewReader("_measurement,v\ncpu,1\n,2\ncpu,3\n"), "ns")
+	var lines []int
+	r.OnError = func(line int, row []string, err error) error {
+		lines = append(lines, line)
+		return nil
+	}
+
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "cpu v=1\ncpu v=3\n" {
+		t.Errorf("unexpected line protocol %q", got)
+	}
+	if len(lines) != 1 || lines[0] != 3 {
+		t.Errorf("expected line 3 to be skipped, got %v", lines)
+	}
+}
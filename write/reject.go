@@ -0,0 +1,82 @@
+package write
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync/atomic"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.WriteService = (*RejectService)(nil)
+
+// RejectService skips the lines another write service rejects as invalid,
+// instead of failing the whole write. The rejected writes are split in
+// halves until the invalid lines are isolated, and the other lines are
+// written.
+type RejectService struct {
+	Service platform.WriteService // Service receives the writes.
+	// Rejects receives the rejected lines, each preceded by a comment with
+	// the error, so that they can be fixed and written again.
+	Rejects io.Writer
+
+	rejected int64
+}
+
+// Rejected returns the number of lines rejected so far.
+func (s *RejectService) Rejected() int64 {
+	return atomic.LoadInt64(&s.rejected)
+}
+
+// Reject writes a rejected line to Rejects.
+func (s *RejectService) Reject(line []byte, err error) error {
+	atomic.AddInt64(&s.rejected, 1)
+	if s.Rejects == nil {
+		return nil
+	}
+	msg := strings.Replace(err.Error(), "\n", " ", -1)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	_, err = fmt.Fprintf(s.Rejects, "# error: %s\n%s", msg, line)
+	return err
+}
+
+// Write sends r to the service, skipping the lines it rejects as invalid.
+func (s *RejectService) Write(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var lines [][]byte
+	for _, l := range bytes.SplitAfter(buf, []byte("\n")) {
+		if len(bytes.TrimSpace(l)) > 0 {
+			lines = append(lines, l)
+		}
+	}
+	return s.write(ctx, org, bucket, lines)
+}
+
+func (s *RejectService) write(ctx context.Context, org, bucket platform.ID, lines [][]byte) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	err := s.Service.Write(ctx, org, bucket, bytes.NewReader(bytes.Join(lines, nil)))
+	if err == nil || platform.ErrorCode(err) != platform.EInvalid {
+		return err
+	}
+	if len(lines) == 1 {
+		return s.Reject(lines[0], err)
+	}
+
+	if err := s.write(ctx, org, bucket, lines[:len(lines)/2]); err != nil {
+		return err
+	}
+	return s.write(ctx, org, bucket, lines[len(lines)/2:])
+}
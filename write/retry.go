@@ -0,0 +1,89 @@
+package write
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failing write is retried.
+	DefaultMaxRetries = 5
+	// DefaultRetryInterval is the wait before the first retry of a write.
+	DefaultRetryInterval = time.Second
+	// DefaultMaxRetryInterval is the longest wait between two retries.
+	DefaultMaxRetryInterval = 30 * time.Second
+)
+
+var _ platform.WriteService = (*RetryService)(nil)
+
+// RetryService retries the writes of another write service failing
+// transiently, doubling the wait between the retries.
+type RetryService struct {
+	Service          platform.WriteService // Service receives the writes and their retries.
+	MaxRetries       int                   // MaxRetries is the number of retries of a write before failing
+	RetryInterval    time.Duration         // RetryInterval is the wait before the first retry
+	MaxRetryInterval time.Duration         // MaxRetryInterval is the longest wait between two retries
+
+	// OnRetry is called, if set, before waiting to retry a write that failed
+	// with err.
+	OnRetry func(err error, retry int, wait time.Duration)
+}
+
+// Retryable reports whether a write failing with err may succeed later: the
+// server is unavailable or overloaded, or it couldn't be reached.
+func Retryable(err error) bool {
+	switch platform.ErrorCode(err) {
+	case platform.EInternal, platform.EUnavailable, platform.ETooManyRequests:
+		return true
+	}
+	return false
+}
+
+// Write sends r to the service, retrying the transient failures.
+func (s *RetryService) Write(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+	// the request is read once per attempt.
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	maxRetries := s.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	wait := s.RetryInterval
+	if wait == 0 {
+		wait = DefaultRetryInterval
+	}
+	maxWait := s.MaxRetryInterval
+	if maxWait == 0 {
+		maxWait = DefaultMaxRetryInterval
+	}
+
+	for retry := 1; ; retry++ {
+		err := s.Service.Write(ctx, org, bucket, bytes.NewReader(buf))
+		if err == nil || retry > maxRetries || !Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		if s.OnRetry != nil {
+			s.OnRetry(err, retry, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if wait *= 2; wait > maxWait {
+			wait = maxWait
+		}
+	}
+}
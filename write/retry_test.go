@@ -0,0 +1,119 @@
+package write
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
+)
+
+func TestRetryService_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		errs    []error
+		writes  int
+		wantErr bool
+	}{
+		{
+			name:   "retries unavailable and too many requests",
+			errs:   []error{&platform.Error{Code: platform.EUnavailable}, &platform.Error{Code: platform.ETooManyRequests}},
+			writes: 3,
+		},
+		{
+			name:    "gives up after max retries",
+			errs:    []error{&platform.Error{Code: platform.EInternal}, &platform.Error{Code: platform.EInternal}, &platform.Error{Code: platform.EInternal}},
+			writes:  3,
+			wantErr: true,
+		},
+		{
+			name:    "does not retry invalid writes",
+			errs:    []error{&platform.Error{Code: platform.EInvalid}},
+			writes:  1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			s := &RetryService{
+				Service: &mock.WriteService{
+					WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+						b, _ := ioutil.ReadAll(r)
+						writes = append(writes, string(b))
+						if len(writes) <= len(tt.errs) {
+							return tt.errs[len(writes)-1]
+						}
+						return nil
+					},
+				},
+				MaxRetries:    2,
+				RetryInterval: time.Millisecond,
+			}
+
+			err := s.Write(context.Background(), platform.ID(1), platform.ID(2), strings.NewReader("m f=1\n"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RetryService.Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(writes) != tt.writes {
+				t.Fatalf("expected %d writes, got %d", tt.writes, len(writes))
+			}
+			for _, w := range writes {
+				if w != "m f=1\n" {
+					t.Errorf("expected every write to send the lines, got %q", w)
+				}
+			}
+		})
+	}
+}
+
+func TestRejectService_Write(t *testing.T) {
+	var written []string
+	var rejects bytes.Buffer
+	s := &RejectService{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				b, _ := ioutil.ReadAll(r)
+				if bytes.Contains(b, []byte("bad")) {
+					return &platform.Error{Code: platform.EInvalid, Msg: "unable to parse"}
+				}
+				written = append(written, string(b))
+				return nil
+			},
+		},
+		Rejects: &rejects,
+	}
+
+	lines := "m f=1\nm f=2\nbad\nm f=3\nm f=4\nbad again"
+	if err := s.Write(context.Background(), platform.ID(1), platform.ID(2), strings.NewReader(lines)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(written, ""), "m f=1\nm f=2\nm f=3\nm f=4\n"; got != want {
+		t.Errorf("unexpected lines written %q, want %q", got, want)
+	}
+	if want := "# error: <invalid> unable to parse\nbad\n# error: <invalid> unable to parse\nbad again\n"; rejects.String() != want {
+		t.Errorf("unexpected rejects %q, want %q", rejects.String(), want)
+	}
+	if s.Rejected() != 2 {
+		t.Errorf("expected 2 rejected lines, got %d", s.Rejected())
+	}
+}
+
+func TestRejectService_Write_Error(t *testing.T) {
+	s := &RejectService{
+		Service: &mock.WriteService{
+			WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+				return &platform.Error{Code: platform.EUnauthorized}
+			},
+		},
+	}
+	err := s.Write(context.Background(), platform.ID(1), platform.ID(2), strings.NewReader("m f=1\nm f=2\n"))
+	if platform.ErrorCode(err) != platform.EUnauthorized {
+		t.Fatalf("expected the error of the service, got %v", err)
+	}
+}
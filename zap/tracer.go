@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/influxdata/platform"
@@ -17,6 +18,9 @@ import (
 
 const (
 	traceHTTPHeader = "Zap-Trace-Span"
+	// traceparentHeader is the header of the W3C trace context, whose trace
+	// IDs are 16 bytes; the IDs of the tracer are their last 8 bytes.
+	traceparentHeader = "Traceparent"
 
 	logTraceIDKey     = "ot_trace_id"
 	logSpanIDKey      = "ot_span_id"
@@ -130,20 +134,40 @@ func injectTextMapWriter(ctx SpanContext, w opentracing.TextMapWriter) error {
 		return err
 	}
 	w.Set(traceHTTPHeader, string(data))
+	w.Set(traceparentHeader, fmt.Sprintf("00-%016x%s-%s-01", 0, ctx.traceID, ctx.spanID))
 	return nil
 }
 
 func extractTextMapReader(ctx *SpanContext, r opentracing.TextMapReader) error {
 	var data []byte
+	var traceparent string
 	r.ForeachKey(func(k, v string) error {
-		if http.CanonicalHeaderKey(k) == traceHTTPHeader {
+		switch http.CanonicalHeaderKey(k) {
+		case traceHTTPHeader:
 			data = []byte(v)
+		case traceparentHeader:
+			traceparent = v
 		}
 		return nil
 	})
+	if data == nil && traceparent != "" {
+		return extractTraceparent(ctx, traceparent)
+	}
 	return json.Unmarshal(data, ctx)
 }
 
+// extractTraceparent reads the IDs of a W3C trace context.
+func extractTraceparent(ctx *SpanContext, traceparent string) error {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 {
+		return fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	if err := ctx.traceID.DecodeFromString(parts[1][16:]); err != nil {
+		return err
+	}
+	return ctx.spanID.DecodeFromString(parts[2])
+}
+
 // Span implements opentracing.Span, all Spans must be created using  the Tracer.
 type Span struct {
 	tracer *Tracer